@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
+	_ "time/tzdata" // recurring appointments expand in Europe/Zagreb even where the OS has no zoneinfo (Windows)
 
 	corehandlers "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers"
+	"github.com/OPetricevic/physio-tracker/backend/internal/workers"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -25,6 +31,10 @@ func main() {
 		log.Fatalf("failed to connect db: %v", err)
 	}
 
+	// Stop on Ctrl+C / SIGTERM: the server drains requests and the workers finish their current run.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Build router (health, auth, protected modules).
 	r := corehandlers.BuildRouter(db)
 
@@ -33,10 +43,27 @@ func main() {
 		Handler: r,
 	}
 
-	log.Printf("listening on :%s", addr)
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("server failed: %v", err)
+	// Background workers.
+	var wg sync.WaitGroup
+	workers.StartWaitlist(ctx, &wg, corehandlers.NewAppointmentService(db))
+	workers.StartReminders(ctx, &wg, corehandlers.NewReminderService(db))
+	workers.StartAnamnesisLock(ctx, &wg, corehandlers.NewAnamnesisService(db))
+
+	go func() {
+		log.Printf("listening on :%s", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("server failed: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("server shutdown: %v", err)
 	}
+	wg.Wait()
 }
 
 func envOrDefault(key, def string) string {
@@ -22,4 +22,16 @@ func (h *Handler) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/patients/{patient_uuid}/anamneses/{uuid}", h.controller.Update).Methods(http.MethodPatch)
 	r.HandleFunc("/patients/{patient_uuid}/anamneses/{uuid}", h.controller.Delete).Methods(http.MethodDelete)
 	r.HandleFunc("/patients/{patient_uuid}/anamneses/{uuid}/pdf", h.controller.GeneratePDF).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/anamneses/{uuid}/revisions", h.controller.Revisions).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/anamneses/{uuid}/revisions/diff", h.controller.RevisionDiff).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/anamneses/{uuid}/sign", h.controller.Sign).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/anamneses/{uuid}/addenda", h.controller.AddAddendum).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/discharge-summaries", h.controller.ListDischargeSummaries).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/discharge-summaries", h.controller.CreateDischargeSummary).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/discharge-summaries/{uuid}/pdf", h.controller.DischargeSummaryPDF).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/progress", h.controller.Progress).Methods(http.MethodGet)
+	r.HandleFunc("/body-chart/regions", h.controller.BodyChartRegions).Methods(http.MethodGet)
+	r.HandleFunc("/red-flags/checklist", h.controller.RedFlagChecklist).Methods(http.MethodGet)
+	r.HandleFunc("/red-flags/checklist", h.controller.UpdateRedFlagChecklist).Methods(http.MethodPut)
+	r.HandleFunc("/red-flags/open", h.controller.OpenRedFlags).Methods(http.MethodGet)
 }
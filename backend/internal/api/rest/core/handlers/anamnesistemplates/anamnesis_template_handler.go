@@ -0,0 +1,26 @@
+package anamnesistemplates
+
+import (
+	"net/http"
+
+	ctrl "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/anamnesistemplates"
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	controller *ctrl.Controller
+}
+
+func NewHandler(controller *ctrl.Controller) *Handler {
+	return &Handler{controller: controller}
+}
+
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	// All routes are protected (doctor auth) via parent router middleware.
+	// Templates are applied through POST /patients/{patient_uuid}/anamneses?template={uuid}.
+	r.HandleFunc("/anamnesis-templates", h.controller.List).Methods(http.MethodGet)
+	r.HandleFunc("/anamnesis-templates", h.controller.Create).Methods(http.MethodPost)
+	r.HandleFunc("/anamnesis-templates/{uuid}", h.controller.Get).Methods(http.MethodGet)
+	r.HandleFunc("/anamnesis-templates/{uuid}", h.controller.Update).Methods(http.MethodPatch)
+	r.HandleFunc("/anamnesis-templates/{uuid}", h.controller.Delete).Methods(http.MethodDelete)
+}
@@ -0,0 +1,55 @@
+package appointments
+
+import (
+	"net/http"
+
+	ctrl "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/appointments"
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	controller *ctrl.Controller
+}
+
+func NewHandler(controller *ctrl.Controller) *Handler {
+	return &Handler{controller: controller}
+}
+
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	// All routes are protected (doctor auth) via parent router middleware.
+	r.HandleFunc("/appointments", h.controller.List).Methods(http.MethodGet)
+	r.HandleFunc("/appointments", h.controller.Create).Methods(http.MethodPost)
+	r.HandleFunc("/appointments/free-slots", h.controller.FreeSlots).Methods(http.MethodGet)
+	r.HandleFunc("/appointments/export.ics", h.controller.ExportICS).Methods(http.MethodGet)
+	r.HandleFunc("/appointments/import", h.controller.ImportICS).Methods(http.MethodPost)
+	r.HandleFunc("/appointments/import/reviews", h.controller.ListImportReviews).Methods(http.MethodGet)
+	r.HandleFunc("/appointments/import/reviews/{uuid}/resolve", h.controller.ResolveImportReview).Methods(http.MethodPost)
+	r.HandleFunc("/appointments/import/reviews/{uuid}/dismiss", h.controller.DismissImportReview).Methods(http.MethodPost)
+	r.HandleFunc("/appointments/{uuid}", h.controller.Update).Methods(http.MethodPatch)
+	r.HandleFunc("/appointments/{uuid}/cancel", h.controller.Cancel).Methods(http.MethodPost)
+	r.HandleFunc("/appointments/{uuid}/outcome", h.controller.RecordOutcome).Methods(http.MethodPost)
+
+	// Waiting list and offers of freed slots.
+	r.HandleFunc("/waiting-list", h.controller.ListWaitlist).Methods(http.MethodGet)
+	r.HandleFunc("/waiting-list", h.controller.CreateWaitlistEntry).Methods(http.MethodPost)
+	r.HandleFunc("/waiting-list/offers", h.controller.ListWaitlistOffers).Methods(http.MethodGet)
+	r.HandleFunc("/waiting-list/offers/{uuid}/accept", h.controller.AcceptWaitlistOffer).Methods(http.MethodPost)
+	r.HandleFunc("/waiting-list/offers/{uuid}/decline", h.controller.DeclineWaitlistOffer).Methods(http.MethodPost)
+	r.HandleFunc("/waiting-list/{uuid}", h.controller.RemoveWaitlistEntry).Methods(http.MethodDelete)
+
+	// Bookable rooms and equipment.
+	r.HandleFunc("/resources", h.controller.ListResources).Methods(http.MethodGet)
+	r.HandleFunc("/resources", h.controller.CreateResource).Methods(http.MethodPost)
+	r.HandleFunc("/resources/schedule", h.controller.ResourceSchedules).Methods(http.MethodGet)
+	r.HandleFunc("/resources/{uuid}", h.controller.UpdateResource).Methods(http.MethodPatch)
+
+	// Secret calendar feed management.
+	r.HandleFunc("/calendar/feed", h.controller.GetCalendarFeed).Methods(http.MethodGet)
+	r.HandleFunc("/calendar/feed", h.controller.RotateCalendarFeed).Methods(http.MethodPost)
+	r.HandleFunc("/calendar/feed", h.controller.RevokeCalendarFeed).Methods(http.MethodDelete)
+}
+
+// RegisterPublicRoutes exposes the feed itself; the secret token in the URL is the only credential.
+func (h *Handler) RegisterPublicRoutes(r *mux.Router) {
+	r.HandleFunc("/calendar/{token}.ics", h.controller.CalendarFeed).Methods(http.MethodGet)
+}
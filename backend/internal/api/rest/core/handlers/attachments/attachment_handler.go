@@ -0,0 +1,27 @@
+package attachments
+
+import (
+	"net/http"
+
+	ctrl "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/attachments"
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	controller *ctrl.Controller
+}
+
+func NewHandler(controller *ctrl.Controller) *Handler {
+	return &Handler{controller: controller}
+}
+
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	// All routes are protected (doctor auth) via parent router middleware. Files are never served
+	// from /static; the download route checks that the patient belongs to the doctor.
+	r.HandleFunc("/patients/{patient_uuid}/attachments", h.controller.List).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/attachments", h.controller.Upload).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/attachments/{uuid}", h.controller.Delete).Methods(http.MethodDelete)
+	r.HandleFunc("/patients/{patient_uuid}/attachments/{uuid}/download", h.controller.Download).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/anamneses/{anamnesis_uuid}/attachments", h.controller.List).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/anamneses/{anamnesis_uuid}/attachments", h.controller.Upload).Methods(http.MethodPost)
+}
@@ -0,0 +1,29 @@
+package consents
+
+import (
+	"net/http"
+
+	ctrl "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/consents"
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	controller *ctrl.Controller
+}
+
+func NewHandler(controller *ctrl.Controller) *Handler {
+	return &Handler{controller: controller}
+}
+
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	// All routes are protected (doctor auth) via parent router middleware.
+	r.HandleFunc("/consent-templates", h.controller.ListTemplates).Methods(http.MethodGet)
+	r.HandleFunc("/consent-templates", h.controller.CreateTemplate).Methods(http.MethodPost)
+	r.HandleFunc("/consent-templates/{uuid}", h.controller.GetTemplate).Methods(http.MethodGet)
+	r.HandleFunc("/consent-templates/{uuid}", h.controller.UpdateTemplate).Methods(http.MethodPatch)
+	r.HandleFunc("/consent-templates/{uuid}", h.controller.DeleteTemplate).Methods(http.MethodDelete)
+	r.HandleFunc("/patients/{patient_uuid}/consents", h.controller.List).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/consents", h.controller.Sign).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/consents/{uuid}/revoke", h.controller.Revoke).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/consents/{uuid}/pdf", h.controller.PDF).Methods(http.MethodGet)
+}
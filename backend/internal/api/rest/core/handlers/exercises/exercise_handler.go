@@ -0,0 +1,31 @@
+package exercises
+
+import (
+	"net/http"
+
+	ctrl "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/exercises"
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	controller *ctrl.Controller
+}
+
+func NewHandler(controller *ctrl.Controller) *Handler {
+	return &Handler{controller: controller}
+}
+
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	// All routes are protected (doctor auth) via parent router middleware.
+	r.HandleFunc("/exercises", h.controller.ListExercises).Methods(http.MethodGet)
+	r.HandleFunc("/exercises", h.controller.CreateExercise).Methods(http.MethodPost)
+	r.HandleFunc("/exercises/{uuid}", h.controller.GetExercise).Methods(http.MethodGet)
+	r.HandleFunc("/exercises/{uuid}", h.controller.UpdateExercise).Methods(http.MethodPatch)
+	r.HandleFunc("/exercises/{uuid}", h.controller.DeleteExercise).Methods(http.MethodDelete)
+	r.HandleFunc("/patients/{patient_uuid}/exercise-programs", h.controller.ListPrograms).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/exercise-programs", h.controller.CreateProgram).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/exercise-programs/{uuid}", h.controller.GetProgram).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/exercise-programs/{uuid}", h.controller.UpdateProgram).Methods(http.MethodPatch)
+	r.HandleFunc("/patients/{patient_uuid}/exercise-programs/{uuid}", h.controller.DeleteProgram).Methods(http.MethodDelete)
+	r.HandleFunc("/patients/{patient_uuid}/exercise-programs/{uuid}/pdf", h.controller.ProgramPDF).Methods(http.MethodGet)
+}
@@ -2,28 +2,71 @@ package handlers
 
 import (
 	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/anamneses"
+	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/anamnesistemplates"
+	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/appointments"
+	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/attachments"
 	backuphandlers "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/backup"
+	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/consents"
 	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/doctorprofiles"
 	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/doctors"
+	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/exercises"
 	uploadhandler "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/files"
+	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/icd10"
 	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/patients"
+	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/questionnaires"
+	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/reminders"
+	"github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/handlers/treatmentplans"
 	canamneses "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/anamneses"
+	canamnesistemplates "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/anamnesistemplates"
+	cappointments "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/appointments"
+	cattachments "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/attachments"
 	cbackup "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/backup"
+	cconsents "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/consents"
 	cdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/doctorprofiles"
 	cdoctors "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/doctors"
+	cexercises "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/exercises"
+	cicd10 "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/icd10"
 	cpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/patients"
+	cquestionnaires "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/questionnaires"
+	creminders "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/reminders"
+	ctreatmentplans "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/treatmentplans"
+	outicd10 "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/icd10"
+	outreminders "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/reminders"
 	dbanamneses "github.com/OPetricevic/physio-tracker/backend/internal/database/anamneses"
+	dbanamnesistemplates "github.com/OPetricevic/physio-tracker/backend/internal/database/anamnesistemplates"
+	dbappointments "github.com/OPetricevic/physio-tracker/backend/internal/database/appointments"
+	dbattachments "github.com/OPetricevic/physio-tracker/backend/internal/database/attachments"
+	dbconsents "github.com/OPetricevic/physio-tracker/backend/internal/database/consents"
+	dbdischargesummaries "github.com/OPetricevic/physio-tracker/backend/internal/database/dischargesummaries"
 	dbdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/database/doctorprofiles"
 	dbdoctors "github.com/OPetricevic/physio-tracker/backend/internal/database/doctors"
+	dbexercises "github.com/OPetricevic/physio-tracker/backend/internal/database/exercises"
 	dbpatients "github.com/OPetricevic/physio-tracker/backend/internal/database/patients"
+	dbquestionnaires "github.com/OPetricevic/physio-tracker/backend/internal/database/questionnaires"
+	dbreminders "github.com/OPetricevic/physio-tracker/backend/internal/database/reminders"
+	dbtreatmentplans "github.com/OPetricevic/physio-tracker/backend/internal/database/treatmentplans"
+	"github.com/OPetricevic/physio-tracker/backend/internal/filestore"
+	icd10catalogue "github.com/OPetricevic/physio-tracker/backend/internal/icd10"
+	"github.com/OPetricevic/physio-tracker/backend/internal/notify"
 	svcanamneses "github.com/OPetricevic/physio-tracker/backend/internal/services/anamneses"
+	svcanamnesistemplates "github.com/OPetricevic/physio-tracker/backend/internal/services/anamnesistemplates"
+	svcappointments "github.com/OPetricevic/physio-tracker/backend/internal/services/appointments"
+	svcattachments "github.com/OPetricevic/physio-tracker/backend/internal/services/attachments"
 	svcbackup "github.com/OPetricevic/physio-tracker/backend/internal/services/backup"
+	svcconsents "github.com/OPetricevic/physio-tracker/backend/internal/services/consents"
 	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
 	svcdoctors "github.com/OPetricevic/physio-tracker/backend/internal/services/doctors"
+	svcexercises "github.com/OPetricevic/physio-tracker/backend/internal/services/exercises"
+	svcicd10 "github.com/OPetricevic/physio-tracker/backend/internal/services/icd10"
 	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	svcquestionnaires "github.com/OPetricevic/physio-tracker/backend/internal/services/questionnaires"
+	svcreminders "github.com/OPetricevic/physio-tracker/backend/internal/services/reminders"
+	svctreatmentplans "github.com/OPetricevic/physio-tracker/backend/internal/services/treatmentplans"
 	"github.com/gorilla/mux"
 	"gorm.io/gorm"
+	"log"
 	"os"
+	"sync"
 )
 
 // Module registers HTTP routes for a feature area.
@@ -31,6 +74,12 @@ type Module interface {
 	Register(r *mux.Router)
 }
 
+// PublicModule is implemented by modules that also expose routes without Bearer auth
+// (e.g. token-protected calendar feeds). They are registered before the protected routes.
+type PublicModule interface {
+	RegisterPublic(r *mux.Router)
+}
+
 // moduleBuilders is the registry: add new modules here to expose new APIs.
 var moduleBuilders = []func(*gorm.DB) Module{
 	NewPatientModule,
@@ -39,6 +88,15 @@ var moduleBuilders = []func(*gorm.DB) Module{
 	NewDoctorProfileModule,
 	NewUploadModule,
 	NewBackupModule,
+	NewAppointmentModule,
+	NewReminderModule,
+	NewQuestionnaireModule,
+	NewTreatmentPlanModule,
+	NewExerciseModule,
+	NewAnamnesisTemplateModule,
+	NewAttachmentModule,
+	NewICD10Module,
+	NewConsentModule,
 }
 
 // Patient module wiring (repo -> service -> controller -> handler).
@@ -48,9 +106,10 @@ type patientModule struct {
 
 func NewPatientModule(db *gorm.DB) Module {
 	repo := dbpatients.NewPatientsRepository(db)
-	svc := svcpatients.NewService(repo)
+	svc := svcpatients.NewService(repo, NewAnamnesisService(db), dbattachments.NewRepository(db), attachmentStore())
 	ctrl := cpatients.NewController(svc)
-	return &patientModule{handler: patients.NewHandler(ctrl)}
+	series := cappointments.NewController(NewAppointmentService(db))
+	return &patientModule{handler: patients.NewHandler(ctrl, series)}
 }
 
 func (m *patientModule) Register(r *mux.Router) {
@@ -64,7 +123,7 @@ type doctorModule struct {
 
 func NewDoctorModule(db *gorm.DB) Module {
 	repo := dbdoctors.NewDoctorsRepository(db)
-	svc := svcdoctors.NewService(repo)
+	svc := svcdoctors.NewService(repo, dbattachments.NewRepository(db), attachmentStore())
 	ctrl := cdoctors.NewController(svc)
 	return &doctorModule{handler: doctors.NewHandler(ctrl)}
 }
@@ -79,13 +138,20 @@ type anamnesisModule struct {
 }
 
 func NewAnamnesisModule(db *gorm.DB) Module {
+	ctrl := canamneses.NewController(NewAnamnesisService(db))
+	return &anamnesisModule{handler: anamneses.NewHandler(ctrl)}
+}
+
+// NewAnamnesisService is shared by the anamnesis module and the anamnesis lock worker.
+func NewAnamnesisService(db *gorm.DB) svcanamneses.Service {
 	repo := dbanamneses.NewRepository(db)
 	pRepo := dbpatients.NewPatientsRepository(db)
 	profRepo := dbdoctorprofiles.NewRepository(db)
 	dRepo := dbdoctors.NewDoctorsRepository(db)
-	svc := svcanamneses.NewService(repo, pRepo, profRepo, dRepo)
-	ctrl := canamneses.NewController(svc)
-	return &anamnesisModule{handler: anamneses.NewHandler(ctrl)}
+	qRepo := dbquestionnaires.NewRepository(db)
+	planRepo := dbtreatmentplans.NewRepository(db)
+	templRepo := dbanamnesistemplates.NewRepository(db)
+	return svcanamneses.NewService(repo, pRepo, profRepo, dRepo, qRepo, planRepo, templRepo, ICD10Catalogue(), dbdischargesummaries.NewRepository(db), dbconsents.NewRepository(db))
 }
 
 func (m *anamnesisModule) Register(r *mux.Router) {
@@ -136,3 +202,209 @@ func NewBackupModule(_ *gorm.DB) Module {
 func (m *backupModule) Register(r *mux.Router) {
 	m.handler.RegisterRoutes(r)
 }
+
+// Appointment module wiring.
+type appointmentModule struct {
+	handler *appointments.Handler
+}
+
+func NewAppointmentModule(db *gorm.DB) Module {
+	ctrl := cappointments.NewController(NewAppointmentService(db))
+	return &appointmentModule{handler: appointments.NewHandler(ctrl)}
+}
+
+// NewAppointmentService is shared by the appointment and patient modules (series routes are
+// patient-scoped) and by the background workers started in main.
+func NewAppointmentService(db *gorm.DB) svcappointments.Service {
+	repo := dbappointments.NewRepository(db)
+	seriesRepo := dbappointments.NewSeriesRepository(db)
+	pRepo := dbpatients.NewPatientsRepository(db)
+	aRepo := dbanamneses.NewRepository(db)
+	profRepo := dbdoctorprofiles.NewRepository(db)
+	feedRepo := dbappointments.NewFeedRepository(db)
+	importRepo := dbappointments.NewImportRepository(db)
+	waitlistRepo := dbappointments.NewWaitlistRepository(db)
+	resourceRepo := dbappointments.NewResourceRepository(db)
+	return svcappointments.NewService(repo, seriesRepo, pRepo, aRepo, profRepo, feedRepo, importRepo, waitlistRepo, resourceRepo)
+}
+
+func (m *appointmentModule) Register(r *mux.Router) {
+	m.handler.RegisterRoutes(r)
+}
+
+func (m *appointmentModule) RegisterPublic(r *mux.Router) {
+	m.handler.RegisterPublicRoutes(r)
+}
+
+// Reminder module wiring.
+type reminderModule struct {
+	handler *reminders.Handler
+}
+
+func NewReminderModule(db *gorm.DB) Module {
+	ctrl := creminders.NewController(NewReminderService(db))
+	return &reminderModule{handler: reminders.NewHandler(ctrl)}
+}
+
+// NewReminderService is shared by the reminder module and the reminder worker started in main.
+// E-mail and SMS are enabled by their SMTP_* and SMS_* environment variables.
+func NewReminderService(db *gorm.DB) svcreminders.Service {
+	notifiers := map[string]outreminders.Notifier{}
+	if cfg, ok := notify.SMTPConfigFromEnv(); ok {
+		notifiers[svcreminders.ChannelEmail] = notify.NewSMTP(cfg)
+	}
+	if cfg, ok := notify.SMSConfigFromEnv(); ok {
+		notifiers[svcreminders.ChannelSMS] = notify.NewSMSGateway(cfg)
+	}
+	return svcreminders.NewService(
+		dbreminders.NewRepository(db),
+		NewAppointmentService(db),
+		dbappointments.NewRepository(db),
+		dbpatients.NewPatientsRepository(db),
+		dbdoctors.NewDoctorsRepository(db),
+		dbdoctorprofiles.NewRepository(db),
+		notifiers)
+}
+
+func (m *reminderModule) Register(r *mux.Router) {
+	m.handler.RegisterRoutes(r)
+}
+
+// Questionnaire module wiring.
+type questionnaireModule struct {
+	handler *questionnaires.Handler
+}
+
+func NewQuestionnaireModule(db *gorm.DB) Module {
+	svc := svcquestionnaires.NewService(
+		dbquestionnaires.NewRepository(db),
+		dbpatients.NewPatientsRepository(db),
+		dbanamneses.NewRepository(db))
+	ctrl := cquestionnaires.NewController(svc)
+	return &questionnaireModule{handler: questionnaires.NewHandler(ctrl)}
+}
+
+func (m *questionnaireModule) Register(r *mux.Router) {
+	m.handler.RegisterRoutes(r)
+}
+
+// Treatment plan module wiring.
+type treatmentPlanModule struct {
+	handler *treatmentplans.Handler
+}
+
+func NewTreatmentPlanModule(db *gorm.DB) Module {
+	svc := svctreatmentplans.NewService(dbtreatmentplans.NewRepository(db), dbpatients.NewPatientsRepository(db))
+	ctrl := ctreatmentplans.NewController(svc)
+	return &treatmentPlanModule{handler: treatmentplans.NewHandler(ctrl)}
+}
+
+func (m *treatmentPlanModule) Register(r *mux.Router) {
+	m.handler.RegisterRoutes(r)
+}
+
+// Exercise library and home exercise program module wiring.
+type exerciseModule struct {
+	handler *exercises.Handler
+}
+
+func NewExerciseModule(db *gorm.DB) Module {
+	svc := svcexercises.NewService(
+		dbexercises.NewRepository(db),
+		dbpatients.NewPatientsRepository(db),
+		dbdoctorprofiles.NewRepository(db),
+		dbdoctors.NewDoctorsRepository(db))
+	ctrl := cexercises.NewController(svc)
+	return &exerciseModule{handler: exercises.NewHandler(ctrl)}
+}
+
+func (m *exerciseModule) Register(r *mux.Router) {
+	m.handler.RegisterRoutes(r)
+}
+
+// Anamnesis template module wiring.
+type anamnesisTemplateModule struct {
+	handler *anamnesistemplates.Handler
+}
+
+func NewAnamnesisTemplateModule(db *gorm.DB) Module {
+	svc := svcanamnesistemplates.NewService(dbanamnesistemplates.NewRepository(db))
+	ctrl := canamnesistemplates.NewController(svc)
+	return &anamnesisTemplateModule{handler: anamnesistemplates.NewHandler(ctrl)}
+}
+
+func (m *anamnesisTemplateModule) Register(r *mux.Router) {
+	m.handler.RegisterRoutes(r)
+}
+
+// Attachment module wiring. Files are kept in ATTACHMENTS_DIR, outside the /static uploads folder.
+type attachmentModule struct {
+	handler *attachments.Handler
+}
+
+func NewAttachmentModule(db *gorm.DB) Module {
+	store := attachmentStore()
+	svc := svcattachments.NewService(
+		dbattachments.NewRepository(db),
+		store,
+		dbpatients.NewPatientsRepository(db),
+		dbanamneses.NewRepository(db))
+	ctrl := cattachments.NewController(svc, store.MaxSize())
+	return &attachmentModule{handler: attachments.NewHandler(ctrl)}
+}
+
+func (m *attachmentModule) Register(r *mux.Router) {
+	m.handler.RegisterRoutes(r)
+}
+
+// attachmentStore is shared with the patient and doctor modules, which remove the files of the
+// attachments deleted with a patient or doctor.
+func attachmentStore() *filestore.Local {
+	return filestore.NewLocal(filestore.DirFromEnv(), filestore.MaxSizeFromEnv())
+}
+
+// ICD-10 code search module wiring.
+type icd10Module struct {
+	handler *icd10.Handler
+}
+
+func NewICD10Module(_ *gorm.DB) Module {
+	ctrl := cicd10.NewController(svcicd10.NewService(ICD10Catalogue()))
+	return &icd10Module{handler: icd10.NewHandler(ctrl)}
+}
+
+func (m *icd10Module) Register(r *mux.Router) {
+	m.handler.RegisterRoutes(r)
+}
+
+// Consent template and signed consent module wiring.
+type consentModule struct {
+	handler *consents.Handler
+}
+
+func NewConsentModule(db *gorm.DB) Module {
+	svc := svcconsents.NewService(
+		dbconsents.NewRepository(db),
+		dbpatients.NewPatientsRepository(db),
+		dbdoctorprofiles.NewRepository(db),
+		dbdoctors.NewDoctorsRepository(db))
+	ctrl := cconsents.NewController(svc)
+	return &consentModule{handler: consents.NewHandler(ctrl)}
+}
+
+func (m *consentModule) Register(r *mux.Router) {
+	m.handler.RegisterRoutes(r)
+}
+
+// ICD10Catalogue loads the code list from ICD10_FILE once; it is shared by the search module and
+// the anamnesis service. Without the file the server still starts, but no code can be searched or set.
+var ICD10Catalogue = sync.OnceValue(func() outicd10.Catalogue {
+	path := icd10catalogue.PathFromEnv()
+	c, err := icd10catalogue.Load(path)
+	if err != nil {
+		log.Printf("icd-10 catalogue unavailable: %v", err)
+		return &icd10catalogue.Catalogue{}
+	}
+	log.Printf("icd-10 catalogue: %d codes from %s", c.Len(), path)
+	return c
+})
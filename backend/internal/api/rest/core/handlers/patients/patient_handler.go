@@ -3,6 +3,7 @@ package patients
 import (
 	"net/http"
 
+	cappointments "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/appointments"
 	ctrl "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/patients"
 	"github.com/gorilla/mux"
 )
@@ -10,10 +11,11 @@ import (
 // PatientHandler wires routes to patient controller HTTP methods.
 type PatientHandler struct {
 	controller *ctrl.PatientController
+	series     *cappointments.Controller
 }
 
-func NewHandler(controller *ctrl.PatientController) *PatientHandler {
-	return &PatientHandler{controller: controller}
+func NewHandler(controller *ctrl.PatientController, series *cappointments.Controller) *PatientHandler {
+	return &PatientHandler{controller: controller, series: series}
 }
 
 func (h *PatientHandler) RegisterRoutes(r *mux.Router) {
@@ -21,4 +23,12 @@ func (h *PatientHandler) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/patients", h.controller.ListPatients).Methods(http.MethodGet)
 	r.HandleFunc("/patients/{uuid}", h.controller.UpdatePatient).Methods(http.MethodPatch)
 	r.HandleFunc("/patients/{uuid}", h.controller.DeletePatient).Methods(http.MethodDelete)
+
+	// Recurring appointment series of a patient.
+	r.HandleFunc("/patients/{patient_uuid}/appointment-series", h.series.ListSeries).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/appointment-series", h.series.CreateSeries).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/appointment-series/{uuid}/cancel", h.series.CancelSeries).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/appointment-series/{uuid}/occurrences", h.series.UpdateOccurrence).Methods(http.MethodPatch)
+	r.HandleFunc("/patients/{patient_uuid}/appointment-series/{uuid}/occurrences/cancel", h.series.CancelOccurrence).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/appointment-series/{uuid}/occurrences/outcome", h.series.RecordOccurrenceOutcome).Methods(http.MethodPost)
 }
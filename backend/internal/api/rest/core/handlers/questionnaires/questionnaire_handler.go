@@ -0,0 +1,24 @@
+package questionnaires
+
+import (
+	"net/http"
+
+	ctrl "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/questionnaires"
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	controller *ctrl.Controller
+}
+
+func NewHandler(controller *ctrl.Controller) *Handler {
+	return &Handler{controller: controller}
+}
+
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	// All routes are protected (doctor auth) via parent router middleware.
+	r.HandleFunc("/questionnaires", h.controller.Definitions).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/questionnaires", h.controller.List).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/questionnaires", h.controller.Create).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/questionnaires/{uuid}", h.controller.Delete).Methods(http.MethodDelete)
+}
@@ -0,0 +1,26 @@
+package reminders
+
+import (
+	"net/http"
+
+	ctrl "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/reminders"
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	controller *ctrl.Controller
+}
+
+func NewHandler(controller *ctrl.Controller) *Handler {
+	return &Handler{controller: controller}
+}
+
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	// All routes are protected (doctor auth) via parent router middleware.
+	r.HandleFunc("/reminders", h.controller.List).Methods(http.MethodGet)
+	r.HandleFunc("/reminders/settings", h.controller.GetSettings).Methods(http.MethodGet)
+	r.HandleFunc("/reminders/settings", h.controller.UpdateSettings).Methods(http.MethodPut)
+	r.HandleFunc("/reminders/{uuid}/retry", h.controller.Retry).Methods(http.MethodPost)
+	r.HandleFunc("/appointments/{uuid}/reminders", h.controller.ListForAppointment).Methods(http.MethodGet)
+	r.HandleFunc("/appointment-series/{uuid}/occurrences/reminders", h.controller.ListForOccurrence).Methods(http.MethodGet)
+}
@@ -46,13 +46,22 @@ func BuildRouter(db *gorm.DB) *mux.Router {
 	staticDir := http.Dir("uploads")
 	r.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(staticDir)))
 
+	modules := make([]Module, 0, len(moduleBuilders))
+	for _, build := range moduleBuilders {
+		m := build(db)
+		if pm, ok := m.(PublicModule); ok {
+			pm.RegisterPublic(api)
+		}
+		modules = append(modules, m)
+	}
+
 	// Protected routes: everything else goes under a subrouter with auth middleware.
 	protected := api.PathPrefix("/").Subrouter()
 	protected.Use(mwauth.AuthMiddleware(tokenRepo))
 	protected.HandleFunc("/auth/change-password", authController.ChangePassword).Methods(http.MethodPost)
 
-	for _, build := range moduleBuilders {
-		build(db).Register(protected)
+	for _, m := range modules {
+		m.Register(protected)
 	}
 
 	// Serve frontend build (if present)
@@ -0,0 +1,25 @@
+package treatmentplans
+
+import (
+	"net/http"
+
+	ctrl "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/inbound/treatmentplans"
+	"github.com/gorilla/mux"
+)
+
+type Handler struct {
+	controller *ctrl.Controller
+}
+
+func NewHandler(controller *ctrl.Controller) *Handler {
+	return &Handler{controller: controller}
+}
+
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	// All routes are protected (doctor auth) via parent router middleware.
+	r.HandleFunc("/patients/{patient_uuid}/treatment-plans", h.controller.List).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/treatment-plans", h.controller.Create).Methods(http.MethodPost)
+	r.HandleFunc("/patients/{patient_uuid}/treatment-plans/{uuid}", h.controller.Get).Methods(http.MethodGet)
+	r.HandleFunc("/patients/{patient_uuid}/treatment-plans/{uuid}", h.controller.Update).Methods(http.MethodPatch)
+	r.HandleFunc("/patients/{patient_uuid}/treatment-plans/{uuid}", h.controller.Delete).Methods(http.MethodDelete)
+}
@@ -49,6 +49,9 @@ func (c *Controller) Create(w http.ResponseWriter, r *http.Request) {
 	if patientUUID, ok := vars["patient_uuid"]; ok && patientUUID != "" {
 		req.PatientUuid = patientUUID
 	}
+	if template := r.URL.Query().Get("template"); template != "" {
+		req.TemplateUuid = template
+	}
 	if err := common.ValidateProto(&req); err != nil {
 		common.WriteJSONError(w, "invalid_request", "create anamneza: "+err.Error(), http.StatusBadRequest)
 		return
@@ -161,7 +164,7 @@ func (c *Controller) GeneratePDF(w http.ResponseWriter, r *http.Request) {
 
 	onlyCurrent := r.URL.Query().Get("only_current") == "true"
 
-	bytes, err := c.svc.GeneratePDF(r.Context(), doctorUUID, patientUUID, anamnesisUUID, req.IncludeVisitUuids, onlyCurrent)
+	bytes, err := c.svc.GeneratePDF(r.Context(), doctorUUID, patientUUID, anamnesisUUID, req.IncludeVisitUuids, onlyCurrent, req.GetIncludeCharts())
 	if err != nil {
 		// Log full error for debugging 500s.
 		log.Printf("generate pdf failed: %v", err)
@@ -182,6 +185,294 @@ func (c *Controller) GeneratePDF(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(bytes)
 }
 
+// BodyChartRegions lists the named body chart areas per view for the pain map.
+func (c *Controller) BodyChartRegions(w http.ResponseWriter, r *http.Request) {
+	common.WriteProto(w, &pb.ListBodyChartRegionsResponse{Regions: c.svc.BodyChartRegions()}, http.StatusOK)
+}
+
+// Progress returns the patient's measurements and questionnaire scores as time series.
+func (c *Controller) Progress(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	resp, err := c.svc.Progress(r.Context(), doctorUUID, mux.Vars(r)["patient_uuid"])
+	if err != nil {
+		switch {
+		case isSvcErr(err, se.ErrInvalidRequest):
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+		case isSvcErr(err, se.ErrNotFound):
+			common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+		default:
+			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteProto(w, resp, http.StatusOK)
+}
+
+// Revisions lists the edit history of a note.
+func (c *Controller) Revisions(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	list, err := c.svc.Revisions(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"])
+	if err != nil {
+		switch {
+		case isSvcErr(err, se.ErrInvalidRequest):
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+		case isSvcErr(err, se.ErrNotFound):
+			common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+		default:
+			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteProto(w, &pb.ListAnamnesisRevisionsResponse{Revisions: list}, http.StatusOK)
+}
+
+// RevisionDiff compares two revisions of a note (?from=&to=, by default the last change).
+func (c *Controller) RevisionDiff(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	q := r.URL.Query()
+	from := parsePositiveInt(q.Get("from"), 0)
+	to := parsePositiveInt(q.Get("to"), 0)
+	resp, err := c.svc.RevisionDiff(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"], int32(from), int32(to))
+	if err != nil {
+		switch {
+		case isSvcErr(err, se.ErrInvalidRequest):
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+		case isSvcErr(err, se.ErrNotFound):
+			common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+		default:
+			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteProto(w, resp, http.StatusOK)
+}
+
+// Sign signs and locks a note; afterwards it is corrected only through addenda.
+func (c *Controller) Sign(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	anm, err := c.svc.Sign(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"])
+	if err != nil {
+		switch {
+		case isSvcErr(err, se.ErrInvalidRequest):
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+		case isSvcErr(err, se.ErrNotFound):
+			common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+		case isSvcErr(err, se.ErrConflict):
+			common.WriteJSONError(w, "conflict", err.Error(), http.StatusConflict)
+		default:
+			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteProto(w, anm, http.StatusOK)
+}
+
+// AddAddendum appends a timestamped correction to a locked note.
+func (c *Controller) AddAddendum(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateAnamnesisAddendumRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "add addendum: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	req.PatientUuid = vars["patient_uuid"]
+	req.AnamnesisUuid = vars["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "add addendum: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	ad, err := c.svc.AddAddendum(r.Context(), doctorUUID, &req)
+	if err != nil {
+		switch {
+		case isSvcErr(err, se.ErrInvalidRequest):
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+		case isSvcErr(err, se.ErrNotFound):
+			common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+		default:
+			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteProto(w, &pb.AnamnesisAddendumResponse{Addendum: ad}, http.StatusCreated)
+}
+
+// CreateDischargeSummary generates and stores the final report of a treatment episode.
+func (c *Controller) CreateDischargeSummary(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateDischargeSummaryRequest
+	body, _ := io.ReadAll(r.Body)
+	if len(body) > 0 {
+		if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+			common.WriteJSONError(w, "invalid_request", "create discharge summary: invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+	req.PatientUuid = mux.Vars(r)["patient_uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create discharge summary: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	summary, err := c.svc.CreateDischargeSummary(r.Context(), doctorUUID, &req)
+	if err != nil {
+		switch {
+		case isSvcErr(err, se.ErrInvalidRequest):
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+		case isSvcErr(err, se.ErrNotFound):
+			common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+		default:
+			log.Printf("create discharge summary failed: %v", err)
+			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteProto(w, &pb.DischargeSummaryResponse{Summary: summary}, http.StatusCreated)
+}
+
+func (c *Controller) ListDischargeSummaries(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.ListDischargeSummaries(r.Context(), doctorUUID, mux.Vars(r)["patient_uuid"])
+	if err != nil {
+		switch {
+		case isSvcErr(err, se.ErrInvalidRequest):
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+		case isSvcErr(err, se.ErrNotFound):
+			common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+		default:
+			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteProto(w, &pb.ListDischargeSummariesResponse{Summaries: list}, http.StatusOK)
+}
+
+// DischargeSummaryPDF returns the stored PDF byte for byte.
+func (c *Controller) DischargeSummaryPDF(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	bytes, err := c.svc.DischargeSummaryPDF(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"])
+	if err != nil {
+		switch {
+		case isSvcErr(err, se.ErrInvalidRequest):
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+		case isSvcErr(err, se.ErrNotFound):
+			common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+		default:
+			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"discharge-summary.pdf\"")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(bytes)
+}
+
+// RedFlagChecklist returns the red-flag questions for the visit form.
+func (c *Controller) RedFlagChecklist(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	resp, err := c.svc.RedFlagChecklist(r.Context(), doctorUUID)
+	if err != nil {
+		common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	common.WriteProto(w, resp, http.StatusOK)
+}
+
+// UpdateRedFlagChecklist replaces the doctor's red-flag questions; an empty list restores the default.
+func (c *Controller) UpdateRedFlagChecklist(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.UpdateRedFlagChecklistRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update red flag checklist: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update red flag checklist: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := c.svc.UpdateRedFlagChecklist(r.Context(), doctorUUID, &req)
+	if err != nil {
+		switch {
+		case isSvcErr(err, se.ErrInvalidRequest):
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+		case isSvcErr(err, se.ErrNotFound):
+			common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+		case isSvcErr(err, se.ErrConflict):
+			common.WriteJSONError(w, "conflict", err.Error(), http.StatusConflict)
+		default:
+			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteProto(w, resp, http.StatusOK)
+}
+
+// OpenRedFlags lists the patients whose visits have positive red flags without an action taken.
+func (c *Controller) OpenRedFlags(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.OpenRedFlags(r.Context(), doctorUUID)
+	if err != nil {
+		switch {
+		case isSvcErr(err, se.ErrInvalidRequest):
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+		default:
+			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	common.WriteProto(w, &pb.ListOpenRedFlagsResponse{Items: list}, http.StatusOK)
+}
+
 func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) {
 	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
 	if !ok {
@@ -196,6 +487,8 @@ func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) {
 			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
 		case isSvcErr(err, se.ErrNotFound):
 			common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+		case isSvcErr(err, se.ErrConflict):
+			common.WriteJSONError(w, "conflict", err.Error(), http.StatusConflict)
 		default:
 			common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
 		}
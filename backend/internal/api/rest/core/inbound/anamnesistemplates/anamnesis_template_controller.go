@@ -0,0 +1,126 @@
+package anamnesistemplates
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svc "github.com/OPetricevic/physio-tracker/backend/internal/services/anamnesistemplates"
+	"github.com/gorilla/mux"
+)
+
+type Controller struct {
+	svc svc.Service
+}
+
+func NewController(s svc.Service) *Controller {
+	return &Controller{svc: s}
+}
+
+func (c *Controller) Create(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateAnamnesisTemplateRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create anamnesis template: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create anamnesis template: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	t, err := c.svc.Create(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.AnamnesisTemplateResponse{Template: t}, http.StatusCreated)
+}
+
+func (c *Controller) Update(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.UpdateAnamnesisTemplateRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update anamnesis template: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Uuid = mux.Vars(r)["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update anamnesis template: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	t, err := c.svc.Update(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.AnamnesisTemplateResponse{Template: t}, http.StatusOK)
+}
+
+func (c *Controller) Get(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	t, err := c.svc.Get(r.Context(), doctorUUID, mux.Vars(r)["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.AnamnesisTemplateResponse{Template: t}, http.StatusOK)
+}
+
+// List returns the doctor's templates; ?condition= narrows them to one condition.
+func (c *Controller) List(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.List(r.Context(), doctorUUID, r.URL.Query().Get("condition"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListAnamnesisTemplatesResponse{Templates: list}, http.StatusOK)
+}
+
+func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := c.svc.Delete(r.Context(), doctorUUID, mux.Vars(r)["uuid"]); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, se.ErrInvalidRequest):
+		common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+	case errors.Is(err, se.ErrNotFound):
+		common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+	case errors.Is(err, se.ErrConflict):
+		common.WriteJSONError(w, "conflict", err.Error(), http.StatusConflict)
+	default:
+		common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+	}
+}
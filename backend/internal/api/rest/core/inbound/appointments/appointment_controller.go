@@ -0,0 +1,237 @@
+package appointments
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svc "github.com/OPetricevic/physio-tracker/backend/internal/services/appointments"
+	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Controller struct {
+	svc svc.Service
+}
+
+func NewController(s svc.Service) *Controller {
+	return &Controller{svc: s}
+}
+
+func (c *Controller) Create(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateAppointmentRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create appointment: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create appointment: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := c.svc.Create(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, resp, http.StatusCreated)
+}
+
+func (c *Controller) Update(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.UpdateAppointmentRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update appointment: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if appointmentUUID := mux.Vars(r)["uuid"]; appointmentUUID != "" {
+		req.Uuid = appointmentUUID
+	}
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update appointment: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := c.svc.Update(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, resp, http.StatusOK)
+}
+
+func (c *Controller) Cancel(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CancelAppointmentRequest
+	body, _ := io.ReadAll(r.Body)
+	if len(body) > 0 {
+		if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+			common.WriteJSONError(w, "invalid_request", "cancel appointment: invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+	req.Uuid = mux.Vars(r)["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "cancel appointment: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	appt, err := c.svc.Cancel(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.AppointmentResponse{Appointment: appt}, http.StatusOK)
+}
+
+// RecordOutcome marks an appointment as attended, no-show or cancelled (by patient, by clinic, late).
+func (c *Controller) RecordOutcome(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.RecordOutcomeRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "record outcome: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Uuid = mux.Vars(r)["uuid"]
+	req.SeriesUuid = ""
+	req.OriginalStartsAt = nil
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "record outcome: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	appt, err := c.svc.RecordOutcome(r.Context(), doctorUUID, "", &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.AppointmentResponse{Appointment: appt}, http.StatusOK)
+}
+
+// List returns appointments overlapping ?from=&to= (RFC3339 or YYYY-MM-DD).
+func (c *Controller) List(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	q := r.URL.Query()
+	from, err := parseTime(q.Get("from"))
+	if err != nil {
+		common.WriteJSONError(w, "invalid_request", "list appointments: invalid from", http.StatusBadRequest)
+		return
+	}
+	to, err := parseTime(q.Get("to"))
+	if err != nil {
+		common.WriteJSONError(w, "invalid_request", "list appointments: invalid to", http.StatusBadRequest)
+		return
+	}
+	req := &pb.ListAppointmentsRequest{
+		From:        timestamppb.New(from),
+		To:          timestamppb.New(to),
+		PatientUuid: q.Get("patient_uuid"),
+	}
+	list, err := c.svc.List(r.Context(), doctorUUID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListAppointmentsResponse{Appointments: list}, http.StatusOK)
+}
+
+// FreeSlots returns bookable slots in ?from=&to= for ?duration= minutes (optional ?step= minutes).
+func (c *Controller) FreeSlots(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	q := r.URL.Query()
+	from, err := parseTime(q.Get("from"))
+	if err != nil {
+		common.WriteJSONError(w, "invalid_request", "free slots: invalid from", http.StatusBadRequest)
+		return
+	}
+	to, err := parseTime(q.Get("to"))
+	if err != nil {
+		common.WriteJSONError(w, "invalid_request", "free slots: invalid to", http.StatusBadRequest)
+		return
+	}
+	duration, err := strconv.Atoi(q.Get("duration"))
+	if err != nil {
+		common.WriteJSONError(w, "invalid_request", "free slots: invalid duration", http.StatusBadRequest)
+		return
+	}
+	step := 0
+	if v := q.Get("step"); v != "" {
+		if step, err = strconv.Atoi(v); err != nil {
+			common.WriteJSONError(w, "invalid_request", "free slots: invalid step", http.StatusBadRequest)
+			return
+		}
+	}
+	req := &pb.ListFreeSlotsRequest{
+		From:            timestamppb.New(from),
+		To:              timestamppb.New(to),
+		DurationMinutes: int32(duration),
+		StepMinutes:     int32(step),
+	}
+	resp, err := c.svc.FreeSlots(r.Context(), doctorUUID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, resp, http.StatusOK)
+}
+
+func parseTime(val string) (time.Time, error) {
+	s := strings.TrimSpace(val)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	var conflictErr *svc.ConflictError
+	if errors.As(err, &conflictErr) {
+		common.WriteProto(w, &pb.AppointmentConflictResponse{
+			Error:     "conflict",
+			Message:   err.Error(),
+			Conflicts: conflictErr.Conflicts,
+		}, http.StatusConflict)
+		return
+	}
+	switch {
+	case errors.Is(err, se.ErrInvalidRequest):
+		common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+	case errors.Is(err, se.ErrNotFound):
+		common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+	case errors.Is(err, se.ErrConflict):
+		common.WriteJSONError(w, "conflict", err.Error(), http.StatusConflict)
+	default:
+		common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,102 @@
+package appointments
+
+import (
+	"net/http"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	"github.com/gorilla/mux"
+)
+
+// ExportICS downloads the doctor's appointments as .ics; ?from=&to= are optional.
+func (c *Controller) ExportICS(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	q := r.URL.Query()
+	var from, to time.Time
+	var err error
+	if v := q.Get("from"); v != "" {
+		if from, err = parseTime(v); err != nil {
+			common.WriteJSONError(w, "invalid_request", "export calendar: invalid from", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if to, err = parseTime(v); err != nil {
+			common.WriteJSONError(w, "invalid_request", "export calendar: invalid to", http.StatusBadRequest)
+			return
+		}
+	}
+	data, err := c.svc.ExportICS(r.Context(), doctorUUID, from, to)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="appointments.ics"`)
+	writeICS(w, data)
+}
+
+// CalendarFeed serves the subscribable feed identified by the secret token (no Bearer auth).
+func (c *Controller) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	data, err := c.svc.FeedICS(r.Context(), mux.Vars(r)["token"])
+	if err != nil {
+		// Do not tell apart unknown and revoked tokens.
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeICS(w, data)
+}
+
+func (c *Controller) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	feed, err := c.svc.GetCalendarFeed(r.Context(), doctorUUID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.CalendarFeedResponse{Feed: feed}, http.StatusOK)
+}
+
+// RotateCalendarFeed creates the feed or issues a new token; the old URL stops working.
+func (c *Controller) RotateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	feed, err := c.svc.RotateCalendarFeed(r.Context(), doctorUUID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.CalendarFeedResponse{Feed: feed}, http.StatusCreated)
+}
+
+func (c *Controller) RevokeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := c.svc.RevokeCalendarFeed(r.Context(), doctorUUID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeICS(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
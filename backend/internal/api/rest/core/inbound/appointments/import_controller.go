@@ -0,0 +1,100 @@
+package appointments
+
+import (
+	"io"
+	"net/http"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	"github.com/gorilla/mux"
+)
+
+// maxImportSize limits uploaded .ics files; a few years of a busy calendar stay well below it.
+const maxImportSize = 2 << 20
+
+// ImportICS reads an uploaded .ics file (multipart field "file") into appointments.
+func (c *Controller) ImportICS(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize+1<<10)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		common.WriteJSONError(w, "invalid_request", "import appointments: invalid upload", http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		common.WriteJSONError(w, "invalid_request", "import appointments: missing file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxImportSize+1))
+	if err != nil || len(data) > maxImportSize {
+		common.WriteJSONError(w, "invalid_request", "import appointments: file too large", http.StatusBadRequest)
+		return
+	}
+	res, err := c.svc.ImportICS(r.Context(), doctorUUID, data)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, res, http.StatusOK)
+}
+
+// ListImportReviews returns imported events waiting for a patient; ?status= filters (pending, resolved, dismissed).
+func (c *Controller) ListImportReviews(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.ListImportReviews(r.Context(), doctorUUID, r.URL.Query().Get("status"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListImportReviewsResponse{Reviews: list}, http.StatusOK)
+}
+
+// ResolveImportReview books a reviewed event for the patient given in the body.
+func (c *Controller) ResolveImportReview(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.ResolveImportReviewRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "resolve import review: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Uuid = mux.Vars(r)["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "resolve import review: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	rv, err := c.svc.ResolveImportReview(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ImportReviewResponse{Review: rv}, http.StatusOK)
+}
+
+func (c *Controller) DismissImportReview(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	rv, err := c.svc.DismissImportReview(r.Context(), doctorUUID, mux.Vars(r)["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ImportReviewResponse{Review: rv}, http.StatusOK)
+}
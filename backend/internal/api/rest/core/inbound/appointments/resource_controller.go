@@ -0,0 +1,91 @@
+package appointments
+
+import (
+	"io"
+	"net/http"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	"github.com/gorilla/mux"
+)
+
+// ListResources returns the practice's rooms and equipment; ?include_inactive=true adds retired ones.
+func (c *Controller) ListResources(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	includeInactive := r.URL.Query().Get("include_inactive") == "true"
+	list, err := c.svc.ListResources(r.Context(), doctorUUID, includeInactive)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListResourcesResponse{Resources: list}, http.StatusOK)
+}
+
+func (c *Controller) CreateResource(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateResourceRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create resource: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create resource: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	res, err := c.svc.CreateResource(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ResourceResponse{Resource: res}, http.StatusCreated)
+}
+
+func (c *Controller) UpdateResource(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.UpdateResourceRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update resource: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Uuid = mux.Vars(r)["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update resource: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	res, err := c.svc.UpdateResource(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ResourceResponse{Resource: res}, http.StatusOK)
+}
+
+// ResourceSchedules returns each active resource's appointments on ?date=YYYY-MM-DD (default today).
+func (c *Controller) ResourceSchedules(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	resp, err := c.svc.ResourceSchedules(r.Context(), doctorUUID, r.URL.Query().Get("date"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, resp, http.StatusOK)
+}
@@ -0,0 +1,156 @@
+package appointments
+
+import (
+	"io"
+	"net/http"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	"github.com/gorilla/mux"
+)
+
+func (c *Controller) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateAppointmentSeriesRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create appointment series: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if patientUUID := mux.Vars(r)["patient_uuid"]; patientUUID != "" {
+		req.PatientUuid = patientUUID
+	}
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create appointment series: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := c.svc.CreateSeries(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, resp, http.StatusCreated)
+}
+
+func (c *Controller) ListSeries(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.ListSeries(r.Context(), doctorUUID, mux.Vars(r)["patient_uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListAppointmentSeriesResponse{Series: list}, http.StatusOK)
+}
+
+func (c *Controller) CancelSeries(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CancelAppointmentRequest
+	body, _ := io.ReadAll(r.Body)
+	if len(body) > 0 {
+		if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+			common.WriteJSONError(w, "invalid_request", "cancel appointment series: invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+	vars := mux.Vars(r)
+	ser, err := c.svc.CancelSeries(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"], req.GetReason())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.AppointmentSeriesResponse{Series: ser}, http.StatusOK)
+}
+
+// UpdateOccurrence moves/edits a single occurrence or, with scope "following", splits the series.
+func (c *Controller) UpdateOccurrence(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.UpdateOccurrenceRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update occurrence: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	req.SeriesUuid = vars["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update occurrence: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := c.svc.UpdateOccurrence(r.Context(), doctorUUID, vars["patient_uuid"], &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, resp, http.StatusOK)
+}
+
+func (c *Controller) CancelOccurrence(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CancelOccurrenceRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "cancel occurrence: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	req.SeriesUuid = vars["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "cancel occurrence: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := c.svc.CancelOccurrence(r.Context(), doctorUUID, vars["patient_uuid"], &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, resp, http.StatusOK)
+}
+
+// RecordOccurrenceOutcome sets the outcome of one occurrence, identified by original_starts_at.
+func (c *Controller) RecordOccurrenceOutcome(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.RecordOutcomeRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "record occurrence outcome: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	req.Uuid = ""
+	req.SeriesUuid = vars["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "record occurrence outcome: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	appt, err := c.svc.RecordOutcome(r.Context(), doctorUUID, vars["patient_uuid"], &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.AppointmentResponse{Appointment: appt}, http.StatusOK)
+}
@@ -0,0 +1,107 @@
+package appointments
+
+import (
+	"io"
+	"net/http"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	"github.com/gorilla/mux"
+)
+
+// ListWaitlist returns waiting-list entries; ?status= filters (waiting, offered, booked, removed).
+func (c *Controller) ListWaitlist(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.ListWaitlist(r.Context(), doctorUUID, r.URL.Query().Get("status"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListWaitlistResponse{Entries: list}, http.StatusOK)
+}
+
+func (c *Controller) CreateWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateWaitlistEntryRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create waitlist entry: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create waitlist entry: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	entry, err := c.svc.CreateWaitlistEntry(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.WaitlistEntryResponse{Entry: entry}, http.StatusCreated)
+}
+
+func (c *Controller) RemoveWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := c.svc.RemoveWaitlistEntry(r.Context(), doctorUUID, mux.Vars(r)["uuid"]); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWaitlistOffers returns offers of freed slots; ?status= filters (pending, accepted, ...).
+func (c *Controller) ListWaitlistOffers(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.ListWaitlistOffers(r.Context(), doctorUUID, r.URL.Query().Get("status"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListWaitlistOffersResponse{Offers: list}, http.StatusOK)
+}
+
+// AcceptWaitlistOffer books the offered slot for the patient.
+func (c *Controller) AcceptWaitlistOffer(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	resp, err := c.svc.AcceptWaitlistOffer(r.Context(), doctorUUID, mux.Vars(r)["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, resp, http.StatusOK)
+}
+
+func (c *Controller) DeclineWaitlistOffer(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	offer, err := c.svc.DeclineWaitlistOffer(r.Context(), doctorUUID, mux.Vars(r)["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.WaitlistOfferResponse{Offer: offer}, http.StatusOK)
+}
@@ -0,0 +1,150 @@
+package attachments
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svc "github.com/OPetricevic/physio-tracker/backend/internal/services/attachments"
+	"github.com/gorilla/mux"
+)
+
+// formOverhead is allowed on top of the file size for the multipart headers and other fields.
+const formOverhead = 1 << 20
+
+type Controller struct {
+	svc     svc.Service
+	maxSize int64
+}
+
+// NewController takes the largest accepted file in bytes; larger request bodies are cut off.
+func NewController(s svc.Service, maxSize int64) *Controller {
+	return &Controller{svc: s, maxSize: maxSize}
+}
+
+// Upload expects multipart/form-data with the field "file" and an optional "description". On the
+// patient route, "anamnesis_uuid" links the file to a visit; the visit route links it by its path.
+func (c *Controller) Upload(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, c.maxSize+formOverhead)
+	if err := r.ParseMultipartForm(formOverhead); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			common.WriteJSONError(w, "invalid_request", "upload attachment: file over "+strconv.FormatInt(c.maxSize>>20, 10)+" MB", http.StatusRequestEntityTooLarge)
+			return
+		}
+		common.WriteJSONError(w, "invalid_request", "upload attachment: invalid form data", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		common.WriteJSONError(w, "invalid_request", "upload attachment: file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	vars := mux.Vars(r)
+	anamnesisUUID := vars["anamnesis_uuid"]
+	if anamnesisUUID == "" {
+		anamnesisUUID = r.FormValue("anamnesis_uuid")
+	}
+	a, err := c.svc.Upload(r.Context(), doctorUUID, svc.Upload{
+		PatientUUID:   vars["patient_uuid"],
+		AnamnesisUUID: anamnesisUUID,
+		FileName:      header.Filename,
+		Description:   r.FormValue("description"),
+		Content:       file,
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.AttachmentResponse{Attachment: a}, http.StatusCreated)
+}
+
+// List returns the patient's attachments, or only a visit's on the visit route or with ?anamnesis_uuid=.
+func (c *Controller) List(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	anamnesisUUID := vars["anamnesis_uuid"]
+	if anamnesisUUID == "" {
+		anamnesisUUID = r.URL.Query().Get("anamnesis_uuid")
+	}
+	list, err := c.svc.List(r.Context(), doctorUUID, vars["patient_uuid"], anamnesisUUID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListAttachmentsResponse{Attachments: list}, http.StatusOK)
+}
+
+// Download streams the file to the doctor who owns the patient. PDFs and images are shown inline;
+// ?download=1 forces a save dialog.
+func (c *Controller) Download(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	a, content, err := c.svc.Open(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	defer content.Close()
+
+	disposition := "inline"
+	if r.URL.Query().Get("download") == "1" {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Type", a.GetContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.GetFileName()}))
+	w.Header().Set("Content-Length", strconv.FormatInt(a.GetSizeBytes(), 10))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, content)
+}
+
+func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	if err := c.svc.Delete(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"]); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, se.ErrInvalidRequest):
+		common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+	case errors.Is(err, se.ErrNotFound):
+		common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+	case errors.Is(err, se.ErrConflict):
+		common.WriteJSONError(w, "conflict", err.Error(), http.StatusConflict)
+	default:
+		common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,199 @@
+package consents
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svc "github.com/OPetricevic/physio-tracker/backend/internal/services/consents"
+	"github.com/gorilla/mux"
+)
+
+type Controller struct {
+	svc svc.Service
+}
+
+func NewController(s svc.Service) *Controller {
+	return &Controller{svc: s}
+}
+
+func (c *Controller) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateConsentTemplateRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create consent template: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create consent template: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	t, err := c.svc.CreateTemplate(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ConsentTemplateResponse{Template: t}, http.StatusCreated)
+}
+
+func (c *Controller) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.UpdateConsentTemplateRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update consent template: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Uuid = mux.Vars(r)["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update consent template: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	t, err := c.svc.UpdateTemplate(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ConsentTemplateResponse{Template: t}, http.StatusOK)
+}
+
+func (c *Controller) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	t, err := c.svc.GetTemplate(r.Context(), doctorUUID, mux.Vars(r)["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ConsentTemplateResponse{Template: t}, http.StatusOK)
+}
+
+// ListTemplates returns the doctor's consent templates; ?include_inactive=true adds retired ones.
+func (c *Controller) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.ListTemplates(r.Context(), doctorUUID, r.URL.Query().Get("include_inactive") == "true")
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListConsentTemplatesResponse{Templates: list}, http.StatusOK)
+}
+
+func (c *Controller) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := c.svc.DeleteTemplate(r.Context(), doctorUUID, mux.Vars(r)["uuid"]); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Sign stores a consent signed on the tablet; signature_png is the base64 PNG of the canvas.
+func (c *Controller) Sign(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.SignConsentRequest
+	body, _ := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "sign consent: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.PatientUuid = mux.Vars(r)["patient_uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "sign consent: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	consent, err := c.svc.Sign(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ConsentResponse{Consent: consent}, http.StatusCreated)
+}
+
+func (c *Controller) List(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.List(r.Context(), doctorUUID, mux.Vars(r)["patient_uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListConsentsResponse{Consents: list}, http.StatusOK)
+}
+
+func (c *Controller) Revoke(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	consent, err := c.svc.Revoke(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ConsentResponse{Consent: consent}, http.StatusOK)
+}
+
+func (c *Controller) PDF(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	bytes, err := c.svc.PDF(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"consent.pdf\"")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(bytes)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, se.ErrInvalidRequest):
+		common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+	case errors.Is(err, se.ErrNotFound):
+		common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+	case errors.Is(err, se.ErrConflict):
+		common.WriteJSONError(w, "conflict", err.Error(), http.StatusConflict)
+	default:
+		common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -67,7 +67,7 @@ func (c *Controller) UpsertProfile(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch {
 		case errors.Is(err, se.ErrInvalidRequest):
-			common.WriteJSONError(w, "invalid_request", "upsert doctor profile: invalid request", http.StatusBadRequest)
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
 		case errors.Is(err, se.ErrNotFound):
 			common.WriteJSONError(w, "not_found", "upsert doctor profile: not found", http.StatusNotFound)
 		default:
@@ -0,0 +1,127 @@
+package exercises
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svc "github.com/OPetricevic/physio-tracker/backend/internal/services/exercises"
+	"github.com/gorilla/mux"
+)
+
+type Controller struct {
+	svc svc.Service
+}
+
+func NewController(s svc.Service) *Controller {
+	return &Controller{svc: s}
+}
+
+// ListExercises returns the doctor's library by name; ?q= filters by name, ?include_inactive=true adds retired ones.
+func (c *Controller) ListExercises(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	q := r.URL.Query()
+	list, err := c.svc.ListExercises(r.Context(), doctorUUID, q.Get("q"), q.Get("include_inactive") == "true")
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListExercisesResponse{Exercises: list}, http.StatusOK)
+}
+
+func (c *Controller) CreateExercise(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateExerciseRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create exercise: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create exercise: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	e, err := c.svc.CreateExercise(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ExerciseResponse{Exercise: e}, http.StatusCreated)
+}
+
+func (c *Controller) GetExercise(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	e, err := c.svc.GetExercise(r.Context(), doctorUUID, mux.Vars(r)["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ExerciseResponse{Exercise: e}, http.StatusOK)
+}
+
+func (c *Controller) UpdateExercise(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.UpdateExerciseRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update exercise: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.Uuid = mux.Vars(r)["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update exercise: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	e, err := c.svc.UpdateExercise(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ExerciseResponse{Exercise: e}, http.StatusOK)
+}
+
+func (c *Controller) DeleteExercise(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := c.svc.DeleteExercise(r.Context(), doctorUUID, mux.Vars(r)["uuid"]); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, se.ErrInvalidRequest):
+		common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+	case errors.Is(err, se.ErrNotFound):
+		common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+	case errors.Is(err, se.ErrConflict):
+		common.WriteJSONError(w, "conflict", err.Error(), http.StatusConflict)
+	default:
+		common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+	}
+}
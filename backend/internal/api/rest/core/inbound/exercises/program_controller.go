@@ -0,0 +1,127 @@
+package exercises
+
+import (
+	"io"
+	"log"
+	"net/http"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	"github.com/gorilla/mux"
+)
+
+func (c *Controller) ListPrograms(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.ListPrograms(r.Context(), doctorUUID, mux.Vars(r)["patient_uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListExerciseProgramsResponse{Programs: list}, http.StatusOK)
+}
+
+func (c *Controller) CreateProgram(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateExerciseProgramRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create exercise program: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.PatientUuid = mux.Vars(r)["patient_uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create exercise program: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	p, err := c.svc.CreateProgram(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ExerciseProgramResponse{Program: p}, http.StatusCreated)
+}
+
+func (c *Controller) GetProgram(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	p, err := c.svc.GetProgram(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ExerciseProgramResponse{Program: p}, http.StatusOK)
+}
+
+func (c *Controller) UpdateProgram(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.UpdateExerciseProgramRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update exercise program: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	req.PatientUuid = vars["patient_uuid"]
+	req.Uuid = vars["uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update exercise program: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	p, err := c.svc.UpdateProgram(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ExerciseProgramResponse{Program: p}, http.StatusOK)
+}
+
+func (c *Controller) DeleteProgram(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	if err := c.svc.DeleteProgram(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"]); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ProgramPDF returns the printable, patient-facing program with the exercise pictures.
+func (c *Controller) ProgramPDF(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	bytes, err := c.svc.ProgramPDF(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"])
+	if err != nil {
+		log.Printf("generate program pdf failed: %v", err)
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"exercise-program.pdf\"")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(bytes)
+}
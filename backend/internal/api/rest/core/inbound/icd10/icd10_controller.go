@@ -0,0 +1,43 @@
+package icd10
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svc "github.com/OPetricevic/physio-tracker/backend/internal/services/icd10"
+)
+
+type Controller struct {
+	svc svc.Service
+}
+
+func NewController(s svc.Service) *Controller {
+	return &Controller{svc: s}
+}
+
+// Search expects ?q= (a code such as "M54" or words of the description) and an optional ?limit=.
+func (c *Controller) Search(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			common.WriteJSONError(w, "invalid_request", "search icd-10: invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	codes, err := c.svc.Search(r.Context(), r.URL.Query().Get("q"), limit)
+	if err != nil {
+		if errors.Is(err, se.ErrInvalidRequest) {
+			common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+			return
+		}
+		common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	common.WriteProto(w, &pb.SearchDiagnosisCodesResponse{Codes: codes}, http.StatusOK)
+}
@@ -138,7 +138,8 @@ func (c *PatientController) ListPatients(w http.ResponseWriter, r *http.Request)
 	pageSize := parsePositiveInt(q.Get("page_size"), 20)
 	currentPage := parsePositiveInt(q.Get("current_page"), 1)
 	req := &pb.ListPatientsRequest{
-		Query: q.Get("query"),
+		Query:     q.Get("query"),
+		NoShowGte: int32(parsePositiveInt(q.Get("no_show_gte"), 0)),
 	}
 	list, err := c.svc.List(r.Context(), req, doctorUUID, pageSize, currentPage)
 	if err != nil {
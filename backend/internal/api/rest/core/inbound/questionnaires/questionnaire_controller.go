@@ -0,0 +1,94 @@
+package questionnaires
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svc "github.com/OPetricevic/physio-tracker/backend/internal/services/questionnaires"
+	"github.com/gorilla/mux"
+)
+
+type Controller struct {
+	svc svc.Service
+}
+
+func NewController(s svc.Service) *Controller {
+	return &Controller{svc: s}
+}
+
+// Definitions lists the built-in questionnaires with their items for the forms.
+func (c *Controller) Definitions(w http.ResponseWriter, r *http.Request) {
+	common.WriteProto(w, &pb.ListQuestionnaireDefinitionsResponse{Questionnaires: c.svc.Definitions()}, http.StatusOK)
+}
+
+func (c *Controller) Create(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.CreateQuestionnaireResultRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create questionnaire result: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	req.PatientUuid = mux.Vars(r)["patient_uuid"]
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "create questionnaire result: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	res, err := c.svc.Create(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.QuestionnaireResultResponse{Result: res}, http.StatusCreated)
+}
+
+// List returns a patient's results oldest first; ?code= limits them to one questionnaire.
+func (c *Controller) List(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.List(r.Context(), doctorUUID, mux.Vars(r)["patient_uuid"], r.URL.Query().Get("code"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListQuestionnaireResultsResponse{Results: list}, http.StatusOK)
+}
+
+func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	if err := c.svc.Delete(r.Context(), doctorUUID, vars["patient_uuid"], vars["uuid"]); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, se.ErrInvalidRequest):
+		common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+	case errors.Is(err, se.ErrNotFound):
+		common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+	case errors.Is(err, se.ErrConflict):
+		common.WriteJSONError(w, "conflict", err.Error(), http.StatusConflict)
+	default:
+		common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+	}
+}
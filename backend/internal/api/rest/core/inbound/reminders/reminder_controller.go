@@ -0,0 +1,160 @@
+package reminders
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	common "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/common"
+	mwauth "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/middleware"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svc "github.com/OPetricevic/physio-tracker/backend/internal/services/reminders"
+	"github.com/gorilla/mux"
+)
+
+type Controller struct {
+	svc svc.Service
+}
+
+func NewController(s svc.Service) *Controller {
+	return &Controller{svc: s}
+}
+
+func (c *Controller) GetSettings(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	set, err := c.svc.GetSettings(r.Context(), doctorUUID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ReminderSettingsResponse{Settings: set}, http.StatusOK)
+}
+
+func (c *Controller) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var req pb.UpdateReminderSettingsRequest
+	body, _ := io.ReadAll(r.Body)
+	if err := common.JSONPB.Unmarshal(body, &req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update reminder settings: invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := common.ValidateProto(&req); err != nil {
+		common.WriteJSONError(w, "invalid_request", "update reminder settings: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	set, err := c.svc.UpdateSettings(r.Context(), doctorUUID, &req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ReminderSettingsResponse{Settings: set}, http.StatusOK)
+}
+
+// List returns the outbox for appointments starting in ?from=&to=; ?status= filters
+// (pending, sending, sent, failed, cancelled).
+func (c *Controller) List(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	q := r.URL.Query()
+	from, err := parseTime(q.Get("from"))
+	if err != nil {
+		common.WriteJSONError(w, "invalid_request", "list reminders: invalid from", http.StatusBadRequest)
+		return
+	}
+	to, err := parseTime(q.Get("to"))
+	if err != nil {
+		common.WriteJSONError(w, "invalid_request", "list reminders: invalid to", http.StatusBadRequest)
+		return
+	}
+	list, err := c.svc.List(r.Context(), doctorUUID, q.Get("status"), from, to)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListRemindersResponse{Reminders: list}, http.StatusOK)
+}
+
+// ListForAppointment shows the delivery status of an appointment's reminders.
+func (c *Controller) ListForAppointment(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.ListForAppointment(r.Context(), doctorUUID, mux.Vars(r)["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListRemindersResponse{Reminders: list}, http.StatusOK)
+}
+
+// ListForOccurrence shows the reminders of a series occurrence given by ?original_starts_at=,
+// also before the occurrence was edited and got its own uuid.
+func (c *Controller) ListForOccurrence(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	orig, err := time.Parse(time.RFC3339, strings.TrimSpace(r.URL.Query().Get("original_starts_at")))
+	if err != nil {
+		common.WriteJSONError(w, "invalid_request", "list occurrence reminders: invalid original_starts_at", http.StatusBadRequest)
+		return
+	}
+	list, err := c.svc.ListForOccurrence(r.Context(), doctorUUID, mux.Vars(r)["uuid"], orig)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ListRemindersResponse{Reminders: list}, http.StatusOK)
+}
+
+func (c *Controller) Retry(w http.ResponseWriter, r *http.Request) {
+	doctorUUID, ok := mwauth.GetDoctorUUID(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	rem, err := c.svc.Retry(r.Context(), doctorUUID, mux.Vars(r)["uuid"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	common.WriteProto(w, &pb.ReminderResponse{Reminder: rem}, http.StatusOK)
+}
+
+func parseTime(val string) (time.Time, error) {
+	s := strings.TrimSpace(val)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, se.ErrInvalidRequest):
+		common.WriteJSONError(w, "invalid_request", err.Error(), http.StatusBadRequest)
+	case errors.Is(err, se.ErrNotFound):
+		common.WriteJSONError(w, "not_found", err.Error(), http.StatusNotFound)
+	case errors.Is(err, se.ErrConflict):
+		common.WriteJSONError(w, "conflict", err.Error(), http.StatusConflict)
+	default:
+		common.WriteJSONError(w, "internal_error", err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -13,10 +14,19 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		start := time.Now()
 		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
 		next.ServeHTTP(rec, r)
-		log.Printf("%s %s -> %d (%s)", r.Method, r.URL.Path, rec.status, time.Since(start))
+		log.Printf("%s %s -> %d (%s)", r.Method, redactPath(r.URL.Path), rec.status, time.Since(start))
 	})
 }
 
+// redactPath hides secret calendar feed tokens so they do not end up in logs.
+func redactPath(path string) string {
+	const feedPrefix = "/api/calendar/"
+	if strings.HasPrefix(path, feedPrefix) && strings.HasSuffix(path, ".ics") {
+		return feedPrefix + "***.ics"
+	}
+	return path
+}
+
 type statusRecorder struct {
 	http.ResponseWriter
 	status int
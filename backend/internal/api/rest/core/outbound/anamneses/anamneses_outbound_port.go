@@ -2,16 +2,30 @@ package anamneses
 
 import (
 	"context"
+	"time"
 
 	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
 )
 
 // Repository defines outbound persistence for anamneses.
 type Repository interface {
-	Create(ctx context.Context, a *pb.Anamnesis) (*pb.Anamnesis, error)
-	Update(ctx context.Context, a *pb.Anamnesis) (*pb.Anamnesis, error)
+	// Create and Update also record the saved version in the edit history; changedBy is the doctor.
+	// Update and Delete refuse a locked note with ErrConflict.
+	Create(ctx context.Context, a *pb.Anamnesis, changedBy string) (*pb.Anamnesis, error)
+	Update(ctx context.Context, a *pb.Anamnesis, changedBy string) (*pb.Anamnesis, error)
 	Get(ctx context.Context, uuid string) (*pb.Anamnesis, error)
-	Delete(ctx context.Context, uuid string) error
+	// Delete keeps the deleted version as the final revision of the note, changed by deletedBy.
+	Delete(ctx context.Context, uuid, deletedBy string, at time.Time) error
 	List(ctx context.Context, patientUUID string, doctorUUID string, query string, limit, offset int) ([]*pb.Anamnesis, error)
 	ListByUUIDs(ctx context.Context, uuids []string) ([]*pb.Anamnesis, error)
+	// ListRevisions returns the saved versions of a note, oldest first.
+	ListRevisions(ctx context.Context, anamnesisUUID string) ([]*pb.AnamnesisRevision, error)
+	// Sign signs and locks a note that is not signed yet.
+	Sign(ctx context.Context, uuid, doctorUUID string, at time.Time) (*pb.Anamnesis, error)
+	LockCreatedBefore(ctx context.Context, cutoff, at time.Time) (int64, error)
+	CreateAddendum(ctx context.Context, ad *pb.AnamnesisAddendum) (*pb.AnamnesisAddendum, error)
+	// RedFlagChecklist is empty when the doctor uses the default checklist.
+	RedFlagChecklist(ctx context.Context, doctorUUID string) ([]*pb.RedFlagItem, error)
+	SetRedFlagChecklist(ctx context.Context, doctorUUID string, items []*pb.RedFlagItem) error
+	ListOpenRedFlags(ctx context.Context, doctorUUID string) ([]*pb.OpenRedFlag, error)
 }
@@ -0,0 +1,17 @@
+package anamnesistemplates
+
+import (
+	"context"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+// Repository stores the doctors' anamnesis templates. Names are unique per doctor, ignoring case.
+type Repository interface {
+	Create(ctx context.Context, t *pb.AnamnesisTemplate) (*pb.AnamnesisTemplate, error)
+	Update(ctx context.Context, t *pb.AnamnesisTemplate) (*pb.AnamnesisTemplate, error)
+	Get(ctx context.Context, uuid string) (*pb.AnamnesisTemplate, error)
+	// List returns the doctor's templates by name; condition, when set, keeps only that condition.
+	List(ctx context.Context, doctorUUID, condition string) ([]*pb.AnamnesisTemplate, error)
+	Delete(ctx context.Context, uuid string) error
+}
@@ -0,0 +1,77 @@
+package appointments
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+// Repository defines outbound persistence for appointments.
+type Repository interface {
+	Create(ctx context.Context, a *pb.Appointment) (*pb.Appointment, error)
+	Update(ctx context.Context, a *pb.Appointment) (*pb.Appointment, error)
+	Get(ctx context.Context, uuid string) (*pb.Appointment, error)
+	// ListByRange returns the doctor's appointments overlapping [from, to), oldest first.
+	ListByRange(ctx context.Context, doctorUUID string, from, to time.Time, patientUUID string) ([]*pb.Appointment, error)
+	GetOccurrence(ctx context.Context, seriesUUID string, originalStart time.Time) (*pb.Appointment, error)
+	ListExceptions(ctx context.Context, seriesUUIDs []string, from, to time.Time) ([]*pb.Appointment, error)
+}
+
+// SeriesRepository defines outbound persistence for recurring appointment series.
+type SeriesRepository interface {
+	Create(ctx context.Context, s *pb.AppointmentSeries) (*pb.AppointmentSeries, error)
+	Update(ctx context.Context, s *pb.AppointmentSeries) (*pb.AppointmentSeries, error)
+	Get(ctx context.Context, uuid string) (*pb.AppointmentSeries, error)
+	ListActive(ctx context.Context, doctorUUID, patientUUID string, to time.Time) ([]*pb.AppointmentSeries, error)
+	ListByPatient(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.AppointmentSeries, error)
+	Split(ctx context.Context, old, next *pb.AppointmentSeries, carried []*pb.Appointment) (*pb.AppointmentSeries, error)
+	Truncate(ctx context.Context, s *pb.AppointmentSeries, from time.Time, reason string) (*pb.AppointmentSeries, error)
+}
+
+// FeedRepository stores the per-doctor calendar feed token.
+type FeedRepository interface {
+	// Get returns the feed without its token, which is only stored as a hash.
+	Get(ctx context.Context, doctorUUID string) (*pb.CalendarFeed, error)
+	GetByTokenHash(ctx context.Context, tokenSHA256 string) (doctorUUID string, err error)
+	// Save replaces the doctor's token hash (a rotated token invalidates the old URL).
+	Save(ctx context.Context, doctorUUID, tokenSHA256 string, createdAt time.Time) error
+	Delete(ctx context.Context, doctorUUID string) error
+}
+
+// ImportRepository supports iCalendar import: de-duplication by external UID and the review list.
+type ImportRepository interface {
+	ImportedUIDs(ctx context.Context, doctorUUID string) (map[string]bool, error)
+	SeriesByExternalUID(ctx context.Context, doctorUUID, uid string) (*pb.AppointmentSeries, error)
+	// SaveImport stores the series, appointments and reviews of one import in a single transaction.
+	SaveImport(ctx context.Context, doctorUUID string, series []*pb.AppointmentSeries, appointments []*pb.Appointment, reviews []*pb.ImportReview) error
+	GetReview(ctx context.Context, doctorUUID, uuid string) (*pb.ImportReview, error)
+	UpdateReviewStatus(ctx context.Context, rv *pb.ImportReview, doctorUUID string) (*pb.ImportReview, error)
+	ListReviews(ctx context.Context, doctorUUID, status string) ([]*pb.ImportReview, error)
+}
+
+// WaitlistRepository stores the waiting list and the offers of freed slots.
+type WaitlistRepository interface {
+	CreateEntry(ctx context.Context, e *pb.WaitlistEntry) (*pb.WaitlistEntry, error)
+	UpdateEntryStatus(ctx context.Context, uuid, status string, from []string, at time.Time) error
+	GetEntry(ctx context.Context, uuid string) (*pb.WaitlistEntry, error)
+	ListEntries(ctx context.Context, doctorUUID, status string) ([]*pb.WaitlistEntry, error)
+	Candidates(ctx context.Context, doctorUUID string, start, end time.Time) ([]*pb.WaitlistEntry, error)
+	CreateOffer(ctx context.Context, o *pb.WaitlistOffer) (*pb.WaitlistOffer, error)
+	CloseOffer(ctx context.Context, o *pb.WaitlistOffer) (*pb.WaitlistOffer, error)
+	// AcceptOffer creates the appointment and closes the pending offer with it atomically.
+	AcceptOffer(ctx context.Context, o *pb.WaitlistOffer, a *pb.Appointment) (*pb.WaitlistOffer, *pb.Appointment, error)
+	GetOffer(ctx context.Context, uuid string) (*pb.WaitlistOffer, error)
+	ListOffers(ctx context.Context, doctorUUID, status string) ([]*pb.WaitlistOffer, error)
+	PendingOfferForEntry(ctx context.Context, entryUUID string) (*pb.WaitlistOffer, error)
+	ListExpiredOffers(ctx context.Context, now time.Time) ([]*pb.WaitlistOffer, error)
+	OfferedEntries(ctx context.Context, doctorUUID string, start time.Time) (map[string]bool, error)
+}
+
+// ResourceRepository stores the practice's bookable rooms and equipment.
+type ResourceRepository interface {
+	Create(ctx context.Context, res *pb.Resource) (*pb.Resource, error)
+	Update(ctx context.Context, res *pb.Resource) (*pb.Resource, error)
+	Get(ctx context.Context, uuid string) (*pb.Resource, error)
+	List(ctx context.Context, doctorUUID string, activeOnly bool) ([]*pb.Resource, error)
+}
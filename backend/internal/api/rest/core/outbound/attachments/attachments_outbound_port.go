@@ -0,0 +1,35 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+// Repository stores the metadata of patient and visit attachments.
+type Repository interface {
+	// Create stores the attachment with the key of its content in the Store.
+	Create(ctx context.Context, a *pb.Attachment, storageKey string) (*pb.Attachment, error)
+	// Get returns the attachment and the key of its content.
+	Get(ctx context.Context, uuid string) (*pb.Attachment, string, error)
+	// List returns the patient's attachments, newest first; anamnesisUUID, when set, keeps only that visit's.
+	List(ctx context.Context, patientUUID, anamnesisUUID string) ([]*pb.Attachment, error)
+	Delete(ctx context.Context, uuid string) error
+	// StorageKeys returns the content keys of a doctor's or a patient's attachments, so that their
+	// files can be removed when the rows go with the doctor or patient.
+	StorageKeys(ctx context.Context, doctorUUID, patientUUID string) ([]string, error)
+}
+
+// Store keeps the content of attachments outside the publicly served uploads folder.
+type Store interface {
+	// Save writes r under key and returns the number of bytes written.
+	Save(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Remove deletes the content; a missing key is not an error.
+	Remove(ctx context.Context, key string) error
+}
+
+// ErrTooLarge is returned by stores when the content exceeds their size limit.
+var ErrTooLarge = errors.New("attachment too large")
@@ -0,0 +1,29 @@
+package consents
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+// Repository stores the consent templates and the consents signed by patients. Template names are
+// unique per doctor, ignoring case. A signed consent is never updated except for its revocation.
+type Repository interface {
+	CreateTemplate(ctx context.Context, t *pb.ConsentTemplate) (*pb.ConsentTemplate, error)
+	UpdateTemplate(ctx context.Context, t *pb.ConsentTemplate) (*pb.ConsentTemplate, error)
+	GetTemplate(ctx context.Context, uuid string) (*pb.ConsentTemplate, error)
+	// ListTemplates returns the doctor's templates by name, inactive ones only on request.
+	ListTemplates(ctx context.Context, doctorUUID string, includeInactive bool) ([]*pb.ConsentTemplate, error)
+	DeleteTemplate(ctx context.Context, uuid string) error
+
+	Create(ctx context.Context, c *pb.Consent, signature []byte) (*pb.Consent, error)
+	Get(ctx context.Context, uuid string) (*pb.Consent, error)
+	GetSignature(ctx context.Context, uuid string) ([]byte, error)
+	// List returns the patient's consents, newest first, without their signatures.
+	List(ctx context.Context, patientUUID string) ([]*pb.Consent, error)
+	// Revoke marks a consent revoked; re.ErrConflict when it already is.
+	Revoke(ctx context.Context, uuid string, at time.Time) error
+	// HasValid reports whether the patient has a consent of the kind that is not revoked or expired at the given time.
+	HasValid(ctx context.Context, patientUUID, kind string, at time.Time) (bool, error)
+}
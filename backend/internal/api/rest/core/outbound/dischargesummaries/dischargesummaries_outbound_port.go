@@ -0,0 +1,17 @@
+package dischargesummaries
+
+import (
+	"context"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+// Repository stores discharge summaries with their PDF. There is no update or delete: a stored
+// summary is final.
+type Repository interface {
+	Create(ctx context.Context, d *pb.DischargeSummary, pdf []byte) (*pb.DischargeSummary, error)
+	Get(ctx context.Context, uuid string) (*pb.DischargeSummary, error)
+	GetPDF(ctx context.Context, uuid string) ([]byte, error)
+	// List returns the patient's summaries, newest first, without their PDFs.
+	List(ctx context.Context, patientUUID string) ([]*pb.DischargeSummary, error)
+}
@@ -9,4 +9,5 @@ import (
 type Repository interface {
 	GetByDoctor(ctx context.Context, doctorUUID string) (*pt.DoctorProfile, error)
 	Upsert(ctx context.Context, profile *pt.DoctorProfile) (*pt.DoctorProfile, error)
+	GetSchedule(ctx context.Context, doctorUUID string) (*pt.WorkingSchedule, error)
 }
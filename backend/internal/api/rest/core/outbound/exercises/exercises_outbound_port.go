@@ -0,0 +1,24 @@
+package exercises
+
+import (
+	"context"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+// Repository stores the exercise library and the home exercise programs built from it.
+type Repository interface {
+	CreateExercise(ctx context.Context, e *pb.Exercise) (*pb.Exercise, error)
+	UpdateExercise(ctx context.Context, e *pb.Exercise) (*pb.Exercise, error)
+	GetExercise(ctx context.Context, uuid string) (*pb.Exercise, error)
+	// ListExercises returns the doctor's exercises by name; query filters by name, inactive ones only on request.
+	ListExercises(ctx context.Context, doctorUUID, query string, includeInactive bool) ([]*pb.Exercise, error)
+	DeleteExercise(ctx context.Context, uuid string) error
+
+	// Programs are returned with their items in order, each carrying its library exercise.
+	CreateProgram(ctx context.Context, p *pb.ExerciseProgram) (*pb.ExerciseProgram, error)
+	UpdateProgram(ctx context.Context, p *pb.ExerciseProgram) (*pb.ExerciseProgram, error)
+	GetProgram(ctx context.Context, uuid string) (*pb.ExerciseProgram, error)
+	ListPrograms(ctx context.Context, patientUUID string) ([]*pb.ExerciseProgram, error)
+	DeleteProgram(ctx context.Context, uuid string) error
+}
@@ -0,0 +1,12 @@
+package icd10
+
+import pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+
+// Catalogue is the read-only ICD-10 (MKB-10) code list.
+type Catalogue interface {
+	// Search matches the query against codes and descriptions, ignoring case and diacritics; the
+	// best matches come first.
+	Search(query string, limit int) []*pb.DiagnosisCode
+	// Lookup returns the entry for a code; "m545" and "M54.5" both find "M54.5".
+	Lookup(code string) (*pb.DiagnosisCode, bool)
+}
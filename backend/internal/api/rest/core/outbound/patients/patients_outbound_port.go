@@ -11,5 +11,6 @@ type Repository interface {
 	Update(ctx context.Context, p *pb.Patient) (*pb.Patient, error)
 	List(ctx context.Context, filter *pb.ListPatientsRequest, doctorUUID string, limit, offset int) ([]*pb.Patient, error)
 	Get(ctx context.Context, uuid string) (*pb.Patient, error)
+	// Delete fails with ErrConflict while the patient has a locked note.
 	Delete(ctx context.Context, uuid string) error
 }
@@ -0,0 +1,17 @@
+package questionnaires
+
+import (
+	"context"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+// Repository stores scored questionnaire results.
+type Repository interface {
+	Create(ctx context.Context, res *pb.QuestionnaireResult) (*pb.QuestionnaireResult, error)
+	Get(ctx context.Context, uuid string) (*pb.QuestionnaireResult, error)
+	Delete(ctx context.Context, uuid string) error
+	// List returns a patient's results oldest first; an empty code returns all questionnaires.
+	List(ctx context.Context, patientUUID, code string) ([]*pb.QuestionnaireResult, error)
+	ListForAnamneses(ctx context.Context, anamnesisUUIDs []string) ([]*pb.QuestionnaireResult, error)
+}
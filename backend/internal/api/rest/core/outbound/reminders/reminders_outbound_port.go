@@ -0,0 +1,46 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+// Repository stores reminder settings and the reminder outbox.
+type Repository interface {
+	GetSettings(ctx context.Context, doctorUUID string) (*pb.ReminderSettings, error)
+	UpsertSettings(ctx context.Context, s *pb.ReminderSettings) (*pb.ReminderSettings, error)
+	ListEnabledSettings(ctx context.Context) ([]*pb.ReminderSettings, error)
+
+	// Enqueue inserts the reminder unless one with the same dedupe key exists; it reports whether a row was added.
+	Enqueue(ctx context.Context, rem *pb.Reminder, dedupeKey string) (bool, error)
+	// ClaimDue marks up to limit due pending reminders as sending and returns them.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*pb.Reminder, error)
+	// Finish stores the result of a delivery attempt of a reminder that is being sent.
+	Finish(ctx context.Context, rem *pb.Reminder) error
+	// FailStale marks reminders stuck in sending since before cutoff as failed and returns their count.
+	FailStale(ctx context.Context, cutoff, now time.Time, reason string) (int, error)
+	Retry(ctx context.Context, uuid string, at time.Time) error
+	Get(ctx context.Context, uuid string) (*pb.Reminder, error)
+	List(ctx context.Context, doctorUUID, status string, from, to time.Time) ([]*pb.Reminder, error)
+	// ListForAppointment matches the appointment uuid and/or the series occurrence; either may be empty.
+	ListForAppointment(ctx context.Context, doctorUUID, appointmentUUID, seriesUUID string, originalStart time.Time) ([]*pb.Reminder, error)
+}
+
+// Message is a rendered reminder ready for delivery.
+type Message struct {
+	To      string // e-mail address or phone number
+	Subject string // ignored by SMS
+	Body    string
+}
+
+// Notifier delivers messages over one channel (e-mail, SMS).
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ErrRejected is wrapped by notifiers when the provider refused the message for good
+// (bad recipient, invalid credentials); such reminders are not retried.
+var ErrRejected = errors.New("message rejected")
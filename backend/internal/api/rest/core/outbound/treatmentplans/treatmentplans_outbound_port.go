@@ -0,0 +1,16 @@
+package treatmentplans
+
+import (
+	"context"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+// Repository stores treatment plans. Returned plans carry sessions_done, the number of linked visits.
+type Repository interface {
+	Create(ctx context.Context, plan *pb.TreatmentPlan) (*pb.TreatmentPlan, error)
+	Update(ctx context.Context, plan *pb.TreatmentPlan) (*pb.TreatmentPlan, error)
+	Get(ctx context.Context, uuid string) (*pb.TreatmentPlan, error)
+	List(ctx context.Context, patientUUID string) ([]*pb.TreatmentPlan, error)
+	Delete(ctx context.Context, uuid string) error
+}
@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
 	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/anamneses"
 	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
@@ -21,42 +23,111 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
-func (r *Repository) Create(ctx context.Context, a *pb.Anamnesis) (*pb.Anamnesis, error) {
+// Create stores the note together with its first revision.
+func (r *Repository) Create(ctx context.Context, a *pb.Anamnesis, changedBy string) (*pb.Anamnesis, error) {
 	rec, err := pbToRecord(a)
 	if err != nil {
 		return nil, fmt.Errorf("creating anamnesis: convert: %w", err)
 	}
-	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rec).Error; err != nil {
+			return err
+		}
+		if err := insertDetails(tx, a); err != nil {
+			return err
+		}
+		return insertRevision(tx, a, changedBy, rec.CreatedAt, false)
+	})
+	if err != nil {
 		return nil, fmt.Errorf("creating anamnesis: insert: %w", err)
 	}
-	return recordToPB(rec), nil
+	res := recordToPB(rec)
+	res.Measurements = a.GetMeasurements()
+	res.PainMarks = a.GetPainMarks()
+	res.DiagnosisCodes = a.GetDiagnosisCodes()
+	res.RedFlags = a.GetRedFlags()
+	if err := attachSessionNumbers(r.db.WithContext(ctx), []*pb.Anamnesis{res}); err != nil {
+		return nil, fmt.Errorf("creating anamnesis: %w", err)
+	}
+	return res, nil
 }
 
-func (r *Repository) Update(ctx context.Context, a *pb.Anamnesis) (*pb.Anamnesis, error) {
+// Update overwrites the note and records the new version as its next revision.
+func (r *Repository) Update(ctx context.Context, a *pb.Anamnesis, changedBy string) (*pb.Anamnesis, error) {
 	rec, err := pbToRecord(a)
 	if err != nil {
 		return nil, fmt.Errorf("updating anamnesis: convert: %w", err)
 	}
-	res := r.db.WithContext(ctx).
-		Model(&anamnesisRecord{}).
-		Where("uuid = ?", a.GetUuid()).
-		Updates(map[string]interface{}{
-			"patient_uuid":        rec.PatientUuid,
-			"anamnesis":           rec.Anamnesis,
-			"status":              rec.Status,
-			"diagnosis":           rec.Diagnosis,
-			"therapy":             rec.Therapy,
-			"other_info":          rec.OtherInfo,
-			"include_visit_uuids": pq.StringArray(rec.IncludeVisitUuids),
-			"updated_at":          rec.UpdatedAt,
-		})
-	if res.Error != nil {
-		return nil, fmt.Errorf("updating anamnesis: %w", res.Error)
-	}
-	if res.RowsAffected == 0 {
-		return nil, fmt.Errorf("updating anamnesis: %w", re.ErrNotFound)
-	}
-	return recordToPB(rec), nil
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the note row serializes concurrent edits, so they get consecutive revision numbers.
+		var current anamnesisRecord
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", a.GetUuid()).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return re.ErrNotFound
+			}
+			return err
+		}
+		if current.LockedAt != nil {
+			return re.ErrConflict
+		}
+		if err := ensureBaseline(tx, current); err != nil {
+			return err
+		}
+		// A signed or auto-locked note is never overwritten, only corrected with addenda.
+		res := tx.Model(&anamnesisRecord{}).
+			Where("uuid = ? AND locked_at IS NULL", a.GetUuid()).
+			Updates(map[string]interface{}{
+				"patient_uuid":        rec.PatientUuid,
+				"anamnesis":           rec.Anamnesis,
+				"status":              rec.Status,
+				"diagnosis":           rec.Diagnosis,
+				"therapy":             rec.Therapy,
+				"other_info":          rec.OtherInfo,
+				"include_visit_uuids": pq.StringArray(rec.IncludeVisitUuids),
+				"treatment_plan_uuid": rec.TreatmentPlanUuid,
+				"red_flag_action":     rec.RedFlagAction,
+				"updated_at":          rec.UpdatedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// The row is locked by this transaction, so it exists and was locked in the meantime.
+			return re.ErrConflict
+		}
+		if err := tx.Where("anamnesis_uuid = ?", a.GetUuid()).Delete(&measurementRecord{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("anamnesis_uuid = ?", a.GetUuid()).Delete(&painMarkRecord{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("anamnesis_uuid = ?", a.GetUuid()).Delete(&diagnosisRecord{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("anamnesis_uuid = ?", a.GetUuid()).Delete(&redFlagRecord{}).Error; err != nil {
+			return err
+		}
+		if err := insertDetails(tx, a); err != nil {
+			return err
+		}
+		changedAt := time.Now().UTC()
+		if rec.UpdatedAt != nil {
+			changedAt = *rec.UpdatedAt
+		}
+		return insertRevision(tx, a, changedBy, changedAt, false)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("updating anamnesis: %w", err)
+	}
+	res := recordToPB(rec)
+	res.Measurements = a.GetMeasurements()
+	res.PainMarks = a.GetPainMarks()
+	res.DiagnosisCodes = a.GetDiagnosisCodes()
+	res.RedFlags = a.GetRedFlags()
+	if err := attachSessionNumbers(r.db.WithContext(ctx), []*pb.Anamnesis{res}); err != nil {
+		return nil, fmt.Errorf("updating anamnesis: %w", err)
+	}
+	return res, nil
 }
 
 func (r *Repository) Get(ctx context.Context, uuid string) (*pb.Anamnesis, error) {
@@ -67,16 +138,45 @@ func (r *Repository) Get(ctx context.Context, uuid string) (*pb.Anamnesis, error
 		}
 		return nil, fmt.Errorf("getting anamnesis: %w", err)
 	}
-	return recordToPB(rec), nil
+	res := []*pb.Anamnesis{recordToPB(rec)}
+	if err := attachDetails(r.db.WithContext(ctx), res); err != nil {
+		return nil, fmt.Errorf("getting anamnesis: %w", err)
+	}
+	return res[0], nil
 }
 
-func (r *Repository) Delete(ctx context.Context, uuid string) error {
-	res := r.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&anamnesisRecord{})
-	if res.Error != nil {
-		return fmt.Errorf("delete anamnesis: %w", res.Error)
-	}
-	if res.RowsAffected == 0 {
-		return fmt.Errorf("delete anamnesis: %w", re.ErrNotFound)
+// Delete removes a note that is not locked; a locked one is reported as a conflict. The deleted
+// version is kept as the final revision of the note, changed by deletedBy.
+func (r *Repository) Delete(ctx context.Context, uuid, deletedBy string, at time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current anamnesisRecord
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", uuid).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return re.ErrNotFound
+			}
+			return err
+		}
+		if current.LockedAt != nil {
+			return re.ErrConflict
+		}
+		list := []*pb.Anamnesis{recordToPB(current)}
+		if err := attachDetails(tx, list); err != nil {
+			return err
+		}
+		if err := insertRevision(tx, list[0], deletedBy, at, true); err != nil {
+			return err
+		}
+		res := tx.Where("uuid = ? AND locked_at IS NULL", uuid).Delete(&anamnesisRecord{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return re.ErrConflict
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("delete anamnesis: %w", err)
 	}
 	return nil
 }
@@ -91,7 +191,8 @@ func (r *Repository) List(ctx context.Context, patientUUID string, doctorUUID st
 	}
 	if strings.TrimSpace(query) != "" {
 		like := "%" + strings.ToLower(strings.TrimSpace(query)) + "%"
-		q = q.Where("LOWER(anamneses.diagnosis) LIKE ?", like)
+		code := strings.ToUpper(strings.TrimSpace(query)) + "%"
+		q = q.Where("(LOWER(anamneses.diagnosis) LIKE ? OR EXISTS (SELECT 1 FROM anamnesis_diagnoses d WHERE d.anamnesis_uuid = anamneses.uuid AND d.code LIKE ?))", like, code)
 	}
 	if limit > 0 {
 		q = q.Limit(limit).Offset(offset)
@@ -103,6 +204,9 @@ func (r *Repository) List(ctx context.Context, patientUUID string, doctorUUID st
 	for _, rec := range recs {
 		res = append(res, recordToPB(rec))
 	}
+	if err := attachDetails(r.db.WithContext(ctx), res); err != nil {
+		return nil, fmt.Errorf("listing anamneses: %w", err)
+	}
 	return res, nil
 }
 
@@ -118,7 +222,147 @@ func (r *Repository) ListByUUIDs(ctx context.Context, uuids []string) ([]*pb.Ana
 	for _, rec := range recs {
 		res = append(res, recordToPB(rec))
 	}
+	if err := attachDetails(r.db.WithContext(ctx), res); err != nil {
+		return nil, fmt.Errorf("listing anamneses by uuids: %w", err)
+	}
 	return res, nil
 }
 
+// insertDetails stores the measurements, body chart, coded diagnoses and red-flag screening of a visit.
+func insertDetails(tx *gorm.DB, a *pb.Anamnesis) error {
+	if recs := measurementRecords(a); len(recs) > 0 {
+		if err := tx.Create(&recs).Error; err != nil {
+			return err
+		}
+	}
+	if recs := painMarkRecords(a); len(recs) > 0 {
+		if err := tx.Create(&recs).Error; err != nil {
+			return err
+		}
+	}
+	if recs := diagnosisRecords(a); len(recs) > 0 {
+		if err := tx.Create(&recs).Error; err != nil {
+			return err
+		}
+	}
+	if recs := redFlagRecords(a); len(recs) > 0 {
+		return tx.Create(&recs).Error
+	}
+	return nil
+}
+
+// attachDetails loads the measurements, body charts, coded diagnoses, red flags, addenda and plan session numbers of all given visits.
+func attachDetails(db *gorm.DB, list []*pb.Anamnesis) error {
+	if len(list) == 0 {
+		return nil
+	}
+	if err := attachSessionNumbers(db, list); err != nil {
+		return err
+	}
+	byUUID := make(map[string]*pb.Anamnesis, len(list))
+	uuids := make([]string, 0, len(list))
+	for _, a := range list {
+		a.Measurements = []*pb.Measurement{}
+		a.PainMarks = []*pb.PainMark{}
+		a.Addenda = []*pb.AnamnesisAddendum{}
+		a.DiagnosisCodes = []*pb.DiagnosisCode{}
+		a.RedFlags = []*pb.RedFlagAnswer{}
+		byUUID[a.GetUuid()] = a
+		uuids = append(uuids, a.GetUuid())
+	}
+	var recs []measurementRecord
+	if err := db.
+		Where("anamnesis_uuid IN ?", uuids).
+		Order("anamnesis_uuid, position").
+		Find(&recs).Error; err != nil {
+		return fmt.Errorf("load measurements: %w", err)
+	}
+	for _, rec := range recs {
+		if a, ok := byUUID[rec.AnamnesisUuid]; ok {
+			a.Measurements = append(a.Measurements, measurementRecordToPB(rec))
+		}
+	}
+	var marks []painMarkRecord
+	if err := db.
+		Where("anamnesis_uuid IN ?", uuids).
+		Order("anamnesis_uuid, position").
+		Find(&marks).Error; err != nil {
+		return fmt.Errorf("load pain marks: %w", err)
+	}
+	for _, rec := range marks {
+		if a, ok := byUUID[rec.AnamnesisUuid]; ok {
+			a.PainMarks = append(a.PainMarks, painMarkRecordToPB(rec))
+		}
+	}
+	var diagnoses []diagnosisRecord
+	if err := db.
+		Where("anamnesis_uuid IN ?", uuids).
+		Order("anamnesis_uuid, position").
+		Find(&diagnoses).Error; err != nil {
+		return fmt.Errorf("load diagnoses: %w", err)
+	}
+	for _, rec := range diagnoses {
+		if a, ok := byUUID[rec.AnamnesisUuid]; ok {
+			a.DiagnosisCodes = append(a.DiagnosisCodes, &pb.DiagnosisCode{Code: rec.Code, Description: rec.Description})
+		}
+	}
+	var flags []redFlagRecord
+	if err := db.
+		Where("anamnesis_uuid IN ?", uuids).
+		Order("anamnesis_uuid, position").
+		Find(&flags).Error; err != nil {
+		return fmt.Errorf("load red flags: %w", err)
+	}
+	for _, rec := range flags {
+		if a, ok := byUUID[rec.AnamnesisUuid]; ok {
+			a.RedFlags = append(a.RedFlags, redFlagRecordToPB(rec))
+		}
+	}
+	var addenda []addendumRecord
+	if err := db.
+		Where("anamnesis_uuid IN ?", uuids).
+		Order("anamnesis_uuid, created_at").
+		Find(&addenda).Error; err != nil {
+		return fmt.Errorf("load addenda: %w", err)
+	}
+	for _, rec := range addenda {
+		if a, ok := byUUID[rec.AnamnesisUuid]; ok {
+			a.Addenda = append(a.Addenda, addendumRecordToPB(rec))
+		}
+	}
+	return nil
+}
+
+// attachSessionNumbers numbers the visits of each treatment plan by date ("session 4 of 10").
+func attachSessionNumbers(db *gorm.DB, list []*pb.Anamnesis) error {
+	plans := map[string]bool{}
+	var planUUIDs []string
+	for _, a := range list {
+		if p := a.GetTreatmentPlanUuid(); p != "" && !plans[p] {
+			plans[p] = true
+			planUUIDs = append(planUUIDs, p)
+		}
+	}
+	if len(planUUIDs) == 0 {
+		return nil
+	}
+	var rows []struct {
+		Uuid          string
+		SessionNumber int32
+	}
+	if err := db.Raw(`
+		SELECT uuid, ROW_NUMBER() OVER (PARTITION BY treatment_plan_uuid ORDER BY created_at, uuid) AS session_number
+		FROM anamneses WHERE treatment_plan_uuid IN ?`, planUUIDs).Scan(&rows).Error; err != nil {
+		return fmt.Errorf("number plan sessions: %w", err)
+	}
+	numbers := make(map[string]int32, len(rows))
+	for _, row := range rows {
+		numbers[row.Uuid] = row.SessionNumber
+	}
+	for _, a := range list {
+		a.SessionNumber = numbers[a.GetUuid()]
+	}
+	return nil
+}
+
 var _ out.Repository = (*Repository)(nil)
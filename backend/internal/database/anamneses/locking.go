@@ -0,0 +1,54 @@
+package anamneses
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"gorm.io/gorm"
+)
+
+// Sign marks a not yet signed note as signed by the doctor and locks it.
+func (r *Repository) Sign(ctx context.Context, uuid, doctorUUID string, at time.Time) (*pb.Anamnesis, error) {
+	res := r.db.WithContext(ctx).Model(&anamnesisRecord{}).
+		Where("uuid = ? AND signed_at IS NULL", uuid).
+		Updates(map[string]interface{}{
+			"signed_at": at,
+			"signed_by": doctorUUID,
+			"locked_at": gorm.Expr("COALESCE(locked_at, ?)", at),
+		})
+	if res.Error != nil {
+		return nil, fmt.Errorf("signing anamnesis: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("signing anamnesis: %w", re.ErrNotFound)
+	}
+	return r.Get(ctx, uuid)
+}
+
+// LockCreatedBefore locks all unlocked notes created before cutoff and returns how many were locked.
+// Notes with positive red flags stay open until the action taken is recorded.
+func (r *Repository) LockCreatedBefore(ctx context.Context, cutoff, at time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&anamnesisRecord{}).
+		Where("locked_at IS NULL AND created_at < ?", cutoff).
+		Where("NOT (red_flag_action = '' AND EXISTS (SELECT 1 FROM anamnesis_red_flags f WHERE f.anamnesis_uuid = anamneses.uuid AND f.positive))").
+		Update("locked_at", at)
+	if res.Error != nil {
+		return 0, fmt.Errorf("locking anamneses: %w", res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+func (r *Repository) CreateAddendum(ctx context.Context, ad *pb.AnamnesisAddendum) (*pb.AnamnesisAddendum, error) {
+	rec := addendumToRecord(ad)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating anamnesis addendum: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating anamnesis addendum: %w", err)
+	}
+	return addendumRecordToPB(rec), nil
+}
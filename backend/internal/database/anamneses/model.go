@@ -18,6 +18,11 @@ type anamnesisRecord struct {
 	Therapy           string         `gorm:"column:therapy"`
 	OtherInfo         string         `gorm:"column:other_info"`
 	IncludeVisitUuids pq.StringArray `gorm:"column:include_visit_uuids;type:text[]"`
+	TreatmentPlanUuid *string        `gorm:"column:treatment_plan_uuid"`
+	SignedAt          *time.Time     `gorm:"column:signed_at"`
+	SignedBy          *string        `gorm:"column:signed_by"`
+	LockedAt          *time.Time     `gorm:"column:locked_at"`
+	RedFlagAction     string         `gorm:"column:red_flag_action"`
 	CreatedAt         time.Time      `gorm:"column:created_at"`
 	UpdatedAt         *time.Time     `gorm:"column:updated_at"`
 }
@@ -29,7 +34,11 @@ func recordToPB(rec anamnesisRecord) *pb.Anamnesis {
 	if rec.UpdatedAt != nil {
 		upd = timestamppb.New(*rec.UpdatedAt)
 	}
-	return &pb.Anamnesis{
+	plan := ""
+	if rec.TreatmentPlanUuid != nil {
+		plan = *rec.TreatmentPlanUuid
+	}
+	a := &pb.Anamnesis{
 		Uuid:              rec.Uuid,
 		PatientUuid:       rec.PatientUuid,
 		Anamnesis:         rec.Anamnesis,
@@ -38,9 +47,21 @@ func recordToPB(rec anamnesisRecord) *pb.Anamnesis {
 		Therapy:           rec.Therapy,
 		OtherInfo:         rec.OtherInfo,
 		IncludeVisitUuids: []string(rec.IncludeVisitUuids),
+		TreatmentPlanUuid: plan,
+		RedFlagAction:     rec.RedFlagAction,
 		CreatedAt:         timestamppb.New(rec.CreatedAt),
 		UpdatedAt:         upd,
 	}
+	if rec.SignedAt != nil {
+		a.SignedAt = timestamppb.New(*rec.SignedAt)
+	}
+	if rec.SignedBy != nil {
+		a.SignedByUuid = *rec.SignedBy
+	}
+	if rec.LockedAt != nil {
+		a.LockedAt = timestamppb.New(*rec.LockedAt)
+	}
+	return a
 }
 
 func pbToRecord(a *pb.Anamnesis) (anamnesisRecord, error) {
@@ -57,6 +78,21 @@ func pbToRecord(a *pb.Anamnesis) (anamnesisRecord, error) {
 		Therapy:           a.GetTherapy(),
 		OtherInfo:         a.GetOtherInfo(),
 		IncludeVisitUuids: pq.StringArray(include),
+		RedFlagAction:     a.GetRedFlagAction(),
+	}
+	if plan := a.GetTreatmentPlanUuid(); plan != "" {
+		rec.TreatmentPlanUuid = &plan
+	}
+	if a.GetSignedAt() != nil {
+		t := a.GetSignedAt().AsTime()
+		rec.SignedAt = &t
+	}
+	if by := a.GetSignedByUuid(); by != "" {
+		rec.SignedBy = &by
+	}
+	if a.GetLockedAt() != nil {
+		t := a.GetLockedAt().AsTime()
+		rec.LockedAt = &t
 	}
 	if a.GetCreatedAt() != nil {
 		rec.CreatedAt = a.GetCreatedAt().AsTime()
@@ -67,3 +103,214 @@ func pbToRecord(a *pb.Anamnesis) (anamnesisRecord, error) {
 	}
 	return rec, nil
 }
+
+type measurementRecord struct {
+	Uuid          string    `gorm:"column:uuid;primaryKey"`
+	AnamnesisUuid string    `gorm:"column:anamnesis_uuid"`
+	Position      int       `gorm:"column:position"`
+	Kind          string    `gorm:"column:kind"`
+	Site          string    `gorm:"column:site"`
+	Side          string    `gorm:"column:side"`
+	Movement      string    `gorm:"column:movement"`
+	Value         float64   `gorm:"column:value"`
+	Unit          string    `gorm:"column:unit"`
+	Note          string    `gorm:"column:note"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+func (measurementRecord) TableName() string { return "anamnesis_measurements" }
+
+func measurementRecordToPB(rec measurementRecord) *pb.Measurement {
+	return &pb.Measurement{
+		Uuid:          rec.Uuid,
+		AnamnesisUuid: rec.AnamnesisUuid,
+		Kind:          rec.Kind,
+		Site:          rec.Site,
+		Side:          rec.Side,
+		Movement:      rec.Movement,
+		Value:         rec.Value,
+		Unit:          rec.Unit,
+		Note:          rec.Note,
+		CreatedAt:     timestamppb.New(rec.CreatedAt),
+	}
+}
+
+// measurementRecords keeps the request order in position so the visit reads back the same way.
+func measurementRecords(a *pb.Anamnesis) []measurementRecord {
+	recs := make([]measurementRecord, 0, len(a.GetMeasurements()))
+	for i, m := range a.GetMeasurements() {
+		rec := measurementRecord{
+			Uuid:          m.GetUuid(),
+			AnamnesisUuid: a.GetUuid(),
+			Position:      i,
+			Kind:          m.GetKind(),
+			Site:          m.GetSite(),
+			Side:          m.GetSide(),
+			Movement:      m.GetMovement(),
+			Value:         m.GetValue(),
+			Unit:          m.GetUnit(),
+			Note:          m.GetNote(),
+		}
+		if m.GetCreatedAt() != nil {
+			rec.CreatedAt = m.GetCreatedAt().AsTime()
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+type painMarkRecord struct {
+	Uuid          string    `gorm:"column:uuid;primaryKey"`
+	AnamnesisUuid string    `gorm:"column:anamnesis_uuid"`
+	Position      int       `gorm:"column:position"`
+	View          string    `gorm:"column:view"`
+	Region        string    `gorm:"column:region"`
+	X             float64   `gorm:"column:x"`
+	Y             float64   `gorm:"column:y"`
+	PainType      string    `gorm:"column:pain_type"`
+	Intensity     int32     `gorm:"column:intensity"`
+	Note          string    `gorm:"column:note"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+func (painMarkRecord) TableName() string { return "anamnesis_pain_marks" }
+
+func painMarkRecordToPB(rec painMarkRecord) *pb.PainMark {
+	return &pb.PainMark{
+		Uuid:          rec.Uuid,
+		AnamnesisUuid: rec.AnamnesisUuid,
+		View:          rec.View,
+		Region:        rec.Region,
+		X:             rec.X,
+		Y:             rec.Y,
+		PainType:      rec.PainType,
+		Intensity:     rec.Intensity,
+		Note:          rec.Note,
+		CreatedAt:     timestamppb.New(rec.CreatedAt),
+	}
+}
+
+func painMarkRecords(a *pb.Anamnesis) []painMarkRecord {
+	recs := make([]painMarkRecord, 0, len(a.GetPainMarks()))
+	for i, m := range a.GetPainMarks() {
+		rec := painMarkRecord{
+			Uuid:          m.GetUuid(),
+			AnamnesisUuid: a.GetUuid(),
+			Position:      i,
+			View:          m.GetView(),
+			Region:        m.GetRegion(),
+			X:             m.GetX(),
+			Y:             m.GetY(),
+			PainType:      m.GetPainType(),
+			Intensity:     m.GetIntensity(),
+			Note:          m.GetNote(),
+		}
+		if m.GetCreatedAt() != nil {
+			rec.CreatedAt = m.GetCreatedAt().AsTime()
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+// diagnosisRecord is one ICD-10 code of a visit; position keeps the primary diagnosis first.
+type diagnosisRecord struct {
+	AnamnesisUuid string `gorm:"column:anamnesis_uuid;primaryKey"`
+	Position      int    `gorm:"column:position"`
+	Code          string `gorm:"column:code;primaryKey"`
+	Description   string `gorm:"column:description"`
+}
+
+func (diagnosisRecord) TableName() string { return "anamnesis_diagnoses" }
+
+func diagnosisRecords(a *pb.Anamnesis) []diagnosisRecord {
+	recs := make([]diagnosisRecord, 0, len(a.GetDiagnosisCodes()))
+	for i, d := range a.GetDiagnosisCodes() {
+		recs = append(recs, diagnosisRecord{
+			AnamnesisUuid: a.GetUuid(),
+			Position:      i,
+			Code:          d.GetCode(),
+			Description:   d.GetDescription(),
+		})
+	}
+	return recs
+}
+
+// redFlagRecord is one answer of a visit's red-flag screening; position keeps the checklist order.
+type redFlagRecord struct {
+	AnamnesisUuid string `gorm:"column:anamnesis_uuid;primaryKey"`
+	Position      int    `gorm:"column:position"`
+	Code          string `gorm:"column:code;primaryKey"`
+	Label         string `gorm:"column:label"`
+	Positive      bool   `gorm:"column:positive"`
+	Note          string `gorm:"column:note"`
+}
+
+func (redFlagRecord) TableName() string { return "anamnesis_red_flags" }
+
+func redFlagRecordToPB(rec redFlagRecord) *pb.RedFlagAnswer {
+	return &pb.RedFlagAnswer{Code: rec.Code, Label: rec.Label, Positive: rec.Positive, Note: rec.Note}
+}
+
+func redFlagRecords(a *pb.Anamnesis) []redFlagRecord {
+	recs := make([]redFlagRecord, 0, len(a.GetRedFlags()))
+	for i, f := range a.GetRedFlags() {
+		recs = append(recs, redFlagRecord{
+			AnamnesisUuid: a.GetUuid(),
+			Position:      i,
+			Code:          f.GetCode(),
+			Label:         f.GetLabel(),
+			Positive:      f.GetPositive(),
+			Note:          f.GetNote(),
+		})
+	}
+	return recs
+}
+
+// redFlagItemRecord is one question of a doctor's own red-flag checklist.
+type redFlagItemRecord struct {
+	DoctorUuid string `gorm:"column:doctor_uuid;primaryKey"`
+	Position   int    `gorm:"column:position"`
+	Code       string `gorm:"column:code;primaryKey"`
+	Label      string `gorm:"column:label"`
+}
+
+func (redFlagItemRecord) TableName() string { return "red_flag_items" }
+
+type addendumRecord struct {
+	Uuid          string    `gorm:"column:uuid;primaryKey"`
+	AnamnesisUuid string    `gorm:"column:anamnesis_uuid"`
+	DoctorUuid    *string   `gorm:"column:doctor_uuid"`
+	Text          string    `gorm:"column:text"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+func (addendumRecord) TableName() string { return "anamnesis_addenda" }
+
+func addendumRecordToPB(rec addendumRecord) *pb.AnamnesisAddendum {
+	ad := &pb.AnamnesisAddendum{
+		Uuid:          rec.Uuid,
+		AnamnesisUuid: rec.AnamnesisUuid,
+		Text:          rec.Text,
+		CreatedAt:     timestamppb.New(rec.CreatedAt),
+	}
+	if rec.DoctorUuid != nil {
+		ad.DoctorUuid = *rec.DoctorUuid
+	}
+	return ad
+}
+
+func addendumToRecord(ad *pb.AnamnesisAddendum) addendumRecord {
+	rec := addendumRecord{
+		Uuid:          ad.GetUuid(),
+		AnamnesisUuid: ad.GetAnamnesisUuid(),
+		Text:          ad.GetText(),
+	}
+	if d := ad.GetDoctorUuid(); d != "" {
+		rec.DoctorUuid = &d
+	}
+	if ad.GetCreatedAt() != nil {
+		rec.CreatedAt = ad.GetCreatedAt().AsTime()
+	}
+	return rec
+}
@@ -0,0 +1,108 @@
+package anamneses
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// RedFlagChecklist returns the doctor's own checklist in order; it is empty when none is configured.
+func (r *Repository) RedFlagChecklist(ctx context.Context, doctorUUID string) ([]*pb.RedFlagItem, error) {
+	var recs []redFlagItemRecord
+	if err := r.db.WithContext(ctx).
+		Where("doctor_uuid = ?", doctorUUID).
+		Order("position").
+		Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("loading red flag checklist: %w", err)
+	}
+	res := make([]*pb.RedFlagItem, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, &pb.RedFlagItem{Code: rec.Code, Label: rec.Label})
+	}
+	return res, nil
+}
+
+// SetRedFlagChecklist replaces the doctor's checklist; an empty list removes it.
+func (r *Repository) SetRedFlagChecklist(ctx context.Context, doctorUUID string, items []*pb.RedFlagItem) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("doctor_uuid = ?", doctorUUID).Delete(&redFlagItemRecord{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		recs := make([]redFlagItemRecord, 0, len(items))
+		for i, it := range items {
+			recs = append(recs, redFlagItemRecord{DoctorUuid: doctorUUID, Position: i, Code: it.GetCode(), Label: it.GetLabel()})
+		}
+		return tx.Create(&recs).Error
+	})
+	if err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return fmt.Errorf("saving red flag checklist: %w", re.ErrNotFound)
+		}
+		if dbErrs.IsUniqueViolation(err) {
+			return fmt.Errorf("saving red flag checklist: %w", re.ErrConflict)
+		}
+		return fmt.Errorf("saving red flag checklist: %w", err)
+	}
+	return nil
+}
+
+// ListOpenRedFlags returns the doctor's visits with positive red flags and no action taken, newest first.
+func (r *Repository) ListOpenRedFlags(ctx context.Context, doctorUUID string) ([]*pb.OpenRedFlag, error) {
+	var rows []struct {
+		Uuid        string
+		PatientUuid string
+		FirstName   string
+		LastName    string
+		CreatedAt   time.Time
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT a.uuid, a.patient_uuid, p.first_name, p.last_name, a.created_at
+		FROM anamneses a
+		JOIN patients p ON p.uuid = a.patient_uuid
+		WHERE p.doctor_uuid = ? AND a.red_flag_action = ''
+		  AND EXISTS (SELECT 1 FROM anamnesis_red_flags f WHERE f.anamnesis_uuid = a.uuid AND f.positive)
+		ORDER BY a.created_at DESC`, doctorUUID).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("listing open red flags: %w", err)
+	}
+	res := make([]*pb.OpenRedFlag, 0, len(rows))
+	if len(rows) == 0 {
+		return res, nil
+	}
+	byUUID := make(map[string]*pb.OpenRedFlag, len(rows))
+	uuids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		item := &pb.OpenRedFlag{
+			PatientUuid:      row.PatientUuid,
+			PatientFirstName: row.FirstName,
+			PatientLastName:  row.LastName,
+			AnamnesisUuid:    row.Uuid,
+			VisitAt:          timestamppb.New(row.CreatedAt),
+			Flags:            []*pb.RedFlagAnswer{},
+		}
+		res = append(res, item)
+		byUUID[row.Uuid] = item
+		uuids = append(uuids, row.Uuid)
+	}
+	var flags []redFlagRecord
+	if err := r.db.WithContext(ctx).
+		Where("anamnesis_uuid IN ? AND positive", uuids).
+		Order("anamnesis_uuid, position").
+		Find(&flags).Error; err != nil {
+		return nil, fmt.Errorf("listing open red flags: load flags: %w", err)
+	}
+	for _, rec := range flags {
+		if item, ok := byUUID[rec.AnamnesisUuid]; ok {
+			item.Flags = append(item.Flags, redFlagRecordToPB(rec))
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,128 @@
+package anamneses
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// revisionRecord keeps one saved version of a note, including its measurements and body chart, as JSON.
+type revisionRecord struct {
+	Uuid          string    `gorm:"column:uuid;primaryKey"`
+	AnamnesisUuid string    `gorm:"column:anamnesis_uuid"`
+	Revision      int32     `gorm:"column:revision"`
+	ChangedBy     *string   `gorm:"column:changed_by"`
+	ChangedAt     time.Time `gorm:"column:changed_at"`
+	Content       string    `gorm:"column:content;type:jsonb"`
+	Deleted       bool      `gorm:"column:deleted"`
+}
+
+func (revisionRecord) TableName() string { return "anamnesis_revisions" }
+
+// insertRevision stores a as the next revision of its note; deleted marks the last version of a
+// deleted note.
+func insertRevision(tx *gorm.DB, a *pb.Anamnesis, changedBy string, changedAt time.Time, deleted bool) error {
+	content := proto.Clone(a).(*pb.Anamnesis)
+	content.SessionNumber = 0 // derived from the plan, not part of the note
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("encode revision: %w", err)
+	}
+	var last int32
+	if err := tx.Model(&revisionRecord{}).
+		Where("anamnesis_uuid = ?", a.GetUuid()).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&last).Error; err != nil {
+		return fmt.Errorf("number revision: %w", err)
+	}
+	rec := revisionRecord{
+		Uuid:          uuid.NewString(),
+		AnamnesisUuid: a.GetUuid(),
+		Revision:      last + 1,
+		ChangedAt:     changedAt,
+		Content:       string(b),
+		Deleted:       deleted,
+	}
+	if changedBy != "" {
+		rec.ChangedBy = &changedBy
+	}
+	return tx.Create(&rec).Error
+}
+
+// ensureBaseline stores the current version of a note saved before the history existed as its
+// first revision, so that the update about to happen does not lose it. current is the locked row;
+// everything is read through tx.
+func ensureBaseline(tx *gorm.DB, current anamnesisRecord) error {
+	var n int64
+	if err := tx.Model(&revisionRecord{}).Where("anamnesis_uuid = ?", current.Uuid).Count(&n).Error; err != nil {
+		return fmt.Errorf("count revisions: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+	list := []*pb.Anamnesis{recordToPB(current)}
+	if err := attachDetails(tx, list); err != nil {
+		return err
+	}
+	at := current.CreatedAt
+	if current.UpdatedAt != nil {
+		at = *current.UpdatedAt
+	}
+	return insertRevision(tx, list[0], "", at, false)
+}
+
+// ListRevisions returns the versions of a note, oldest first. A note not edited since before the
+// history existed has no stored revision yet; its current version is returned as revision 1.
+func (r *Repository) ListRevisions(ctx context.Context, anamnesisUUID string) ([]*pb.AnamnesisRevision, error) {
+	var recs []revisionRecord
+	if err := r.db.WithContext(ctx).
+		Where("anamnesis_uuid = ?", anamnesisUUID).
+		Order("revision").
+		Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing anamnesis revisions: %w", err)
+	}
+	if len(recs) == 0 {
+		current, err := r.Get(ctx, anamnesisUUID)
+		if err != nil {
+			return nil, fmt.Errorf("listing anamnesis revisions: %w", err)
+		}
+		current.SessionNumber = 0
+		changedAt := current.GetCreatedAt()
+		if current.GetUpdatedAt() != nil {
+			changedAt = current.GetUpdatedAt()
+		}
+		return []*pb.AnamnesisRevision{{
+			AnamnesisUuid: anamnesisUUID,
+			Revision:      1,
+			ChangedAt:     changedAt,
+			Content:       current,
+		}}, nil
+	}
+	res := make([]*pb.AnamnesisRevision, 0, len(recs))
+	for _, rec := range recs {
+		var content pb.Anamnesis
+		if err := protojson.Unmarshal([]byte(rec.Content), &content); err != nil {
+			return nil, fmt.Errorf("listing anamnesis revisions: decode revision %d: %w", rec.Revision, err)
+		}
+		rev := &pb.AnamnesisRevision{
+			Uuid:          rec.Uuid,
+			AnamnesisUuid: rec.AnamnesisUuid,
+			Revision:      rec.Revision,
+			ChangedAt:     timestamppb.New(rec.ChangedAt),
+			Content:       &content,
+			Deleted:       rec.Deleted,
+		}
+		if rec.ChangedBy != nil {
+			rev.ChangedByUuid = *rec.ChangedBy
+		}
+		res = append(res, rev)
+	}
+	return res, nil
+}
@@ -0,0 +1,224 @@
+package anamnesistemplates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/anamnesistemplates"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gorm.io/gorm"
+)
+
+// templateRecord keeps the default measurements as JSON; they are always read and written with their template.
+type templateRecord struct {
+	Uuid         string     `gorm:"column:uuid;primaryKey"`
+	DoctorUuid   string     `gorm:"column:doctor_uuid"`
+	Name         string     `gorm:"column:name"`
+	Condition    string     `gorm:"column:condition"`
+	Anamnesis    string     `gorm:"column:anamnesis"`
+	Status       string     `gorm:"column:status"`
+	Diagnosis    string     `gorm:"column:diagnosis"`
+	Therapy      string     `gorm:"column:therapy"`
+	OtherInfo    string     `gorm:"column:other_info"`
+	Measurements string     `gorm:"column:measurements;type:jsonb"`
+	CreatedAt    time.Time  `gorm:"column:created_at"`
+	UpdatedAt    *time.Time `gorm:"column:updated_at"`
+}
+
+func (templateRecord) TableName() string { return "anamnesis_templates" }
+
+type measurementJSON struct {
+	Kind     string   `json:"kind"`
+	Site     string   `json:"site"`
+	Side     string   `json:"side"`
+	Movement string   `json:"movement"`
+	Value    *float64 `json:"value,omitempty"` // nil for defaults that only prefill the visit form
+	Note     string   `json:"note"`
+}
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, t *pb.AnamnesisTemplate) (*pb.AnamnesisTemplate, error) {
+	rec, err := pbToRecord(t)
+	if err != nil {
+		return nil, fmt.Errorf("creating anamnesis template: convert: %w", err)
+	}
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("creating anamnesis template: %w", re.ErrConflict)
+		}
+		return nil, fmt.Errorf("creating anamnesis template: %w", err)
+	}
+	return recordToPB(rec)
+}
+
+func (r *Repository) Update(ctx context.Context, t *pb.AnamnesisTemplate) (*pb.AnamnesisTemplate, error) {
+	rec, err := pbToRecord(t)
+	if err != nil {
+		return nil, fmt.Errorf("updating anamnesis template: convert: %w", err)
+	}
+	res := r.db.WithContext(ctx).Model(&templateRecord{}).
+		Where("uuid = ?", t.GetUuid()).
+		Updates(map[string]interface{}{
+			"name":         rec.Name,
+			"condition":    rec.Condition,
+			"anamnesis":    rec.Anamnesis,
+			"status":       rec.Status,
+			"diagnosis":    rec.Diagnosis,
+			"therapy":      rec.Therapy,
+			"other_info":   rec.OtherInfo,
+			"measurements": rec.Measurements,
+			"updated_at":   rec.UpdatedAt,
+		})
+	if res.Error != nil {
+		if dbErrs.IsUniqueViolation(res.Error) {
+			return nil, fmt.Errorf("updating anamnesis template: %w", re.ErrConflict)
+		}
+		return nil, fmt.Errorf("updating anamnesis template: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("updating anamnesis template: %w", re.ErrNotFound)
+	}
+	return recordToPB(rec)
+}
+
+func (r *Repository) Get(ctx context.Context, uuid string) (*pb.AnamnesisTemplate, error) {
+	var rec templateRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting anamnesis template: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting anamnesis template: %w", err)
+	}
+	t, err := recordToPB(rec)
+	if err != nil {
+		return nil, fmt.Errorf("getting anamnesis template: %w", err)
+	}
+	return t, nil
+}
+
+func (r *Repository) List(ctx context.Context, doctorUUID, condition string) ([]*pb.AnamnesisTemplate, error) {
+	q := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID)
+	if condition = strings.TrimSpace(condition); condition != "" {
+		q = q.Where("LOWER(condition) = LOWER(?)", condition)
+	}
+	var recs []templateRecord
+	if err := q.Order("LOWER(name)").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing anamnesis templates: %w", err)
+	}
+	res := make([]*pb.AnamnesisTemplate, 0, len(recs))
+	for _, rec := range recs {
+		t, err := recordToPB(rec)
+		if err != nil {
+			return nil, fmt.Errorf("listing anamnesis templates: %w", err)
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, uuid string) error {
+	res := r.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&templateRecord{})
+	if res.Error != nil {
+		return fmt.Errorf("deleting anamnesis template: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("deleting anamnesis template: %w", re.ErrNotFound)
+	}
+	return nil
+}
+
+func pbToRecord(t *pb.AnamnesisTemplate) (templateRecord, error) {
+	measurements := make([]measurementJSON, 0, len(t.GetMeasurements()))
+	for _, m := range t.GetMeasurements() {
+		mj := measurementJSON{
+			Kind:     m.GetKind(),
+			Site:     m.GetSite(),
+			Side:     m.GetSide(),
+			Movement: m.GetMovement(),
+			Note:     m.GetNote(),
+		}
+		if m.Value != nil {
+			v := m.GetValue().GetValue()
+			mj.Value = &v
+		}
+		measurements = append(measurements, mj)
+	}
+	measurementsJSON, err := json.Marshal(measurements)
+	if err != nil {
+		return templateRecord{}, err
+	}
+	rec := templateRecord{
+		Uuid:         t.GetUuid(),
+		DoctorUuid:   t.GetDoctorUuid(),
+		Name:         t.GetName(),
+		Condition:    t.GetCondition(),
+		Anamnesis:    t.GetAnamnesis(),
+		Status:       t.GetStatus(),
+		Diagnosis:    t.GetDiagnosis(),
+		Therapy:      t.GetTherapy(),
+		OtherInfo:    t.GetOtherInfo(),
+		Measurements: string(measurementsJSON),
+	}
+	if t.GetCreatedAt() != nil {
+		rec.CreatedAt = t.GetCreatedAt().AsTime()
+	}
+	if t.GetUpdatedAt() != nil {
+		u := t.GetUpdatedAt().AsTime()
+		rec.UpdatedAt = &u
+	}
+	return rec, nil
+}
+
+func recordToPB(rec templateRecord) (*pb.AnamnesisTemplate, error) {
+	var measurements []measurementJSON
+	if err := json.Unmarshal([]byte(rec.Measurements), &measurements); err != nil {
+		return nil, fmt.Errorf("decode measurements: %w", err)
+	}
+	t := &pb.AnamnesisTemplate{
+		Uuid:         rec.Uuid,
+		DoctorUuid:   rec.DoctorUuid,
+		Name:         rec.Name,
+		Condition:    rec.Condition,
+		Anamnesis:    rec.Anamnesis,
+		Status:       rec.Status,
+		Diagnosis:    rec.Diagnosis,
+		Therapy:      rec.Therapy,
+		OtherInfo:    rec.OtherInfo,
+		Measurements: make([]*pb.MeasurementInput, 0, len(measurements)),
+		CreatedAt:    timestamppb.New(rec.CreatedAt),
+	}
+	for _, m := range measurements {
+		in := &pb.MeasurementInput{
+			Kind:     m.Kind,
+			Site:     m.Site,
+			Side:     m.Side,
+			Movement: m.Movement,
+			Note:     m.Note,
+		}
+		if m.Value != nil {
+			in.Value = wrapperspb.Double(*m.Value)
+		}
+		t.Measurements = append(t.Measurements, in)
+	}
+	if rec.UpdatedAt != nil {
+		t.UpdatedAt = timestamppb.New(*rec.UpdatedAt)
+	}
+	return t, nil
+}
+
+var _ out.Repository = (*Repository)(nil)
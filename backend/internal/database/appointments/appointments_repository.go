@@ -0,0 +1,130 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/appointments"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, a *pb.Appointment) (*pb.Appointment, error) {
+	rec := pbToRecord(a)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating appointment: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating appointment: insert: %w", err)
+	}
+	return recordToPB(rec), nil
+}
+
+func (r *Repository) Update(ctx context.Context, a *pb.Appointment) (*pb.Appointment, error) {
+	rec := pbToRecord(a)
+	res := r.db.WithContext(ctx).
+		Model(&appointmentRecord{}).
+		Where("uuid = ?", a.GetUuid()).
+		Updates(map[string]interface{}{
+			"patient_uuid":   rec.PatientUuid,
+			"anamnesis_uuid": rec.AnamnesisUuid,
+			"starts_at":      rec.StartsAt,
+			"ends_at":        rec.EndsAt,
+			"status":         rec.Status,
+			"note":           rec.Note,
+			"cancel_reason":  rec.CancelReason,
+			"cancelled_at":   rec.CancelledAt,
+			"updated_at":     rec.UpdatedAt,
+			"outcome":        rec.Outcome,
+			"outcome_at":     rec.OutcomeAt,
+			"outcome_reason": rec.OutcomeReason,
+			"resource_uuids": rec.ResourceUuids,
+		})
+	if res.Error != nil {
+		if dbErrs.IsForeignKeyViolation(res.Error) {
+			return nil, fmt.Errorf("updating appointment: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("updating appointment: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("updating appointment: %w", re.ErrNotFound)
+	}
+	return recordToPB(rec), nil
+}
+
+func (r *Repository) Get(ctx context.Context, uuid string) (*pb.Appointment, error) {
+	var rec appointmentRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting appointment: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting appointment: %w", err)
+	}
+	return recordToPB(rec), nil
+}
+
+func (r *Repository) ListByRange(ctx context.Context, doctorUUID string, from, to time.Time, patientUUID string) ([]*pb.Appointment, error) {
+	var recs []appointmentRecord
+	q := r.db.WithContext(ctx).Model(&appointmentRecord{}).
+		Where("doctor_uuid = ?", doctorUUID).
+		Where("starts_at < ? AND ends_at > ?", to, from)
+	if strings.TrimSpace(patientUUID) != "" {
+		q = q.Where("patient_uuid = ?", patientUUID)
+	}
+	if err := q.Order("starts_at ASC").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing appointments: %w", err)
+	}
+	res := make([]*pb.Appointment, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, recordToPB(rec))
+	}
+	return res, nil
+}
+
+// GetOccurrence returns the stored exception for a series occurrence, if any.
+func (r *Repository) GetOccurrence(ctx context.Context, seriesUUID string, originalStart time.Time) (*pb.Appointment, error) {
+	var rec appointmentRecord
+	if err := r.db.WithContext(ctx).
+		Where("series_uuid = ? AND original_starts_at = ?", seriesUUID, originalStart).
+		First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting appointment occurrence: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting appointment occurrence: %w", err)
+	}
+	return recordToPB(rec), nil
+}
+
+// ListExceptions returns stored occurrences of the given series whose original start is in [from, to).
+func (r *Repository) ListExceptions(ctx context.Context, seriesUUIDs []string, from, to time.Time) ([]*pb.Appointment, error) {
+	if len(seriesUUIDs) == 0 {
+		return []*pb.Appointment{}, nil
+	}
+	var recs []appointmentRecord
+	if err := r.db.WithContext(ctx).
+		Where("series_uuid IN ?", seriesUUIDs).
+		Where("original_starts_at >= ? AND original_starts_at < ?", from, to).
+		Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing appointment exceptions: %w", err)
+	}
+	res := make([]*pb.Appointment, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, recordToPB(rec))
+	}
+	return res, nil
+}
+
+var _ out.Repository = (*Repository)(nil)
@@ -0,0 +1,82 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/appointments"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type feedRecord struct {
+	DoctorUuid string    `gorm:"column:doctor_uuid;primaryKey"`
+	TokenHash  string    `gorm:"column:token_sha256"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (feedRecord) TableName() string { return "calendar_feed_tokens" }
+
+type FeedRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedRepository(db *gorm.DB) *FeedRepository {
+	return &FeedRepository{db: db}
+}
+
+func (r *FeedRepository) Get(ctx context.Context, doctorUUID string) (*pb.CalendarFeed, error) {
+	var rec feedRecord
+	if err := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting calendar feed: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting calendar feed: %w", err)
+	}
+	return &pb.CalendarFeed{CreatedAt: timestamppb.New(rec.CreatedAt)}, nil
+}
+
+func (r *FeedRepository) GetByTokenHash(ctx context.Context, tokenSHA256 string) (string, error) {
+	var rec feedRecord
+	if err := r.db.WithContext(ctx).Where("token_sha256 = ?", tokenSHA256).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("getting calendar feed by token: %w", re.ErrNotFound)
+		}
+		return "", fmt.Errorf("getting calendar feed by token: %w", err)
+	}
+	return rec.DoctorUuid, nil
+}
+
+func (r *FeedRepository) Save(ctx context.Context, doctorUUID, tokenSHA256 string, createdAt time.Time) error {
+	rec := feedRecord{DoctorUuid: doctorUUID, TokenHash: tokenSHA256, CreatedAt: createdAt}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "doctor_uuid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_sha256", "created_at"}),
+	}).Create(&rec).Error
+	if err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return fmt.Errorf("saving calendar feed: %w", re.ErrNotFound)
+		}
+		return fmt.Errorf("saving calendar feed: %w", err)
+	}
+	return nil
+}
+
+func (r *FeedRepository) Delete(ctx context.Context, doctorUUID string) error {
+	res := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID).Delete(&feedRecord{})
+	if res.Error != nil {
+		return fmt.Errorf("deleting calendar feed: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("deleting calendar feed: %w", re.ErrNotFound)
+	}
+	return nil
+}
+
+var _ out.FeedRepository = (*FeedRepository)(nil)
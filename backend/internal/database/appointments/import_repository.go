@@ -0,0 +1,222 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/appointments"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"github.com/lib/pq"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// importReviewRecord maps appointment_import_reviews; exdates are RFC3339 UTC strings.
+type importReviewRecord struct {
+	Uuid            string         `gorm:"column:uuid;primaryKey"`
+	DoctorUuid      string         `gorm:"column:doctor_uuid"`
+	ExternalUid     string         `gorm:"column:external_uid"`
+	Summary         string         `gorm:"column:summary"`
+	StartsAt        time.Time      `gorm:"column:starts_at"`
+	EndsAt          time.Time      `gorm:"column:ends_at"`
+	Rrule           string         `gorm:"column:rrule"`
+	Exdates         pq.StringArray `gorm:"column:exdates;type:text[]"`
+	Timezone        string         `gorm:"column:timezone"`
+	Reason          string         `gorm:"column:reason"`
+	Status          string         `gorm:"column:status"`
+	AppointmentUuid *string        `gorm:"column:appointment_uuid"`
+	SeriesUuid      *string        `gorm:"column:series_uuid"`
+	CreatedAt       time.Time      `gorm:"column:created_at"`
+	ResolvedAt      *time.Time     `gorm:"column:resolved_at"`
+}
+
+func (importReviewRecord) TableName() string { return "appointment_import_reviews" }
+
+type ImportRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+// ImportedUIDs returns every external UID the doctor already has as appointment, series or review.
+func (r *ImportRepository) ImportedUIDs(ctx context.Context, doctorUUID string) (map[string]bool, error) {
+	var uids []string
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT external_uid FROM appointments WHERE doctor_uuid = ? AND external_uid IS NOT NULL
+		UNION SELECT external_uid FROM appointment_series WHERE doctor_uuid = ? AND external_uid IS NOT NULL
+		UNION SELECT external_uid FROM appointment_import_reviews WHERE doctor_uuid = ?`,
+		doctorUUID, doctorUUID, doctorUUID).Scan(&uids).Error
+	if err != nil {
+		return nil, fmt.Errorf("listing imported uids: %w", err)
+	}
+	res := make(map[string]bool, len(uids))
+	for _, uid := range uids {
+		res[uid] = true
+	}
+	return res, nil
+}
+
+func (r *ImportRepository) SeriesByExternalUID(ctx context.Context, doctorUUID, uid string) (*pb.AppointmentSeries, error) {
+	var rec seriesRecord
+	if err := r.db.WithContext(ctx).Where("doctor_uuid = ? AND external_uid = ?", doctorUUID, uid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting series by external uid: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting series by external uid: %w", err)
+	}
+	return seriesRecordToPB(rec), nil
+}
+
+// SaveImport inserts a whole import in one transaction; series go first because the
+// appointments of their cancelled and changed occurrences reference them.
+func (r *ImportRepository) SaveImport(ctx context.Context, doctorUUID string, series []*pb.AppointmentSeries, appointments []*pb.Appointment, reviews []*pb.ImportReview) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, s := range series {
+			rec := pbToSeriesRecord(s)
+			if err := tx.Create(&rec).Error; err != nil {
+				return err
+			}
+		}
+		for _, a := range appointments {
+			rec := pbToRecord(a)
+			if err := tx.Create(&rec).Error; err != nil {
+				return err
+			}
+		}
+		for _, rv := range reviews {
+			rec := pbToReviewRecord(rv, doctorUUID)
+			if err := tx.Create(&rec).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return fmt.Errorf("saving import: %w", re.ErrNotFound)
+		}
+		if dbErrs.IsUniqueViolation(err) {
+			return fmt.Errorf("saving import: %w", re.ErrConflict)
+		}
+		return fmt.Errorf("saving import: %w", err)
+	}
+	return nil
+}
+
+// GetReview returns a review of the doctor; reviews of other doctors are not found.
+func (r *ImportRepository) GetReview(ctx context.Context, doctorUUID, uuid string) (*pb.ImportReview, error) {
+	var rec importReviewRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ? AND doctor_uuid = ?", uuid, doctorUUID).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting import review: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting import review: %w", err)
+	}
+	return reviewRecordToPB(rec), nil
+}
+
+// UpdateReviewStatus stores the outcome of a review (status, created appointment/series, resolved_at).
+func (r *ImportRepository) UpdateReviewStatus(ctx context.Context, rv *pb.ImportReview, doctorUUID string) (*pb.ImportReview, error) {
+	rec := pbToReviewRecord(rv, doctorUUID)
+	res := r.db.WithContext(ctx).Model(&importReviewRecord{}).
+		Where("uuid = ? AND doctor_uuid = ?", rv.GetUuid(), doctorUUID).
+		Updates(map[string]interface{}{
+			"status":           rec.Status,
+			"appointment_uuid": rec.AppointmentUuid,
+			"series_uuid":      rec.SeriesUuid,
+			"resolved_at":      rec.ResolvedAt,
+		})
+	if res.Error != nil {
+		return nil, fmt.Errorf("updating import review: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("updating import review: %w", re.ErrNotFound)
+	}
+	return reviewRecordToPB(rec), nil
+}
+
+func (r *ImportRepository) ListReviews(ctx context.Context, doctorUUID, status string) ([]*pb.ImportReview, error) {
+	var recs []importReviewRecord
+	q := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID)
+	if strings.TrimSpace(status) != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Order("starts_at ASC").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing import reviews: %w", err)
+	}
+	res := make([]*pb.ImportReview, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, reviewRecordToPB(rec))
+	}
+	return res, nil
+}
+
+func reviewRecordToPB(rec importReviewRecord) *pb.ImportReview {
+	rv := &pb.ImportReview{
+		Uuid:        rec.Uuid,
+		ExternalUid: rec.ExternalUid,
+		Summary:     rec.Summary,
+		StartsAt:    timestamppb.New(rec.StartsAt),
+		EndsAt:      timestamppb.New(rec.EndsAt),
+		Rrule:       rec.Rrule,
+		Timezone:    rec.Timezone,
+		Reason:      rec.Reason,
+		Status:      rec.Status,
+		CreatedAt:   timestamppb.New(rec.CreatedAt),
+	}
+	for _, v := range rec.Exdates {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			rv.Exdates = append(rv.Exdates, timestamppb.New(t))
+		}
+	}
+	if rec.AppointmentUuid != nil {
+		rv.AppointmentUuid = *rec.AppointmentUuid
+	}
+	if rec.SeriesUuid != nil {
+		rv.SeriesUuid = *rec.SeriesUuid
+	}
+	if rec.ResolvedAt != nil {
+		rv.ResolvedAt = timestamppb.New(*rec.ResolvedAt)
+	}
+	return rv
+}
+
+func pbToReviewRecord(rv *pb.ImportReview, doctorUUID string) importReviewRecord {
+	rec := importReviewRecord{
+		Uuid:        rv.GetUuid(),
+		DoctorUuid:  doctorUUID,
+		ExternalUid: rv.GetExternalUid(),
+		Summary:     rv.GetSummary(),
+		StartsAt:    rv.GetStartsAt().AsTime(),
+		EndsAt:      rv.GetEndsAt().AsTime(),
+		Rrule:       rv.GetRrule(),
+		Exdates:     pq.StringArray{},
+		Timezone:    rv.GetTimezone(),
+		Reason:      rv.GetReason(),
+		Status:      rv.GetStatus(),
+		CreatedAt:   rv.GetCreatedAt().AsTime(),
+	}
+	for _, t := range rv.GetExdates() {
+		rec.Exdates = append(rec.Exdates, t.AsTime().UTC().Format(time.RFC3339))
+	}
+	if v := rv.GetAppointmentUuid(); v != "" {
+		rec.AppointmentUuid = &v
+	}
+	if v := rv.GetSeriesUuid(); v != "" {
+		rec.SeriesUuid = &v
+	}
+	if rv.GetResolvedAt() != nil {
+		t := rv.GetResolvedAt().AsTime()
+		rec.ResolvedAt = &t
+	}
+	return rec
+}
+
+var _ out.ImportRepository = (*ImportRepository)(nil)
@@ -0,0 +1,203 @@
+package appointments
+
+import (
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	"github.com/lib/pq"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// appointmentRecord maps the appointments table (nullable anamnesis link and timestamps).
+type appointmentRecord struct {
+	Uuid          string     `gorm:"column:uuid;primaryKey"`
+	DoctorUuid    string     `gorm:"column:doctor_uuid"`
+	PatientUuid   string     `gorm:"column:patient_uuid"`
+	AnamnesisUuid *string    `gorm:"column:anamnesis_uuid"`
+	StartsAt      time.Time  `gorm:"column:starts_at"`
+	EndsAt        time.Time  `gorm:"column:ends_at"`
+	Status        string     `gorm:"column:status"`
+	Note          string     `gorm:"column:note"`
+	CancelReason  string     `gorm:"column:cancel_reason"`
+	CancelledAt   *time.Time `gorm:"column:cancelled_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at"`
+	UpdatedAt     *time.Time `gorm:"column:updated_at"`
+	// Exceptions of a recurring series.
+	SeriesUuid       *string        `gorm:"column:series_uuid"`
+	OriginalStartsAt *time.Time     `gorm:"column:original_starts_at"`
+	ExternalUid      *string        `gorm:"column:external_uid"`
+	Outcome          *string        `gorm:"column:outcome"`
+	OutcomeAt        *time.Time     `gorm:"column:outcome_at"`
+	OutcomeReason    string         `gorm:"column:outcome_reason"`
+	ResourceUuids    pq.StringArray `gorm:"column:resource_uuids;type:text[]"`
+}
+
+func (appointmentRecord) TableName() string { return "appointments" }
+
+func recordToPB(rec appointmentRecord) *pb.Appointment {
+	a := &pb.Appointment{
+		Uuid:          rec.Uuid,
+		DoctorUuid:    rec.DoctorUuid,
+		PatientUuid:   rec.PatientUuid,
+		StartsAt:      timestamppb.New(rec.StartsAt),
+		EndsAt:        timestamppb.New(rec.EndsAt),
+		Status:        rec.Status,
+		Note:          rec.Note,
+		CancelReason:  rec.CancelReason,
+		CreatedAt:     timestamppb.New(rec.CreatedAt),
+		OutcomeReason: rec.OutcomeReason,
+		ResourceUuids: []string(rec.ResourceUuids),
+	}
+	if rec.AnamnesisUuid != nil {
+		a.AnamnesisUuid = *rec.AnamnesisUuid
+	}
+	if rec.CancelledAt != nil {
+		a.CancelledAt = timestamppb.New(*rec.CancelledAt)
+	}
+	if rec.UpdatedAt != nil {
+		a.UpdatedAt = timestamppb.New(*rec.UpdatedAt)
+	}
+	if rec.SeriesUuid != nil {
+		a.SeriesUuid = *rec.SeriesUuid
+	}
+	if rec.OriginalStartsAt != nil {
+		a.OriginalStartsAt = timestamppb.New(*rec.OriginalStartsAt)
+	}
+	if rec.ExternalUid != nil {
+		a.ExternalUid = *rec.ExternalUid
+	}
+	if rec.Outcome != nil {
+		a.Outcome = *rec.Outcome
+	}
+	if rec.OutcomeAt != nil {
+		a.OutcomeAt = timestamppb.New(*rec.OutcomeAt)
+	}
+	return a
+}
+
+func pbToRecord(a *pb.Appointment) appointmentRecord {
+	rec := appointmentRecord{
+		Uuid:          a.GetUuid(),
+		DoctorUuid:    a.GetDoctorUuid(),
+		PatientUuid:   a.GetPatientUuid(),
+		Status:        a.GetStatus(),
+		Note:          a.GetNote(),
+		CancelReason:  a.GetCancelReason(),
+		OutcomeReason: a.GetOutcomeReason(),
+		ResourceUuids: pq.StringArray(a.GetResourceUuids()),
+	}
+	if rec.ResourceUuids == nil {
+		rec.ResourceUuids = pq.StringArray{}
+	}
+	if v := a.GetAnamnesisUuid(); v != "" {
+		rec.AnamnesisUuid = &v
+	}
+	if a.GetStartsAt() != nil {
+		rec.StartsAt = a.GetStartsAt().AsTime()
+	}
+	if a.GetEndsAt() != nil {
+		rec.EndsAt = a.GetEndsAt().AsTime()
+	}
+	if a.GetCancelledAt() != nil {
+		t := a.GetCancelledAt().AsTime()
+		rec.CancelledAt = &t
+	}
+	if a.GetCreatedAt() != nil {
+		rec.CreatedAt = a.GetCreatedAt().AsTime()
+	}
+	if a.GetUpdatedAt() != nil {
+		t := a.GetUpdatedAt().AsTime()
+		rec.UpdatedAt = &t
+	}
+	if v := a.GetSeriesUuid(); v != "" {
+		rec.SeriesUuid = &v
+	}
+	if a.GetOriginalStartsAt() != nil {
+		t := a.GetOriginalStartsAt().AsTime()
+		rec.OriginalStartsAt = &t
+	}
+	if v := a.GetExternalUid(); v != "" {
+		rec.ExternalUid = &v
+	}
+	if v := a.GetOutcome(); v != "" {
+		rec.Outcome = &v
+	}
+	if a.GetOutcomeAt() != nil {
+		t := a.GetOutcomeAt().AsTime()
+		rec.OutcomeAt = &t
+	}
+	return rec
+}
+
+// seriesRecord maps the appointment_series table.
+type seriesRecord struct {
+	Uuid            string         `gorm:"column:uuid;primaryKey"`
+	DoctorUuid      string         `gorm:"column:doctor_uuid"`
+	PatientUuid     string         `gorm:"column:patient_uuid"`
+	Rrule           string         `gorm:"column:rrule"`
+	StartsAt        time.Time      `gorm:"column:starts_at"`
+	DurationMinutes int32          `gorm:"column:duration_minutes"`
+	Timezone        string         `gorm:"column:timezone"`
+	Note            string         `gorm:"column:note"`
+	Status          string         `gorm:"column:status"`
+	CreatedAt       time.Time      `gorm:"column:created_at"`
+	UpdatedAt       *time.Time     `gorm:"column:updated_at"`
+	ExternalUid     *string        `gorm:"column:external_uid"`
+	ResourceUuids   pq.StringArray `gorm:"column:resource_uuids;type:text[]"`
+}
+
+func (seriesRecord) TableName() string { return "appointment_series" }
+
+func seriesRecordToPB(rec seriesRecord) *pb.AppointmentSeries {
+	s := &pb.AppointmentSeries{
+		Uuid:            rec.Uuid,
+		DoctorUuid:      rec.DoctorUuid,
+		PatientUuid:     rec.PatientUuid,
+		Rrule:           rec.Rrule,
+		StartsAt:        timestamppb.New(rec.StartsAt),
+		DurationMinutes: rec.DurationMinutes,
+		Timezone:        rec.Timezone,
+		Note:            rec.Note,
+		Status:          rec.Status,
+		CreatedAt:       timestamppb.New(rec.CreatedAt),
+		ResourceUuids:   []string(rec.ResourceUuids),
+	}
+	if rec.UpdatedAt != nil {
+		s.UpdatedAt = timestamppb.New(*rec.UpdatedAt)
+	}
+	if rec.ExternalUid != nil {
+		s.ExternalUid = *rec.ExternalUid
+	}
+	return s
+}
+
+func pbToSeriesRecord(s *pb.AppointmentSeries) seriesRecord {
+	rec := seriesRecord{
+		Uuid:            s.GetUuid(),
+		DoctorUuid:      s.GetDoctorUuid(),
+		PatientUuid:     s.GetPatientUuid(),
+		Rrule:           s.GetRrule(),
+		DurationMinutes: s.GetDurationMinutes(),
+		Timezone:        s.GetTimezone(),
+		Note:            s.GetNote(),
+		Status:          s.GetStatus(),
+		ResourceUuids:   pq.StringArray(s.GetResourceUuids()),
+	}
+	if rec.ResourceUuids == nil {
+		rec.ResourceUuids = pq.StringArray{}
+	}
+	if s.GetStartsAt() != nil {
+		rec.StartsAt = s.GetStartsAt().AsTime()
+	}
+	if s.GetCreatedAt() != nil {
+		rec.CreatedAt = s.GetCreatedAt().AsTime()
+	}
+	if s.GetUpdatedAt() != nil {
+		t := s.GetUpdatedAt().AsTime()
+		rec.UpdatedAt = &t
+	}
+	if v := s.GetExternalUid(); v != "" {
+		rec.ExternalUid = &v
+	}
+	return rec
+}
@@ -0,0 +1,135 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/appointments"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+type resourceRecord struct {
+	Uuid       string     `gorm:"column:uuid;primaryKey"`
+	DoctorUuid string     `gorm:"column:doctor_uuid"`
+	Name       string     `gorm:"column:name"`
+	Kind       string     `gorm:"column:kind"`
+	Note       string     `gorm:"column:note"`
+	Active     bool       `gorm:"column:active"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	UpdatedAt  *time.Time `gorm:"column:updated_at"`
+}
+
+func (resourceRecord) TableName() string { return "resources" }
+
+type ResourceRepository struct {
+	db *gorm.DB
+}
+
+func NewResourceRepository(db *gorm.DB) *ResourceRepository {
+	return &ResourceRepository{db: db}
+}
+
+func (r *ResourceRepository) Create(ctx context.Context, res *pb.Resource) (*pb.Resource, error) {
+	rec := pbToResourceRecord(res)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("creating resource: %w", re.ErrConflict)
+		}
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating resource: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating resource: %w", err)
+	}
+	return resourceRecordToPB(rec), nil
+}
+
+func (r *ResourceRepository) Update(ctx context.Context, res *pb.Resource) (*pb.Resource, error) {
+	rec := pbToResourceRecord(res)
+	result := r.db.WithContext(ctx).Model(&resourceRecord{}).
+		Where("uuid = ?", res.GetUuid()).
+		Updates(map[string]interface{}{
+			"name":       rec.Name,
+			"kind":       rec.Kind,
+			"note":       rec.Note,
+			"active":     rec.Active,
+			"updated_at": rec.UpdatedAt,
+		})
+	if result.Error != nil {
+		if dbErrs.IsUniqueViolation(result.Error) {
+			return nil, fmt.Errorf("updating resource: %w", re.ErrConflict)
+		}
+		return nil, fmt.Errorf("updating resource: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("updating resource: %w", re.ErrNotFound)
+	}
+	return resourceRecordToPB(rec), nil
+}
+
+func (r *ResourceRepository) Get(ctx context.Context, uuid string) (*pb.Resource, error) {
+	var rec resourceRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting resource: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting resource: %w", err)
+	}
+	return resourceRecordToPB(rec), nil
+}
+
+func (r *ResourceRepository) List(ctx context.Context, doctorUUID string, activeOnly bool) ([]*pb.Resource, error) {
+	var recs []resourceRecord
+	q := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID)
+	if activeOnly {
+		q = q.Where("active")
+	}
+	if err := q.Order("kind ASC, LOWER(name) ASC").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing resources: %w", err)
+	}
+	res := make([]*pb.Resource, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, resourceRecordToPB(rec))
+	}
+	return res, nil
+}
+
+func resourceRecordToPB(rec resourceRecord) *pb.Resource {
+	res := &pb.Resource{
+		Uuid:       rec.Uuid,
+		DoctorUuid: rec.DoctorUuid,
+		Name:       rec.Name,
+		Kind:       rec.Kind,
+		Note:       rec.Note,
+		Active:     rec.Active,
+		CreatedAt:  timestamppb.New(rec.CreatedAt),
+	}
+	if rec.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*rec.UpdatedAt)
+	}
+	return res
+}
+
+func pbToResourceRecord(res *pb.Resource) resourceRecord {
+	rec := resourceRecord{
+		Uuid:       res.GetUuid(),
+		DoctorUuid: res.GetDoctorUuid(),
+		Name:       res.GetName(),
+		Kind:       res.GetKind(),
+		Note:       res.GetNote(),
+		Active:     res.GetActive(),
+		CreatedAt:  res.GetCreatedAt().AsTime(),
+	}
+	if res.GetUpdatedAt() != nil {
+		t := res.GetUpdatedAt().AsTime()
+		rec.UpdatedAt = &t
+	}
+	return rec
+}
+
+var _ out.ResourceRepository = (*ResourceRepository)(nil)
@@ -0,0 +1,178 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/appointments"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"gorm.io/gorm"
+)
+
+type SeriesRepository struct {
+	db *gorm.DB
+}
+
+func NewSeriesRepository(db *gorm.DB) *SeriesRepository {
+	return &SeriesRepository{db: db}
+}
+
+func (r *SeriesRepository) Create(ctx context.Context, s *pb.AppointmentSeries) (*pb.AppointmentSeries, error) {
+	rec := pbToSeriesRecord(s)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating appointment series: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating appointment series: insert: %w", err)
+	}
+	return seriesRecordToPB(rec), nil
+}
+
+func (r *SeriesRepository) Update(ctx context.Context, s *pb.AppointmentSeries) (*pb.AppointmentSeries, error) {
+	rec := pbToSeriesRecord(s)
+	res := r.db.WithContext(ctx).Model(&seriesRecord{}).Where("uuid = ?", s.GetUuid()).Updates(seriesUpdates(rec))
+	if res.Error != nil {
+		return nil, fmt.Errorf("updating appointment series: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("updating appointment series: %w", re.ErrNotFound)
+	}
+	return seriesRecordToPB(rec), nil
+}
+
+func (r *SeriesRepository) Get(ctx context.Context, uuid string) (*pb.AppointmentSeries, error) {
+	var rec seriesRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting appointment series: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting appointment series: %w", err)
+	}
+	return seriesRecordToPB(rec), nil
+}
+
+// ListActive returns active series of the doctor that start before `to`; the service
+// decides from the rule whether any occurrence falls in the requested range.
+func (r *SeriesRepository) ListActive(ctx context.Context, doctorUUID, patientUUID string, to time.Time) ([]*pb.AppointmentSeries, error) {
+	var recs []seriesRecord
+	q := r.db.WithContext(ctx).Model(&seriesRecord{}).
+		Where("doctor_uuid = ? AND status = ?", doctorUUID, "active").
+		Where("starts_at < ?", to)
+	if strings.TrimSpace(patientUUID) != "" {
+		q = q.Where("patient_uuid = ?", patientUUID)
+	}
+	if err := q.Order("starts_at ASC").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing appointment series: %w", err)
+	}
+	return seriesRecordsToPB(recs), nil
+}
+
+func (r *SeriesRepository) ListByPatient(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.AppointmentSeries, error) {
+	var recs []seriesRecord
+	if err := r.db.WithContext(ctx).
+		Where("doctor_uuid = ? AND patient_uuid = ?", doctorUUID, patientUUID).
+		Order("starts_at DESC").
+		Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing appointment series by patient: %w", err)
+	}
+	return seriesRecordsToPB(recs), nil
+}
+
+// Split truncates `old` and inserts `next` in one transaction. Stored exceptions of the old
+// series that the service mapped onto the new series (`carried`) are re-pointed to it, so moved,
+// cancelled and linked occurrences keep their state. Nothing is deleted.
+func (r *SeriesRepository) Split(ctx context.Context, old, next *pb.AppointmentSeries, carried []*pb.Appointment) (*pb.AppointmentSeries, error) {
+	oldRec := pbToSeriesRecord(old)
+	nextRec := pbToSeriesRecord(next)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&seriesRecord{}).Where("uuid = ?", old.GetUuid()).Updates(seriesUpdates(oldRec))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return re.ErrNotFound
+		}
+		if err := tx.Create(&nextRec).Error; err != nil {
+			return err
+		}
+		for _, a := range carried {
+			rec := pbToRecord(a)
+			res := tx.Model(&appointmentRecord{}).
+				Where("uuid = ? AND series_uuid = ?", a.GetUuid(), old.GetUuid()).
+				Updates(map[string]interface{}{
+					"series_uuid":        rec.SeriesUuid,
+					"original_starts_at": rec.OriginalStartsAt,
+					"starts_at":          rec.StartsAt,
+					"ends_at":            rec.EndsAt,
+					"updated_at":         rec.UpdatedAt,
+				})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return re.ErrNotFound
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("splitting appointment series: %w", err)
+	}
+	return seriesRecordToPB(nextRec), nil
+}
+
+// Truncate updates the series (shortened rule or cancelled status) and cancels stored
+// exceptions from `from` onwards in one transaction.
+func (r *SeriesRepository) Truncate(ctx context.Context, s *pb.AppointmentSeries, from time.Time, reason string) (*pb.AppointmentSeries, error) {
+	rec := pbToSeriesRecord(s)
+	now := time.Now().UTC()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&seriesRecord{}).Where("uuid = ?", s.GetUuid()).Updates(seriesUpdates(rec))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return re.ErrNotFound
+		}
+		return tx.Model(&appointmentRecord{}).
+			Where("series_uuid = ? AND original_starts_at >= ? AND status <> ?", s.GetUuid(), from, "cancelled").
+			Updates(map[string]interface{}{
+				"status":        "cancelled",
+				"cancel_reason": reason,
+				"cancelled_at":  now,
+				"updated_at":    now,
+			}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("truncating appointment series: %w", err)
+	}
+	return seriesRecordToPB(rec), nil
+}
+
+func seriesUpdates(rec seriesRecord) map[string]interface{} {
+	return map[string]interface{}{
+		"rrule":            rec.Rrule,
+		"starts_at":        rec.StartsAt,
+		"duration_minutes": rec.DurationMinutes,
+		"timezone":         rec.Timezone,
+		"note":             rec.Note,
+		"status":           rec.Status,
+		"updated_at":       rec.UpdatedAt,
+		"resource_uuids":   rec.ResourceUuids,
+	}
+}
+
+func seriesRecordsToPB(recs []seriesRecord) []*pb.AppointmentSeries {
+	res := make([]*pb.AppointmentSeries, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, seriesRecordToPB(rec))
+	}
+	return res
+}
+
+var _ out.SeriesRepository = (*SeriesRepository)(nil)
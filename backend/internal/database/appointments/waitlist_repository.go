@@ -0,0 +1,364 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/appointments"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"github.com/lib/pq"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+type waitlistEntryRecord struct {
+	Uuid            string        `gorm:"column:uuid;primaryKey"`
+	DoctorUuid      string        `gorm:"column:doctor_uuid"`
+	PatientUuid     string        `gorm:"column:patient_uuid"`
+	WindowFrom      time.Time     `gorm:"column:window_from"`
+	WindowTo        time.Time     `gorm:"column:window_to"`
+	DayStart        string        `gorm:"column:day_start"`
+	DayEnd          string        `gorm:"column:day_end"`
+	Weekdays        pq.Int32Array `gorm:"column:weekdays;type:integer[]"`
+	DurationMinutes int32         `gorm:"column:duration_minutes"`
+	Priority        int32         `gorm:"column:priority"`
+	Status          string        `gorm:"column:status"`
+	Note            string        `gorm:"column:note"`
+	CreatedAt       time.Time     `gorm:"column:created_at"`
+	UpdatedAt       *time.Time    `gorm:"column:updated_at"`
+}
+
+func (waitlistEntryRecord) TableName() string { return "waitlist_entries" }
+
+type waitlistOfferRecord struct {
+	Uuid            string     `gorm:"column:uuid;primaryKey"`
+	DoctorUuid      string     `gorm:"column:doctor_uuid"`
+	EntryUuid       string     `gorm:"column:entry_uuid"`
+	PatientUuid     string     `gorm:"column:patient_uuid"`
+	StartsAt        time.Time  `gorm:"column:starts_at"`
+	EndsAt          time.Time  `gorm:"column:ends_at"`
+	SlotEndsAt      time.Time  `gorm:"column:slot_ends_at"`
+	Status          string     `gorm:"column:status"`
+	ExpiresAt       time.Time  `gorm:"column:expires_at"`
+	AppointmentUuid *string    `gorm:"column:appointment_uuid"`
+	CreatedAt       time.Time  `gorm:"column:created_at"`
+	RespondedAt     *time.Time `gorm:"column:responded_at"`
+}
+
+func (waitlistOfferRecord) TableName() string { return "waitlist_offers" }
+
+type WaitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+func (r *WaitlistRepository) CreateEntry(ctx context.Context, e *pb.WaitlistEntry) (*pb.WaitlistEntry, error) {
+	rec := pbToEntryRecord(e)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating waitlist entry: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating waitlist entry: %w", err)
+	}
+	return entryRecordToPB(rec), nil
+}
+
+// UpdateEntryStatus moves an entry to status; only entries currently in one of from are changed.
+func (r *WaitlistRepository) UpdateEntryStatus(ctx context.Context, uuid, status string, from []string, at time.Time) error {
+	res := r.db.WithContext(ctx).Model(&waitlistEntryRecord{}).
+		Where("uuid = ? AND status IN ?", uuid, from).
+		Updates(map[string]interface{}{"status": status, "updated_at": at})
+	if res.Error != nil {
+		return fmt.Errorf("updating waitlist entry: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("updating waitlist entry: %w", re.ErrConflict)
+	}
+	return nil
+}
+
+func (r *WaitlistRepository) GetEntry(ctx context.Context, uuid string) (*pb.WaitlistEntry, error) {
+	var rec waitlistEntryRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting waitlist entry: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting waitlist entry: %w", err)
+	}
+	return entryRecordToPB(rec), nil
+}
+
+func (r *WaitlistRepository) ListEntries(ctx context.Context, doctorUUID, status string) ([]*pb.WaitlistEntry, error) {
+	var recs []waitlistEntryRecord
+	q := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID)
+	if strings.TrimSpace(status) != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Order("priority DESC, created_at ASC").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing waitlist entries: %w", err)
+	}
+	return entryRecordsToPB(recs), nil
+}
+
+// Candidates returns waiting entries whose window covers [start, end) and whose duration fits,
+// best first: highest priority, then the longest waiting.
+func (r *WaitlistRepository) Candidates(ctx context.Context, doctorUUID string, start, end time.Time) ([]*pb.WaitlistEntry, error) {
+	var recs []waitlistEntryRecord
+	err := r.db.WithContext(ctx).
+		Where("doctor_uuid = ? AND status = ?", doctorUUID, "waiting").
+		Where("window_from <= ? AND window_to >= ?", start, end).
+		Where("duration_minutes <= ?", int(end.Sub(start)/time.Minute)).
+		Order("priority DESC, created_at ASC").
+		Find(&recs).Error
+	if err != nil {
+		return nil, fmt.Errorf("listing waitlist candidates: %w", err)
+	}
+	return entryRecordsToPB(recs), nil
+}
+
+func (r *WaitlistRepository) CreateOffer(ctx context.Context, o *pb.WaitlistOffer) (*pb.WaitlistOffer, error) {
+	rec := pbToOfferRecord(o)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("creating waitlist offer: %w", re.ErrConflict)
+		}
+		return nil, fmt.Errorf("creating waitlist offer: %w", err)
+	}
+	return offerRecordToPB(rec), nil
+}
+
+// CloseOffer stores the response to a pending offer. It fails with ErrConflict when the offer is
+// no longer pending, so an accept racing the expiry worker is decided by the database.
+func (r *WaitlistRepository) CloseOffer(ctx context.Context, o *pb.WaitlistOffer) (*pb.WaitlistOffer, error) {
+	rec := pbToOfferRecord(o)
+	res := r.db.WithContext(ctx).Model(&waitlistOfferRecord{}).
+		Where("uuid = ? AND status = ?", o.GetUuid(), "pending").
+		Updates(map[string]interface{}{
+			"status":           rec.Status,
+			"appointment_uuid": rec.AppointmentUuid,
+			"responded_at":     rec.RespondedAt,
+		})
+	if res.Error != nil {
+		return nil, fmt.Errorf("closing waitlist offer: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("closing waitlist offer: %w", re.ErrConflict)
+	}
+	return offerRecordToPB(rec), nil
+}
+
+// AcceptOffer books the appointment and closes the pending offer with it in one transaction, so an
+// accepted offer always has its appointment. It fails with ErrConflict when the offer is no longer
+// pending.
+func (r *WaitlistRepository) AcceptOffer(ctx context.Context, o *pb.WaitlistOffer, a *pb.Appointment) (*pb.WaitlistOffer, *pb.Appointment, error) {
+	apptRec := pbToRecord(a)
+	rec := pbToOfferRecord(o)
+	rec.AppointmentUuid = &apptRec.Uuid
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&apptRec).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&waitlistOfferRecord{}).
+			Where("uuid = ? AND status = ?", o.GetUuid(), "pending").
+			Updates(map[string]interface{}{
+				"status":           rec.Status,
+				"appointment_uuid": rec.AppointmentUuid,
+				"responded_at":     rec.RespondedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return re.ErrConflict
+		}
+		return nil
+	})
+	if err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, nil, fmt.Errorf("accepting waitlist offer: %w", re.ErrNotFound)
+		}
+		return nil, nil, fmt.Errorf("accepting waitlist offer: %w", err)
+	}
+	return offerRecordToPB(rec), recordToPB(apptRec), nil
+}
+
+func (r *WaitlistRepository) GetOffer(ctx context.Context, uuid string) (*pb.WaitlistOffer, error) {
+	var rec waitlistOfferRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting waitlist offer: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting waitlist offer: %w", err)
+	}
+	return offerRecordToPB(rec), nil
+}
+
+func (r *WaitlistRepository) ListOffers(ctx context.Context, doctorUUID, status string) ([]*pb.WaitlistOffer, error) {
+	var recs []waitlistOfferRecord
+	q := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID)
+	if strings.TrimSpace(status) != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Order("created_at DESC").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing waitlist offers: %w", err)
+	}
+	return offerRecordsToPB(recs), nil
+}
+
+// PendingOfferForEntry returns the open offer of an entry, if any.
+func (r *WaitlistRepository) PendingOfferForEntry(ctx context.Context, entryUUID string) (*pb.WaitlistOffer, error) {
+	var rec waitlistOfferRecord
+	if err := r.db.WithContext(ctx).Where("entry_uuid = ? AND status = ?", entryUUID, "pending").First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting pending waitlist offer: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting pending waitlist offer: %w", err)
+	}
+	return offerRecordToPB(rec), nil
+}
+
+// ListExpiredOffers returns pending offers of all doctors that expired at or before now.
+func (r *WaitlistRepository) ListExpiredOffers(ctx context.Context, now time.Time) ([]*pb.WaitlistOffer, error) {
+	var recs []waitlistOfferRecord
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at <= ?", "pending", now).
+		Order("expires_at ASC").
+		Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing expired waitlist offers: %w", err)
+	}
+	return offerRecordsToPB(recs), nil
+}
+
+// OfferedEntries returns the entries that were already offered the slot starting at start.
+func (r *WaitlistRepository) OfferedEntries(ctx context.Context, doctorUUID string, start time.Time) (map[string]bool, error) {
+	var uuids []string
+	if err := r.db.WithContext(ctx).Model(&waitlistOfferRecord{}).
+		Where("doctor_uuid = ? AND starts_at = ?", doctorUUID, start).
+		Pluck("entry_uuid", &uuids).Error; err != nil {
+		return nil, fmt.Errorf("listing offered entries: %w", err)
+	}
+	res := make(map[string]bool, len(uuids))
+	for _, id := range uuids {
+		res[id] = true
+	}
+	return res, nil
+}
+
+func entryRecordToPB(rec waitlistEntryRecord) *pb.WaitlistEntry {
+	e := &pb.WaitlistEntry{
+		Uuid:            rec.Uuid,
+		DoctorUuid:      rec.DoctorUuid,
+		PatientUuid:     rec.PatientUuid,
+		WindowFrom:      timestamppb.New(rec.WindowFrom),
+		WindowTo:        timestamppb.New(rec.WindowTo),
+		DayStart:        rec.DayStart,
+		DayEnd:          rec.DayEnd,
+		Weekdays:        []int32(rec.Weekdays),
+		DurationMinutes: rec.DurationMinutes,
+		Priority:        rec.Priority,
+		Status:          rec.Status,
+		Note:            rec.Note,
+		CreatedAt:       timestamppb.New(rec.CreatedAt),
+	}
+	if rec.UpdatedAt != nil {
+		e.UpdatedAt = timestamppb.New(*rec.UpdatedAt)
+	}
+	return e
+}
+
+func entryRecordsToPB(recs []waitlistEntryRecord) []*pb.WaitlistEntry {
+	res := make([]*pb.WaitlistEntry, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, entryRecordToPB(rec))
+	}
+	return res
+}
+
+func pbToEntryRecord(e *pb.WaitlistEntry) waitlistEntryRecord {
+	rec := waitlistEntryRecord{
+		Uuid:            e.GetUuid(),
+		DoctorUuid:      e.GetDoctorUuid(),
+		PatientUuid:     e.GetPatientUuid(),
+		WindowFrom:      e.GetWindowFrom().AsTime(),
+		WindowTo:        e.GetWindowTo().AsTime(),
+		DayStart:        e.GetDayStart(),
+		DayEnd:          e.GetDayEnd(),
+		Weekdays:        pq.Int32Array(e.GetWeekdays()),
+		DurationMinutes: e.GetDurationMinutes(),
+		Priority:        e.GetPriority(),
+		Status:          e.GetStatus(),
+		Note:            e.GetNote(),
+		CreatedAt:       e.GetCreatedAt().AsTime(),
+	}
+	if rec.Weekdays == nil {
+		rec.Weekdays = pq.Int32Array{}
+	}
+	if e.GetUpdatedAt() != nil {
+		t := e.GetUpdatedAt().AsTime()
+		rec.UpdatedAt = &t
+	}
+	return rec
+}
+
+func offerRecordToPB(rec waitlistOfferRecord) *pb.WaitlistOffer {
+	o := &pb.WaitlistOffer{
+		Uuid:        rec.Uuid,
+		DoctorUuid:  rec.DoctorUuid,
+		EntryUuid:   rec.EntryUuid,
+		PatientUuid: rec.PatientUuid,
+		StartsAt:    timestamppb.New(rec.StartsAt),
+		EndsAt:      timestamppb.New(rec.EndsAt),
+		SlotEndsAt:  timestamppb.New(rec.SlotEndsAt),
+		Status:      rec.Status,
+		ExpiresAt:   timestamppb.New(rec.ExpiresAt),
+		CreatedAt:   timestamppb.New(rec.CreatedAt),
+	}
+	if rec.AppointmentUuid != nil {
+		o.AppointmentUuid = *rec.AppointmentUuid
+	}
+	if rec.RespondedAt != nil {
+		o.RespondedAt = timestamppb.New(*rec.RespondedAt)
+	}
+	return o
+}
+
+func offerRecordsToPB(recs []waitlistOfferRecord) []*pb.WaitlistOffer {
+	res := make([]*pb.WaitlistOffer, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, offerRecordToPB(rec))
+	}
+	return res
+}
+
+func pbToOfferRecord(o *pb.WaitlistOffer) waitlistOfferRecord {
+	rec := waitlistOfferRecord{
+		Uuid:        o.GetUuid(),
+		DoctorUuid:  o.GetDoctorUuid(),
+		EntryUuid:   o.GetEntryUuid(),
+		PatientUuid: o.GetPatientUuid(),
+		StartsAt:    o.GetStartsAt().AsTime(),
+		EndsAt:      o.GetEndsAt().AsTime(),
+		SlotEndsAt:  o.GetSlotEndsAt().AsTime(),
+		Status:      o.GetStatus(),
+		ExpiresAt:   o.GetExpiresAt().AsTime(),
+		CreatedAt:   o.GetCreatedAt().AsTime(),
+	}
+	if v := o.GetAppointmentUuid(); v != "" {
+		rec.AppointmentUuid = &v
+	}
+	if o.GetRespondedAt() != nil {
+		t := o.GetRespondedAt().AsTime()
+		rec.RespondedAt = &t
+	}
+	return rec
+}
+
+var _ out.WaitlistRepository = (*WaitlistRepository)(nil)
@@ -0,0 +1,146 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/attachments"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+type attachmentRecord struct {
+	Uuid          string    `gorm:"column:uuid;primaryKey"`
+	DoctorUuid    string    `gorm:"column:doctor_uuid"`
+	PatientUuid   string    `gorm:"column:patient_uuid"`
+	AnamnesisUuid *string   `gorm:"column:anamnesis_uuid"`
+	FileName      string    `gorm:"column:file_name"`
+	ContentType   string    `gorm:"column:content_type"`
+	SizeBytes     int64     `gorm:"column:size_bytes"`
+	Description   string    `gorm:"column:description"`
+	StorageKey    string    `gorm:"column:storage_key"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+func (attachmentRecord) TableName() string { return "attachments" }
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, a *pb.Attachment, storageKey string) (*pb.Attachment, error) {
+	rec := pbToRecord(a, storageKey)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating attachment: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating attachment: %w", err)
+	}
+	return recordToPB(rec), nil
+}
+
+func (r *Repository) Get(ctx context.Context, uuid string) (*pb.Attachment, string, error) {
+	var rec attachmentRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", fmt.Errorf("getting attachment: %w", re.ErrNotFound)
+		}
+		return nil, "", fmt.Errorf("getting attachment: %w", err)
+	}
+	return recordToPB(rec), rec.StorageKey, nil
+}
+
+func (r *Repository) List(ctx context.Context, patientUUID, anamnesisUUID string) ([]*pb.Attachment, error) {
+	q := r.db.WithContext(ctx).Where("patient_uuid = ?", patientUUID)
+	if anamnesisUUID != "" {
+		q = q.Where("anamnesis_uuid = ?", anamnesisUUID)
+	}
+	var recs []attachmentRecord
+	if err := q.Order("created_at DESC").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing attachments: %w", err)
+	}
+	res := make([]*pb.Attachment, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, recordToPB(rec))
+	}
+	return res, nil
+}
+
+// StorageKeys returns the content keys of the attachments of a doctor or of one patient; an empty
+// argument is not filtered on, and with both empty nothing is returned.
+func (r *Repository) StorageKeys(ctx context.Context, doctorUUID, patientUUID string) ([]string, error) {
+	if doctorUUID == "" && patientUUID == "" {
+		return nil, nil
+	}
+	q := r.db.WithContext(ctx).Model(&attachmentRecord{})
+	if doctorUUID != "" {
+		q = q.Where("doctor_uuid = ?", doctorUUID)
+	}
+	if patientUUID != "" {
+		q = q.Where("patient_uuid = ?", patientUUID)
+	}
+	var keys []string
+	if err := q.Pluck("storage_key", &keys).Error; err != nil {
+		return nil, fmt.Errorf("listing attachment keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, uuid string) error {
+	res := r.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&attachmentRecord{})
+	if res.Error != nil {
+		return fmt.Errorf("deleting attachment: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("deleting attachment: %w", re.ErrNotFound)
+	}
+	return nil
+}
+
+func pbToRecord(a *pb.Attachment, storageKey string) attachmentRecord {
+	rec := attachmentRecord{
+		Uuid:        a.GetUuid(),
+		DoctorUuid:  a.GetDoctorUuid(),
+		PatientUuid: a.GetPatientUuid(),
+		FileName:    a.GetFileName(),
+		ContentType: a.GetContentType(),
+		SizeBytes:   a.GetSizeBytes(),
+		Description: a.GetDescription(),
+		StorageKey:  storageKey,
+	}
+	if v := a.GetAnamnesisUuid(); v != "" {
+		rec.AnamnesisUuid = &v
+	}
+	if a.GetCreatedAt() != nil {
+		rec.CreatedAt = a.GetCreatedAt().AsTime()
+	}
+	return rec
+}
+
+func recordToPB(rec attachmentRecord) *pb.Attachment {
+	a := &pb.Attachment{
+		Uuid:        rec.Uuid,
+		DoctorUuid:  rec.DoctorUuid,
+		PatientUuid: rec.PatientUuid,
+		FileName:    rec.FileName,
+		ContentType: rec.ContentType,
+		SizeBytes:   rec.SizeBytes,
+		Description: rec.Description,
+		CreatedAt:   timestamppb.New(rec.CreatedAt),
+	}
+	if rec.AnamnesisUuid != nil {
+		a.AnamnesisUuid = *rec.AnamnesisUuid
+	}
+	return a
+}
+
+var _ out.Repository = (*Repository)(nil)
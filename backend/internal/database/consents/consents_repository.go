@@ -0,0 +1,300 @@
+package consents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/consents"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+type templateRecord struct {
+	Uuid        string     `gorm:"column:uuid;primaryKey"`
+	DoctorUuid  string     `gorm:"column:doctor_uuid"`
+	Name        string     `gorm:"column:name"`
+	Kind        string     `gorm:"column:kind"`
+	Text        string     `gorm:"column:text"`
+	ValidMonths int32      `gorm:"column:valid_months"`
+	Active      bool       `gorm:"column:active"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	UpdatedAt   *time.Time `gorm:"column:updated_at"`
+}
+
+func (templateRecord) TableName() string { return "consent_templates" }
+
+// consentColumns are read for listings; the signature is only loaded for the PDF.
+var consentColumns = []string{
+	"uuid", "doctor_uuid", "patient_uuid", "template_uuid", "kind", "title", "text", "text_sha256",
+	"signer_name", "signature_sha256", "signed_at", "expires_at", "revoked_at",
+}
+
+type consentRecord struct {
+	Uuid            string     `gorm:"column:uuid;primaryKey"`
+	DoctorUuid      string     `gorm:"column:doctor_uuid"`
+	PatientUuid     string     `gorm:"column:patient_uuid"`
+	TemplateUuid    *string    `gorm:"column:template_uuid"`
+	Kind            string     `gorm:"column:kind"`
+	Title           string     `gorm:"column:title"`
+	Text            string     `gorm:"column:text"`
+	TextSha256      string     `gorm:"column:text_sha256"`
+	SignerName      string     `gorm:"column:signer_name"`
+	Signature       []byte     `gorm:"column:signature"`
+	SignatureSha256 string     `gorm:"column:signature_sha256"`
+	SignedAt        time.Time  `gorm:"column:signed_at"`
+	ExpiresAt       *time.Time `gorm:"column:expires_at"`
+	RevokedAt       *time.Time `gorm:"column:revoked_at"`
+}
+
+func (consentRecord) TableName() string { return "patient_consents" }
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateTemplate(ctx context.Context, t *pb.ConsentTemplate) (*pb.ConsentTemplate, error) {
+	rec := templateToRecord(t)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("creating consent template: %w", re.ErrConflict)
+		}
+		return nil, fmt.Errorf("creating consent template: %w", err)
+	}
+	return templateToPB(rec), nil
+}
+
+func (r *Repository) UpdateTemplate(ctx context.Context, t *pb.ConsentTemplate) (*pb.ConsentTemplate, error) {
+	rec := templateToRecord(t)
+	res := r.db.WithContext(ctx).Model(&templateRecord{}).
+		Where("uuid = ?", t.GetUuid()).
+		Updates(map[string]interface{}{
+			"name":         rec.Name,
+			"kind":         rec.Kind,
+			"text":         rec.Text,
+			"valid_months": rec.ValidMonths,
+			"active":       rec.Active,
+			"updated_at":   rec.UpdatedAt,
+		})
+	if res.Error != nil {
+		if dbErrs.IsUniqueViolation(res.Error) {
+			return nil, fmt.Errorf("updating consent template: %w", re.ErrConflict)
+		}
+		return nil, fmt.Errorf("updating consent template: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("updating consent template: %w", re.ErrNotFound)
+	}
+	return templateToPB(rec), nil
+}
+
+func (r *Repository) GetTemplate(ctx context.Context, uuid string) (*pb.ConsentTemplate, error) {
+	var rec templateRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting consent template: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting consent template: %w", err)
+	}
+	return templateToPB(rec), nil
+}
+
+func (r *Repository) ListTemplates(ctx context.Context, doctorUUID string, includeInactive bool) ([]*pb.ConsentTemplate, error) {
+	q := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID)
+	if !includeInactive {
+		q = q.Where("active")
+	}
+	var recs []templateRecord
+	if err := q.Order("LOWER(name)").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing consent templates: %w", err)
+	}
+	res := make([]*pb.ConsentTemplate, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, templateToPB(rec))
+	}
+	return res, nil
+}
+
+func (r *Repository) DeleteTemplate(ctx context.Context, uuid string) error {
+	res := r.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&templateRecord{})
+	if res.Error != nil {
+		return fmt.Errorf("deleting consent template: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("deleting consent template: %w", re.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *Repository) Create(ctx context.Context, c *pb.Consent, signature []byte) (*pb.Consent, error) {
+	rec := consentToRecord(c, signature)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating consent: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating consent: %w", err)
+	}
+	return consentToPB(rec), nil
+}
+
+func (r *Repository) Get(ctx context.Context, uuid string) (*pb.Consent, error) {
+	var rec consentRecord
+	if err := r.db.WithContext(ctx).Select(consentColumns).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting consent: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting consent: %w", err)
+	}
+	return consentToPB(rec), nil
+}
+
+func (r *Repository) GetSignature(ctx context.Context, uuid string) ([]byte, error) {
+	var rec consentRecord
+	if err := r.db.WithContext(ctx).Select("signature").Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting consent signature: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting consent signature: %w", err)
+	}
+	return rec.Signature, nil
+}
+
+func (r *Repository) List(ctx context.Context, patientUUID string) ([]*pb.Consent, error) {
+	var recs []consentRecord
+	if err := r.db.WithContext(ctx).
+		Select(consentColumns).
+		Where("patient_uuid = ?", patientUUID).
+		Order("signed_at DESC").
+		Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing consents: %w", err)
+	}
+	res := make([]*pb.Consent, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, consentToPB(rec))
+	}
+	return res, nil
+}
+
+func (r *Repository) Revoke(ctx context.Context, uuid string, at time.Time) error {
+	res := r.db.WithContext(ctx).Model(&consentRecord{}).
+		Where("uuid = ? AND revoked_at IS NULL", uuid).
+		Update("revoked_at", at)
+	if res.Error != nil {
+		return fmt.Errorf("revoking consent: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("revoking consent: %w", re.ErrConflict)
+	}
+	return nil
+}
+
+func (r *Repository) HasValid(ctx context.Context, patientUUID, kind string, at time.Time) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&consentRecord{}).
+		Where("patient_uuid = ? AND kind = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", patientUUID, kind, at).
+		Count(&n).Error
+	if err != nil {
+		return false, fmt.Errorf("checking consents: %w", err)
+	}
+	return n > 0, nil
+}
+
+func templateToRecord(t *pb.ConsentTemplate) templateRecord {
+	rec := templateRecord{
+		Uuid:        t.GetUuid(),
+		DoctorUuid:  t.GetDoctorUuid(),
+		Name:        t.GetName(),
+		Kind:        t.GetKind(),
+		Text:        t.GetText(),
+		ValidMonths: t.GetValidMonths(),
+		Active:      t.GetActive(),
+	}
+	if t.GetCreatedAt() != nil {
+		rec.CreatedAt = t.GetCreatedAt().AsTime()
+	}
+	if t.GetUpdatedAt() != nil {
+		u := t.GetUpdatedAt().AsTime()
+		rec.UpdatedAt = &u
+	}
+	return rec
+}
+
+func templateToPB(rec templateRecord) *pb.ConsentTemplate {
+	t := &pb.ConsentTemplate{
+		Uuid:        rec.Uuid,
+		DoctorUuid:  rec.DoctorUuid,
+		Name:        rec.Name,
+		Kind:        rec.Kind,
+		Text:        rec.Text,
+		ValidMonths: rec.ValidMonths,
+		Active:      rec.Active,
+		CreatedAt:   timestamppb.New(rec.CreatedAt),
+	}
+	if rec.UpdatedAt != nil {
+		t.UpdatedAt = timestamppb.New(*rec.UpdatedAt)
+	}
+	return t
+}
+
+func consentToRecord(c *pb.Consent, signature []byte) consentRecord {
+	rec := consentRecord{
+		Uuid:            c.GetUuid(),
+		DoctorUuid:      c.GetDoctorUuid(),
+		PatientUuid:     c.GetPatientUuid(),
+		Kind:            c.GetKind(),
+		Title:           c.GetTitle(),
+		Text:            c.GetText(),
+		TextSha256:      c.GetTextSha256(),
+		SignerName:      c.GetSignerName(),
+		Signature:       signature,
+		SignatureSha256: c.GetSignatureSha256(),
+		SignedAt:        c.GetSignedAt().AsTime(),
+	}
+	if t := c.GetTemplateUuid(); t != "" {
+		rec.TemplateUuid = &t
+	}
+	if c.GetExpiresAt() != nil {
+		e := c.GetExpiresAt().AsTime()
+		rec.ExpiresAt = &e
+	}
+	if c.GetRevokedAt() != nil {
+		v := c.GetRevokedAt().AsTime()
+		rec.RevokedAt = &v
+	}
+	return rec
+}
+
+func consentToPB(rec consentRecord) *pb.Consent {
+	c := &pb.Consent{
+		Uuid:            rec.Uuid,
+		DoctorUuid:      rec.DoctorUuid,
+		PatientUuid:     rec.PatientUuid,
+		Kind:            rec.Kind,
+		Title:           rec.Title,
+		Text:            rec.Text,
+		TextSha256:      rec.TextSha256,
+		SignerName:      rec.SignerName,
+		SignatureSha256: rec.SignatureSha256,
+		SignedAt:        timestamppb.New(rec.SignedAt),
+	}
+	if rec.TemplateUuid != nil {
+		c.TemplateUuid = *rec.TemplateUuid
+	}
+	if rec.ExpiresAt != nil {
+		c.ExpiresAt = timestamppb.New(*rec.ExpiresAt)
+	}
+	if rec.RevokedAt != nil {
+		c.RevokedAt = timestamppb.New(*rec.RevokedAt)
+	}
+	return c
+}
+
+var _ out.Repository = (*Repository)(nil)
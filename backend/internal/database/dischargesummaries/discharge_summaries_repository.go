@@ -0,0 +1,149 @@
+package dischargesummaries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/dischargesummaries"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"github.com/lib/pq"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// summaryColumns are read for listings; the PDF is only loaded on download.
+var summaryColumns = []string{
+	"uuid", "doctor_uuid", "patient_uuid", "treatment_plan_uuid", "anamnesis_uuids", "period_start",
+	"period_end", "session_count", "home_recommendations", "conclusion", "sha256", "size_bytes", "created_at",
+}
+
+type summaryRecord struct {
+	Uuid                string         `gorm:"column:uuid;primaryKey"`
+	DoctorUuid          string         `gorm:"column:doctor_uuid"`
+	PatientUuid         string         `gorm:"column:patient_uuid"`
+	TreatmentPlanUuid   *string        `gorm:"column:treatment_plan_uuid"`
+	AnamnesisUuids      pq.StringArray `gorm:"column:anamnesis_uuids;type:text[]"`
+	PeriodStart         time.Time      `gorm:"column:period_start"`
+	PeriodEnd           time.Time      `gorm:"column:period_end"`
+	SessionCount        int32          `gorm:"column:session_count"`
+	HomeRecommendations string         `gorm:"column:home_recommendations"`
+	Conclusion          string         `gorm:"column:conclusion"`
+	Pdf                 []byte         `gorm:"column:pdf"`
+	Sha256              string         `gorm:"column:sha256"`
+	SizeBytes           int64          `gorm:"column:size_bytes"`
+	CreatedAt           time.Time      `gorm:"column:created_at"`
+}
+
+func (summaryRecord) TableName() string { return "discharge_summaries" }
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, d *pb.DischargeSummary, pdf []byte) (*pb.DischargeSummary, error) {
+	rec := pbToRecord(d, pdf)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating discharge summary: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating discharge summary: %w", err)
+	}
+	return recordToPB(rec), nil
+}
+
+func (r *Repository) Get(ctx context.Context, uuid string) (*pb.DischargeSummary, error) {
+	var rec summaryRecord
+	if err := r.db.WithContext(ctx).Select(summaryColumns).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting discharge summary: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting discharge summary: %w", err)
+	}
+	return recordToPB(rec), nil
+}
+
+func (r *Repository) GetPDF(ctx context.Context, uuid string) ([]byte, error) {
+	var rec summaryRecord
+	if err := r.db.WithContext(ctx).Select("pdf").Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting discharge summary pdf: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting discharge summary pdf: %w", err)
+	}
+	return rec.Pdf, nil
+}
+
+func (r *Repository) List(ctx context.Context, patientUUID string) ([]*pb.DischargeSummary, error) {
+	var recs []summaryRecord
+	if err := r.db.WithContext(ctx).
+		Select(summaryColumns).
+		Where("patient_uuid = ?", patientUUID).
+		Order("created_at DESC").
+		Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing discharge summaries: %w", err)
+	}
+	res := make([]*pb.DischargeSummary, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, recordToPB(rec))
+	}
+	return res, nil
+}
+
+func pbToRecord(d *pb.DischargeSummary, pdf []byte) summaryRecord {
+	visits := d.GetAnamnesisUuids()
+	if visits == nil {
+		visits = []string{}
+	}
+	rec := summaryRecord{
+		Uuid:                d.GetUuid(),
+		DoctorUuid:          d.GetDoctorUuid(),
+		PatientUuid:         d.GetPatientUuid(),
+		AnamnesisUuids:      pq.StringArray(visits),
+		PeriodStart:         d.GetPeriodStart().AsTime(),
+		PeriodEnd:           d.GetPeriodEnd().AsTime(),
+		SessionCount:        d.GetSessionCount(),
+		HomeRecommendations: d.GetHomeRecommendations(),
+		Conclusion:          d.GetConclusion(),
+		Pdf:                 pdf,
+		Sha256:              d.GetSha256(),
+		SizeBytes:           d.GetSizeBytes(),
+	}
+	if plan := d.GetTreatmentPlanUuid(); plan != "" {
+		rec.TreatmentPlanUuid = &plan
+	}
+	if d.GetCreatedAt() != nil {
+		rec.CreatedAt = d.GetCreatedAt().AsTime()
+	}
+	return rec
+}
+
+func recordToPB(rec summaryRecord) *pb.DischargeSummary {
+	d := &pb.DischargeSummary{
+		Uuid:                rec.Uuid,
+		DoctorUuid:          rec.DoctorUuid,
+		PatientUuid:         rec.PatientUuid,
+		AnamnesisUuids:      []string(rec.AnamnesisUuids),
+		PeriodStart:         timestamppb.New(rec.PeriodStart),
+		PeriodEnd:           timestamppb.New(rec.PeriodEnd),
+		SessionCount:        rec.SessionCount,
+		HomeRecommendations: rec.HomeRecommendations,
+		Conclusion:          rec.Conclusion,
+		Sha256:              rec.Sha256,
+		SizeBytes:           rec.SizeBytes,
+		CreatedAt:           timestamppb.New(rec.CreatedAt),
+	}
+	if rec.TreatmentPlanUuid != nil {
+		d.TreatmentPlanUuid = *rec.TreatmentPlanUuid
+	}
+	return d
+}
+
+var _ out.Repository = (*Repository)(nil)
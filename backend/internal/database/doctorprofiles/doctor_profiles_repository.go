@@ -31,6 +31,11 @@ func (r *Repository) GetByDoctor(ctx context.Context, doctorUUID string) (*pt.Do
 	if err != nil {
 		return nil, fmt.Errorf("get doctor profile: convert to PB: %w", err)
 	}
+	if sched, err := getSchedule(r.db.WithContext(ctx), doctorUUID); err == nil {
+		pbObj.Schedule = sched
+	} else if !errors.Is(err, re.ErrNotFound) {
+		return nil, fmt.Errorf("get doctor profile: %w", err)
+	}
 	return &pbObj, nil
 }
 
@@ -40,6 +45,11 @@ func (r *Repository) Upsert(ctx context.Context, profile *pt.DoctorProfile) (*pt
 		return nil, fmt.Errorf("upsert doctor profile: convert to ORM: %w", err)
 	}
 	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if profile.GetSchedule() != nil {
+			if err := saveSchedule(tx, profile.GetDoctorUuid(), profile.GetSchedule()); err != nil {
+				return err
+			}
+		}
 		var existing pt.DoctorProfileORM
 		if err := tx.Where("doctor_uuid = ?", profile.GetDoctorUuid()).First(&existing).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -73,6 +83,12 @@ func (r *Repository) Upsert(ctx context.Context, profile *pt.DoctorProfile) (*pt
 	if err != nil {
 		return nil, fmt.Errorf("upsert doctor profile: convert to PB: %w", err)
 	}
+	pbObj.Schedule = profile.GetSchedule()
+	if pbObj.Schedule == nil {
+		if sched, err := getSchedule(r.db.WithContext(ctx), profile.GetDoctorUuid()); err == nil {
+			pbObj.Schedule = sched
+		}
+	}
 	return &pbObj, nil
 }
 
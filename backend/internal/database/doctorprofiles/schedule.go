@@ -0,0 +1,54 @@
+package doctorprofiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pt "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// scheduleRecord stores the whole working schedule as JSON; it is always read and written as a unit.
+type scheduleRecord struct {
+	DoctorUuid string    `gorm:"column:doctor_uuid;primaryKey"`
+	Schedule   string    `gorm:"column:schedule;type:jsonb"`
+	UpdatedAt  time.Time `gorm:"column:updated_at"`
+}
+
+func (scheduleRecord) TableName() string { return "doctor_working_schedules" }
+
+func (r *Repository) GetSchedule(ctx context.Context, doctorUUID string) (*pt.WorkingSchedule, error) {
+	return getSchedule(r.db.WithContext(ctx), doctorUUID)
+}
+
+func getSchedule(db *gorm.DB, doctorUUID string) (*pt.WorkingSchedule, error) {
+	var rec scheduleRecord
+	if err := db.Where("doctor_uuid = ?", doctorUUID).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get working schedule: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get working schedule: %w", err)
+	}
+	var sched pt.WorkingSchedule
+	if err := protojson.Unmarshal([]byte(rec.Schedule), &sched); err != nil {
+		return nil, fmt.Errorf("get working schedule: decode: %w", err)
+	}
+	return &sched, nil
+}
+
+func saveSchedule(tx *gorm.DB, doctorUUID string, sched *pt.WorkingSchedule) error {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(sched)
+	if err != nil {
+		return fmt.Errorf("encode working schedule: %w", err)
+	}
+	rec := scheduleRecord{DoctorUuid: doctorUUID, Schedule: string(b), UpdatedAt: time.Now().UTC()}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "doctor_uuid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"schedule", "updated_at"}),
+	}).Create(&rec).Error
+}
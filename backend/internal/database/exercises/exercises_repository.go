@@ -0,0 +1,265 @@
+package exercises
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/exercises"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateExercise(ctx context.Context, e *pb.Exercise) (*pb.Exercise, error) {
+	rec := exerciseToRecord(e)
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("creating exercise: %w", re.ErrConflict)
+		}
+		return nil, fmt.Errorf("creating exercise: %w", err)
+	}
+	return exerciseToPB(rec), nil
+}
+
+func (r *Repository) UpdateExercise(ctx context.Context, e *pb.Exercise) (*pb.Exercise, error) {
+	rec := exerciseToRecord(e)
+	res := r.db.WithContext(ctx).Model(&exerciseRecord{}).
+		Where("uuid = ?", e.GetUuid()).
+		Updates(map[string]interface{}{
+			"name":                 rec.Name,
+			"description":          rec.Description,
+			"image_url":            rec.ImageUrl,
+			"default_sets":         rec.DefaultSets,
+			"default_reps":         rec.DefaultReps,
+			"default_hold_seconds": rec.DefaultHoldSeconds,
+			"default_frequency":    rec.DefaultFrequency,
+			"active":               rec.Active,
+			"updated_at":           rec.UpdatedAt,
+		})
+	if res.Error != nil {
+		if dbErrs.IsUniqueViolation(res.Error) {
+			return nil, fmt.Errorf("updating exercise: %w", re.ErrConflict)
+		}
+		return nil, fmt.Errorf("updating exercise: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("updating exercise: %w", re.ErrNotFound)
+	}
+	return exerciseToPB(rec), nil
+}
+
+func (r *Repository) GetExercise(ctx context.Context, uuid string) (*pb.Exercise, error) {
+	var rec exerciseRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting exercise: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting exercise: %w", err)
+	}
+	return exerciseToPB(rec), nil
+}
+
+func (r *Repository) ListExercises(ctx context.Context, doctorUUID, query string, includeInactive bool) ([]*pb.Exercise, error) {
+	q := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID)
+	if !includeInactive {
+		q = q.Where("active")
+	}
+	if query = strings.TrimSpace(query); query != "" {
+		q = q.Where("name ILIKE ?", "%"+query+"%")
+	}
+	var recs []exerciseRecord
+	if err := q.Order("LOWER(name)").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing exercises: %w", err)
+	}
+	res := make([]*pb.Exercise, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, exerciseToPB(rec))
+	}
+	return res, nil
+}
+
+// DeleteExercise fails with ErrConflict while a program still uses the exercise.
+func (r *Repository) DeleteExercise(ctx context.Context, uuid string) error {
+	res := r.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&exerciseRecord{})
+	if res.Error != nil {
+		if dbErrs.IsForeignKeyViolation(res.Error) {
+			return fmt.Errorf("deleting exercise: %w", re.ErrConflict)
+		}
+		return fmt.Errorf("deleting exercise: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("deleting exercise: %w", re.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *Repository) CreateProgram(ctx context.Context, p *pb.ExerciseProgram) (*pb.ExerciseProgram, error) {
+	rec := programToRecord(p)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rec).Error; err != nil {
+			return err
+		}
+		return insertItems(tx, p)
+	})
+	if err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating exercise program: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating exercise program: %w", err)
+	}
+	return r.GetProgram(ctx, rec.Uuid)
+}
+
+func (r *Repository) UpdateProgram(ctx context.Context, p *pb.ExerciseProgram) (*pb.ExerciseProgram, error) {
+	rec := programToRecord(p)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&programRecord{}).
+			Where("uuid = ?", p.GetUuid()).
+			Updates(map[string]interface{}{
+				"title":      rec.Title,
+				"notes":      rec.Notes,
+				"updated_at": rec.UpdatedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return re.ErrNotFound
+		}
+		if err := tx.Where("program_uuid = ?", p.GetUuid()).Delete(&itemRecord{}).Error; err != nil {
+			return err
+		}
+		return insertItems(tx, p)
+	})
+	if err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("updating exercise program: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("updating exercise program: %w", err)
+	}
+	return r.GetProgram(ctx, rec.Uuid)
+}
+
+func (r *Repository) GetProgram(ctx context.Context, uuid string) (*pb.ExerciseProgram, error) {
+	var rec programRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting exercise program: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting exercise program: %w", err)
+	}
+	res := []*pb.ExerciseProgram{programToPB(rec)}
+	if err := r.attachItems(ctx, res); err != nil {
+		return nil, fmt.Errorf("getting exercise program: %w", err)
+	}
+	return res[0], nil
+}
+
+func (r *Repository) ListPrograms(ctx context.Context, patientUUID string) ([]*pb.ExerciseProgram, error) {
+	var recs []programRecord
+	if err := r.db.WithContext(ctx).
+		Where("patient_uuid = ?", patientUUID).
+		Order("created_at DESC").
+		Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing exercise programs: %w", err)
+	}
+	res := make([]*pb.ExerciseProgram, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, programToPB(rec))
+	}
+	if err := r.attachItems(ctx, res); err != nil {
+		return nil, fmt.Errorf("listing exercise programs: %w", err)
+	}
+	return res, nil
+}
+
+func (r *Repository) DeleteProgram(ctx context.Context, uuid string) error {
+	res := r.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&programRecord{})
+	if res.Error != nil {
+		return fmt.Errorf("deleting exercise program: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("deleting exercise program: %w", re.ErrNotFound)
+	}
+	return nil
+}
+
+func insertItems(tx *gorm.DB, p *pb.ExerciseProgram) error {
+	recs := itemRecords(p)
+	if len(recs) == 0 {
+		return nil
+	}
+	return tx.Create(&recs).Error
+}
+
+// attachItems loads the items of all given programs with their library exercises and
+// resolves the effective dosage (override, else library default).
+func (r *Repository) attachItems(ctx context.Context, list []*pb.ExerciseProgram) error {
+	if len(list) == 0 {
+		return nil
+	}
+	byUUID := make(map[string]*pb.ExerciseProgram, len(list))
+	uuids := make([]string, 0, len(list))
+	for _, p := range list {
+		byUUID[p.GetUuid()] = p
+		uuids = append(uuids, p.GetUuid())
+	}
+	var items []itemRecord
+	if err := r.db.WithContext(ctx).
+		Where("program_uuid IN ?", uuids).
+		Order("program_uuid, position").
+		Find(&items).Error; err != nil {
+		return fmt.Errorf("load program items: %w", err)
+	}
+	exerciseUUIDs := make([]string, 0, len(items))
+	for _, it := range items {
+		exerciseUUIDs = append(exerciseUUIDs, it.ExerciseUuid)
+	}
+	var exRecs []exerciseRecord
+	if len(exerciseUUIDs) > 0 {
+		if err := r.db.WithContext(ctx).Where("uuid IN ?", exerciseUUIDs).Find(&exRecs).Error; err != nil {
+			return fmt.Errorf("load program exercises: %w", err)
+		}
+	}
+	exercises := make(map[string]*pb.Exercise, len(exRecs))
+	for _, rec := range exRecs {
+		exercises[rec.Uuid] = exerciseToPB(rec)
+	}
+	for _, rec := range items {
+		it := itemToPB(rec)
+		if ex := exercises[rec.ExerciseUuid]; ex != nil {
+			it.Exercise = ex
+			it.Sets = ex.GetDefaultSets()
+			it.Reps = ex.GetDefaultReps()
+			it.HoldSeconds = ex.GetDefaultHoldSeconds()
+			it.Frequency = ex.GetDefaultFrequency()
+		}
+		if it.GetSetsOverride() != nil {
+			it.Sets = it.GetSetsOverride().GetValue()
+		}
+		if it.GetRepsOverride() != nil {
+			it.Reps = it.GetRepsOverride().GetValue()
+		}
+		if it.GetHoldSecondsOverride() != nil {
+			it.HoldSeconds = it.GetHoldSecondsOverride().GetValue()
+		}
+		if it.GetFrequencyOverride() != nil {
+			it.Frequency = it.GetFrequencyOverride().GetValue()
+		}
+		byUUID[rec.ProgramUuid].Items = append(byUUID[rec.ProgramUuid].Items, it)
+	}
+	return nil
+}
+
+var _ out.Repository = (*Repository)(nil)
@@ -0,0 +1,180 @@
+package exercises
+
+import (
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type exerciseRecord struct {
+	Uuid               string     `gorm:"column:uuid;primaryKey"`
+	DoctorUuid         string     `gorm:"column:doctor_uuid"`
+	Name               string     `gorm:"column:name"`
+	Description        string     `gorm:"column:description"`
+	ImageUrl           string     `gorm:"column:image_url"`
+	DefaultSets        int32      `gorm:"column:default_sets"`
+	DefaultReps        int32      `gorm:"column:default_reps"`
+	DefaultHoldSeconds int32      `gorm:"column:default_hold_seconds"`
+	DefaultFrequency   string     `gorm:"column:default_frequency"`
+	Active             bool       `gorm:"column:active"`
+	CreatedAt          time.Time  `gorm:"column:created_at"`
+	UpdatedAt          *time.Time `gorm:"column:updated_at"`
+}
+
+func (exerciseRecord) TableName() string { return "exercises" }
+
+type programRecord struct {
+	Uuid        string     `gorm:"column:uuid;primaryKey"`
+	DoctorUuid  string     `gorm:"column:doctor_uuid"`
+	PatientUuid string     `gorm:"column:patient_uuid"`
+	Title       string     `gorm:"column:title"`
+	Notes       string     `gorm:"column:notes"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	UpdatedAt   *time.Time `gorm:"column:updated_at"`
+}
+
+func (programRecord) TableName() string { return "exercise_programs" }
+
+// itemRecord is one exercise of a program; nil overrides fall back to the library defaults.
+type itemRecord struct {
+	ProgramUuid         string  `gorm:"column:program_uuid;primaryKey"`
+	Position            int32   `gorm:"column:position;primaryKey"`
+	ExerciseUuid        string  `gorm:"column:exercise_uuid"`
+	SetsOverride        *int32  `gorm:"column:sets_override"`
+	RepsOverride        *int32  `gorm:"column:reps_override"`
+	HoldSecondsOverride *int32  `gorm:"column:hold_seconds_override"`
+	FrequencyOverride   *string `gorm:"column:frequency_override"`
+	Notes               string  `gorm:"column:notes"`
+}
+
+func (itemRecord) TableName() string { return "exercise_program_items" }
+
+func exerciseToPB(rec exerciseRecord) *pb.Exercise {
+	e := &pb.Exercise{
+		Uuid:               rec.Uuid,
+		DoctorUuid:         rec.DoctorUuid,
+		Name:               rec.Name,
+		Description:        rec.Description,
+		ImageUrl:           rec.ImageUrl,
+		DefaultSets:        rec.DefaultSets,
+		DefaultReps:        rec.DefaultReps,
+		DefaultHoldSeconds: rec.DefaultHoldSeconds,
+		DefaultFrequency:   rec.DefaultFrequency,
+		Active:             rec.Active,
+		CreatedAt:          timestamppb.New(rec.CreatedAt),
+	}
+	if rec.UpdatedAt != nil {
+		e.UpdatedAt = timestamppb.New(*rec.UpdatedAt)
+	}
+	return e
+}
+
+func exerciseToRecord(e *pb.Exercise) exerciseRecord {
+	rec := exerciseRecord{
+		Uuid:               e.GetUuid(),
+		DoctorUuid:         e.GetDoctorUuid(),
+		Name:               e.GetName(),
+		Description:        e.GetDescription(),
+		ImageUrl:           e.GetImageUrl(),
+		DefaultSets:        e.GetDefaultSets(),
+		DefaultReps:        e.GetDefaultReps(),
+		DefaultHoldSeconds: e.GetDefaultHoldSeconds(),
+		DefaultFrequency:   e.GetDefaultFrequency(),
+		Active:             e.GetActive(),
+	}
+	if e.GetCreatedAt() != nil {
+		rec.CreatedAt = e.GetCreatedAt().AsTime()
+	}
+	if e.GetUpdatedAt() != nil {
+		t := e.GetUpdatedAt().AsTime()
+		rec.UpdatedAt = &t
+	}
+	return rec
+}
+
+func programToPB(rec programRecord) *pb.ExerciseProgram {
+	p := &pb.ExerciseProgram{
+		Uuid:        rec.Uuid,
+		DoctorUuid:  rec.DoctorUuid,
+		PatientUuid: rec.PatientUuid,
+		Title:       rec.Title,
+		Notes:       rec.Notes,
+		Items:       []*pb.ExerciseProgramItem{},
+		CreatedAt:   timestamppb.New(rec.CreatedAt),
+	}
+	if rec.UpdatedAt != nil {
+		p.UpdatedAt = timestamppb.New(*rec.UpdatedAt)
+	}
+	return p
+}
+
+func programToRecord(p *pb.ExerciseProgram) programRecord {
+	rec := programRecord{
+		Uuid:        p.GetUuid(),
+		DoctorUuid:  p.GetDoctorUuid(),
+		PatientUuid: p.GetPatientUuid(),
+		Title:       p.GetTitle(),
+		Notes:       p.GetNotes(),
+	}
+	if p.GetCreatedAt() != nil {
+		rec.CreatedAt = p.GetCreatedAt().AsTime()
+	}
+	if p.GetUpdatedAt() != nil {
+		t := p.GetUpdatedAt().AsTime()
+		rec.UpdatedAt = &t
+	}
+	return rec
+}
+
+func itemRecords(p *pb.ExerciseProgram) []itemRecord {
+	recs := make([]itemRecord, 0, len(p.GetItems()))
+	for i, it := range p.GetItems() {
+		rec := itemRecord{
+			ProgramUuid:  p.GetUuid(),
+			Position:     int32(i + 1),
+			ExerciseUuid: it.GetExerciseUuid(),
+			Notes:        it.GetNotes(),
+		}
+		if v := it.GetSetsOverride(); v != nil {
+			n := v.GetValue()
+			rec.SetsOverride = &n
+		}
+		if v := it.GetRepsOverride(); v != nil {
+			n := v.GetValue()
+			rec.RepsOverride = &n
+		}
+		if v := it.GetHoldSecondsOverride(); v != nil {
+			n := v.GetValue()
+			rec.HoldSecondsOverride = &n
+		}
+		if v := it.GetFrequencyOverride(); v != nil {
+			s := v.GetValue()
+			rec.FrequencyOverride = &s
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+func itemToPB(rec itemRecord) *pb.ExerciseProgramItem {
+	it := &pb.ExerciseProgramItem{
+		ExerciseUuid: rec.ExerciseUuid,
+		Position:     rec.Position,
+		Notes:        rec.Notes,
+	}
+	if rec.SetsOverride != nil {
+		it.SetsOverride = wrapperspb.Int32(*rec.SetsOverride)
+	}
+	if rec.RepsOverride != nil {
+		it.RepsOverride = wrapperspb.Int32(*rec.RepsOverride)
+	}
+	if rec.HoldSecondsOverride != nil {
+		it.HoldSecondsOverride = wrapperspb.Int32(*rec.HoldSecondsOverride)
+	}
+	if rec.FrequencyOverride != nil {
+		it.FrequencyOverride = wrapperspb.String(*rec.FrequencyOverride)
+	}
+	return it
+}
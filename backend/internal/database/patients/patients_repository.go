@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	pt "github.com/OPetricevic/physio-tracker/backend/golang/patients"
 	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
@@ -66,13 +67,92 @@ func (r *PatientsRepository) List(ctx context.Context, filter *pt.ListPatientsRe
 			q = q.Where("LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ? OR LOWER(phone) LIKE ?", like, like, like)
 		}
 	}
+	if n := filter.GetNoShowGte(); n > 0 {
+		q = q.Where("(SELECT COUNT(*) FROM appointments a WHERE a.patient_uuid = patients.uuid AND a.outcome = 'no_show') >= ?", n)
+	}
 	if limit > 0 {
 		q = q.Limit(limit).Offset(offset)
 	}
 	if err := q.Order("created_at DESC").Find(&orms).Error; err != nil {
 		return nil, fmt.Errorf("listing patients: %w", err)
 	}
-	return patientORMsToProto(ctx, orms)
+	res, err := patientORMsToProto(ctx, orms)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachAttendance(ctx, res); err != nil {
+		return nil, fmt.Errorf("listing patients: %w", err)
+	}
+	if err := r.attachConsents(ctx, res); err != nil {
+		return nil, fmt.Errorf("listing patients: %w", err)
+	}
+	return res, nil
+}
+
+// attachAttendance fills the no-show and late-cancel counts of the listed patients.
+func (r *PatientsRepository) attachAttendance(ctx context.Context, list []*pt.Patient) error {
+	if len(list) == 0 {
+		return nil
+	}
+	byUUID := make(map[string]*pt.Patient, len(list))
+	uuids := make([]string, 0, len(list))
+	for _, p := range list {
+		byUUID[p.GetUuid()] = p
+		uuids = append(uuids, p.GetUuid())
+	}
+	var rows []struct {
+		PatientUuid string
+		NoShows     int32
+		LateCancels int32
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT patient_uuid,
+			COUNT(*) FILTER (WHERE outcome = 'no_show') AS no_shows,
+			COUNT(*) FILTER (WHERE outcome = 'late_cancel') AS late_cancels
+		FROM appointments
+		WHERE patient_uuid IN ? AND outcome IN ('no_show', 'late_cancel')
+		GROUP BY patient_uuid`, uuids).Scan(&rows).Error
+	if err != nil {
+		return fmt.Errorf("counting no-shows: %w", err)
+	}
+	for _, row := range rows {
+		if p, ok := byUUID[row.PatientUuid]; ok {
+			p.NoShowCount = row.NoShows
+			p.LateCancelCount = row.LateCancels
+		}
+	}
+	return nil
+}
+
+// attachConsents fills the kinds of the listed patients' consents that are neither revoked nor expired.
+func (r *PatientsRepository) attachConsents(ctx context.Context, list []*pt.Patient) error {
+	if len(list) == 0 {
+		return nil
+	}
+	byUUID := make(map[string]*pt.Patient, len(list))
+	uuids := make([]string, 0, len(list))
+	for _, p := range list {
+		byUUID[p.GetUuid()] = p
+		uuids = append(uuids, p.GetUuid())
+	}
+	var rows []struct {
+		PatientUuid string
+		Kind        string
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT patient_uuid, kind
+		FROM patient_consents
+		WHERE patient_uuid IN ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY patient_uuid, kind`, uuids, time.Now().UTC()).Scan(&rows).Error
+	if err != nil {
+		return fmt.Errorf("loading consents: %w", err)
+	}
+	for _, row := range rows {
+		if p, ok := byUUID[row.PatientUuid]; ok {
+			p.ValidConsents = append(p.ValidConsents, row.Kind)
+		}
+	}
+	return nil
 }
 
 func (r *PatientsRepository) Get(ctx context.Context, uuid string) (*pt.Patient, error) {
@@ -90,12 +170,23 @@ func (r *PatientsRepository) Get(ctx context.Context, uuid string) (*pt.Patient,
 	return &pbObj, nil
 }
 
+// Delete removes the patient with their records. A patient with a locked note is kept, since
+// signed notes are never deleted, and reported as ErrConflict.
 func (r *PatientsRepository) Delete(ctx context.Context, uuid string) error {
-	res := r.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&pt.PatientORM{})
+	res := r.db.WithContext(ctx).
+		Where("uuid = ? AND NOT EXISTS (SELECT 1 FROM anamneses WHERE anamneses.patient_uuid = patients.uuid AND anamneses.locked_at IS NOT NULL)", uuid).
+		Delete(&pt.PatientORM{})
 	if res.Error != nil {
 		return fmt.Errorf("delete patient: %w", res.Error)
 	}
 	if res.RowsAffected == 0 {
+		var n int64
+		if err := r.db.WithContext(ctx).Model(&pt.PatientORM{}).Where("uuid = ?", uuid).Count(&n).Error; err != nil {
+			return fmt.Errorf("delete patient: %w", err)
+		}
+		if n > 0 {
+			return fmt.Errorf("delete patient: %w", re.ErrConflict)
+		}
 		return fmt.Errorf("delete patient: %w", re.ErrNotFound)
 	}
 	return nil
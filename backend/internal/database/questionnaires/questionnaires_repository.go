@@ -0,0 +1,208 @@
+package questionnaires
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/questionnaires"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gorm.io/gorm"
+)
+
+// resultRecord keeps answers and subscores as JSON; they are only ever read with the result.
+type resultRecord struct {
+	Uuid          string    `gorm:"column:uuid;primaryKey"`
+	DoctorUuid    string    `gorm:"column:doctor_uuid"`
+	PatientUuid   string    `gorm:"column:patient_uuid"`
+	AnamnesisUuid *string   `gorm:"column:anamnesis_uuid"`
+	Code          string    `gorm:"column:code"`
+	Answers       string    `gorm:"column:answers;type:jsonb"`
+	Score         *float64  `gorm:"column:score"`
+	Subscores     string    `gorm:"column:subscores;type:jsonb"`
+	MissingItems  int32     `gorm:"column:missing_items"`
+	CompletedAt   time.Time `gorm:"column:completed_at"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+func (resultRecord) TableName() string { return "questionnaire_results" }
+
+type answerJSON struct {
+	Item  int32 `json:"item"`
+	Value int32 `json:"value"`
+}
+
+type subscoreJSON struct {
+	Code         string  `json:"code"`
+	Name         string  `json:"name"`
+	Score        float64 `json:"score"`
+	MissingItems int32   `json:"missing_items"`
+}
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, res *pb.QuestionnaireResult) (*pb.QuestionnaireResult, error) {
+	rec, err := pbToRecord(res)
+	if err != nil {
+		return nil, fmt.Errorf("creating questionnaire result: convert: %w", err)
+	}
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating questionnaire result: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating questionnaire result: %w", err)
+	}
+	return recordToPB(rec)
+}
+
+func (r *Repository) Get(ctx context.Context, uuid string) (*pb.QuestionnaireResult, error) {
+	var rec resultRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting questionnaire result: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting questionnaire result: %w", err)
+	}
+	return recordToPB(rec)
+}
+
+func (r *Repository) Delete(ctx context.Context, uuid string) error {
+	res := r.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&resultRecord{})
+	if res.Error != nil {
+		return fmt.Errorf("deleting questionnaire result: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("deleting questionnaire result: %w", re.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *Repository) List(ctx context.Context, patientUUID, code string) ([]*pb.QuestionnaireResult, error) {
+	var recs []resultRecord
+	q := r.db.WithContext(ctx).Where("patient_uuid = ?", patientUUID)
+	if code != "" {
+		q = q.Where("code = ?", code)
+	}
+	if err := q.Order("completed_at, created_at").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing questionnaire results: %w", err)
+	}
+	return recordsToPB(recs)
+}
+
+func (r *Repository) ListForAnamneses(ctx context.Context, anamnesisUUIDs []string) ([]*pb.QuestionnaireResult, error) {
+	if len(anamnesisUUIDs) == 0 {
+		return []*pb.QuestionnaireResult{}, nil
+	}
+	var recs []resultRecord
+	if err := r.db.WithContext(ctx).
+		Where("anamnesis_uuid IN ?", anamnesisUUIDs).
+		Order("completed_at, created_at").
+		Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing questionnaire results for anamneses: %w", err)
+	}
+	return recordsToPB(recs)
+}
+
+func pbToRecord(res *pb.QuestionnaireResult) (resultRecord, error) {
+	answers := make([]answerJSON, 0, len(res.GetAnswers()))
+	for _, a := range res.GetAnswers() {
+		answers = append(answers, answerJSON{Item: a.GetItem(), Value: a.GetValue()})
+	}
+	subscores := make([]subscoreJSON, 0, len(res.GetSubscores()))
+	for _, s := range res.GetSubscores() {
+		subscores = append(subscores, subscoreJSON{Code: s.GetCode(), Name: s.GetName(), Score: s.GetScore(), MissingItems: s.GetMissingItems()})
+	}
+	answersJSON, err := json.Marshal(answers)
+	if err != nil {
+		return resultRecord{}, err
+	}
+	subscoresJSON, err := json.Marshal(subscores)
+	if err != nil {
+		return resultRecord{}, err
+	}
+	rec := resultRecord{
+		Uuid:         res.GetUuid(),
+		DoctorUuid:   res.GetDoctorUuid(),
+		PatientUuid:  res.GetPatientUuid(),
+		Code:         res.GetCode(),
+		Answers:      string(answersJSON),
+		Subscores:    string(subscoresJSON),
+		MissingItems: res.GetMissingItems(),
+	}
+	if res.GetAnamnesisUuid() != "" {
+		v := res.GetAnamnesisUuid()
+		rec.AnamnesisUuid = &v
+	}
+	if res.Score != nil {
+		v := res.GetScore().GetValue()
+		rec.Score = &v
+	}
+	if res.GetCompletedAt() != nil {
+		rec.CompletedAt = res.GetCompletedAt().AsTime()
+	}
+	if res.GetCreatedAt() != nil {
+		rec.CreatedAt = res.GetCreatedAt().AsTime()
+	}
+	return rec, nil
+}
+
+func recordToPB(rec resultRecord) (*pb.QuestionnaireResult, error) {
+	var answers []answerJSON
+	if err := json.Unmarshal([]byte(rec.Answers), &answers); err != nil {
+		return nil, fmt.Errorf("decode questionnaire answers: %w", err)
+	}
+	var subscores []subscoreJSON
+	if err := json.Unmarshal([]byte(rec.Subscores), &subscores); err != nil {
+		return nil, fmt.Errorf("decode questionnaire subscores: %w", err)
+	}
+	res := &pb.QuestionnaireResult{
+		Uuid:         rec.Uuid,
+		DoctorUuid:   rec.DoctorUuid,
+		PatientUuid:  rec.PatientUuid,
+		Code:         rec.Code,
+		Answers:      make([]*pb.QuestionnaireAnswer, 0, len(answers)),
+		Subscores:    make([]*pb.QuestionnaireSubscore, 0, len(subscores)),
+		MissingItems: rec.MissingItems,
+		CompletedAt:  timestamppb.New(rec.CompletedAt),
+		CreatedAt:    timestamppb.New(rec.CreatedAt),
+	}
+	if rec.AnamnesisUuid != nil {
+		res.AnamnesisUuid = *rec.AnamnesisUuid
+	}
+	if rec.Score != nil {
+		res.Score = wrapperspb.Double(*rec.Score)
+	}
+	for _, a := range answers {
+		res.Answers = append(res.Answers, &pb.QuestionnaireAnswer{Item: a.Item, Value: a.Value})
+	}
+	for _, s := range subscores {
+		res.Subscores = append(res.Subscores, &pb.QuestionnaireSubscore{Code: s.Code, Name: s.Name, Score: s.Score, MissingItems: s.MissingItems})
+	}
+	return res, nil
+}
+
+func recordsToPB(recs []resultRecord) ([]*pb.QuestionnaireResult, error) {
+	res := make([]*pb.QuestionnaireResult, 0, len(recs))
+	for _, rec := range recs {
+		r, err := recordToPB(rec)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+var _ out.Repository = (*Repository)(nil)
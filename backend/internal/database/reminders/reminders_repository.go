@@ -0,0 +1,328 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/reminders"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"github.com/lib/pq"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type settingsRecord struct {
+	DoctorUuid   string         `gorm:"column:doctor_uuid;primaryKey"`
+	Enabled      bool           `gorm:"column:enabled"`
+	HoursBefore  int32          `gorm:"column:hours_before"`
+	Channels     pq.StringArray `gorm:"column:channels;type:text[]"`
+	EmailSubject string         `gorm:"column:email_subject"`
+	EmailBody    string         `gorm:"column:email_body"`
+	SmsBody      string         `gorm:"column:sms_body"`
+	UpdatedAt    time.Time      `gorm:"column:updated_at"`
+}
+
+func (settingsRecord) TableName() string { return "reminder_settings" }
+
+type reminderRecord struct {
+	Uuid                string     `gorm:"column:uuid;primaryKey"`
+	DoctorUuid          string     `gorm:"column:doctor_uuid"`
+	PatientUuid         string     `gorm:"column:patient_uuid"`
+	AppointmentUuid     *string    `gorm:"column:appointment_uuid"`
+	SeriesUuid          *string    `gorm:"column:series_uuid"`
+	OriginalStartsAt    *time.Time `gorm:"column:original_starts_at"`
+	AppointmentStartsAt time.Time  `gorm:"column:appointment_starts_at"`
+	Channel             string     `gorm:"column:channel"`
+	Recipient           string     `gorm:"column:recipient"`
+	Subject             string     `gorm:"column:subject"`
+	Body                string     `gorm:"column:body"`
+	Status              string     `gorm:"column:status"`
+	Attempts            int32      `gorm:"column:attempts"`
+	LastError           string     `gorm:"column:last_error"`
+	SendAfter           time.Time  `gorm:"column:send_after"`
+	SentAt              *time.Time `gorm:"column:sent_at"`
+	DedupeKey           string     `gorm:"column:dedupe_key"`
+	CreatedAt           time.Time  `gorm:"column:created_at"`
+	UpdatedAt           *time.Time `gorm:"column:updated_at"`
+}
+
+func (reminderRecord) TableName() string { return "reminder_outbox" }
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) GetSettings(ctx context.Context, doctorUUID string) (*pb.ReminderSettings, error) {
+	var rec settingsRecord
+	if err := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting reminder settings: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting reminder settings: %w", err)
+	}
+	return settingsRecordToPB(rec), nil
+}
+
+func (r *Repository) UpsertSettings(ctx context.Context, s *pb.ReminderSettings) (*pb.ReminderSettings, error) {
+	rec := settingsRecord{
+		DoctorUuid:   s.GetDoctorUuid(),
+		Enabled:      s.GetEnabled(),
+		HoursBefore:  s.GetHoursBefore(),
+		Channels:     pq.StringArray(s.GetChannels()),
+		EmailSubject: s.GetEmailSubject(),
+		EmailBody:    s.GetEmailBody(),
+		SmsBody:      s.GetSmsBody(),
+		UpdatedAt:    s.GetUpdatedAt().AsTime(),
+	}
+	if rec.Channels == nil {
+		rec.Channels = pq.StringArray{}
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "doctor_uuid"}},
+		UpdateAll: true,
+	}).Create(&rec).Error
+	if err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("saving reminder settings: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("saving reminder settings: %w", err)
+	}
+	return settingsRecordToPB(rec), nil
+}
+
+func (r *Repository) ListEnabledSettings(ctx context.Context) ([]*pb.ReminderSettings, error) {
+	var recs []settingsRecord
+	if err := r.db.WithContext(ctx).Where("enabled").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing reminder settings: %w", err)
+	}
+	res := make([]*pb.ReminderSettings, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, settingsRecordToPB(rec))
+	}
+	return res, nil
+}
+
+func (r *Repository) Enqueue(ctx context.Context, rem *pb.Reminder, dedupeKey string) (bool, error) {
+	rec := pbToRecord(rem)
+	rec.DedupeKey = dedupeKey
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "dedupe_key"}}, DoNothing: true}).
+		Create(&rec)
+	if res.Error != nil {
+		if dbErrs.IsForeignKeyViolation(res.Error) {
+			return false, fmt.Errorf("enqueueing reminder: %w", re.ErrNotFound)
+		}
+		return false, fmt.Errorf("enqueueing reminder: %w", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// ClaimDue uses SKIP LOCKED so two workers never pick up the same reminder.
+func (r *Repository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*pb.Reminder, error) {
+	var recs []reminderRecord
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE reminder_outbox SET status = 'sending', attempts = attempts + 1, updated_at = ?
+		WHERE uuid IN (
+			SELECT uuid FROM reminder_outbox
+			WHERE status = 'pending' AND send_after <= ?
+			ORDER BY send_after
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`, now, now, limit).Scan(&recs).Error
+	if err != nil {
+		return nil, fmt.Errorf("claiming due reminders: %w", err)
+	}
+	return recordsToPB(recs), nil
+}
+
+func (r *Repository) Finish(ctx context.Context, rem *pb.Reminder) error {
+	rec := pbToRecord(rem)
+	res := r.db.WithContext(ctx).Model(&reminderRecord{}).
+		Where("uuid = ? AND status = ?", rem.GetUuid(), "sending").
+		Updates(map[string]interface{}{
+			"status":     rec.Status,
+			"last_error": rec.LastError,
+			"send_after": rec.SendAfter,
+			"sent_at":    rec.SentAt,
+			"updated_at": rec.UpdatedAt,
+		})
+	if res.Error != nil {
+		return fmt.Errorf("finishing reminder: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("finishing reminder: %w", re.ErrConflict)
+	}
+	return nil
+}
+
+func (r *Repository) FailStale(ctx context.Context, cutoff, now time.Time, reason string) (int, error) {
+	res := r.db.WithContext(ctx).Model(&reminderRecord{}).
+		Where("status = ? AND updated_at < ?", "sending", cutoff).
+		Updates(map[string]interface{}{"status": "failed", "last_error": reason, "updated_at": now})
+	if res.Error != nil {
+		return 0, fmt.Errorf("failing stale reminders: %w", res.Error)
+	}
+	return int(res.RowsAffected), nil
+}
+
+// Retry puts a failed reminder back in the queue; other states fail with ErrConflict.
+func (r *Repository) Retry(ctx context.Context, uuid string, at time.Time) error {
+	res := r.db.WithContext(ctx).Model(&reminderRecord{}).
+		Where("uuid = ? AND status = ?", uuid, "failed").
+		Updates(map[string]interface{}{"status": "pending", "send_after": at, "updated_at": at})
+	if res.Error != nil {
+		return fmt.Errorf("retrying reminder: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("retrying reminder: %w", re.ErrConflict)
+	}
+	return nil
+}
+
+func (r *Repository) Get(ctx context.Context, uuid string) (*pb.Reminder, error) {
+	var rec reminderRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting reminder: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting reminder: %w", err)
+	}
+	return recordToPB(rec), nil
+}
+
+// List returns reminders of appointments starting in [from, to).
+func (r *Repository) List(ctx context.Context, doctorUUID, status string, from, to time.Time) ([]*pb.Reminder, error) {
+	var recs []reminderRecord
+	q := r.db.WithContext(ctx).
+		Where("doctor_uuid = ?", doctorUUID).
+		Where("appointment_starts_at >= ? AND appointment_starts_at < ?", from, to)
+	if strings.TrimSpace(status) != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Order("appointment_starts_at ASC, created_at ASC").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing reminders: %w", err)
+	}
+	return recordsToPB(recs), nil
+}
+
+// ListForAppointment returns the reminders of a stored appointment or of a series occurrence.
+func (r *Repository) ListForAppointment(ctx context.Context, doctorUUID, appointmentUUID, seriesUUID string, originalStart time.Time) ([]*pb.Reminder, error) {
+	var recs []reminderRecord
+	q := r.db.WithContext(ctx).Where("doctor_uuid = ?", doctorUUID)
+	switch {
+	case seriesUUID != "" && appointmentUUID != "":
+		q = q.Where("appointment_uuid = ? OR (series_uuid = ? AND original_starts_at = ?)", appointmentUUID, seriesUUID, originalStart)
+	case seriesUUID != "":
+		q = q.Where("series_uuid = ? AND original_starts_at = ?", seriesUUID, originalStart)
+	default:
+		q = q.Where("appointment_uuid = ?", appointmentUUID)
+	}
+	if err := q.Order("created_at ASC").Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing appointment reminders: %w", err)
+	}
+	return recordsToPB(recs), nil
+}
+
+func settingsRecordToPB(rec settingsRecord) *pb.ReminderSettings {
+	return &pb.ReminderSettings{
+		DoctorUuid:   rec.DoctorUuid,
+		Enabled:      rec.Enabled,
+		HoursBefore:  rec.HoursBefore,
+		Channels:     []string(rec.Channels),
+		EmailSubject: rec.EmailSubject,
+		EmailBody:    rec.EmailBody,
+		SmsBody:      rec.SmsBody,
+		UpdatedAt:    timestamppb.New(rec.UpdatedAt),
+	}
+}
+
+func recordToPB(rec reminderRecord) *pb.Reminder {
+	rem := &pb.Reminder{
+		Uuid:                rec.Uuid,
+		DoctorUuid:          rec.DoctorUuid,
+		PatientUuid:         rec.PatientUuid,
+		AppointmentStartsAt: timestamppb.New(rec.AppointmentStartsAt),
+		Channel:             rec.Channel,
+		Recipient:           rec.Recipient,
+		Subject:             rec.Subject,
+		Body:                rec.Body,
+		Status:              rec.Status,
+		Attempts:            rec.Attempts,
+		LastError:           rec.LastError,
+		SendAfter:           timestamppb.New(rec.SendAfter),
+		CreatedAt:           timestamppb.New(rec.CreatedAt),
+	}
+	if rec.AppointmentUuid != nil {
+		rem.AppointmentUuid = *rec.AppointmentUuid
+	}
+	if rec.SeriesUuid != nil {
+		rem.SeriesUuid = *rec.SeriesUuid
+	}
+	if rec.OriginalStartsAt != nil {
+		rem.OriginalStartsAt = timestamppb.New(*rec.OriginalStartsAt)
+	}
+	if rec.SentAt != nil {
+		rem.SentAt = timestamppb.New(*rec.SentAt)
+	}
+	if rec.UpdatedAt != nil {
+		rem.UpdatedAt = timestamppb.New(*rec.UpdatedAt)
+	}
+	return rem
+}
+
+func recordsToPB(recs []reminderRecord) []*pb.Reminder {
+	res := make([]*pb.Reminder, 0, len(recs))
+	for _, rec := range recs {
+		res = append(res, recordToPB(rec))
+	}
+	return res
+}
+
+func pbToRecord(rem *pb.Reminder) reminderRecord {
+	rec := reminderRecord{
+		Uuid:                rem.GetUuid(),
+		DoctorUuid:          rem.GetDoctorUuid(),
+		PatientUuid:         rem.GetPatientUuid(),
+		AppointmentStartsAt: rem.GetAppointmentStartsAt().AsTime(),
+		Channel:             rem.GetChannel(),
+		Recipient:           rem.GetRecipient(),
+		Subject:             rem.GetSubject(),
+		Body:                rem.GetBody(),
+		Status:              rem.GetStatus(),
+		Attempts:            rem.GetAttempts(),
+		LastError:           rem.GetLastError(),
+		SendAfter:           rem.GetSendAfter().AsTime(),
+		CreatedAt:           rem.GetCreatedAt().AsTime(),
+	}
+	if v := rem.GetAppointmentUuid(); v != "" {
+		rec.AppointmentUuid = &v
+	}
+	if v := rem.GetSeriesUuid(); v != "" {
+		rec.SeriesUuid = &v
+	}
+	if rem.GetOriginalStartsAt() != nil {
+		t := rem.GetOriginalStartsAt().AsTime()
+		rec.OriginalStartsAt = &t
+	}
+	if rem.GetSentAt() != nil {
+		t := rem.GetSentAt().AsTime()
+		rec.SentAt = &t
+	}
+	if rem.GetUpdatedAt() != nil {
+		t := rem.GetUpdatedAt().AsTime()
+		rec.UpdatedAt = &t
+	}
+	return rec
+}
+
+var _ out.Repository = (*Repository)(nil)
@@ -0,0 +1,273 @@
+package treatmentplans
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/treatmentplans"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	dbErrs "github.com/OPetricevic/physio-tracker/backend/internal/database/dberrors"
+	"github.com/lib/pq"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// planRecord keeps the goals as JSON; they are always read and written with their plan.
+type planRecord struct {
+	Uuid              string         `gorm:"column:uuid;primaryKey"`
+	DoctorUuid        string         `gorm:"column:doctor_uuid"`
+	PatientUuid       string         `gorm:"column:patient_uuid"`
+	ReferralDiagnosis string         `gorm:"column:referral_diagnosis"`
+	Goals             string         `gorm:"column:goals;type:jsonb"`
+	PlannedSessions   int32          `gorm:"column:planned_sessions"`
+	Frequency         string         `gorm:"column:frequency"`
+	Modalities        pq.StringArray `gorm:"column:modalities;type:text[]"`
+	Status            string         `gorm:"column:status"`
+	StartDate         time.Time      `gorm:"column:start_date;type:date"`
+	ClosedAt          *time.Time     `gorm:"column:closed_at"`
+	CreatedAt         time.Time      `gorm:"column:created_at"`
+	UpdatedAt         *time.Time     `gorm:"column:updated_at"`
+}
+
+func (planRecord) TableName() string { return "treatment_plans" }
+
+type goalJSON struct {
+	Uuid        string     `json:"uuid"`
+	Description string     `json:"description"`
+	Measure     string     `json:"measure"`
+	TargetDate  string     `json:"target_date"`
+	Status      string     `json:"status"`
+	AchievedAt  *time.Time `json:"achieved_at,omitempty"`
+}
+
+const goalAchieved = "achieved"
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, plan *pb.TreatmentPlan) (*pb.TreatmentPlan, error) {
+	rec, err := pbToRecord(plan)
+	if err != nil {
+		return nil, fmt.Errorf("creating treatment plan: convert: %w", err)
+	}
+	if err := r.db.WithContext(ctx).Create(&rec).Error; err != nil {
+		if dbErrs.IsForeignKeyViolation(err) {
+			return nil, fmt.Errorf("creating treatment plan: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("creating treatment plan: %w", err)
+	}
+	return r.withSessions(ctx, rec, "creating treatment plan")
+}
+
+func (r *Repository) Update(ctx context.Context, plan *pb.TreatmentPlan) (*pb.TreatmentPlan, error) {
+	rec, err := pbToRecord(plan)
+	if err != nil {
+		return nil, fmt.Errorf("updating treatment plan: convert: %w", err)
+	}
+	res := r.db.WithContext(ctx).Model(&planRecord{}).
+		Where("uuid = ?", plan.GetUuid()).
+		Updates(map[string]interface{}{
+			"referral_diagnosis": rec.ReferralDiagnosis,
+			"goals":              rec.Goals,
+			"planned_sessions":   rec.PlannedSessions,
+			"frequency":          rec.Frequency,
+			"modalities":         rec.Modalities,
+			"status":             rec.Status,
+			"start_date":         rec.StartDate,
+			"closed_at":          rec.ClosedAt,
+			"updated_at":         rec.UpdatedAt,
+		})
+	if res.Error != nil {
+		return nil, fmt.Errorf("updating treatment plan: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("updating treatment plan: %w", re.ErrNotFound)
+	}
+	return r.withSessions(ctx, rec, "updating treatment plan")
+}
+
+func (r *Repository) Get(ctx context.Context, uuid string) (*pb.TreatmentPlan, error) {
+	var rec planRecord
+	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("getting treatment plan: %w", re.ErrNotFound)
+		}
+		return nil, fmt.Errorf("getting treatment plan: %w", err)
+	}
+	return r.withSessions(ctx, rec, "getting treatment plan")
+}
+
+func (r *Repository) List(ctx context.Context, patientUUID string) ([]*pb.TreatmentPlan, error) {
+	var recs []planRecord
+	if err := r.db.WithContext(ctx).
+		Where("patient_uuid = ?", patientUUID).
+		Order("start_date DESC, created_at DESC").
+		Find(&recs).Error; err != nil {
+		return nil, fmt.Errorf("listing treatment plans: %w", err)
+	}
+	if len(recs) == 0 {
+		return []*pb.TreatmentPlan{}, nil
+	}
+	uuids := make([]string, 0, len(recs))
+	for _, rec := range recs {
+		uuids = append(uuids, rec.Uuid)
+	}
+	var counts []struct {
+		TreatmentPlanUuid string
+		Sessions          int32
+	}
+	if err := r.db.WithContext(ctx).Table("anamneses").
+		Select("treatment_plan_uuid, COUNT(*) AS sessions").
+		Where("treatment_plan_uuid IN ?", uuids).
+		Group("treatment_plan_uuid").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("listing treatment plans: count sessions: %w", err)
+	}
+	sessions := make(map[string]int32, len(counts))
+	for _, c := range counts {
+		sessions[c.TreatmentPlanUuid] = c.Sessions
+	}
+	res := make([]*pb.TreatmentPlan, 0, len(recs))
+	for _, rec := range recs {
+		plan, err := recordToPB(rec)
+		if err != nil {
+			return nil, fmt.Errorf("listing treatment plans: %w", err)
+		}
+		plan.SessionsDone = sessions[rec.Uuid]
+		res = append(res, plan)
+	}
+	return res, nil
+}
+
+// Delete removes the plan; linked visits stay and lose the link (ON DELETE SET NULL).
+func (r *Repository) Delete(ctx context.Context, uuid string) error {
+	res := r.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&planRecord{})
+	if res.Error != nil {
+		return fmt.Errorf("deleting treatment plan: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("deleting treatment plan: %w", re.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *Repository) withSessions(ctx context.Context, rec planRecord, op string) (*pb.TreatmentPlan, error) {
+	plan, err := recordToPB(rec)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	var n int64
+	if err := r.db.WithContext(ctx).Table("anamneses").Where("treatment_plan_uuid = ?", rec.Uuid).Count(&n).Error; err != nil {
+		return nil, fmt.Errorf("%s: count sessions: %w", op, err)
+	}
+	plan.SessionsDone = int32(n)
+	return plan, nil
+}
+
+func pbToRecord(plan *pb.TreatmentPlan) (planRecord, error) {
+	goals := make([]goalJSON, 0, len(plan.GetGoals()))
+	for _, g := range plan.GetGoals() {
+		gj := goalJSON{
+			Uuid:        g.GetUuid(),
+			Description: g.GetDescription(),
+			Measure:     g.GetMeasure(),
+			TargetDate:  g.GetTargetDate(),
+			Status:      g.GetStatus(),
+		}
+		if g.GetAchievedAt() != nil {
+			t := g.GetAchievedAt().AsTime()
+			gj.AchievedAt = &t
+		}
+		goals = append(goals, gj)
+	}
+	goalsJSON, err := json.Marshal(goals)
+	if err != nil {
+		return planRecord{}, err
+	}
+	start, err := time.Parse("2006-01-02", plan.GetStartDate())
+	if err != nil {
+		return planRecord{}, fmt.Errorf("start date: %w", err)
+	}
+	modalities := plan.GetModalities()
+	if modalities == nil {
+		modalities = []string{}
+	}
+	rec := planRecord{
+		Uuid:              plan.GetUuid(),
+		DoctorUuid:        plan.GetDoctorUuid(),
+		PatientUuid:       plan.GetPatientUuid(),
+		ReferralDiagnosis: plan.GetReferralDiagnosis(),
+		Goals:             string(goalsJSON),
+		PlannedSessions:   plan.GetPlannedSessions(),
+		Frequency:         plan.GetFrequency(),
+		Modalities:        pq.StringArray(modalities),
+		Status:            plan.GetStatus(),
+		StartDate:         start,
+	}
+	if plan.GetClosedAt() != nil {
+		t := plan.GetClosedAt().AsTime()
+		rec.ClosedAt = &t
+	}
+	if plan.GetCreatedAt() != nil {
+		rec.CreatedAt = plan.GetCreatedAt().AsTime()
+	}
+	if plan.GetUpdatedAt() != nil {
+		t := plan.GetUpdatedAt().AsTime()
+		rec.UpdatedAt = &t
+	}
+	return rec, nil
+}
+
+func recordToPB(rec planRecord) (*pb.TreatmentPlan, error) {
+	var goals []goalJSON
+	if err := json.Unmarshal([]byte(rec.Goals), &goals); err != nil {
+		return nil, fmt.Errorf("decode goals: %w", err)
+	}
+	plan := &pb.TreatmentPlan{
+		Uuid:              rec.Uuid,
+		DoctorUuid:        rec.DoctorUuid,
+		PatientUuid:       rec.PatientUuid,
+		ReferralDiagnosis: rec.ReferralDiagnosis,
+		Goals:             make([]*pb.TreatmentGoal, 0, len(goals)),
+		PlannedSessions:   rec.PlannedSessions,
+		Frequency:         rec.Frequency,
+		Modalities:        []string(rec.Modalities),
+		Status:            rec.Status,
+		StartDate:         rec.StartDate.Format("2006-01-02"),
+		CreatedAt:         timestamppb.New(rec.CreatedAt),
+	}
+	for _, g := range goals {
+		goal := &pb.TreatmentGoal{
+			Uuid:        g.Uuid,
+			Description: g.Description,
+			Measure:     g.Measure,
+			TargetDate:  g.TargetDate,
+			Status:      g.Status,
+		}
+		if g.AchievedAt != nil {
+			goal.AchievedAt = timestamppb.New(*g.AchievedAt)
+		}
+		if g.Status == goalAchieved {
+			plan.GoalsAchieved++
+		}
+		plan.Goals = append(plan.Goals, goal)
+	}
+	if rec.ClosedAt != nil {
+		plan.ClosedAt = timestamppb.New(*rec.ClosedAt)
+	}
+	if rec.UpdatedAt != nil {
+		plan.UpdatedAt = timestamppb.New(*rec.UpdatedAt)
+	}
+	return plan, nil
+}
+
+var _ out.Repository = (*Repository)(nil)
@@ -0,0 +1,115 @@
+// Package filestore keeps attachment content on the local disk.
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/attachments"
+)
+
+// DefaultMaxSize is the largest attachment accepted when ATTACHMENTS_MAX_MB is not set.
+const DefaultMaxSize = 20 << 20
+
+// MaxSizeFromEnv reads ATTACHMENTS_MAX_MB, the largest accepted attachment in megabytes.
+func MaxSizeFromEnv() int64 {
+	if mb, err := strconv.Atoi(strings.TrimSpace(os.Getenv("ATTACHMENTS_MAX_MB"))); err == nil && mb > 0 {
+		return int64(mb) << 20
+	}
+	return DefaultMaxSize
+}
+
+// DirFromEnv reads ATTACHMENTS_DIR (default "attachments"). The directory must not be served by
+// the /static file server.
+func DirFromEnv() string {
+	if dir := strings.TrimSpace(os.Getenv("ATTACHMENTS_DIR")); dir != "" {
+		return dir
+	}
+	return "attachments"
+}
+
+// Local stores every file under its key inside one directory, readable only by the server user.
+type Local struct {
+	dir     string
+	maxSize int64
+}
+
+func NewLocal(dir string, maxSize int64) *Local {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &Local{dir: dir, maxSize: maxSize}
+}
+
+// MaxSize is the largest content Save accepts.
+func (l *Local) MaxSize() int64 { return l.maxSize }
+
+// Save writes the content to a temporary file first and renames it into place, so a failed or
+// oversized upload never leaves a partial file under the key.
+func (l *Local) Save(_ context.Context, key string, r io.Reader) (int64, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return 0, fmt.Errorf("create attachment dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("create attachment file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	n, err := io.Copy(tmp, io.LimitReader(r, l.maxSize+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("write attachment: %w", err)
+	}
+	if n > l.maxSize {
+		return 0, fmt.Errorf("write attachment: over %d MB: %w", l.maxSize>>20, out.ErrTooLarge)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("write attachment: %w", err)
+	}
+	return n, nil
+}
+
+func (l *Local) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open attachment: %w", err)
+	}
+	return f, nil
+}
+
+func (l *Local) Remove(_ context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove attachment: %w", err)
+	}
+	return nil
+}
+
+// path maps a key to a file inside the store; keys are generated by the service, but a key that
+// would escape the directory is still refused.
+func (l *Local) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == "." || strings.HasPrefix(clean, "..") {
+		return "", fmt.Errorf("invalid attachment key %q", key)
+	}
+	return filepath.Join(l.dir, clean), nil
+}
@@ -0,0 +1,48 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/attachments"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewLocal(dir, 10)
+
+	if n, err := store.Save(ctx, "doctor/a", strings.NewReader("0123456789")); err != nil || n != 10 {
+		t.Fatalf("save: got %d, %v", n, err)
+	}
+	rc, err := store.Open(ctx, "doctor/a")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	b, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(b) != "0123456789" {
+		t.Errorf("open: got %q", b)
+	}
+
+	if _, err := store.Save(ctx, "doctor/b", strings.NewReader("01234567890")); !errors.Is(err, out.ErrTooLarge) {
+		t.Errorf("oversized: got %v, want ErrTooLarge", err)
+	}
+	if _, err := os.Stat(dir + "/doctor/b"); !os.IsNotExist(err) {
+		t.Errorf("oversized upload left a file: %v", err)
+	}
+	if _, err := store.Save(ctx, "../escape", strings.NewReader("x")); err == nil {
+		t.Error("key outside the store was accepted")
+	}
+
+	if err := store.Remove(ctx, "doctor/a"); err != nil {
+		t.Errorf("remove: %v", err)
+	}
+	if err := store.Remove(ctx, "doctor/a"); err != nil {
+		t.Errorf("remove missing: %v", err)
+	}
+}
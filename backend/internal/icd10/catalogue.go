@@ -0,0 +1,193 @@
+// Package icd10 loads the ICD-10 (MKB-10) code list bundled with the server and searches it.
+package icd10
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/icd10"
+)
+
+// PathFromEnv reads ICD10_FILE (default assets/icd10/mkb10.csv).
+func PathFromEnv() string {
+	if p := strings.TrimSpace(os.Getenv("ICD10_FILE")); p != "" {
+		return p
+	}
+	return filepath.Join("assets", "icd10", "mkb10.csv")
+}
+
+type entry struct {
+	code        string
+	description string
+	key         string   // code without the dot, upper case
+	words       []string // folded description words
+	text        string   // folded description
+}
+
+// Catalogue keeps the whole code list in memory; it is a few thousand rows at most. The zero value
+// is an empty catalogue.
+type Catalogue struct {
+	entries []entry
+	byKey   map[string]int
+}
+
+var _ out.Catalogue = (*Catalogue)(nil)
+
+// Load reads a semicolon-separated file of code;description rows. A first row starting with
+// "code" is treated as a header.
+func Load(path string) (*Catalogue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("load icd-10 catalogue: %w", err)
+	}
+	defer f.Close()
+	c, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("load icd-10 catalogue %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse reads the catalogue format described at Load.
+func Parse(r io.Reader) (*Catalogue, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = ';'
+	cr.FieldsPerRecord = 2
+	c := &Catalogue{byKey: map[string]int{}}
+	for line := 1; ; line++ {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		code, description := strings.ToUpper(strings.TrimSpace(row[0])), strings.TrimSpace(row[1])
+		if line == 1 && strings.EqualFold(code, "code") {
+			continue
+		}
+		key := codeKey(code)
+		if key == "" || description == "" {
+			return nil, fmt.Errorf("line %d: code and description are required", line)
+		}
+		if _, dup := c.byKey[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate code %s", line, code)
+		}
+		text := fold(description)
+		c.byKey[key] = len(c.entries)
+		c.entries = append(c.entries, entry{code: code, description: description, key: key, words: words(text), text: text})
+	}
+	return c, nil
+}
+
+// Len is the number of codes in the catalogue.
+func (c *Catalogue) Len() int { return len(c.entries) }
+
+func (c *Catalogue) Lookup(code string) (*pb.DiagnosisCode, bool) {
+	i, ok := c.byKey[codeKey(code)]
+	if !ok {
+		return nil, false
+	}
+	return c.entries[i].toPB(), true
+}
+
+// Search ranks exact codes first, then code prefixes ("M54" finds M54.1 to M54.9), then
+// descriptions whose words start with every query word, then descriptions merely containing them.
+func (c *Catalogue) Search(query string, limit int) []*pb.DiagnosisCode {
+	res := []*pb.DiagnosisCode{}
+	key, terms := codeKey(query), words(fold(query))
+	if key == "" && len(terms) == 0 {
+		return res
+	}
+	type hit struct{ rank, index int }
+	var hits []hit
+	for i, e := range c.entries {
+		rank := -1
+		switch {
+		case key != "" && e.key == key:
+			rank = 0
+		case key != "" && strings.HasPrefix(e.key, key):
+			rank = 1
+		case len(terms) > 0 && matchesAll(terms, func(t string) bool { return hasWordPrefix(e.words, t) }):
+			rank = 2
+		case len(terms) > 0 && matchesAll(terms, func(t string) bool { return strings.Contains(e.text, t) }):
+			rank = 3
+		}
+		if rank >= 0 {
+			hits = append(hits, hit{rank, i})
+		}
+	}
+	sort.SliceStable(hits, func(a, b int) bool { return hits[a].rank < hits[b].rank })
+	for _, h := range hits {
+		if limit > 0 && len(res) == limit {
+			break
+		}
+		res = append(res, c.entries[h.index].toPB())
+	}
+	return res
+}
+
+func (e entry) toPB() *pb.DiagnosisCode {
+	return &pb.DiagnosisCode{Code: e.code, Description: e.description}
+}
+
+func matchesAll(terms []string, match func(string) bool) bool {
+	for _, t := range terms {
+		if !match(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasWordPrefix(words []string, prefix string) bool {
+	for _, w := range words {
+		if strings.HasPrefix(w, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// codeKey normalises a code for comparison: upper case without the dot or spaces. Anything that
+// cannot be the start of a code (a letter followed by digits) gives "".
+func codeKey(s string) string {
+	s = strings.ToUpper(strings.NewReplacer(".", "", " ", "").Replace(strings.TrimSpace(s)))
+	if s == "" || s[0] < 'A' || s[0] > 'Z' {
+		return ""
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return s
+}
+
+// foldReplacer strips the diacritics of Croatian and the common western European letters, so that
+// "krizobolja" finds "Križobolja".
+var foldReplacer = strings.NewReplacer(
+	"č", "c", "ć", "c", "đ", "d", "š", "s", "ž", "z",
+	"á", "a", "à", "a", "â", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ñ", "n",
+)
+
+func fold(s string) string {
+	return foldReplacer.Replace(strings.ToLower(s))
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+}
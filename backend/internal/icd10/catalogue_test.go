@@ -0,0 +1,88 @@
+package icd10
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sample = `code;description
+M54.2;Cervikalgija
+M54.4;Lumbago s išijasom
+M54.5;Križobolja
+M75.1;Sindrom rotatorne manšete
+S83.5;Uganuće i istegnuće (prednjega) (stražnjega) križnog ligamenta koljena
+`
+
+func codes(t *testing.T, c *Catalogue, query string) string {
+	t.Helper()
+	var res []string
+	for _, d := range c.Search(query, 0) {
+		res = append(res, d.GetCode())
+	}
+	return strings.Join(res, ",")
+}
+
+func TestSearch(t *testing.T) {
+	c, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	cases := map[string]string{
+		"M54":           "M54.2,M54.4,M54.5",
+		"m54.5":         "M54.5",
+		"M545":          "M54.5",
+		"krizobolja":    "M54.5",
+		"KRIŽ":          "M54.5,S83.5",
+		"mansete":       "M75.1",
+		"ligament kolj": "S83.5",
+		"isijas":        "M54.4",
+		"bolja":         "M54.5", // inside a word, ranked after word prefixes
+		"koljeno":       "",
+	}
+	for query, want := range cases {
+		if got := codes(t, c, query); got != want {
+			t.Errorf("Search(%q) = %s, want %s", query, got, want)
+		}
+	}
+	if got := len(c.Search("M", 2)); got != 2 {
+		t.Errorf("limit: got %d results, want 2", got)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	c, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if d, ok := c.Lookup(" m75.1 "); !ok || d.GetCode() != "M75.1" || d.GetDescription() != "Sindrom rotatorne manšete" {
+		t.Errorf("Lookup: got %v, %v", d, ok)
+	}
+	if _, ok := c.Lookup("M99.9"); ok {
+		t.Error("Lookup found a code that is not in the catalogue")
+	}
+	if _, ok := (&Catalogue{}).Lookup("M54.5"); ok {
+		t.Error("empty catalogue found a code")
+	}
+}
+
+func TestParseRejectsDuplicates(t *testing.T) {
+	if _, err := Parse(strings.NewReader("M54.5;Križobolja\nM545;Križobolja\n")); err == nil {
+		t.Error("duplicate code accepted")
+	}
+}
+
+func TestBundledCatalogue(t *testing.T) {
+	path := filepath.Join("..", "..", "assets", "icd10", "mkb10.csv")
+	if _, err := os.Stat(path); err != nil {
+		t.Skip("bundled catalogue not found")
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, ok := c.Lookup("M54.5"); !ok {
+		t.Error("bundled catalogue has no M54.5")
+	}
+}
@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/reminders"
+)
+
+// SMSConfig configures the SMS gateway notifier.
+type SMSConfig struct {
+	URL     string
+	Token   string // sent as a Bearer token when set
+	Sender  string // sender name or number, if the gateway supports it
+	Timeout time.Duration
+}
+
+// SMSConfigFromEnv reads SMS_GATEWAY_URL, SMS_GATEWAY_TOKEN and SMS_SENDER. ok is false when SMS is
+// not configured.
+func SMSConfigFromEnv() (SMSConfig, bool) {
+	cfg := SMSConfig{
+		URL:    strings.TrimSpace(os.Getenv("SMS_GATEWAY_URL")),
+		Token:  strings.TrimSpace(os.Getenv("SMS_GATEWAY_TOKEN")),
+		Sender: strings.TrimSpace(os.Getenv("SMS_SENDER")),
+	}
+	return cfg, cfg.URL != ""
+}
+
+// SMSGateway posts messages as JSON ({"to", "from", "text"}) to an HTTP gateway. Any 2xx reply
+// counts as accepted.
+type SMSGateway struct {
+	cfg    SMSConfig
+	client *http.Client
+}
+
+func NewSMSGateway(cfg SMSConfig) *SMSGateway {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &SMSGateway{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+type smsRequest struct {
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+	Text string `json:"text"`
+}
+
+func (n *SMSGateway) Send(ctx context.Context, msg out.Message) error {
+	if strings.TrimSpace(msg.To) == "" {
+		return fmt.Errorf("sms: empty recipient: %w", out.ErrRejected)
+	}
+	payload, err := json.Marshal(smsRequest{To: msg.To, From: n.cfg.Sender, Text: msg.Body})
+	if err != nil {
+		return fmt.Errorf("sms: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("sms: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.cfg.Token)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+	err = fmt.Errorf("sms gateway: %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+	// Client errors will not go away on retry; timeouts and rate limits will.
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%v: %w", err, out.ErrRejected)
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/reminders"
+)
+
+func TestSMSGatewaySend(t *testing.T) {
+	var (
+		got  smsRequest
+		auth string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	n := NewSMSGateway(SMSConfig{URL: srv.URL, Token: "tok", Sender: "Fizio"})
+	if err := n.Send(context.Background(), out.Message{To: "+385911234567", Body: "Podsjetnik: termin sutra u 09:30"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if auth != "Bearer tok" {
+		t.Fatalf("authorization = %q", auth)
+	}
+	want := smsRequest{To: "+385911234567", From: "Fizio", Text: "Podsjetnik: termin sutra u 09:30"}
+	if got != want {
+		t.Fatalf("request = %+v, want %+v", got, want)
+	}
+}
+
+func TestSMSGatewayErrors(t *testing.T) {
+	cases := []struct {
+		status   int
+		rejected bool
+	}{
+		{http.StatusBadRequest, true},
+		{http.StatusUnauthorized, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusServiceUnavailable, false},
+	}
+	for _, tc := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "nope", tc.status)
+		}))
+		err := NewSMSGateway(SMSConfig{URL: srv.URL}).Send(context.Background(), out.Message{To: "+385911234567", Body: "x"})
+		srv.Close()
+		if err == nil {
+			t.Fatalf("status %d: expected error", tc.status)
+		}
+		if errors.Is(err, out.ErrRejected) != tc.rejected {
+			t.Fatalf("status %d: err = %v, rejected want %v", tc.status, err, tc.rejected)
+		}
+	}
+}
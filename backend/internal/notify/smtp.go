@@ -0,0 +1,165 @@
+// Package notify delivers reminder messages over e-mail (SMTP) and SMS (HTTP gateway).
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/reminders"
+	"github.com/google/uuid"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// SMTPConfig configures the e-mail notifier.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // empty = no authentication
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// SMTPConfigFromEnv reads SMTP_HOST, SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD and
+// SMTP_FROM. ok is false when e-mail is not configured.
+func SMTPConfigFromEnv() (SMTPConfig, bool) {
+	cfg := SMTPConfig{
+		Host:     strings.TrimSpace(os.Getenv("SMTP_HOST")),
+		Port:     587,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     strings.TrimSpace(os.Getenv("SMTP_FROM")),
+	}
+	if p, err := strconv.Atoi(os.Getenv("SMTP_PORT")); err == nil && p > 0 {
+		cfg.Port = p
+	}
+	return cfg, cfg.Host != "" && cfg.From != ""
+}
+
+// SMTP sends plain-text e-mails. STARTTLS is used whenever the server offers it; net/smtp refuses
+// to send credentials over an unencrypted connection to anything but localhost.
+type SMTP struct {
+	cfg SMTPConfig
+}
+
+func NewSMTP(cfg SMTPConfig) *SMTP {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &SMTP{cfg: cfg}
+}
+
+func (n *SMTP) Send(ctx context.Context, msg out.Message) error {
+	from, err := mail.ParseAddress(n.cfg.From)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid sender %q: %w", n.cfg.From, err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid recipient %q: %w", msg.To, out.ErrRejected)
+	}
+	data, err := buildMail(from, to, msg.Subject, msg.Body, time.Now())
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+
+	addr := net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port))
+	dialer := net.Dialer{Timeout: n.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("smtp: connect %s: %w", addr, err)
+	}
+	deadline := time.Now().Add(n.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.cfg.Host}); err != nil {
+			return fmt.Errorf("smtp: starttls: %w", err)
+		}
+	}
+	if n.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)); err != nil {
+			return smtpError("auth", err)
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return smtpError("mail from", err)
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return smtpError("rcpt to", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError("data", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return smtpError("data", err)
+	}
+	if err := w.Close(); err != nil {
+		return smtpError("data", err)
+	}
+	return c.Quit()
+}
+
+// smtpError marks permanent (5xx) replies as rejected; 4xx and network errors are retried.
+func smtpError(step string, err error) error {
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return fmt.Errorf("smtp %s: %v: %w", step, err, out.ErrRejected)
+	}
+	return fmt.Errorf("smtp %s: %w", step, err)
+}
+
+// buildMail renders a UTF-8 plain-text message; header values are single-line and encoded.
+func buildMail(from, to *mail.Address, subject, body string, at time.Time) ([]byte, error) {
+	subject = strings.Join(strings.Fields(subject), " ")
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", at.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", uuid.NewString(), domainOf(from.Address))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(&buf)
+	body = strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\r\n")
+	return buf.Bytes(), nil
+}
+
+func domainOf(addr string) string {
+	if i := strings.LastIndex(addr, "@"); i >= 0 {
+		return addr[i+1:]
+	}
+	return "localhost"
+}
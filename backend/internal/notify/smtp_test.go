@@ -0,0 +1,164 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/reminders"
+)
+
+// smtpStandIn is a minimal SMTP server for one session. rcptReply overrides the RCPT TO answer.
+type smtpStandIn struct {
+	ln        net.Listener
+	rcptReply string
+	done      chan struct{}
+
+	auth, from, to string
+	data           string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpStandIn{ln: ln, rcptReply: "250 OK", done: make(chan struct{})}
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *smtpStandIn) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "AUTH PLAIN"):
+			s.auth = strings.TrimSpace(line[len("AUTH PLAIN"):])
+			reply("235 Authenticated")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = line[len("MAIL FROM:"):]
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.to = line[len("RCPT TO:"):]
+			reply(s.rcptReply)
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			s.data = b.String()
+			reply("250 Queued")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *smtpStandIn) config() SMTPConfig {
+	addr := s.ln.Addr().(*net.TCPAddr)
+	return SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "Ordinacija <ordinacija@example.com>"}
+}
+
+func TestSMTPSend(t *testing.T) {
+	srv := newSMTPStandIn(t)
+	go srv.serve()
+	cfg := srv.config()
+	cfg.Username, cfg.Password = "user", "secret"
+
+	err := NewSMTP(cfg).Send(context.Background(), out.Message{
+		To:      "ana.horvat@example.com",
+		Subject: "Podsjetnik: termin 03.11.2026. u 09:30",
+		Body:    "Poštovana Ana,\npodsjećamo Vas na termin.",
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	<-srv.done
+
+	if srv.from != "<ordinacija@example.com>" || srv.to != "<ana.horvat@example.com>" {
+		t.Fatalf("envelope = %s -> %s", srv.from, srv.to)
+	}
+	creds, _ := base64.StdEncoding.DecodeString(srv.auth)
+	if string(creds) != "\x00user\x00secret" {
+		t.Fatalf("auth = %q", creds)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(srv.data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Podsjetnik: termin 03.11.2026. u 09:30" {
+		t.Fatalf("subject = %q (%v)", subject, err)
+	}
+	body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if want := "Poštovana Ana,\r\npodsjećamo Vas na termin.\r\n"; string(body) != want {
+		t.Fatalf("body = %q, want %q", body, want)
+	}
+}
+
+func TestSMTPRejectedRecipient(t *testing.T) {
+	srv := newSMTPStandIn(t)
+	srv.rcptReply = "550 No such user"
+	go srv.serve()
+
+	err := NewSMTP(srv.config()).Send(context.Background(), out.Message{To: "nobody@example.com", Body: "x"})
+	if !errors.Is(err, out.ErrRejected) {
+		t.Fatalf("err = %v, want ErrRejected", err)
+	}
+}
+
+func TestSMTPTemporaryFailure(t *testing.T) {
+	srv := newSMTPStandIn(t)
+	srv.rcptReply = "451 Try again later"
+	go srv.serve()
+
+	err := NewSMTP(srv.config()).Send(context.Background(), out.Message{To: "ana@example.com", Body: "x"})
+	if err == nil || errors.Is(err, out.ErrRejected) {
+		t.Fatalf("err = %v, want a retryable error", err)
+	}
+}
+
+func TestSMTPInvalidRecipient(t *testing.T) {
+	err := NewSMTP(SMTPConfig{Host: "127.0.0.1", Port: 1, From: "a@example.com"}).
+		Send(context.Background(), out.Message{To: "not an address", Body: "x"})
+	if !errors.Is(err, out.ErrRejected) {
+		t.Fatalf("err = %v, want ErrRejected", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"sort"
 	"strings"
 	"time"
@@ -13,14 +14,24 @@ import (
 
 	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
 	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/anamneses"
+	outtemplates "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/anamnesistemplates"
+	outconsents "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/consents"
+	outdischarge "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/dischargesummaries"
 	doctorprofilesoutboundport "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctorprofiles"
 	outdoctors "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctors"
+	outicd10 "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/icd10"
 	outboundportpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	outquestionnaires "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/questionnaires"
+	outtreatmentplans "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/treatmentplans"
 	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
 	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcconsents "github.com/OPetricevic/physio-tracker/backend/internal/services/consents"
+	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	svcquestionnaires "github.com/OPetricevic/physio-tracker/backend/internal/services/questionnaires"
+	svctreatmentplans "github.com/OPetricevic/physio-tracker/backend/internal/services/treatmentplans"
 	"github.com/google/uuid"
 	"github.com/jackc/pgconn"
-	"github.com/jung-kurt/gofpdf"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/gorm"
 )
@@ -31,26 +42,66 @@ type Service interface {
 	List(ctx context.Context, doctorUUID, patientUUID, query string, pageSize, currentPage int) ([]*pb.Anamnesis, error)
 	Delete(ctx context.Context, doctorUUID, uuid string) error
 	Get(ctx context.Context, doctorUUID, uuid string) (*pb.Anamnesis, error)
-	GeneratePDF(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string, include []string, onlyCurrent bool) ([]byte, error)
+	GeneratePDF(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string, include []string, onlyCurrent, charts bool) ([]byte, error)
+	Progress(ctx context.Context, doctorUUID, patientUUID string) (*pb.PatientProgressResponse, error)
+	BodyChartRegions() []*pb.BodyChartRegion
+	Revisions(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string) ([]*pb.AnamnesisRevision, error)
+	RevisionDiff(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string, from, to int32) (*pb.AnamnesisRevisionDiffResponse, error)
+	Sign(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string) (*pb.Anamnesis, error)
+	AddAddendum(ctx context.Context, doctorUUID string, req *pb.CreateAnamnesisAddendumRequest) (*pb.AnamnesisAddendum, error)
+	// CreateDischargeSummary builds the final report of a treatment episode and stores its PDF unchanged.
+	CreateDischargeSummary(ctx context.Context, doctorUUID string, req *pb.CreateDischargeSummaryRequest) (*pb.DischargeSummary, error)
+	ListDischargeSummaries(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.DischargeSummary, error)
+	DischargeSummaryPDF(ctx context.Context, doctorUUID, patientUUID, uuid string) ([]byte, error)
+	// RedFlagChecklist is the doctor's red-flag screening, or the default spinal checklist.
+	RedFlagChecklist(ctx context.Context, doctorUUID string) (*pb.RedFlagChecklistResponse, error)
+	UpdateRedFlagChecklist(ctx context.Context, doctorUUID string, req *pb.UpdateRedFlagChecklistRequest) (*pb.RedFlagChecklistResponse, error)
+	OpenRedFlags(ctx context.Context, doctorUUID string) ([]*pb.OpenRedFlag, error)
+	// AutoLock is run periodically by the anamnesis lock worker.
+	AutoLock(ctx context.Context) (int64, error)
+	// HasLockedNotes reports whether one of the patient's notes is signed or auto-locked.
+	HasLockedNotes(ctx context.Context, patientUUID string) (bool, error)
 }
 
 type service struct {
-	repo        out.Repository
-	patientRepo outboundportpatients.Repository
-	profileRepo doctorprofilesoutboundport.Repository
-	doctorRepo  outdoctors.Repository
+	repo          out.Repository
+	patientRepo   outboundportpatients.Repository
+	profileRepo   doctorprofilesoutboundport.Repository
+	doctorRepo    outdoctors.Repository
+	resultRepo    outquestionnaires.Repository
+	planRepo      outtreatmentplans.Repository
+	templRepo     outtemplates.Repository
+	catalogue     outicd10.Catalogue
+	dischargeRepo outdischarge.Repository
+	consentRepo   outconsents.Repository
+
+	autoLockAfter time.Duration
 }
 
 func NewService(
 	repo out.Repository,
 	pRepo outboundportpatients.Repository,
 	profRepo doctorprofilesoutboundport.Repository,
-	dRepo outdoctors.Repository) Service {
+	dRepo outdoctors.Repository,
+	qRepo outquestionnaires.Repository,
+	planRepo outtreatmentplans.Repository,
+	templRepo outtemplates.Repository,
+	catalogue outicd10.Catalogue,
+	dischargeRepo outdischarge.Repository,
+	consentRepo outconsents.Repository) Service {
 	return &service{
-		repo:        repo,
-		patientRepo: pRepo,
-		profileRepo: profRepo,
-		doctorRepo:  dRepo}
+		repo:          repo,
+		patientRepo:   pRepo,
+		profileRepo:   profRepo,
+		doctorRepo:    dRepo,
+		resultRepo:    qRepo,
+		planRepo:      planRepo,
+		templRepo:     templRepo,
+		catalogue:     catalogue,
+		dischargeRepo: dischargeRepo,
+		consentRepo:   consentRepo,
+
+		autoLockAfter: AutoLockAfterFromEnv()}
 }
 
 func (s *service) Create(ctx context.Context, doctorUUID string, req *pb.CreateAnamnesisRequest) (*pb.Anamnesis, error) {
@@ -61,26 +112,69 @@ func (s *service) Create(ctx context.Context, doctorUUID string, req *pb.CreateA
 	if include == nil {
 		include = []string{}
 	}
+	planUUID := strings.TrimSpace(req.GetTreatmentPlanUuid())
+	if err := s.checkPlan(ctx, doctorUUID, req.GetPatientUuid(), planUUID); err != nil {
+		return nil, fmt.Errorf("create anamnesis: %w", err)
+	}
 	now := time.Now().UTC()
+	if templateUUID := strings.TrimSpace(req.GetTemplateUuid()); templateUUID != "" {
+		if err := s.applyTemplate(ctx, doctorUUID, templateUUID, req, now); err != nil {
+			return nil, fmt.Errorf("create anamnesis: %w", err)
+		}
+	}
+	anamnesisUUID := uuid.NewString()
+	measurements, err := buildMeasurements(anamnesisUUID, req.GetMeasurements(), now)
+	if err != nil {
+		return nil, fmt.Errorf("create anamnesis: %w", err)
+	}
+	painMarks, err := buildPainMarks(anamnesisUUID, req.GetPainMarks(), now)
+	if err != nil {
+		return nil, fmt.Errorf("create anamnesis: %w", err)
+	}
+	diagnosisCodes, err := buildDiagnosisCodes(s.catalogue, req.GetDiagnosisCodes(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create anamnesis: %w", err)
+	}
+	redFlags, err := s.redFlagAnswers(ctx, doctorUUID, req.GetRedFlags(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create anamnesis: %w", err)
+	}
 	a := &pb.Anamnesis{
-		Uuid:              uuid.NewString(),
+		Uuid:              anamnesisUUID,
 		PatientUuid:       strings.TrimSpace(req.GetPatientUuid()),
 		Anamnesis:         strings.TrimSpace(req.GetAnamnesis()),
 		Status:            strings.TrimSpace(req.GetStatus()),
 		Diagnosis:         strings.TrimSpace(req.GetDiagnosis()),
+		DiagnosisCodes:    diagnosisCodes,
 		Therapy:           strings.TrimSpace(req.GetTherapy()),
 		OtherInfo:         strings.TrimSpace(req.GetOtherInfo()),
 		IncludeVisitUuids: include,
+		Measurements:      measurements,
+		PainMarks:         painMarks,
+		RedFlags:          redFlags,
+		RedFlagAction:     strings.TrimSpace(req.GetRedFlagAction()),
+		TreatmentPlanUuid: planUUID,
 		CreatedAt:         timestamppb.New(now),
 		UpdatedAt:         nil,
 	}
-	created, err := s.repo.Create(ctx, a)
+	created, err := s.repo.Create(ctx, a, doctorUUID)
 	if err != nil {
 		if isUniqueViolation(err) {
 			return nil, fmt.Errorf("create anamnesis: %w", se.ErrConflict)
 		}
 		return nil, fmt.Errorf("create anamnesis: %w", err)
 	}
+	// The note is saved either way; a missing consent is for the doctor to follow up.
+	switch ok, err := s.consentRepo.HasValid(ctx, a.GetPatientUuid(), svcconsents.KindTreatment, now); {
+	case err != nil:
+		log.Printf("anamneses: checking treatment consent of patient %s: %v", a.GetPatientUuid(), err)
+		created.Warnings = append(created.Warnings, "the treatment consent of the patient could not be verified")
+	case !ok:
+		created.Warnings = append(created.Warnings, "the patient has no valid treatment consent")
+	}
+	if hasOpenRedFlags(created) {
+		created.Warnings = append(created.Warnings, "positive red flags: record the action taken before signing")
+	}
 	return created, nil
 }
 
@@ -98,6 +192,9 @@ func (s *service) Update(ctx context.Context, doctorUUID string, req *pb.UpdateA
 	if strings.TrimSpace(existing.GetPatientUuid()) != strings.TrimSpace(req.GetPatientUuid()) {
 		return nil, fmt.Errorf("update anamnesis: %w", se.ErrInvalidRequest)
 	}
+	if err := s.checkUnlocked(existing, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("update anamnesis: %w", err)
+	}
 	if req.Anamnesis != nil {
 		existing.Anamnesis = strings.TrimSpace(req.Anamnesis.GetValue())
 	}
@@ -116,13 +213,49 @@ func (s *service) Update(ctx context.Context, doctorUUID string, req *pb.UpdateA
 	if req.IncludeVisitUuids != nil {
 		existing.IncludeVisitUuids = req.IncludeVisitUuids
 	}
-	existing.UpdatedAt = timestamppb.New(time.Now().UTC())
+	if req.TreatmentPlanUuid != nil {
+		planUUID := strings.TrimSpace(req.TreatmentPlanUuid.GetValue())
+		if planUUID != existing.GetTreatmentPlanUuid() {
+			if err := s.checkPlan(ctx, doctorUUID, existing.GetPatientUuid(), planUUID); err != nil {
+				return nil, fmt.Errorf("update anamnesis: %w", err)
+			}
+		}
+		existing.TreatmentPlanUuid = planUUID
+	}
+	now := time.Now().UTC()
+	if req.Measurements != nil {
+		if existing.Measurements, err = buildMeasurements(existing.GetUuid(), req.GetMeasurements().GetItems(), now); err != nil {
+			return nil, fmt.Errorf("update anamnesis: %w", err)
+		}
+	}
+	if req.PainMarks != nil {
+		if existing.PainMarks, err = buildPainMarks(existing.GetUuid(), req.GetPainMarks().GetItems(), now); err != nil {
+			return nil, fmt.Errorf("update anamnesis: %w", err)
+		}
+	}
+	if req.DiagnosisCodes != nil {
+		if existing.DiagnosisCodes, err = buildDiagnosisCodes(s.catalogue, req.GetDiagnosisCodes().GetCodes(), existing.GetDiagnosisCodes()); err != nil {
+			return nil, fmt.Errorf("update anamnesis: %w", err)
+		}
+	}
+	if req.RedFlags != nil {
+		if existing.RedFlags, err = s.redFlagAnswers(ctx, doctorUUID, req.GetRedFlags().GetItems(), existing.GetRedFlags()); err != nil {
+			return nil, fmt.Errorf("update anamnesis: %w", err)
+		}
+	}
+	if req.RedFlagAction != nil {
+		existing.RedFlagAction = strings.TrimSpace(req.RedFlagAction.GetValue())
+	}
+	existing.UpdatedAt = timestamppb.New(now)
 
-	updated, err := s.repo.Update(ctx, existing)
+	updated, err := s.repo.Update(ctx, existing, doctorUUID)
 	if err != nil {
 		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("update anamnesis: %w", se.ErrNotFound)
 		}
+		if errors.Is(err, re.ErrConflict) {
+			return nil, fmt.Errorf("update anamnesis: %s was locked meanwhile, add an addendum instead: %w", existing.GetUuid(), se.ErrConflict)
+		}
 		if isUniqueViolation(err) {
 			return nil, fmt.Errorf("update anamnesis: %w", se.ErrConflict)
 		}
@@ -153,12 +286,26 @@ func (s *service) Delete(ctx context.Context, doctorUUID, uuid string) error {
 	if strings.TrimSpace(uuid) == "" {
 		return fmt.Errorf("delete anamnesis: %w", se.ErrInvalidRequest)
 	}
-	if err := s.repo.Delete(ctx, uuid); err != nil {
+	existing, err := s.repo.Get(ctx, uuid)
+	if err != nil {
 		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
 			return fmt.Errorf("delete anamnesis: %w", se.ErrNotFound)
 		}
 		return fmt.Errorf("delete anamnesis: %w", err)
 	}
+	now := time.Now().UTC()
+	if err := s.checkUnlocked(existing, now); err != nil {
+		return fmt.Errorf("delete anamnesis: %w", err)
+	}
+	if err := s.repo.Delete(ctx, uuid, doctorUUID, now); err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("delete anamnesis: %w", se.ErrNotFound)
+		}
+		if errors.Is(err, re.ErrConflict) {
+			return fmt.Errorf("delete anamnesis: %s was locked meanwhile: %w", uuid, se.ErrConflict)
+		}
+		return fmt.Errorf("delete anamnesis: %w", err)
+	}
 	return nil
 }
 
@@ -176,6 +323,67 @@ func (s *service) Get(ctx context.Context, doctorUUID, uuid string) (*pb.Anamnes
 	return anm, nil
 }
 
+// checkPlan verifies that a visit may be linked to the plan: it must belong to the patient and still be active.
+func (s *service) checkPlan(ctx context.Context, doctorUUID, patientUUID, planUUID string) error {
+	if planUUID == "" {
+		return nil
+	}
+	plan, err := s.planRepo.Get(ctx, planUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return fmt.Errorf("treatment plan %s: %w", planUUID, se.ErrNotFound)
+		}
+		return fmt.Errorf("load treatment plan: %w", err)
+	}
+	if plan.GetDoctorUuid() != doctorUUID || plan.GetPatientUuid() != strings.TrimSpace(patientUUID) {
+		return fmt.Errorf("treatment plan %s: %w", planUUID, se.ErrNotFound)
+	}
+	if plan.GetStatus() != svctreatmentplans.StatusActive {
+		return fmt.Errorf("treatment plan %s is %s: %w", planUUID, plan.GetStatus(), se.ErrInvalidRequest)
+	}
+	return nil
+}
+
+// applyTemplate prefills the request from one of the doctor's templates, resolving its placeholders
+// for the patient.
+func (s *service) applyTemplate(ctx context.Context, doctorUUID, templateUUID string, req *pb.CreateAnamnesisRequest, now time.Time) error {
+	t, err := s.templRepo.Get(ctx, templateUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return fmt.Errorf("template %s: %w", templateUUID, se.ErrNotFound)
+		}
+		return fmt.Errorf("load template: %w", err)
+	}
+	if t.GetDoctorUuid() != doctorUUID {
+		return fmt.Errorf("template %s: %w", templateUUID, se.ErrNotFound)
+	}
+	patient, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, strings.TrimSpace(req.GetPatientUuid()))
+	if err != nil {
+		return fmt.Errorf("patient %s: %w", req.GetPatientUuid(), err)
+	}
+	last, err := s.repo.List(ctx, patient.GetUuid(), doctorUUID, "", 1, 0)
+	if err != nil {
+		return fmt.Errorf("load last visit: %w", err)
+	}
+	var lastVisit *pb.Anamnesis
+	if len(last) > 0 {
+		lastVisit = last[0]
+	}
+	// Dates, ages and day counts are those of the practice, not of UTC.
+	profile, _ := s.profileRepo.GetByDoctor(ctx, doctorUUID) // optional
+	loc, err := svcdoctorprofiles.ScheduleLocation(profile.GetSchedule())
+	if err != nil {
+		loc = time.UTC
+	}
+	applyTemplate(req, t, templateValues(patient, lastVisit, now.In(loc)))
+	return nil
+}
+
+// BodyChartRegions lists the named areas of the body chart for the visit form.
+func (s *service) BodyChartRegions() []*pb.BodyChartRegion {
+	return bodyRegionList()
+}
+
 func isUniqueViolation(err error) bool {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
@@ -184,7 +392,7 @@ func isUniqueViolation(err error) bool {
 	return false
 }
 
-func (s *service) GeneratePDF(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string, include []string, onlyCurrent bool) ([]byte, error) {
+func (s *service) GeneratePDF(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string, include []string, onlyCurrent, charts bool) ([]byte, error) {
 	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(patientUUID) == "" || strings.TrimSpace(anamnesisUUID) == "" {
 		return nil, fmt.Errorf("generate pdf: %w", se.ErrInvalidRequest)
 	}
@@ -239,124 +447,57 @@ func (s *service) GeneratePDF(ctx context.Context, doctorUUID, patientUUID, anam
 	profile, _ := s.profileRepo.GetByDoctor(ctx, doctorUUID) // optional
 	doctor, _ := s.doctorRepo.Get(ctx, doctorUUID)           // optional
 
-	return buildPDF(profile, doctor, patient, target, prior)
-}
-
-func buildPDF(profile *pb.DoctorProfile, doctor *pb.Doctor, patient *pb.Patient, current *pb.Anamnesis, prior []*pb.Anamnesis) ([]byte, error) {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	// UTF-8 fonts to render regional characters correctly.
-	fontDir := filepath.Join("assets", "fonts")
-	pdf.SetFontLocation(fontDir)
-	pdf.AddUTF8Font("DejaVu", "", "DejaVuSans.ttf")
-	pdf.AddUTF8Font("DejaVu", "B", "DejaVuSans-Bold.ttf")
-	if pdf.Err() {
-		return nil, fmt.Errorf("generate pdf: load fonts from %s: %v", fontDir, pdf.Error())
-	}
-	pdf.SetMargins(15, 15, 15)
-	pdf.AddPage()
-	printedOn := time.Now().Format("02.01.2006.")
-	tr := func(s string) string { return s }
-
-	// Header
-	headerTop := 15.0
-	logoSize := 24.0
-	textX := 45.0
-	if profile != nil && profile.GetLogoPath() != "" {
-		if local := localFromStatic(profile.GetLogoPath()); local != "" {
-			pdf.ImageOptions(local, 15, headerTop, logoSize, 0, false, gofpdf.ImageOptions{ImageType: "", ReadDpi: true}, 0, "")
-		}
+	// Questionnaire results linked to a printed visit are listed under that visit.
+	visitUUIDs := []string{target.GetUuid()}
+	for _, v := range prior {
+		visitUUIDs = append(visitUUIDs, v.GetUuid())
+	}
+	linked, err := s.resultRepo.ListForAnamneses(ctx, visitUUIDs)
+	if err != nil {
+		return nil, fmt.Errorf("generate pdf: load questionnaire results: %w", err)
+	}
+	results := map[string][]*pb.QuestionnaireResult{}
+	for _, res := range linked {
+		results[res.GetAnamnesisUuid()] = append(results[res.GetAnamnesisUuid()], res)
 	}
-	// printed date top-right
-	pdf.SetFont("DejaVu", "", 10)
-	pdf.SetXY(-70, headerTop)
-	pdf.Cell(55, 5, tr("Datum ispisa: "+printedOn))
 
-	pdf.SetFont("DejaVu", "B", 12)
-	if profile != nil {
-		pdf.SetXY(textX, headerTop)
-		pdf.Cell(0, 6, tr(profile.GetPracticeName()))
-		pdf.Ln(6)
-		pdf.SetFont("DejaVu", "", 10)
-		pdf.SetX(textX)
-		roleDept := strings.TrimSpace(strings.TrimSpace(profile.GetRoleTitle()) + " " + strings.TrimSpace(profile.GetDepartment()))
-		if roleDept != "" {
-			pdf.Cell(0, 5, tr(roleDept))
-			pdf.Ln(5)
-			pdf.SetX(textX)
+	// The plan of the printed visit is summarised at the top; a deleted plan is simply left out.
+	var plan *pb.TreatmentPlan
+	if planUUID := target.GetTreatmentPlanUuid(); planUUID != "" {
+		plan, err = s.planRepo.Get(ctx, planUUID)
+		if err != nil && !errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("generate pdf: load treatment plan: %w", err)
 		}
-		pdf.SetX(textX)
-		pdf.Cell(0, 5, tr(profile.GetAddress()))
-		pdf.Ln(5)
-		pdf.SetFont("DejaVu", "", 10)
-		pdf.SetX(textX)
-		if strings.TrimSpace(profile.GetPhone()) != "" {
-			pdf.Cell(0, 5, tr(profile.GetPhone()))
-			pdf.Ln(5)
-			pdf.SetX(textX)
-		}
-		if strings.TrimSpace(profile.GetEmail()) != "" {
-			pdf.Cell(0, 5, tr(profile.GetEmail()))
-			pdf.Ln(5)
-			pdf.SetX(textX)
-		}
-		if strings.TrimSpace(profile.GetWebsite()) != "" {
-			pdf.SetX(textX)
-			pdf.Cell(0, 5, tr(strings.TrimSpace(profile.GetWebsite())))
-			pdf.Ln(5)
-		}
-		pdf.Ln(6)
 	}
 
-	//centered title
-	headerBottom := pdf.GetY()
-	pdf.SetX(15)
-	pdf.Line(15, headerBottom, 195, headerBottom)
-	pdf.Ln(6)
-	pdf.SetFont("DejaVu", "B", 12)
-	pdf.CellFormat(0, 6, tr("MIŠLJENJE FIZIOTERAPEUTA"), "", 1, "C", false, 0, "")
-	pdf.Ln(6)
-
-	pdf.SetFont("DejaVu", "B", 10)
-	pdf.Cell(40, 5, tr("Pacijent:"))
-	pdf.SetFont("DejaVu", "", 10)
-	pdf.Cell(0, 5, tr(strings.TrimSpace(patient.GetFirstName()+" "+patient.GetLastName())))
-	pdf.Ln(5)
-	if dob := patient.GetDateOfBirth(); dob != nil && strings.TrimSpace(dob.GetValue()) != "" {
-		pdf.SetFont("DejaVu", "B", 10)
-		pdf.Cell(40, 5, tr("Datum rođenja:"))
-		pdf.SetFont("DejaVu", "", 10)
-		pdf.Cell(0, 5, tr(formatPlainDate(dob.GetValue())))
-		pdf.Ln(5)
-	}
-	if patient.GetPhone() != nil {
-		pdf.SetFont("DejaVu", "B", 10)
-		pdf.Cell(40, 5, tr("Telefon:"))
-		pdf.SetFont("DejaVu", "", 10)
-		pdf.Cell(0, 5, tr(patient.GetPhone().GetValue()))
-		pdf.Ln(5)
+	return buildPDF(profile, doctor, patient, target, prior, plan, results, charts)
+}
+
+func buildPDF(profile *pb.DoctorProfile, doctor *pb.Doctor, patient *pb.Patient, current *pb.Anamnesis, prior []*pb.Anamnesis, plan *pb.TreatmentPlan, results map[string][]*pb.QuestionnaireResult, charts bool) ([]byte, error) {
+	pdf, err := newDocument()
+	if err != nil {
+		return nil, fmt.Errorf("generate pdf: %w", err)
 	}
-	if patient.GetAddress() != nil {
-		pdf.SetFont("DejaVu", "B", 10)
-		pdf.Cell(40, 5, tr("Adresa:"))
-		pdf.SetFont("DejaVu", "", 10)
-		pdf.Cell(0, 5, tr(patient.GetAddress().GetValue()))
-		pdf.Ln(8)
+	// Signing and addendum times are printed in the practice's time zone.
+	loc, err := svcdoctorprofiles.ScheduleLocation(profile.GetSchedule())
+	if err != nil {
+		loc = time.UTC
 	}
+	tr := func(s string) string { return s }
+	drawHeader(pdf, profile, patient, "MIŠLJENJE FIZIOTERAPEUTA")
 
-	// set line to separate header from content (match header thickness)
-	pdf.SetLineWidth(0.2)
-	pdf.Line(15, pdf.GetY(), 195, pdf.GetY())
-	pdf.Ln(6)
+	writeField := fieldWriter(pdf)
 
-	writeField := func(label, value string) {
-		if strings.TrimSpace(value) == "" {
-			return
-		}
-		pdf.SetFont("DejaVu", "B", 10)
-		pdf.MultiCell(0, 5, tr(label), "", "", false)
-		pdf.SetFont("DejaVu", "", 10)
-		pdf.MultiCell(0, 5, tr(value), "", "", false)
-		pdf.Ln(2)
+	if plan != nil {
+		pdf.SetFont("DejaVu", "B", 11)
+		pdf.MultiCell(0, 5, tr("Plan liječenja"), "", "", false)
+		pdf.Ln(1)
+		writeField("Uputna dijagnoza", plan.GetReferralDiagnosis())
+		writeField("Tijek", formatPlanProgress(plan, current.GetSessionNumber()))
+		writeField("Ciljevi", formatGoals(plan.GetGoals()))
+		pdf.SetLineWidth(0.2)
+		pdf.Line(15, pdf.GetY(), 195, pdf.GetY())
+		pdf.Ln(6)
 	}
 
 	visits := make([]*pb.Anamnesis, 0, len(prior)+1)
@@ -377,40 +518,47 @@ func buildPDF(profile *pb.DoctorProfile, doctor *pb.Doctor, patient *pb.Patient,
 	for i, v := range visits {
 		pdf.SetFont("DejaVu", "B", 11)
 		label := fmt.Sprintf("%d. posjet - %s", i+1, formatDate(v.GetCreatedAt()))
+		if plan != nil && v.GetTreatmentPlanUuid() == plan.GetUuid() && v.GetSessionNumber() > 0 {
+			label += fmt.Sprintf(" (termin %d od %d)", v.GetSessionNumber(), plan.GetPlannedSessions())
+		}
 		pdf.MultiCell(0, 5, tr(label), "", "", false)
 		pdf.SetFont("DejaVu", "", 10)
 		writeField("Anamneza", v.GetAnamnesis())
 		writeField("Status", v.GetStatus())
-		writeField("Dijagnoza", v.GetDiagnosis())
+		writeField("Dijagnoza", formatDiagnosis(v))
 		writeField("Terapija", v.GetTherapy())
 		writeField("Ostalo", v.GetOtherInfo())
+		writeField("Mjerenja", formatMeasurements(v.GetMeasurements()))
+		drawBodyChart(pdf, v.GetPainMarks())
+		drawRedFlags(pdf, v)
+		writeField("Upitnici", formatResults(results[v.GetUuid()]))
+		writeField("Dodaci", formatAddenda(v.GetAddenda(), loc))
+		signer := ""
+		if doctor != nil && v.GetSignedByUuid() == doctor.GetUuid() {
+			signer = strings.TrimSpace(doctor.GetFirstName() + " " + doctor.GetLastName())
+		}
+		writeField("Potpis", formatSignature(v, signer, loc))
 		pdf.Ln(2)
 	}
-
-	// Footer signature (doctor)
-	if doctor != nil {
-		name := strings.TrimSpace(doctor.GetFirstName() + " " + doctor.GetLastName())
-		if name != "" {
-			pdf.SetY(-35)
-			pdf.SetX(15)
-			pdf.SetFont("DejaVu", "B", 9)
-			pdf.Cell(0, 5, tr("Fizioterapeut:"))
-			pdf.Ln(5)
-			pdf.SetX(15)
-			pdf.SetFont("DejaVu", "", 9)
-			pdf.Cell(0, 5, tr("bacc.physioth "+name))
-		}
+	if charts && len(visits) > 1 {
+		drawCharts(pdf, chartSeries(visits))
 	}
 
-	if pdf.Err() {
-		return nil, fmt.Errorf("generate pdf: prepare content: %v", pdf.Error())
+	drawDoctorSignature(pdf, doctor)
+
+	b, err := outputDocument(pdf)
+	if err != nil {
+		return nil, fmt.Errorf("generate pdf: %w", err)
 	}
+	return b, nil
+}
 
-	var buf strings.Builder
-	if err := pdf.Output(&buf); err != nil {
-		return nil, fmt.Errorf("generate pdf: render: %w", err)
+func formatResults(list []*pb.QuestionnaireResult) string {
+	lines := make([]string, 0, len(list))
+	for _, res := range list {
+		lines = append(lines, svcquestionnaires.Summary(res))
 	}
-	return []byte(buf.String()), nil
+	return strings.Join(lines, "\n")
 }
 
 func formatDate(ts *timestamppb.Timestamp) string {
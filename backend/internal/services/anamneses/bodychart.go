@@ -0,0 +1,265 @@
+package anamneses
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	ViewFront = "front"
+	ViewBack  = "back"
+
+	bodyWidth  = 40.0 // mm, one outline in the PDF
+	bodyHeight = 88.0
+)
+
+var painTypeLabels = map[string]string{
+	"aching":    "tupa",
+	"sharp":     "oštra",
+	"burning":   "žareća",
+	"tingling":  "trnci",
+	"numbness":  "utrnulost",
+	"radiating": "isijavajuća",
+	"stiffness": "ukočenost",
+}
+
+// painTypeColors tell the pain types apart on the printed chart.
+var painTypeColors = map[string][3]int{
+	"aching":    {200, 60, 40},
+	"sharp":     {220, 20, 60},
+	"burning":   {240, 130, 0},
+	"tingling":  {130, 60, 180},
+	"numbness":  {40, 110, 200},
+	"radiating": {200, 0, 140},
+	"stiffness": {90, 130, 50},
+}
+
+// bodyRegion is an area of the outline. x is given for the patient's right side on the front
+// view, where it is drawn on the left; the other sides and views mirror it.
+type bodyRegion struct {
+	code  string
+	label string
+	front bool
+	back  bool
+	sided bool
+	x, y  float64
+}
+
+var bodyRegions = []bodyRegion{
+	{code: "head", label: "glava", front: true, back: true, x: 0.5, y: 0.065},
+	{code: "neck", label: "vrat", front: true, x: 0.5, y: 0.13},
+	{code: "cervical", label: "vratna kralježnica", back: true, x: 0.5, y: 0.13},
+	{code: "shoulder", label: "rame", front: true, back: true, sided: true, x: 0.3, y: 0.17},
+	{code: "scapula", label: "lopatica", back: true, sided: true, x: 0.39, y: 0.22},
+	{code: "chest", label: "prsni koš", front: true, x: 0.5, y: 0.22},
+	{code: "thoracic", label: "prsna kralježnica", back: true, x: 0.5, y: 0.26},
+	{code: "upper_arm", label: "nadlaktica", front: true, back: true, sided: true, x: 0.24, y: 0.28},
+	{code: "elbow", label: "lakat", front: true, back: true, sided: true, x: 0.21, y: 0.37},
+	{code: "forearm", label: "podlaktica", front: true, back: true, sided: true, x: 0.18, y: 0.44},
+	{code: "wrist_hand", label: "šaka i ručni zglob", front: true, back: true, sided: true, x: 0.15, y: 0.52},
+	{code: "abdomen", label: "trbuh", front: true, x: 0.5, y: 0.36},
+	{code: "lumbar", label: "slabinska kralježnica", back: true, x: 0.5, y: 0.38},
+	{code: "sacrum", label: "križa", back: true, x: 0.5, y: 0.46},
+	{code: "groin", label: "prepona", front: true, sided: true, x: 0.41, y: 0.5},
+	{code: "buttock", label: "gluteus", back: true, sided: true, x: 0.4, y: 0.5},
+	{code: "thigh", label: "natkoljenica", front: true, back: true, sided: true, x: 0.39, y: 0.62},
+	{code: "knee", label: "koljeno", front: true, back: true, sided: true, x: 0.4, y: 0.75},
+	{code: "lower_leg", label: "potkoljenica", front: true, back: true, sided: true, x: 0.41, y: 0.85},
+	{code: "ankle_foot", label: "gležanj i stopalo", front: true, back: true, sided: true, x: 0.41, y: 0.96},
+}
+
+// regionPoints lists every region code with its centre per view, e.g. "back|right_knee".
+var regionPoints = func() map[string]*pb.BodyChartRegion {
+	res := map[string]*pb.BodyChartRegion{}
+	for _, r := range bodyRegionList() {
+		res[r.GetView()+"|"+r.GetCode()] = r
+	}
+	return res
+}()
+
+// bodyRegionList expands the regions to one entry per view and side.
+func bodyRegionList() []*pb.BodyChartRegion {
+	var res []*pb.BodyChartRegion
+	for _, view := range []string{ViewFront, ViewBack} {
+		for _, r := range bodyRegions {
+			if (view == ViewFront && !r.front) || (view == ViewBack && !r.back) {
+				continue
+			}
+			if !r.sided {
+				res = append(res, &pb.BodyChartRegion{Code: r.code, View: view, Label: r.label, X: r.x, Y: r.y})
+				continue
+			}
+			right, left := r.x, 1-r.x
+			if view == ViewBack {
+				right, left = left, right
+			}
+			res = append(res,
+				&pb.BodyChartRegion{Code: "right_" + r.code, View: view, Label: r.label + ", " + sideLabels["right"], X: right, Y: r.y},
+				&pb.BodyChartRegion{Code: "left_" + r.code, View: view, Label: r.label + ", " + sideLabels["left"], X: left, Y: r.y})
+		}
+	}
+	return res
+}
+
+// buildPainMarks validates the body chart of a visit. A region without coordinates is placed at
+// the region's centre; a point without a region is kept as drawn.
+func buildPainMarks(anamnesisUUID string, inputs []*pb.PainMarkInput, now time.Time) ([]*pb.PainMark, error) {
+	res := make([]*pb.PainMark, 0, len(inputs))
+	for i, in := range inputs {
+		view := strings.ToLower(strings.TrimSpace(in.GetView()))
+		if view != ViewFront && view != ViewBack {
+			return nil, fmt.Errorf("pain mark %d: unknown view %q: %w", i+1, in.GetView(), se.ErrInvalidRequest)
+		}
+		painType := strings.ToLower(strings.TrimSpace(in.GetPainType()))
+		if _, ok := painTypeLabels[painType]; !ok {
+			return nil, fmt.Errorf("pain mark %d: unknown pain type %q: %w", i+1, in.GetPainType(), se.ErrInvalidRequest)
+		}
+		if in.GetIntensity() < 0 || in.GetIntensity() > 10 {
+			return nil, fmt.Errorf("pain mark %d: intensity must be between 0 and 10: %w", i+1, se.ErrInvalidRequest)
+		}
+		region := strings.ToLower(strings.TrimSpace(in.GetRegion()))
+		mark := &pb.PainMark{
+			Uuid:          uuid.NewString(),
+			AnamnesisUuid: anamnesisUUID,
+			View:          view,
+			Region:        region,
+			PainType:      painType,
+			Intensity:     in.GetIntensity(),
+			Note:          strings.TrimSpace(in.GetNote()),
+			CreatedAt:     timestamppb.New(now),
+		}
+		if region != "" {
+			r, ok := regionPoints[view+"|"+region]
+			if !ok {
+				return nil, fmt.Errorf("pain mark %d: unknown region %q on the %s view: %w", i+1, in.GetRegion(), view, se.ErrInvalidRequest)
+			}
+			mark.X, mark.Y = r.GetX(), r.GetY()
+		}
+		switch {
+		case (in.X == nil) != (in.Y == nil):
+			return nil, fmt.Errorf("pain mark %d: x and y go together: %w", i+1, se.ErrInvalidRequest)
+		case in.X != nil:
+			mark.X, mark.Y = in.GetX().GetValue(), in.GetY().GetValue()
+			if !inUnit(mark.X) || !inUnit(mark.Y) {
+				return nil, fmt.Errorf("pain mark %d: x and y must be between 0 and 1: %w", i+1, se.ErrInvalidRequest)
+			}
+		case region == "":
+			return nil, fmt.Errorf("pain mark %d: needs a region or a point: %w", i+1, se.ErrInvalidRequest)
+		}
+		res = append(res, mark)
+	}
+	return res, nil
+}
+
+func inUnit(v float64) bool {
+	return !math.IsNaN(v) && v >= 0 && v <= 1
+}
+
+// formatPainMarks lists the marks in the order they are numbered on the chart,
+// e.g. "1. slabinska kralježnica (straga): isijavajuća, 7/10".
+func formatPainMarks(list []*pb.PainMark) string {
+	lines := make([]string, 0, len(list))
+	for i, m := range list {
+		where := "označeno mjesto"
+		if r := regionPoints[m.GetView()+"|"+m.GetRegion()]; r != nil {
+			where = r.GetLabel()
+		}
+		view := "sprijeda"
+		if m.GetView() == ViewBack {
+			view = "straga"
+		}
+		line := fmt.Sprintf("%d. %s (%s): %s, %d/10", i+1, where, view, painTypeLabels[m.GetPainType()], m.GetIntensity())
+		if m.GetNote() != "" {
+			line += " – " + m.GetNote()
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// bodyOutline is the left half of the figure as printed (neck to crotch); the figure is
+// the path mirrored at x = 0.5. The head is drawn separately.
+var bodyOutline = [][2]float64{
+	{0.45, 0.12}, {0.3, 0.155}, {0.23, 0.19}, {0.19, 0.3}, {0.17, 0.37}, {0.13, 0.5}, {0.12, 0.56},
+	{0.17, 0.57}, {0.19, 0.51}, {0.24, 0.38}, {0.28, 0.29}, {0.31, 0.26}, {0.32, 0.4}, {0.3, 0.5},
+	{0.32, 0.7}, {0.34, 0.76}, {0.37, 0.94}, {0.34, 0.99}, {0.46, 0.99}, {0.45, 0.94}, {0.46, 0.76},
+	{0.48, 0.62}, {0.5, 0.53},
+}
+
+// drawBodyChart prints the front and back outline next to each other with the numbered marks,
+// followed by the list of marks.
+// Circle size grows with the intensity, the colour shows the pain type.
+func drawBodyChart(pdf *gofpdf.Fpdf, marks []*pb.PainMark) {
+	if len(marks) == 0 {
+		return
+	}
+	if pdf.GetY()+bodyHeight+14 > pageBottom {
+		pdf.AddPage()
+	}
+	pdf.SetFont("DejaVu", "B", 10)
+	pdf.MultiCell(0, 5, "Mapa boli", "", "", false)
+	top := pdf.GetY() + 6
+	views := []struct {
+		view, label string
+		x           float64
+	}{{ViewFront, "Sprijeda", 40}, {ViewBack, "Straga", 110}}
+	for _, v := range views {
+		pdf.SetFont("DejaVu", "", 8)
+		pdf.SetXY(v.x, top-5)
+		pdf.CellFormat(bodyWidth, 4, v.label, "", 0, "C", false, 0, "")
+		drawOutline(pdf, v.x, top)
+		for i, m := range marks {
+			if m.GetView() == v.view {
+				drawMark(pdf, v.x+m.GetX()*bodyWidth, top+m.GetY()*bodyHeight, i+1, m)
+			}
+		}
+	}
+	pdf.SetLineWidth(0.2)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetFillColor(255, 255, 255)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetXY(15, top+bodyHeight+4)
+	pdf.SetFont("DejaVu", "", 10)
+	pdf.MultiCell(0, 5, formatPainMarks(marks), "", "", false)
+	pdf.Ln(2)
+}
+
+func drawOutline(pdf *gofpdf.Fpdf, x, y float64) {
+	pt := func(px, py float64) gofpdf.PointType {
+		return gofpdf.PointType{X: x + px*bodyWidth, Y: y + py*bodyHeight}
+	}
+	points := make([]gofpdf.PointType, 0, 2*len(bodyOutline))
+	for _, p := range bodyOutline {
+		points = append(points, pt(p[0], p[1]))
+	}
+	for i := len(bodyOutline) - 2; i >= 0; i-- {
+		points = append(points, pt(1-bodyOutline[i][0], bodyOutline[i][1]))
+	}
+	pdf.SetLineWidth(0.3)
+	pdf.SetDrawColor(110, 110, 110)
+	pdf.SetFillColor(245, 245, 245)
+	pdf.Polygon(points, "DF")
+	head := pt(0.5, 0.065)
+	pdf.Ellipse(head.X, head.Y, 0.09*bodyWidth, 0.052*bodyHeight, 0, "DF")
+}
+
+func drawMark(pdf *gofpdf.Fpdf, x, y float64, n int, m *pb.PainMark) {
+	c := painTypeColors[m.GetPainType()]
+	pdf.SetFillColor(c[0], c[1], c[2])
+	pdf.SetDrawColor(c[0], c[1], c[2])
+	pdf.SetAlpha(0.6, "Normal")
+	pdf.Circle(x, y, 1.2+0.2*float64(m.GetIntensity()), "F")
+	pdf.SetAlpha(1, "Normal")
+	pdf.SetFont("DejaVu", "B", 6)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetXY(x-3, y-1.5)
+	pdf.CellFormat(6, 3, fmt.Sprintf("%d", n), "", 0, "C", false, 0, "")
+}
@@ -0,0 +1,140 @@
+package anamneses
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	maxROMCharts = 4
+	chartWidth   = 180.0
+	chartHeight  = 50.0
+	// pageBottom keeps charts clear of the signature printed 35 mm above the page end.
+	pageBottom = 297.0 - 40.0
+)
+
+// chartSeries picks the series worth a chart across the printed visits: pain scores first, then
+// the range-of-motion values that changed the most. Series need at least two points.
+func chartSeries(visits []*pb.Anamnesis) []*pb.ProgressSeries {
+	set := newSeriesSet()
+	set.addMeasurements(visits)
+	var pain, rom []*pb.ProgressSeries
+	for _, ser := range set.list() {
+		if len(ser.GetPoints()) < 2 {
+			continue
+		}
+		switch ser.GetKind() {
+		case MeasurementNRS, MeasurementVAS:
+			pain = append(pain, ser)
+		case MeasurementROM:
+			rom = append(rom, ser)
+		}
+	}
+	sort.SliceStable(rom, func(i, j int) bool {
+		return math.Abs(rom[i].GetChange()) > math.Abs(rom[j].GetChange())
+	})
+	if len(rom) > maxROMCharts {
+		rom = rom[:maxROMCharts]
+	}
+	return append(pain, rom...)
+}
+
+// drawCharts adds a "Tijek terapije" section with one line chart per series.
+func drawCharts(pdf *gofpdf.Fpdf, series []*pb.ProgressSeries) {
+	if len(series) == 0 {
+		return
+	}
+	if pdf.GetY()+12+chartHeight > pageBottom {
+		pdf.AddPage()
+	}
+	pdf.SetFont("DejaVu", "B", 11)
+	pdf.MultiCell(0, 6, "Tijek terapije", "", "", false)
+	pdf.Ln(2)
+	for _, ser := range series {
+		if pdf.GetY()+8+chartHeight > pageBottom {
+			pdf.AddPage()
+		}
+		pdf.SetFont("DejaVu", "B", 9)
+		pdf.MultiCell(0, 5, ser.GetLabel()+" ("+ser.GetUnit()+")", "", "", false)
+		y := pdf.GetY() + 1
+		drawLineChart(pdf, 15, y, chartWidth, chartHeight, ser)
+		pdf.SetY(y + chartHeight + 8)
+	}
+}
+
+// drawLineChart plots the series with visits spaced evenly on the x axis; pain scales keep their
+// full range so charts of different patients compare at a glance.
+func drawLineChart(pdf *gofpdf.Fpdf, x, y, w, h float64, ser *pb.ProgressSeries) {
+	points := ser.GetPoints()
+	lo, hi := chartRange(ser)
+	const axisW = 12.0 // room for the y labels
+	plotX, plotW := x+axisW, w-axisW-4
+	yOf := func(v float64) float64 { return y + h - (v-lo)/(hi-lo)*h }
+	xOf := func(i int) float64 {
+		if len(points) == 1 {
+			return plotX + plotW/2
+		}
+		return plotX + float64(i)*plotW/float64(len(points)-1)
+	}
+
+	pdf.SetLineWidth(0.1)
+	pdf.SetDrawColor(210, 210, 210)
+	pdf.SetFont("DejaVu", "", 7)
+	pdf.SetTextColor(90, 90, 90)
+	const gridLines = 4
+	for i := 0; i <= gridLines; i++ {
+		v := lo + (hi-lo)*float64(i)/gridLines
+		gy := yOf(v)
+		pdf.Line(plotX, gy, plotX+plotW, gy)
+		pdf.SetXY(x, gy-2)
+		pdf.CellFormat(axisW-1, 4, fmt.Sprintf("%g", math.Round(v*10)/10), "", 0, "R", false, 0, "")
+	}
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.Line(plotX, y, plotX, y+h)
+	pdf.Line(plotX, y+h, plotX+plotW, y+h)
+	for i, p := range points {
+		pdf.SetXY(xOf(i)-10, y+h+1)
+		pdf.CellFormat(20, 4, p.GetAt().AsTime().Format("02.01."), "", 0, "C", false, 0, "")
+	}
+
+	pdf.SetLineWidth(0.5)
+	pdf.SetDrawColor(31, 94, 160)
+	pdf.SetFillColor(31, 94, 160)
+	for i, p := range points {
+		if i > 0 {
+			pdf.Line(xOf(i-1), yOf(points[i-1].GetValue()), xOf(i), yOf(p.GetValue()))
+		}
+	}
+	pdf.SetFont("DejaVu", "", 7)
+	pdf.SetTextColor(31, 94, 160)
+	for i, p := range points {
+		px, py := xOf(i), yOf(p.GetValue())
+		pdf.Circle(px, py, 0.8, "F")
+		pdf.SetXY(px-10, py-5)
+		pdf.CellFormat(20, 4, fmt.Sprintf("%g", p.GetValue()), "", 0, "C", false, 0, "")
+	}
+
+	pdf.SetLineWidth(0.2)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// chartRange is the y axis range: the full scale for pain, the data padded to 10° steps for ROM.
+func chartRange(ser *pb.ProgressSeries) (float64, float64) {
+	switch ser.GetKind() {
+	case MeasurementNRS:
+		return 0, 10
+	case MeasurementVAS:
+		return 0, 100
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range ser.GetPoints() {
+		lo = math.Min(lo, p.GetValue())
+		hi = math.Max(hi, p.GetValue())
+	}
+	return math.Floor(lo/10)*10 - 10, math.Ceil(hi/10)*10 + 10
+}
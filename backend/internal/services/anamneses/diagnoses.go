@@ -0,0 +1,66 @@
+package anamneses
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	outicd10 "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/icd10"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+)
+
+const maxDiagnosisCodes = 10
+
+// buildDiagnosisCodes resolves the ICD-10 codes of a visit against the catalogue, primary first.
+// Codes the visit already has keep their stored description, so a note stays editable after its
+// code was dropped from a replaced catalogue.
+func buildDiagnosisCodes(catalogue outicd10.Catalogue, codes []string, existing []*pb.DiagnosisCode) ([]*pb.DiagnosisCode, error) {
+	if len(codes) > maxDiagnosisCodes {
+		return nil, fmt.Errorf("at most %d diagnosis codes: %w", maxDiagnosisCodes, se.ErrInvalidRequest)
+	}
+	kept := make(map[string]*pb.DiagnosisCode, len(existing))
+	for _, d := range existing {
+		kept[diagnosisKey(d.GetCode())] = d
+	}
+	res := make([]*pb.DiagnosisCode, 0, len(codes))
+	seen := map[string]bool{}
+	for _, code := range codes {
+		d, ok := kept[diagnosisKey(code)]
+		if !ok {
+			if d, ok = catalogue.Lookup(code); !ok {
+				return nil, fmt.Errorf("unknown ICD-10 code %q: %w", code, se.ErrInvalidRequest)
+			}
+		}
+		if seen[d.GetCode()] {
+			continue
+		}
+		seen[d.GetCode()] = true
+		res = append(res, &pb.DiagnosisCode{Code: d.GetCode(), Description: d.GetDescription()})
+	}
+	return res, nil
+}
+
+func diagnosisKey(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), ".", ""))
+}
+
+// formatDiagnosisCodes prints one "code description" line per coded diagnosis.
+func formatDiagnosisCodes(list []*pb.DiagnosisCode) string {
+	lines := make([]string, 0, len(list))
+	for _, d := range list {
+		lines = append(lines, d.GetCode()+" "+d.GetDescription())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatDiagnosis is the diagnosis printed in the PDF: the coded diagnoses followed by the free text.
+func formatDiagnosis(a *pb.Anamnesis) string {
+	parts := []string{}
+	if codes := formatDiagnosisCodes(a.GetDiagnosisCodes()); codes != "" {
+		parts = append(parts, codes)
+	}
+	if text := a.GetDiagnosis(); text != "" {
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,41 @@
+package anamneses
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"github.com/OPetricevic/physio-tracker/backend/internal/icd10"
+)
+
+func TestBuildDiagnosisCodes(t *testing.T) {
+	catalogue, err := icd10.Parse(strings.NewReader("M54.5;Križobolja\nM54.4;Lumbago s išijasom\n"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	existing := []*pb.DiagnosisCode{{Code: "M51.1", Description: "Stari opis"}}
+
+	got, err := buildDiagnosisCodes(catalogue, []string{"m544", "M51.1", "M54.4"}, existing)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want := "M54.4 Lumbago s išijasom\nM51.1 Stari opis"; formatDiagnosisCodes(got) != want {
+		t.Errorf("got %q, want %q", formatDiagnosisCodes(got), want)
+	}
+
+	if _, err := buildDiagnosisCodes(catalogue, []string{"M51.1"}, nil); !errors.Is(err, se.ErrInvalidRequest) {
+		t.Errorf("unknown code: got %v, want ErrInvalidRequest", err)
+	}
+}
+
+func TestFormatDiagnosis(t *testing.T) {
+	a := &pb.Anamnesis{
+		Diagnosis:      "Bol nakon podizanja tereta",
+		DiagnosisCodes: []*pb.DiagnosisCode{{Code: "M54.5", Description: "Križobolja"}},
+	}
+	if want := "M54.5 Križobolja\nBol nakon podizanja tereta"; formatDiagnosis(a) != want {
+		t.Errorf("got %q, want %q", formatDiagnosis(a), want)
+	}
+}
@@ -0,0 +1,308 @@
+package anamneses
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CreateDischargeSummary assembles the final report of an episode from the patient's visits,
+// renders it and stores the PDF for good. The episode is the visits of the treatment plan or,
+// without a plan, the visits between the requested dates.
+func (s *service) CreateDischargeSummary(ctx context.Context, doctorUUID string, req *pb.CreateDischargeSummaryRequest) (*pb.DischargeSummary, error) {
+	patientUUID := strings.TrimSpace(req.GetPatientUuid())
+	patient, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID)
+	if err != nil {
+		return nil, fmt.Errorf("create discharge summary: %w", err)
+	}
+	profile, _ := s.profileRepo.GetByDoctor(ctx, doctorUUID) // optional
+	doctor, _ := s.doctorRepo.Get(ctx, doctorUUID)           // optional
+	loc, err := svcdoctorprofiles.ScheduleLocation(profile.GetSchedule())
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var plan *pb.TreatmentPlan
+	planUUID := strings.TrimSpace(req.GetTreatmentPlanUuid())
+	if planUUID != "" {
+		if plan, err = s.planRepo.Get(ctx, planUUID); err != nil {
+			if errors.Is(err, re.ErrNotFound) {
+				return nil, fmt.Errorf("create discharge summary: treatment plan %s: %w", planUUID, se.ErrNotFound)
+			}
+			return nil, fmt.Errorf("create discharge summary: load treatment plan: %w", err)
+		}
+		if plan.GetDoctorUuid() != doctorUUID || plan.GetPatientUuid() != patientUUID {
+			return nil, fmt.Errorf("create discharge summary: treatment plan %s: %w", planUUID, se.ErrNotFound)
+		}
+	}
+	from, to, err := episodeBounds(req.GetFromDate(), req.GetToDate(), loc)
+	if err != nil {
+		return nil, fmt.Errorf("create discharge summary: %w", err)
+	}
+
+	all, err := s.repo.List(ctx, patientUUID, doctorUUID, "", 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("create discharge summary: %w", err)
+	}
+	visits := episodeVisits(all, planUUID, from, to)
+	if len(visits) == 0 {
+		return nil, fmt.Errorf("create discharge summary: no visits in the episode: %w", se.ErrInvalidRequest)
+	}
+
+	now := time.Now().UTC()
+	summary := &pb.DischargeSummary{
+		Uuid:                uuid.NewString(),
+		DoctorUuid:          doctorUUID,
+		PatientUuid:         patientUUID,
+		TreatmentPlanUuid:   planUUID,
+		PeriodStart:         visits[0].GetCreatedAt(),
+		PeriodEnd:           visits[len(visits)-1].GetCreatedAt(),
+		SessionCount:        int32(len(visits)),
+		HomeRecommendations: strings.TrimSpace(req.GetHomeRecommendations()),
+		Conclusion:          strings.TrimSpace(req.GetConclusion()),
+		CreatedAt:           timestamppb.New(now),
+	}
+	for _, v := range visits {
+		summary.AnamnesisUuids = append(summary.AnamnesisUuids, v.GetUuid())
+	}
+	pdf, err := buildDischargePDF(profile, doctor, patient, visits, plan, summary)
+	if err != nil {
+		return nil, fmt.Errorf("create discharge summary: %w", err)
+	}
+	sum := sha256.Sum256(pdf)
+	summary.Sha256 = hex.EncodeToString(sum[:])
+	summary.SizeBytes = int64(len(pdf))
+
+	created, err := s.dischargeRepo.Create(ctx, summary, pdf)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("create discharge summary: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("create discharge summary: %w", err)
+	}
+	return created, nil
+}
+
+func (s *service) ListDischargeSummaries(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.DischargeSummary, error) {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("list discharge summaries: %w", err)
+	}
+	list, err := s.dischargeRepo.List(ctx, patientUUID)
+	if err != nil {
+		return nil, fmt.Errorf("list discharge summaries: %w", err)
+	}
+	return list, nil
+}
+
+// DischargeSummaryPDF returns the stored PDF exactly as it was generated.
+func (s *service) DischargeSummaryPDF(ctx context.Context, doctorUUID, patientUUID, summaryUUID string) ([]byte, error) {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("discharge summary pdf: %w", err)
+	}
+	summary, err := s.dischargeRepo.Get(ctx, summaryUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("discharge summary pdf: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("discharge summary pdf: %w", err)
+	}
+	if summary.GetPatientUuid() != patientUUID || summary.GetDoctorUuid() != doctorUUID {
+		return nil, fmt.Errorf("discharge summary pdf: %w", se.ErrNotFound)
+	}
+	pdf, err := s.dischargeRepo.GetPDF(ctx, summaryUUID)
+	if err != nil {
+		return nil, fmt.Errorf("discharge summary pdf: %w", err)
+	}
+	return pdf, nil
+}
+
+// episodeBounds parses the optional inclusive dates of an episode as days in the practice's time
+// zone; a missing bound leaves that side open.
+func episodeBounds(fromDate, toDate string, loc *time.Location) (time.Time, time.Time, error) {
+	var from, to time.Time
+	if v := strings.TrimSpace(fromDate); v != "" {
+		d, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid from date %q: %w", v, se.ErrInvalidRequest)
+		}
+		from = d
+	}
+	if v := strings.TrimSpace(toDate); v != "" {
+		d, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid to date %q: %w", v, se.ErrInvalidRequest)
+		}
+		to = d.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, fmt.Errorf("from date after to date: %w", se.ErrInvalidRequest)
+	}
+	return from, to, nil
+}
+
+// episodeVisits keeps the visits of the plan (or all visits without one) inside [from, to), oldest first.
+func episodeVisits(all []*pb.Anamnesis, planUUID string, from, to time.Time) []*pb.Anamnesis {
+	res := make([]*pb.Anamnesis, 0, len(all))
+	for _, v := range all {
+		at := v.GetCreatedAt().AsTime()
+		switch {
+		case planUUID != "" && v.GetTreatmentPlanUuid() != planUUID:
+		case !from.IsZero() && at.Before(from):
+		case !to.IsZero() && !at.Before(to):
+		default:
+			res = append(res, v)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].GetCreatedAt().AsTime().Before(res[j].GetCreatedAt().AsTime())
+	})
+	return res
+}
+
+// episodeDiagnosis combines the coded diagnoses of all visits, in the order first recorded, with
+// the latest free-text diagnosis.
+func episodeDiagnosis(visits []*pb.Anamnesis) *pb.Anamnesis {
+	res := &pb.Anamnesis{}
+	seen := map[string]bool{}
+	for _, v := range visits {
+		for _, d := range v.GetDiagnosisCodes() {
+			if !seen[d.GetCode()] {
+				seen[d.GetCode()] = true
+				res.DiagnosisCodes = append(res.DiagnosisCodes, d)
+			}
+		}
+		if v.GetDiagnosis() != "" {
+			res.Diagnosis = v.GetDiagnosis()
+		}
+	}
+	return res
+}
+
+// formatMeasurementDeltas compares the first and last value of every measurement taken at least
+// twice in the episode, e.g. "Opseg pokreta: koljeno flexion, desno: 90° → 125° (+35°)".
+func formatMeasurementDeltas(visits []*pb.Anamnesis) string {
+	set := newSeriesSet()
+	set.addMeasurements(visits)
+	lines := []string{}
+	for _, ser := range set.list() {
+		if len(ser.GetPoints()) < 2 {
+			continue
+		}
+		unit := spacedUnit(ser.GetUnit())
+		line := fmt.Sprintf("%s: %g%s → %g%s (%+g%s)", ser.GetLabel(), ser.GetBaseline(), unit, ser.GetLatest(), unit, roundDelta(ser.GetChange()), unit)
+		switch ser.GetMcidStatus() {
+		case "improved":
+			line += ", klinički značajno poboljšanje"
+		case "worsened":
+			line += ", klinički značajno pogoršanje"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// roundDelta hides float noise such as 0.30000000000000004 in girth differences.
+func roundDelta(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// formatTherapies lists the therapies delivered in the order first given, with how many visits
+// each was recorded on.
+func formatTherapies(visits []*pb.Anamnesis) string {
+	counts := map[string]int{}
+	var order []string
+	for _, v := range visits {
+		t := strings.TrimSpace(v.GetTherapy())
+		if t == "" {
+			continue
+		}
+		if counts[t] == 0 {
+			order = append(order, t)
+		}
+		counts[t]++
+	}
+	lines := make([]string, 0, len(order))
+	for _, t := range order {
+		line := t
+		if counts[t] > 1 {
+			line += fmt.Sprintf(" (%d×)", counts[t])
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatEpisode(summary *pb.DischargeSummary, plan *pb.TreatmentPlan) string {
+	line := fmt.Sprintf("%s – %s, broj terapija: %d", formatDate(summary.GetPeriodStart()), formatDate(summary.GetPeriodEnd()), summary.GetSessionCount())
+	if plan != nil {
+		line += fmt.Sprintf(" od %d planiranih", plan.GetPlannedSessions())
+		if plan.GetFrequency() != "" {
+			line += ", " + plan.GetFrequency()
+		}
+	}
+	return line
+}
+
+func buildDischargePDF(profile *pb.DoctorProfile, doctor *pb.Doctor, patient *pb.Patient, visits []*pb.Anamnesis, plan *pb.TreatmentPlan, summary *pb.DischargeSummary) ([]byte, error) {
+	pdf, err := newDocument()
+	if err != nil {
+		return nil, fmt.Errorf("generate discharge pdf: %w", err)
+	}
+	drawHeader(pdf, profile, patient, "ZAVRŠNO IZVJEŠĆE FIZIOTERAPEUTA")
+	writeField := fieldWriter(pdf)
+	section := func(title string) {
+		pdf.SetFont("DejaVu", "B", 11)
+		pdf.MultiCell(0, 5, title, "", "", false)
+		pdf.Ln(1)
+	}
+
+	first, last := visits[0], visits[len(visits)-1]
+	writeField("Razdoblje liječenja", formatEpisode(summary, plan))
+	if plan != nil {
+		writeField("Uputna dijagnoza", plan.GetReferralDiagnosis())
+	}
+	writeField("Dijagnoza", formatDiagnosis(episodeDiagnosis(visits)))
+
+	section("Početni nalaz – " + formatDate(first.GetCreatedAt()))
+	writeField("Anamneza", first.GetAnamnesis())
+	writeField("Status", first.GetStatus())
+	writeField("Mjerenja", formatMeasurements(first.GetMeasurements()))
+	if len(visits) > 1 {
+		section("Završni nalaz – " + formatDate(last.GetCreatedAt()))
+		writeField("Status", last.GetStatus())
+		writeField("Mjerenja", formatMeasurements(last.GetMeasurements()))
+		writeField("Promjene u mjerenjima", formatMeasurementDeltas(visits))
+	}
+
+	section("Provedeno liječenje")
+	if plan != nil && len(plan.GetModalities()) > 0 {
+		writeField("Modaliteti", strings.Join(plan.GetModalities(), ", "))
+	}
+	writeField("Terapija", formatTherapies(visits))
+	if plan != nil {
+		writeField("Ciljevi", formatGoals(plan.GetGoals()))
+	}
+	writeField("Preporuke za kućni program", summary.GetHomeRecommendations())
+	writeField("Zaključak", summary.GetConclusion())
+
+	drawDoctorSignature(pdf, doctor)
+	b, err := outputDocument(pdf)
+	if err != nil {
+		return nil, fmt.Errorf("generate discharge pdf: %w", err)
+	}
+	return b, nil
+}
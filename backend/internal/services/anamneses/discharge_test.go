@@ -0,0 +1,76 @@
+package anamneses
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestEpisodeVisits(t *testing.T) {
+	day := func(d int) *timestamppb.Timestamp {
+		return timestamppb.New(time.Date(2026, 3, d, 9, 0, 0, 0, time.UTC))
+	}
+	all := []*pb.Anamnesis{
+		{Uuid: "c", CreatedAt: day(12), TreatmentPlanUuid: "p1"},
+		{Uuid: "a", CreatedAt: day(2), TreatmentPlanUuid: "p1"},
+		{Uuid: "x", CreatedAt: day(5)},
+		{Uuid: "b", CreatedAt: day(9), TreatmentPlanUuid: "p1"},
+	}
+	uuids := func(list []*pb.Anamnesis) string {
+		var res []string
+		for _, v := range list {
+			res = append(res, v.GetUuid())
+		}
+		return strings.Join(res, ",")
+	}
+
+	if got := uuids(episodeVisits(all, "p1", time.Time{}, time.Time{})); got != "a,b,c" {
+		t.Errorf("by plan: got %s, want a,b,c", got)
+	}
+	from, to, err := episodeBounds("2026-03-05", "2026-03-09", time.UTC)
+	if err != nil {
+		t.Fatalf("bounds: %v", err)
+	}
+	if got := uuids(episodeVisits(all, "", from, to)); got != "x,b" {
+		t.Errorf("by dates: got %s, want x,b", got)
+	}
+	if _, _, err := episodeBounds("2026-03-10", "2026-03-09", time.UTC); err == nil {
+		t.Error("expected an error for a reversed range")
+	}
+}
+
+func TestFormatMeasurementDeltas(t *testing.T) {
+	visit := func(d int, knee, vas float64) *pb.Anamnesis {
+		return &pb.Anamnesis{
+			CreatedAt: timestamppb.New(time.Date(2026, 3, d, 9, 0, 0, 0, time.UTC)),
+			Measurements: []*pb.Measurement{
+				{Kind: "rom", Site: "knee", Side: "right", Movement: "flexion", Value: knee, Unit: "°"},
+				{Kind: "vas", Value: vas, Unit: "mm"},
+			},
+		}
+	}
+	visits := []*pb.Anamnesis{visit(2, 90, 70), visit(9, 110, 50), visit(16, 125, 20)}
+	got := strings.Split(formatMeasurementDeltas(visits), "\n")
+	if len(got) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(got), got)
+	}
+	if !strings.HasSuffix(got[0], "90° → 125° (+35°)") {
+		t.Errorf("rom line %q", got[0])
+	}
+	if !strings.HasSuffix(got[1], "70 mm → 20 mm (-50 mm), klinički značajno poboljšanje") {
+		t.Errorf("vas line %q", got[1])
+	}
+	if formatMeasurementDeltas(visits[:1]) != "" {
+		t.Error("a single visit has no deltas")
+	}
+}
+
+func TestFormatTherapies(t *testing.T) {
+	visits := []*pb.Anamnesis{{Therapy: "TENS, kineziterapija"}, {Therapy: "TENS, kineziterapija"}, {}, {Therapy: "Laser"}}
+	if want := "TENS, kineziterapija (2×)\nLaser"; formatTherapies(visits) != want {
+		t.Errorf("got %q, want %q", formatTherapies(visits), want)
+	}
+}
@@ -0,0 +1,168 @@
+package anamneses
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// newDocument starts an A4 page with the UTF-8 fonts shared by the visit PDF and the discharge summary.
+func newDocument() (*gofpdf.Fpdf, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	// UTF-8 fonts to render regional characters correctly.
+	fontDir := filepath.Join("assets", "fonts")
+	pdf.SetFontLocation(fontDir)
+	pdf.AddUTF8Font("DejaVu", "", "DejaVuSans.ttf")
+	pdf.AddUTF8Font("DejaVu", "B", "DejaVuSans-Bold.ttf")
+	if pdf.Err() {
+		return nil, fmt.Errorf("load fonts from %s: %v", fontDir, pdf.Error())
+	}
+	pdf.SetMargins(15, 15, 15)
+	pdf.AddPage()
+	return pdf, nil
+}
+
+// drawHeader prints the practice branding, the centred title and the patient block.
+func drawHeader(pdf *gofpdf.Fpdf, profile *pb.DoctorProfile, patient *pb.Patient, title string) {
+	printedOn := time.Now().Format("02.01.2006.")
+	tr := func(s string) string { return s }
+
+	// Header
+	headerTop := 15.0
+	logoSize := 24.0
+	textX := 45.0
+	if profile != nil && profile.GetLogoPath() != "" {
+		if local := localFromStatic(profile.GetLogoPath()); local != "" {
+			pdf.ImageOptions(local, 15, headerTop, logoSize, 0, false, gofpdf.ImageOptions{ImageType: "", ReadDpi: true}, 0, "")
+		}
+	}
+	// printed date top-right
+	pdf.SetFont("DejaVu", "", 10)
+	pdf.SetXY(-70, headerTop)
+	pdf.Cell(55, 5, tr("Datum ispisa: "+printedOn))
+
+	pdf.SetFont("DejaVu", "B", 12)
+	if profile != nil {
+		pdf.SetXY(textX, headerTop)
+		pdf.Cell(0, 6, tr(profile.GetPracticeName()))
+		pdf.Ln(6)
+		pdf.SetFont("DejaVu", "", 10)
+		pdf.SetX(textX)
+		roleDept := strings.TrimSpace(strings.TrimSpace(profile.GetRoleTitle()) + " " + strings.TrimSpace(profile.GetDepartment()))
+		if roleDept != "" {
+			pdf.Cell(0, 5, tr(roleDept))
+			pdf.Ln(5)
+			pdf.SetX(textX)
+		}
+		pdf.SetX(textX)
+		pdf.Cell(0, 5, tr(profile.GetAddress()))
+		pdf.Ln(5)
+		pdf.SetFont("DejaVu", "", 10)
+		pdf.SetX(textX)
+		if strings.TrimSpace(profile.GetPhone()) != "" {
+			pdf.Cell(0, 5, tr(profile.GetPhone()))
+			pdf.Ln(5)
+			pdf.SetX(textX)
+		}
+		if strings.TrimSpace(profile.GetEmail()) != "" {
+			pdf.Cell(0, 5, tr(profile.GetEmail()))
+			pdf.Ln(5)
+			pdf.SetX(textX)
+		}
+		if strings.TrimSpace(profile.GetWebsite()) != "" {
+			pdf.SetX(textX)
+			pdf.Cell(0, 5, tr(strings.TrimSpace(profile.GetWebsite())))
+			pdf.Ln(5)
+		}
+		pdf.Ln(6)
+	}
+
+	//centered title
+	headerBottom := pdf.GetY()
+	pdf.SetX(15)
+	pdf.Line(15, headerBottom, 195, headerBottom)
+	pdf.Ln(6)
+	pdf.SetFont("DejaVu", "B", 12)
+	pdf.CellFormat(0, 6, tr(title), "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont("DejaVu", "B", 10)
+	pdf.Cell(40, 5, tr("Pacijent:"))
+	pdf.SetFont("DejaVu", "", 10)
+	pdf.Cell(0, 5, tr(strings.TrimSpace(patient.GetFirstName()+" "+patient.GetLastName())))
+	pdf.Ln(5)
+	if dob := patient.GetDateOfBirth(); dob != nil && strings.TrimSpace(dob.GetValue()) != "" {
+		pdf.SetFont("DejaVu", "B", 10)
+		pdf.Cell(40, 5, tr("Datum rođenja:"))
+		pdf.SetFont("DejaVu", "", 10)
+		pdf.Cell(0, 5, tr(formatPlainDate(dob.GetValue())))
+		pdf.Ln(5)
+	}
+	if patient.GetPhone() != nil {
+		pdf.SetFont("DejaVu", "B", 10)
+		pdf.Cell(40, 5, tr("Telefon:"))
+		pdf.SetFont("DejaVu", "", 10)
+		pdf.Cell(0, 5, tr(patient.GetPhone().GetValue()))
+		pdf.Ln(5)
+	}
+	if patient.GetAddress() != nil {
+		pdf.SetFont("DejaVu", "B", 10)
+		pdf.Cell(40, 5, tr("Adresa:"))
+		pdf.SetFont("DejaVu", "", 10)
+		pdf.Cell(0, 5, tr(patient.GetAddress().GetValue()))
+		pdf.Ln(8)
+	}
+
+	// set line to separate header from content (match header thickness)
+	pdf.SetLineWidth(0.2)
+	pdf.Line(15, pdf.GetY(), 195, pdf.GetY())
+	pdf.Ln(6)
+}
+
+// fieldWriter returns a function printing a bold label over its text; empty texts are skipped.
+func fieldWriter(pdf *gofpdf.Fpdf) func(label, value string) {
+	return func(label, value string) {
+		if strings.TrimSpace(value) == "" {
+			return
+		}
+		pdf.SetFont("DejaVu", "B", 10)
+		pdf.MultiCell(0, 5, label, "", "", false)
+		pdf.SetFont("DejaVu", "", 10)
+		pdf.MultiCell(0, 5, value, "", "", false)
+		pdf.Ln(2)
+	}
+}
+
+// drawDoctorSignature prints the physiotherapist's name at the bottom of the current page.
+func drawDoctorSignature(pdf *gofpdf.Fpdf, doctor *pb.Doctor) {
+	if doctor == nil {
+		return
+	}
+	name := strings.TrimSpace(doctor.GetFirstName() + " " + doctor.GetLastName())
+	if name == "" {
+		return
+	}
+	pdf.SetY(-35)
+	pdf.SetX(15)
+	pdf.SetFont("DejaVu", "B", 9)
+	pdf.Cell(0, 5, "Fizioterapeut:")
+	pdf.Ln(5)
+	pdf.SetX(15)
+	pdf.SetFont("DejaVu", "", 9)
+	pdf.Cell(0, 5, "bacc.physioth "+name)
+}
+
+func outputDocument(pdf *gofpdf.Fpdf) ([]byte, error) {
+	if pdf.Err() {
+		return nil, fmt.Errorf("prepare content: %v", pdf.Error())
+	}
+	var buf strings.Builder
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render: %w", err)
+	}
+	return []byte(buf.String()), nil
+}
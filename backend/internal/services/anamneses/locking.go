@@ -0,0 +1,182 @@
+package anamneses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// AutoLockAfterFromEnv reads ANAMNESIS_AUTO_LOCK_DAYS: notes older than that many days are locked
+// even when nobody signed them. Zero or unset disables the auto-lock.
+func AutoLockAfterFromEnv() time.Duration {
+	if days, err := strconv.Atoi(strings.TrimSpace(os.Getenv("ANAMNESIS_AUTO_LOCK_DAYS"))); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return 0
+}
+
+// lockedSince reports when a note was locked; a note past the auto-lock age counts as locked even
+// before the worker has marked it, unless it has open red flags.
+func lockedSince(a *pb.Anamnesis, autoLockAfter time.Duration, now time.Time) (time.Time, bool) {
+	if a.GetLockedAt() != nil {
+		return a.GetLockedAt().AsTime(), true
+	}
+	if autoLockAfter > 0 && a.GetCreatedAt() != nil && !hasOpenRedFlags(a) {
+		if at := a.GetCreatedAt().AsTime().Add(autoLockAfter); !at.After(now) {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// CheckUnlocked returns ErrConflict for a signed or auto-locked note. Other services that change
+// what belongs to a note, such as its attachments, use it so that the lock means the same everywhere.
+func CheckUnlocked(a *pb.Anamnesis, autoLockAfter time.Duration, now time.Time) error {
+	if at, locked := lockedSince(a, autoLockAfter, now); locked {
+		return fmt.Errorf("anamnesis %s is locked since %s, add an addendum instead: %w", a.GetUuid(), at.Format("02.01.2006."), se.ErrConflict)
+	}
+	return nil
+}
+
+func (s *service) checkUnlocked(a *pb.Anamnesis, now time.Time) error {
+	return CheckUnlocked(a, s.autoLockAfter, now)
+}
+
+// Sign signs the note as the doctor and locks it.
+func (s *service) Sign(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string) (*pb.Anamnesis, error) {
+	visit, err := s.ownedVisit(ctx, doctorUUID, patientUUID, anamnesisUUID)
+	if err != nil {
+		return nil, fmt.Errorf("sign anamnesis: %w", err)
+	}
+	if visit.GetSignedAt() != nil {
+		return nil, fmt.Errorf("sign anamnesis: already signed on %s: %w", formatDate(visit.GetSignedAt()), se.ErrConflict)
+	}
+	if hasOpenRedFlags(visit) {
+		return nil, fmt.Errorf("sign anamnesis: positive red flags need the action taken first: %w", se.ErrInvalidRequest)
+	}
+	signed, err := s.repo.Sign(ctx, anamnesisUUID, doctorUUID, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("sign anamnesis: %w", se.ErrConflict)
+		}
+		return nil, fmt.Errorf("sign anamnesis: %w", err)
+	}
+	return signed, nil
+}
+
+// AddAddendum appends a correction to a locked note. Notes that are not locked are edited directly.
+func (s *service) AddAddendum(ctx context.Context, doctorUUID string, req *pb.CreateAnamnesisAddendumRequest) (*pb.AnamnesisAddendum, error) {
+	visit, err := s.ownedVisit(ctx, doctorUUID, req.GetPatientUuid(), req.GetAnamnesisUuid())
+	if err != nil {
+		return nil, fmt.Errorf("add addendum: %w", err)
+	}
+	now := time.Now().UTC()
+	if _, locked := lockedSince(visit, s.autoLockAfter, now); !locked {
+		return nil, fmt.Errorf("add addendum: anamnesis %s is not locked, edit it instead: %w", visit.GetUuid(), se.ErrInvalidRequest)
+	}
+	text := strings.TrimSpace(req.GetText())
+	if text == "" {
+		return nil, fmt.Errorf("add addendum: empty text: %w", se.ErrInvalidRequest)
+	}
+	created, err := s.repo.CreateAddendum(ctx, &pb.AnamnesisAddendum{
+		Uuid:          uuid.NewString(),
+		AnamnesisUuid: visit.GetUuid(),
+		DoctorUuid:    doctorUUID,
+		Text:          text,
+		CreatedAt:     timestamppb.New(now),
+	})
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("add addendum: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("add addendum: %w", err)
+	}
+	return created, nil
+}
+
+// AutoLock locks the notes older than ANAMNESIS_AUTO_LOCK_DAYS except those with open red flags; it
+// does nothing when the auto-lock is off.
+func (s *service) AutoLock(ctx context.Context) (int64, error) {
+	if s.autoLockAfter <= 0 {
+		return 0, nil
+	}
+	now := time.Now().UTC()
+	n, err := s.repo.LockCreatedBefore(ctx, now.Add(-s.autoLockAfter), now)
+	if err != nil {
+		return 0, fmt.Errorf("auto-lock anamneses: %w", err)
+	}
+	return n, nil
+}
+
+// HasLockedNotes reports whether one of the patient's notes is locked, including notes past the
+// auto-lock age the worker has not marked yet. The patient service refuses deletion then.
+func (s *service) HasLockedNotes(ctx context.Context, patientUUID string) (bool, error) {
+	visits, err := s.repo.List(ctx, patientUUID, "", "", 0, 0)
+	if err != nil {
+		return false, fmt.Errorf("check locked notes: %w", err)
+	}
+	now := time.Now().UTC()
+	for _, v := range visits {
+		if _, locked := lockedSince(v, s.autoLockAfter, now); locked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ownedVisit loads a note of one of the doctor's patients.
+func (s *service) ownedVisit(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string) (*pb.Anamnesis, error) {
+	if strings.TrimSpace(anamnesisUUID) == "" {
+		return nil, se.ErrInvalidRequest
+	}
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, err
+	}
+	visit, err := s.repo.Get(ctx, anamnesisUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, se.ErrNotFound
+		}
+		return nil, fmt.Errorf("load anamnesis: %w", err)
+	}
+	if visit.GetPatientUuid() != patientUUID {
+		return nil, se.ErrNotFound
+	}
+	return visit, nil
+}
+
+// formatAddenda renders the addenda of a note for the PDF, one timestamped paragraph each.
+func formatAddenda(list []*pb.AnamnesisAddendum, loc *time.Location) string {
+	parts := make([]string, 0, len(list))
+	for _, ad := range list {
+		parts = append(parts, ad.GetCreatedAt().AsTime().In(loc).Format("02.01.2006. 15:04")+" – "+ad.GetText())
+	}
+	return strings.Join(parts, "\n")
+}
+
+// formatSignature describes who signed the note and when, or that it was locked unsigned.
+func formatSignature(a *pb.Anamnesis, signer string, loc *time.Location) string {
+	switch {
+	case a.GetSignedAt() != nil:
+		line := "Potpisano " + a.GetSignedAt().AsTime().In(loc).Format("02.01.2006. u 15:04")
+		if signer != "" {
+			line += ", " + signer
+		}
+		return line
+	case a.GetLockedAt() != nil:
+		return "Zaključano " + a.GetLockedAt().AsTime().In(loc).Format("02.01.2006.")
+	}
+	return ""
+}
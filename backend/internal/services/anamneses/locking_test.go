@@ -0,0 +1,71 @@
+package anamneses
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/anamneses"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCheckUnlocked(t *testing.T) {
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(d int) *timestamppb.Timestamp { return timestamppb.New(now.AddDate(0, 0, -d)) }
+	tests := []struct {
+		name     string
+		autoLock time.Duration
+		visit    *pb.Anamnesis
+		locked   bool
+	}{
+		{"fresh note", 0, &pb.Anamnesis{CreatedAt: daysAgo(1)}, false},
+		{"signed note", 0, &pb.Anamnesis{CreatedAt: daysAgo(1), SignedAt: daysAgo(0), LockedAt: daysAgo(0)}, true},
+		{"old note without auto-lock", 0, &pb.Anamnesis{CreatedAt: daysAgo(400)}, false},
+		{"old note past auto-lock", 7 * 24 * time.Hour, &pb.Anamnesis{CreatedAt: daysAgo(8)}, true},
+		{"recent note before auto-lock", 7 * 24 * time.Hour, &pb.Anamnesis{CreatedAt: daysAgo(6)}, false},
+		{"old note with open red flag", 7 * 24 * time.Hour, &pb.Anamnesis{CreatedAt: daysAgo(8), RedFlags: []*pb.RedFlagAnswer{{Code: "night_pain", Positive: true}}}, false},
+	}
+	for _, tt := range tests {
+		s := &service{autoLockAfter: tt.autoLock}
+		err := s.checkUnlocked(tt.visit, now)
+		if tt.locked && !errors.Is(err, se.ErrConflict) {
+			t.Errorf("%s: got %v, want ErrConflict", tt.name, err)
+		}
+		if !tt.locked && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
+
+type fakeNotes struct {
+	out.Repository
+	visits []*pb.Anamnesis
+}
+
+func (f fakeNotes) List(ctx context.Context, patientUUID, doctorUUID, query string, limit, offset int) ([]*pb.Anamnesis, error) {
+	return f.visits, nil
+}
+
+func TestHasLockedNotes(t *testing.T) {
+	daysAgo := func(d int) *timestamppb.Timestamp { return timestamppb.New(time.Now().AddDate(0, 0, -d)) }
+	tests := []struct {
+		name   string
+		visits []*pb.Anamnesis
+		locked bool
+	}{
+		{"no notes", nil, false},
+		{"open notes", []*pb.Anamnesis{{CreatedAt: daysAgo(1)}, {CreatedAt: daysAgo(3)}}, false},
+		{"one signed note", []*pb.Anamnesis{{CreatedAt: daysAgo(1)}, {CreatedAt: daysAgo(2), LockedAt: daysAgo(1)}}, true},
+		{"note past auto-lock not marked yet", []*pb.Anamnesis{{CreatedAt: daysAgo(8)}}, true},
+	}
+	for _, tt := range tests {
+		s := &service{repo: fakeNotes{visits: tt.visits}, autoLockAfter: 7 * 24 * time.Hour}
+		locked, err := s.HasLockedNotes(context.Background(), "p1")
+		if err != nil || locked != tt.locked {
+			t.Errorf("%s: got %v, %v; want %v", tt.name, locked, err, tt.locked)
+		}
+	}
+}
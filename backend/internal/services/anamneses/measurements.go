@@ -0,0 +1,158 @@
+package anamneses
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	MeasurementROM   = "rom"
+	MeasurementMMT   = "mmt"
+	MeasurementVAS   = "vas"
+	MeasurementNRS   = "nrs"
+	MeasurementGirth = "girth"
+)
+
+// measurementKind describes the accepted range of one measurement kind and how it reads over time.
+type measurementKind struct {
+	unit           string
+	min, max       float64
+	wholeNumbers   bool
+	needsSite      bool
+	needsSide      bool
+	higherIsBetter bool
+	mcid           float64 // 0 when no minimal clinically important difference is established
+}
+
+var measurementKinds = map[string]measurementKind{
+	// Negative values record an extension deficit or hyperextension, e.g. -10° knee extension.
+	MeasurementROM:   {unit: "°", min: -90, max: 360, needsSite: true, needsSide: true, higherIsBetter: true},
+	MeasurementMMT:   {unit: "/5", min: 0, max: 5, wholeNumbers: true, needsSite: true, needsSide: true, higherIsBetter: true},
+	MeasurementVAS:   {unit: "mm", min: 0, max: 100, mcid: 20},
+	MeasurementNRS:   {unit: "/10", min: 0, max: 10, wholeNumbers: true, mcid: 2}, // Farrar et al. 2001
+	MeasurementGirth: {unit: "cm", min: 0.1, max: 300, needsSite: true},
+}
+
+// buildMeasurements validates the requested measurements and turns them into stored ones.
+// Side is required for limb measurements (rom, mmt) because left and right are compared over time.
+func buildMeasurements(anamnesisUUID string, inputs []*pb.MeasurementInput, now time.Time) ([]*pb.Measurement, error) {
+	res := make([]*pb.Measurement, 0, len(inputs))
+	for i, in := range inputs {
+		m, err := normalizeMeasurement(i, in, true)
+		if err != nil {
+			return nil, err
+		}
+		m.Uuid = uuid.NewString()
+		m.AnamnesisUuid = anamnesisUUID
+		m.CreatedAt = timestamppb.New(now)
+		res = append(res, m)
+	}
+	return res, nil
+}
+
+// normalizeMeasurement checks one input against the rules of its kind. A value is range-checked
+// whenever it is set; requireValue is false for template defaults that only name what to measure.
+func normalizeMeasurement(i int, in *pb.MeasurementInput, requireValue bool) (*pb.Measurement, error) {
+	kindName := strings.ToLower(strings.TrimSpace(in.GetKind()))
+	kind, ok := measurementKinds[kindName]
+	if !ok {
+		return nil, fmt.Errorf("measurement %d: unknown kind %q: %w", i+1, in.GetKind(), se.ErrInvalidRequest)
+	}
+	site := strings.TrimSpace(in.GetSite())
+	side := strings.ToLower(strings.TrimSpace(in.GetSide()))
+	movement := strings.TrimSpace(in.GetMovement())
+	value := in.GetValue().GetValue()
+	switch {
+	case kind.needsSite && site == "":
+		return nil, fmt.Errorf("measurement %d: %s needs a site: %w", i+1, kindName, se.ErrInvalidRequest)
+	case kind.needsSide && side == "":
+		return nil, fmt.Errorf("measurement %d: %s needs a side: %w", i+1, kindName, se.ErrInvalidRequest)
+	case side != "" && side != "left" && side != "right" && side != "bilateral":
+		return nil, fmt.Errorf("measurement %d: unknown side %q: %w", i+1, in.GetSide(), se.ErrInvalidRequest)
+	case kindName == MeasurementROM && movement == "":
+		return nil, fmt.Errorf("measurement %d: rom needs a movement: %w", i+1, se.ErrInvalidRequest)
+	case in.Value == nil && requireValue:
+		return nil, fmt.Errorf("measurement %d: %s needs a value: %w", i+1, kindName, se.ErrInvalidRequest)
+	case in.Value == nil:
+	case math.IsNaN(value) || value < kind.min || value > kind.max:
+		return nil, fmt.Errorf("measurement %d: %s must be between %g and %g: %w", i+1, kindName, kind.min, kind.max, se.ErrInvalidRequest)
+	case kind.wholeNumbers && value != math.Trunc(value):
+		return nil, fmt.Errorf("measurement %d: %s must be a whole number: %w", i+1, kindName, se.ErrInvalidRequest)
+	}
+	return &pb.Measurement{
+		Kind:     kindName,
+		Site:     site,
+		Side:     side,
+		Movement: movement,
+		Value:    value,
+		Unit:     kind.unit,
+		Note:     strings.TrimSpace(in.GetNote()),
+	}, nil
+}
+
+var measurementLabels = map[string]string{
+	MeasurementROM:   "Opseg pokreta",
+	MeasurementMMT:   "Manualni mišićni test",
+	MeasurementVAS:   "VAS bol",
+	MeasurementNRS:   "NRS bol",
+	MeasurementGirth: "Opseg",
+}
+
+var sideLabels = map[string]string{
+	"left":      "lijevo",
+	"right":     "desno",
+	"bilateral": "obostrano",
+}
+
+// formatMeasurements renders one line per measurement for the PDF,
+// e.g. "Opseg pokreta: koljeno flexion, desno 95°".
+func formatMeasurements(list []*pb.Measurement) string {
+	lines := make([]string, 0, len(list))
+	for _, m := range list {
+		line := measurementLabel(m.GetKind(), m.GetSite(), m.GetSide(), m.GetMovement())
+		if line == measurementLabels[m.GetKind()] {
+			line += ":" // pain scores have no site, e.g. "NRS bol: 6/10"
+		}
+		line += " " + strings.TrimSpace(fmt.Sprintf("%g%s", m.GetValue(), spacedUnit(m.GetUnit())))
+		if m.GetNote() != "" {
+			line += " (" + m.GetNote() + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// measurementLabel names what was measured, e.g. "Opseg pokreta: koljeno flexion, desno".
+func measurementLabel(kind, site, side, movement string) string {
+	parts := make([]string, 0, 2)
+	for _, p := range []string{site, movement} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	subject := strings.Join(parts, " ")
+	if label := sideLabels[side]; label != "" {
+		if subject != "" {
+			subject += ", "
+		}
+		subject += label
+	}
+	if subject == "" {
+		return measurementLabels[kind]
+	}
+	return measurementLabels[kind] + ": " + subject
+}
+
+func spacedUnit(unit string) string {
+	if unit == "mm" || unit == "cm" {
+		return " " + unit
+	}
+	return unit
+}
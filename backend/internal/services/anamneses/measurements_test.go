@@ -0,0 +1,120 @@
+package anamneses
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestBuildMeasurements(t *testing.T) {
+	rom := func(v float64) *pb.MeasurementInput {
+		return &pb.MeasurementInput{Kind: "rom", Site: "koljeno", Side: "right", Movement: "flexion", Value: wrapperspb.Double(v)}
+	}
+	mmt := func(v float64) *pb.MeasurementInput {
+		return &pb.MeasurementInput{Kind: "mmt", Site: "kvadriceps", Side: "left", Value: wrapperspb.Double(v)}
+	}
+	girth := func(v float64) *pb.MeasurementInput {
+		return &pb.MeasurementInput{Kind: "girth", Site: "potkoljenica 10 cm ispod patele", Value: wrapperspb.Double(v)}
+	}
+	tests := []struct {
+		name string
+		in   *pb.MeasurementInput
+		ok   bool
+	}{
+		{"rom lower bound", rom(-90), true},
+		{"rom below lower bound", rom(-90.5), false},
+		{"rom upper bound", rom(360), true},
+		{"rom above upper bound", rom(360.1), false},
+		{"rom fractional degrees", rom(92.5), true},
+		{"rom not a number", rom(math.NaN()), false},
+		{"rom without movement", &pb.MeasurementInput{Kind: "rom", Site: "koljeno", Side: "right", Value: wrapperspb.Double(90)}, false},
+		{"rom without side", &pb.MeasurementInput{Kind: "rom", Site: "koljeno", Movement: "flexion", Value: wrapperspb.Double(90)}, false},
+		{"rom without site", &pb.MeasurementInput{Kind: "rom", Side: "right", Movement: "flexion", Value: wrapperspb.Double(90)}, false},
+		{"mmt 0", mmt(0), true},
+		{"mmt 5", mmt(5), true},
+		{"mmt 6", mmt(6), false},
+		{"mmt 4.5", mmt(4.5), false},
+		{"nrs 0", &pb.MeasurementInput{Kind: "nrs", Value: wrapperspb.Double(0)}, true},
+		{"nrs 10", &pb.MeasurementInput{Kind: "nrs", Value: wrapperspb.Double(10)}, true},
+		{"nrs 11", &pb.MeasurementInput{Kind: "nrs", Value: wrapperspb.Double(11)}, false},
+		{"nrs -1", &pb.MeasurementInput{Kind: "nrs", Value: wrapperspb.Double(-1)}, false},
+		{"nrs 6.5", &pb.MeasurementInput{Kind: "nrs", Value: wrapperspb.Double(6.5)}, false},
+		{"vas 100", &pb.MeasurementInput{Kind: "vas", Value: wrapperspb.Double(100)}, true},
+		{"vas 100.5", &pb.MeasurementInput{Kind: "vas", Value: wrapperspb.Double(100.5)}, false},
+		{"vas 37.5", &pb.MeasurementInput{Kind: "vas", Value: wrapperspb.Double(37.5)}, true},
+		{"girth minimum", girth(0.1), true},
+		{"girth below minimum", girth(0.09), false},
+		{"girth zero", girth(0), false},
+		{"girth maximum", girth(300), true},
+		{"girth above maximum", girth(300.1), false},
+		{"girth without site", &pb.MeasurementInput{Kind: "girth", Value: wrapperspb.Double(35)}, false},
+		{"girth on one side", &pb.MeasurementInput{Kind: "girth", Site: "natkoljenica", Side: "left", Value: wrapperspb.Double(45)}, true},
+		{"bilateral side", &pb.MeasurementInput{Kind: "rom", Site: "vratna kralježnica", Side: "bilateral", Movement: "rotation", Value: wrapperspb.Double(70)}, true},
+		{"side in capitals", &pb.MeasurementInput{Kind: "rom", Site: "rame", Side: " LEFT ", Movement: "abduction", Value: wrapperspb.Double(120)}, true},
+		{"unknown side", &pb.MeasurementInput{Kind: "rom", Site: "rame", Side: "lijevo", Movement: "abduction", Value: wrapperspb.Double(120)}, false},
+		{"unknown side on a pain score", &pb.MeasurementInput{Kind: "nrs", Side: "both", Value: wrapperspb.Double(3)}, false},
+		{"kind in capitals", &pb.MeasurementInput{Kind: " NRS ", Value: wrapperspb.Double(4)}, true},
+		{"unknown kind", &pb.MeasurementInput{Kind: "spo2", Value: wrapperspb.Double(98)}, false},
+		{"empty kind", &pb.MeasurementInput{Value: wrapperspb.Double(1)}, false},
+		{"no value", &pb.MeasurementInput{Kind: "nrs"}, false},
+	}
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		got, err := buildMeasurements("visit", []*pb.MeasurementInput{tt.in}, now)
+		if !tt.ok {
+			if !errors.Is(err, se.ErrInvalidRequest) {
+				t.Errorf("%s: got %v, want ErrInvalidRequest", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if len(got) != 1 || got[0].GetValue() != tt.in.GetValue().GetValue() || got[0].GetUnit() != measurementKinds[got[0].GetKind()].unit {
+			t.Errorf("%s: got %+v", tt.name, got)
+		}
+	}
+}
+
+func TestBuildMeasurementsNormalizes(t *testing.T) {
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	got, err := buildMeasurements("visit", []*pb.MeasurementInput{
+		{Kind: " ROM ", Site: " rame ", Side: " Left ", Movement: " abduction ", Value: wrapperspb.Double(120), Note: " bolno na kraju "},
+		{Kind: "nrs", Value: wrapperspb.Double(6)},
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := got[0]
+	if m.GetKind() != "rom" || m.GetSite() != "rame" || m.GetSide() != "left" || m.GetMovement() != "abduction" || m.GetNote() != "bolno na kraju" {
+		t.Errorf("fields not trimmed and lower-cased: %+v", m)
+	}
+	if m.GetAnamnesisUuid() != "visit" || m.GetUuid() == "" || m.GetUuid() == got[1].GetUuid() || !m.GetCreatedAt().AsTime().Equal(now) {
+		t.Errorf("identity and timestamp: %+v", m)
+	}
+
+	// One bad entry rejects the whole list and names its position.
+	_, err = buildMeasurements("visit", []*pb.MeasurementInput{{Kind: "nrs", Value: wrapperspb.Double(6)}, {Kind: "nrs", Value: wrapperspb.Double(12)}}, now)
+	if !errors.Is(err, se.ErrInvalidRequest) || !strings.HasPrefix(err.Error(), "measurement 2:") {
+		t.Errorf("got %v, want an error for measurement 2", err)
+	}
+}
+
+func TestFormatMeasurements(t *testing.T) {
+	got := formatMeasurements([]*pb.Measurement{
+		{Kind: "rom", Site: "koljeno", Side: "right", Movement: "flexion", Value: 95, Unit: "°"},
+		{Kind: "nrs", Value: 6, Unit: "/10"},
+		{Kind: "girth", Site: "potkoljenica", Side: "left", Value: 36.5, Unit: "cm", Note: "edem"},
+	})
+	want := "Opseg pokreta: koljeno flexion, desno 95°\nNRS bol: 6/10\nOpseg: potkoljenica, lijevo 36.5 cm (edem)"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
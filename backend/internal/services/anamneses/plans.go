@@ -0,0 +1,57 @@
+package anamneses
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	svctreatmentplans "github.com/OPetricevic/physio-tracker/backend/internal/services/treatmentplans"
+)
+
+var planStatusLabels = map[string]string{
+	svctreatmentplans.StatusActive:    "u tijeku",
+	svctreatmentplans.StatusCompleted: "završen",
+	svctreatmentplans.StatusAbandoned: "prekinut",
+}
+
+var goalStatusLabels = map[string]string{
+	svctreatmentplans.GoalOpen:              "otvoren",
+	svctreatmentplans.GoalAchieved:          "postignut",
+	svctreatmentplans.GoalPartiallyAchieved: "djelomično postignut",
+	svctreatmentplans.GoalNotAchieved:       "nije postignut",
+}
+
+// formatPlanProgress summarises the plan for the PDF header, e.g. "Termin 4 od 10, 3x tjedno".
+func formatPlanProgress(plan *pb.TreatmentPlan, session int32) string {
+	lines := make([]string, 0, 4)
+	line := fmt.Sprintf("Odrađeno %d od %d planiranih termina", plan.GetSessionsDone(), plan.GetPlannedSessions())
+	if session > 0 {
+		line = fmt.Sprintf("Termin %d od %d", session, plan.GetPlannedSessions())
+	}
+	if plan.GetFrequency() != "" {
+		line += ", " + plan.GetFrequency()
+	}
+	lines = append(lines, line)
+	if len(plan.GetModalities()) > 0 {
+		lines = append(lines, "Modaliteti: "+strings.Join(plan.GetModalities(), ", "))
+	}
+	lines = append(lines, fmt.Sprintf("Početak: %s, status: %s", formatPlainDate(plan.GetStartDate()), planStatusLabels[plan.GetStatus()]))
+	if n := len(plan.GetGoals()); n > 0 {
+		lines = append(lines, fmt.Sprintf("Postignuto ciljeva: %d od %d", plan.GetGoalsAchieved(), n))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatGoals lists the SMART goals with their target date and attainment.
+func formatGoals(list []*pb.TreatmentGoal) string {
+	lines := make([]string, 0, len(list))
+	for _, g := range list {
+		line := "- " + g.GetDescription()
+		if g.GetMeasure() != "" {
+			line += " (" + g.GetMeasure() + ")"
+		}
+		line += fmt.Sprintf(", do %s: %s", formatPlainDate(g.GetTargetDate()), goalStatusLabels[g.GetStatus()])
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
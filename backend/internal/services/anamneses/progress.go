@@ -0,0 +1,155 @@
+package anamneses
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	svcquestionnaires "github.com/OPetricevic/physio-tracker/backend/internal/services/questionnaires"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	ProgressSourceMeasurement   = "measurement"
+	ProgressSourceQuestionnaire = "questionnaire"
+)
+
+// Progress groups a patient's measurements and questionnaire scores into time series and
+// compares the latest value with the baseline (the first one).
+func (s *service) Progress(ctx context.Context, doctorUUID, patientUUID string) (*pb.PatientProgressResponse, error) {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("patient progress: %w", err)
+	}
+	visits, err := s.repo.List(ctx, patientUUID, doctorUUID, "", 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("patient progress: %w", err)
+	}
+	results, err := s.resultRepo.List(ctx, patientUUID, "")
+	if err != nil {
+		return nil, fmt.Errorf("patient progress: %w", err)
+	}
+
+	set := newSeriesSet()
+	set.addMeasurements(visits)
+	for _, res := range results {
+		point := func(value float64) *pb.ProgressPoint {
+			return &pb.ProgressPoint{At: res.GetCompletedAt(), Value: value, AnamnesisUuid: res.GetAnamnesisUuid()}
+		}
+		questionnaire := func(subscale, label string) func() *pb.ProgressSeries {
+			return func() *pb.ProgressSeries {
+				unit, higherIsBetter, mcid := svcquestionnaires.Trend(res.GetCode(), subscale)
+				return &pb.ProgressSeries{
+					Source:         ProgressSourceQuestionnaire,
+					Kind:           res.GetCode(),
+					Subscale:       subscale,
+					Label:          label,
+					Unit:           unit,
+					HigherIsBetter: higherIsBetter,
+					Mcid:           mcid,
+				}
+			}
+		}
+		if res.Score != nil {
+			ser := set.get(res.GetCode(), questionnaire("", strings.ToUpper(res.GetCode())))
+			ser.Points = append(ser.Points, point(res.GetScore().GetValue()))
+		}
+		for _, sub := range res.GetSubscores() {
+			ser := set.get(res.GetCode()+"|"+sub.GetCode(), questionnaire(sub.GetCode(), strings.ToUpper(res.GetCode())+": "+sub.GetName()))
+			ser.Points = append(ser.Points, point(sub.GetScore()))
+		}
+	}
+
+	return &pb.PatientProgressResponse{PatientUuid: patientUUID, Series: set.list()}, nil
+}
+
+// seriesSet collects progress series by key in the order they are first seen.
+type seriesSet struct {
+	byKey map[string]*pb.ProgressSeries
+	order []string
+}
+
+func newSeriesSet() *seriesSet {
+	return &seriesSet{byKey: map[string]*pb.ProgressSeries{}}
+}
+
+func (set *seriesSet) get(key string, init func() *pb.ProgressSeries) *pb.ProgressSeries {
+	if ser, ok := set.byKey[key]; ok {
+		return ser
+	}
+	ser := init()
+	ser.Key = key
+	set.byKey[key] = ser
+	set.order = append(set.order, key)
+	return ser
+}
+
+// addMeasurements adds one point per measurement, dated with its visit.
+func (set *seriesSet) addMeasurements(visits []*pb.Anamnesis) {
+	for _, v := range visits {
+		for _, m := range v.GetMeasurements() {
+			key := strings.Join([]string{m.GetKind(), strings.ToLower(m.GetSite()), m.GetSide(), strings.ToLower(m.GetMovement())}, "|")
+			ser := set.get(key, func() *pb.ProgressSeries {
+				kind := measurementKinds[m.GetKind()]
+				return &pb.ProgressSeries{
+					Source:         ProgressSourceMeasurement,
+					Kind:           m.GetKind(),
+					Site:           m.GetSite(),
+					Side:           m.GetSide(),
+					Movement:       m.GetMovement(),
+					Label:          measurementLabel(m.GetKind(), m.GetSite(), m.GetSide(), m.GetMovement()),
+					Unit:           m.GetUnit(),
+					HigherIsBetter: kind.higherIsBetter,
+					Mcid:           kind.mcid,
+				}
+			})
+			ser.Points = append(ser.Points, &pb.ProgressPoint{At: v.GetCreatedAt(), Value: m.GetValue(), AnamnesisUuid: v.GetUuid()})
+		}
+	}
+}
+
+// list returns the summarized series.
+func (set *seriesSet) list() []*pb.ProgressSeries {
+	res := make([]*pb.ProgressSeries, 0, len(set.order))
+	for _, key := range set.order {
+		ser := set.byKey[key]
+		summarize(ser)
+		res = append(res, ser)
+	}
+	return res
+}
+
+// summarize sorts the points and fills in baseline, latest, change and the MCID status.
+func summarize(ser *pb.ProgressSeries) {
+	sort.SliceStable(ser.Points, func(i, j int) bool {
+		return ser.Points[i].GetAt().AsTime().Before(ser.Points[j].GetAt().AsTime())
+	})
+	if len(ser.Points) == 0 {
+		return
+	}
+	ser.Baseline = ser.Points[0].GetValue()
+	ser.Latest = ser.Points[len(ser.Points)-1].GetValue()
+	ser.Change = ser.Latest - ser.Baseline
+	if ser.Baseline != 0 {
+		ser.PercentChange = wrapperspb.Double(ser.Change / math.Abs(ser.Baseline) * 100)
+	}
+	if ser.Mcid <= 0 || len(ser.Points) < 2 {
+		return
+	}
+	improvement := ser.Change
+	if !ser.HigherIsBetter {
+		improvement = -improvement
+	}
+	const eps = 1e-9 // scores are averages, 35.2 - 25 must still reach an MCID of 10.2
+	switch {
+	case improvement >= ser.Mcid-eps:
+		ser.McidStatus = "improved"
+	case improvement <= -ser.Mcid+eps:
+		ser.McidStatus = "worsened"
+	default:
+		ser.McidStatus = "unchanged"
+	}
+}
@@ -0,0 +1,151 @@
+package anamneses
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSummarize(t *testing.T) {
+	day := func(d int) *timestamppb.Timestamp {
+		return timestamppb.New(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC).AddDate(0, 0, d))
+	}
+	points := func(values ...float64) []*pb.ProgressPoint {
+		res := make([]*pb.ProgressPoint, len(values))
+		for i, v := range values {
+			res[i] = &pb.ProgressPoint{At: day(7 * i), Value: v}
+		}
+		return res
+	}
+	tests := []struct {
+		name           string
+		points         []*pb.ProgressPoint
+		higherIsBetter bool
+		mcid           float64
+		baseline       float64
+		latest         float64
+		percent        *float64 // nil when there is no percent change
+		status         string
+	}{
+		{
+			name:   "pain drops by the mcid",
+			points: points(7, 6, 5), mcid: 2,
+			baseline: 7, latest: 5, percent: ptr(-2.0 / 7 * 100), status: "improved",
+		},
+		{
+			name:   "pain rises by the mcid",
+			points: points(3, 5), mcid: 2,
+			baseline: 3, latest: 5, percent: ptr(2.0 / 3 * 100), status: "worsened",
+		},
+		{
+			name:   "pain change below the mcid",
+			points: points(6, 5), mcid: 2,
+			baseline: 6, latest: 5, percent: ptr(-1.0 / 6 * 100), status: "unchanged",
+		},
+		{
+			name:   "questionnaire score rises by the mcid",
+			points: points(40, 55), higherIsBetter: true, mcid: 15,
+			baseline: 40, latest: 55, percent: ptr(37.5), status: "improved",
+		},
+		{
+			name:   "questionnaire score drops by the mcid",
+			points: points(55, 40), higherIsBetter: true, mcid: 15,
+			baseline: 55, latest: 40, percent: ptr(-15.0 / 55 * 100), status: "worsened",
+		},
+		{
+			name:   "averaged score exactly at the mcid despite rounding",
+			points: points(25, 35.2), higherIsBetter: true, mcid: 10.2,
+			baseline: 25, latest: 35.2, percent: ptr(10.2 / 25 * 100), status: "improved",
+		},
+		{
+			name:   "averaged score just below the mcid",
+			points: points(25, 35.19), higherIsBetter: true, mcid: 10.2,
+			baseline: 25, latest: 35.19, percent: ptr(10.19 / 25 * 100), status: "unchanged",
+		},
+		{
+			name:   "zero baseline has no percent change",
+			points: points(0, 4), mcid: 2,
+			baseline: 0, latest: 4, status: "worsened",
+		},
+		{
+			name:   "negative baseline uses its magnitude",
+			points: points(-10, 0), higherIsBetter: true,
+			baseline: -10, latest: 0, percent: ptr(100),
+		},
+		{
+			name:   "rom without an mcid has no status",
+			points: points(90, 120), higherIsBetter: true,
+			baseline: 90, latest: 120, percent: ptr(100.0 / 3),
+		},
+		{
+			name:   "single point has no status",
+			points: points(6), mcid: 2,
+			baseline: 6, latest: 6, percent: ptr(0),
+		},
+		{
+			name:   "points are ordered by date before comparing",
+			points: []*pb.ProgressPoint{{At: day(14), Value: 3}, {At: day(0), Value: 8}, {At: day(7), Value: 5}}, mcid: 2,
+			baseline: 8, latest: 3, percent: ptr(-62.5), status: "improved",
+		},
+	}
+	for _, tt := range tests {
+		ser := &pb.ProgressSeries{Points: tt.points, HigherIsBetter: tt.higherIsBetter, Mcid: tt.mcid}
+		summarize(ser)
+		if ser.GetBaseline() != tt.baseline || ser.GetLatest() != tt.latest || ser.GetChange() != tt.latest-tt.baseline {
+			t.Errorf("%s: baseline %g latest %g change %g", tt.name, ser.GetBaseline(), ser.GetLatest(), ser.GetChange())
+		}
+		switch {
+		case tt.percent == nil && ser.PercentChange != nil:
+			t.Errorf("%s: percent change %g, want none", tt.name, ser.GetPercentChange().GetValue())
+		case tt.percent != nil && (ser.PercentChange == nil || math.Abs(ser.GetPercentChange().GetValue()-*tt.percent) > 1e-9):
+			t.Errorf("%s: percent change %v, want %g", tt.name, ser.GetPercentChange(), *tt.percent)
+		}
+		if ser.GetMcidStatus() != tt.status {
+			t.Errorf("%s: mcid status %q, want %q", tt.name, ser.GetMcidStatus(), tt.status)
+		}
+	}
+
+	empty := &pb.ProgressSeries{Mcid: 2}
+	summarize(empty)
+	if empty.GetBaseline() != 0 || empty.PercentChange != nil || empty.GetMcidStatus() != "" {
+		t.Errorf("empty series: %+v", empty)
+	}
+}
+
+func TestAddMeasurementsGroupsSeries(t *testing.T) {
+	at := func(d int) *timestamppb.Timestamp {
+		return timestamppb.New(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC).AddDate(0, 0, d))
+	}
+	visits := []*pb.Anamnesis{
+		{Uuid: "v2", CreatedAt: at(14), Measurements: []*pb.Measurement{
+			{Kind: "rom", Site: "Koljeno", Side: "right", Movement: "Flexion", Value: 110, Unit: "°"},
+			{Kind: "nrs", Value: 3, Unit: "/10"},
+		}},
+		{Uuid: "v1", CreatedAt: at(0), Measurements: []*pb.Measurement{
+			{Kind: "rom", Site: "koljeno", Side: "right", Movement: "flexion", Value: 80, Unit: "°"},
+			{Kind: "rom", Site: "koljeno", Side: "left", Movement: "flexion", Value: 135, Unit: "°"},
+			{Kind: "nrs", Value: 7, Unit: "/10"},
+		}},
+	}
+	set := newSeriesSet()
+	set.addMeasurements(visits)
+	list := set.list()
+	if len(list) != 3 {
+		t.Fatalf("got %d series, want right knee, pain and left knee", len(list))
+	}
+	right, pain := list[0], list[1]
+	if right.GetKey() != "rom|koljeno|right|flexion" || len(right.GetPoints()) != 2 || right.GetBaseline() != 80 || right.GetLatest() != 110 {
+		t.Errorf("right knee: %+v", right)
+	}
+	if !right.GetHigherIsBetter() || right.GetMcidStatus() != "" {
+		t.Errorf("rom reads higher as better and has no mcid: %+v", right)
+	}
+	if pain.GetHigherIsBetter() || pain.GetMcid() != 2 || pain.GetMcidStatus() != "improved" || pain.GetPoints()[0].GetAnamnesisUuid() != "v1" {
+		t.Errorf("pain: %+v", pain)
+	}
+}
+
+func ptr(v float64) *float64 { return &v }
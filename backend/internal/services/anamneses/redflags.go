@@ -0,0 +1,204 @@
+package anamneses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// defaultRedFlagItems is the spinal screening used until a doctor configures an own checklist.
+var defaultRedFlagItems = []*pb.RedFlagItem{
+	{Code: "cauda_equina", Label: "Simptomi sindroma cauda equina (sedlasta anestezija, poremećaj mokrenja ili stolice)"},
+	{Code: "neuro_deficit", Label: "Progresivni neurološki deficit (slabost, gubitak osjeta)"},
+	{Code: "weight_loss", Label: "Neobjašnjeni gubitak tjelesne težine"},
+	{Code: "night_pain", Label: "Noćna bol koja ne popušta mirovanjem"},
+	{Code: "cancer_history", Label: "Maligna bolest u anamnezi"},
+	{Code: "fever", Label: "Povišena temperatura ili znakovi infekcije"},
+	{Code: "trauma", Label: "Značajna trauma ili manja trauma uz osteoporozu"},
+	{Code: "steroids", Label: "Dugotrajna terapija kortikosteroidima ili imunosupresija"},
+	{Code: "age_onset", Label: "Prva pojava boli prije 20. ili nakon 55. godine"},
+}
+
+// RedFlagChecklist returns the checklist offered on the doctor's visit form.
+func (s *service) RedFlagChecklist(ctx context.Context, doctorUUID string) (*pb.RedFlagChecklistResponse, error) {
+	items, err := s.repo.RedFlagChecklist(ctx, doctorUUID)
+	if err != nil {
+		return nil, fmt.Errorf("get red flag checklist: %w", err)
+	}
+	if len(items) == 0 {
+		return &pb.RedFlagChecklistResponse{Items: defaultRedFlagItems, IsDefault: true}, nil
+	}
+	return &pb.RedFlagChecklistResponse{Items: items}, nil
+}
+
+// UpdateRedFlagChecklist replaces the doctor's checklist. Visits keep the answers they were saved with.
+func (s *service) UpdateRedFlagChecklist(ctx context.Context, doctorUUID string, req *pb.UpdateRedFlagChecklistRequest) (*pb.RedFlagChecklistResponse, error) {
+	items, err := buildRedFlagChecklist(req.GetItems())
+	if err != nil {
+		return nil, fmt.Errorf("update red flag checklist: %w", err)
+	}
+	if err := s.repo.SetRedFlagChecklist(ctx, doctorUUID, items); err != nil {
+		switch {
+		case errors.Is(err, re.ErrNotFound):
+			return nil, fmt.Errorf("update red flag checklist: %w", se.ErrNotFound)
+		case errors.Is(err, re.ErrConflict):
+			return nil, fmt.Errorf("update red flag checklist: %w", se.ErrConflict)
+		}
+		return nil, fmt.Errorf("update red flag checklist: %w", err)
+	}
+	return s.RedFlagChecklist(ctx, doctorUUID)
+}
+
+// OpenRedFlags lists the doctor's visits with positive red flags that have no action taken yet.
+func (s *service) OpenRedFlags(ctx context.Context, doctorUUID string) ([]*pb.OpenRedFlag, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("list open red flags: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.repo.ListOpenRedFlags(ctx, doctorUUID)
+	if err != nil {
+		return nil, fmt.Errorf("list open red flags: %w", err)
+	}
+	return list, nil
+}
+
+// redFlagAnswers resolves the answers of a visit against the doctor's current checklist.
+func (s *service) redFlagAnswers(ctx context.Context, doctorUUID string, inputs []*pb.RedFlagAnswerInput, existing []*pb.RedFlagAnswer) ([]*pb.RedFlagAnswer, error) {
+	if len(inputs) == 0 {
+		return []*pb.RedFlagAnswer{}, nil
+	}
+	checklist, err := s.RedFlagChecklist(ctx, doctorUUID)
+	if err != nil {
+		return nil, err
+	}
+	return buildRedFlags(checklist.GetItems(), inputs, existing)
+}
+
+func buildRedFlagChecklist(inputs []*pb.RedFlagItemInput) ([]*pb.RedFlagItem, error) {
+	items := make([]*pb.RedFlagItem, 0, len(inputs))
+	seen := map[string]bool{}
+	for _, in := range inputs {
+		code := strings.TrimSpace(in.GetCode())
+		label := strings.TrimSpace(in.GetLabel())
+		if code == "" || label == "" {
+			return nil, fmt.Errorf("red flag code and label are required: %w", se.ErrInvalidRequest)
+		}
+		if seen[code] {
+			return nil, fmt.Errorf("duplicate red flag %q: %w", code, se.ErrInvalidRequest)
+		}
+		seen[code] = true
+		items = append(items, &pb.RedFlagItem{Code: code, Label: label})
+	}
+	return items, nil
+}
+
+// buildRedFlags copies the checklist label into each answer. Codes the visit already has keep their
+// stored label, so a note stays editable after the item was removed from the checklist.
+func buildRedFlags(checklist []*pb.RedFlagItem, inputs []*pb.RedFlagAnswerInput, existing []*pb.RedFlagAnswer) ([]*pb.RedFlagAnswer, error) {
+	labels := make(map[string]string, len(checklist)+len(existing))
+	for _, it := range checklist {
+		labels[it.GetCode()] = it.GetLabel()
+	}
+	for _, f := range existing {
+		labels[f.GetCode()] = f.GetLabel()
+	}
+	res := make([]*pb.RedFlagAnswer, 0, len(inputs))
+	seen := map[string]bool{}
+	for _, in := range inputs {
+		code := strings.TrimSpace(in.GetCode())
+		label, ok := labels[code]
+		if !ok {
+			return nil, fmt.Errorf("unknown red flag %q: %w", code, se.ErrInvalidRequest)
+		}
+		if seen[code] {
+			return nil, fmt.Errorf("duplicate red flag %q: %w", code, se.ErrInvalidRequest)
+		}
+		seen[code] = true
+		res = append(res, &pb.RedFlagAnswer{
+			Code:     code,
+			Label:    label,
+			Positive: in.GetPositive(),
+			Note:     strings.TrimSpace(in.GetNote()),
+		})
+	}
+	return res, nil
+}
+
+// hasOpenRedFlags reports a positive red flag without a recorded action taken; such a note can not
+// be signed and is skipped by the auto-lock.
+func hasOpenRedFlags(a *pb.Anamnesis) bool {
+	if strings.TrimSpace(a.GetRedFlagAction()) != "" {
+		return false
+	}
+	for _, f := range a.GetRedFlags() {
+		if f.GetPositive() {
+			return true
+		}
+	}
+	return false
+}
+
+// formatRedFlags prints one "label: DA/ne" line per answer, as compared in the edit history.
+func formatRedFlags(list []*pb.RedFlagAnswer) string {
+	lines := make([]string, 0, len(list))
+	for _, f := range list {
+		lines = append(lines, redFlagLine(f))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func redFlagLine(f *pb.RedFlagAnswer) string {
+	answer := "ne"
+	if f.GetPositive() {
+		answer = "DA"
+	}
+	line := f.GetLabel() + ": " + answer
+	if f.GetNote() != "" {
+		line += " (" + f.GetNote() + ")"
+	}
+	return line
+}
+
+// drawRedFlags prints the screening of a visit with the positive flags in bold red, followed by the
+// action taken or a red notice that it is still missing.
+func drawRedFlags(pdf *gofpdf.Fpdf, a *pb.Anamnesis) {
+	if len(a.GetRedFlags()) == 0 {
+		return
+	}
+	pdf.SetFont("DejaVu", "B", 10)
+	pdf.MultiCell(0, 5, "Crveni alarmi", "", "", false)
+	var negative []string
+	for _, f := range a.GetRedFlags() {
+		if !f.GetPositive() {
+			negative = append(negative, f.GetLabel())
+			continue
+		}
+		pdf.SetTextColor(200, 0, 0)
+		pdf.SetFont("DejaVu", "B", 10)
+		pdf.MultiCell(0, 5, "! "+redFlagLine(f), "", "", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.SetFont("DejaVu", "", 10)
+	if len(negative) > 0 {
+		pdf.MultiCell(0, 5, "Negativno: "+strings.Join(negative, "; "), "", "", false)
+	}
+	switch {
+	case strings.TrimSpace(a.GetRedFlagAction()) != "":
+		pdf.SetFont("DejaVu", "B", 10)
+		pdf.MultiCell(0, 5, "Poduzete mjere", "", "", false)
+		pdf.SetFont("DejaVu", "", 10)
+		pdf.MultiCell(0, 5, a.GetRedFlagAction(), "", "", false)
+	case hasOpenRedFlags(a):
+		pdf.SetTextColor(200, 0, 0)
+		pdf.SetFont("DejaVu", "B", 10)
+		pdf.MultiCell(0, 5, "Poduzete mjere nisu upisane", "", "", false)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFont("DejaVu", "", 10)
+	}
+	pdf.Ln(2)
+}
@@ -0,0 +1,53 @@
+package anamneses
+
+import (
+	"errors"
+	"testing"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+)
+
+func TestBuildRedFlags(t *testing.T) {
+	checklist := []*pb.RedFlagItem{{Code: "night_pain", Label: "Noćna bol"}, {Code: "fever", Label: "Vrućica"}}
+	existing := []*pb.RedFlagAnswer{{Code: "weight_loss", Label: "Gubitak težine"}}
+
+	got, err := buildRedFlags(checklist, []*pb.RedFlagAnswerInput{
+		{Code: "night_pain", Positive: true, Note: " budi ga noću "},
+		{Code: "weight_loss"},
+	}, existing)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want := "Noćna bol: DA (budi ga noću)\nGubitak težine: ne"; formatRedFlags(got) != want {
+		t.Errorf("got %q, want %q", formatRedFlags(got), want)
+	}
+
+	for name, inputs := range map[string][]*pb.RedFlagAnswerInput{
+		"unknown code": {{Code: "weight_loss"}},
+		"duplicate":    {{Code: "fever"}, {Code: "fever", Positive: true}},
+	} {
+		if _, err := buildRedFlags(checklist, inputs, nil); !errors.Is(err, se.ErrInvalidRequest) {
+			t.Errorf("%s: got %v, want ErrInvalidRequest", name, err)
+		}
+	}
+}
+
+func TestHasOpenRedFlags(t *testing.T) {
+	positive := []*pb.RedFlagAnswer{{Code: "fever"}, {Code: "cauda_equina", Positive: true}}
+	tests := []struct {
+		name  string
+		visit *pb.Anamnesis
+		open  bool
+	}{
+		{"no screening", &pb.Anamnesis{}, false},
+		{"all negative", &pb.Anamnesis{RedFlags: []*pb.RedFlagAnswer{{Code: "fever"}}}, false},
+		{"positive without action", &pb.Anamnesis{RedFlags: positive, RedFlagAction: "  "}, true},
+		{"positive with action", &pb.Anamnesis{RedFlags: positive, RedFlagAction: "Upućen na hitni prijem"}, false},
+	}
+	for _, tt := range tests {
+		if got := hasOpenRedFlags(tt.visit); got != tt.open {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.open)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package anamneses
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+)
+
+// revisionField is one field of a note compared between revisions.
+type revisionField struct {
+	name  string
+	value func(a *pb.Anamnesis) string
+}
+
+// revisionFields lists the compared fields in the order the visit form shows them; measurements and
+// the body chart are compared as the lines printed in the PDF.
+var revisionFields = []revisionField{
+	{"anamnesis", (*pb.Anamnesis).GetAnamnesis},
+	{"status", (*pb.Anamnesis).GetStatus},
+	{"diagnosis", (*pb.Anamnesis).GetDiagnosis},
+	{"diagnosis_codes", func(a *pb.Anamnesis) string { return formatDiagnosisCodes(a.GetDiagnosisCodes()) }},
+	{"therapy", (*pb.Anamnesis).GetTherapy},
+	{"other_info", (*pb.Anamnesis).GetOtherInfo},
+	{"measurements", func(a *pb.Anamnesis) string { return formatMeasurements(a.GetMeasurements()) }},
+	{"pain_marks", func(a *pb.Anamnesis) string { return formatPainMarks(a.GetPainMarks()) }},
+	{"red_flags", func(a *pb.Anamnesis) string { return formatRedFlags(a.GetRedFlags()) }},
+	{"red_flag_action", (*pb.Anamnesis).GetRedFlagAction},
+	{"treatment_plan_uuid", (*pb.Anamnesis).GetTreatmentPlanUuid},
+	{"include_visit_uuids", func(a *pb.Anamnesis) string { return strings.Join(a.GetIncludeVisitUuids(), ", ") }},
+}
+
+// diffRevisions returns the fields that differ between two versions of a note.
+func diffRevisions(from, to *pb.Anamnesis) []*pb.AnamnesisFieldChange {
+	changes := []*pb.AnamnesisFieldChange{}
+	for _, f := range revisionFields {
+		if a, b := f.value(from), f.value(to); a != b {
+			changes = append(changes, &pb.AnamnesisFieldChange{Field: f.name, From: a, To: b})
+		}
+	}
+	return changes
+}
+
+// Revisions lists the saved versions of a note, oldest first, each with the fields it changed and
+// the name of the doctor who saved it.
+func (s *service) Revisions(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string) ([]*pb.AnamnesisRevision, error) {
+	revisions, err := s.loadRevisions(ctx, doctorUUID, patientUUID, anamnesisUUID)
+	if err != nil {
+		return nil, fmt.Errorf("list anamnesis revisions: %w", err)
+	}
+	names := map[string]string{}
+	for i, rev := range revisions {
+		if i > 0 {
+			for _, c := range diffRevisions(revisions[i-1].GetContent(), rev.GetContent()) {
+				rev.ChangedFields = append(rev.ChangedFields, c.GetField())
+			}
+		}
+		by := rev.GetChangedByUuid()
+		if by == "" {
+			continue
+		}
+		if _, ok := names[by]; !ok {
+			names[by] = ""
+			if d, err := s.doctorRepo.Get(ctx, by); err == nil {
+				names[by] = strings.TrimSpace(d.GetFirstName() + " " + d.GetLastName())
+			}
+		}
+		rev.ChangedByName = names[by]
+	}
+	return revisions, nil
+}
+
+// RevisionDiff compares two revisions of a note field by field. Zero means the latest revision for
+// to and the one before to for from.
+func (s *service) RevisionDiff(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string, from, to int32) (*pb.AnamnesisRevisionDiffResponse, error) {
+	revisions, err := s.loadRevisions(ctx, doctorUUID, patientUUID, anamnesisUUID)
+	if err != nil {
+		return nil, fmt.Errorf("diff anamnesis revisions: %w", err)
+	}
+	latest := int32(len(revisions))
+	if to == 0 {
+		to = latest
+	}
+	if from == 0 {
+		from = max(to-1, 1)
+	}
+	if from < 1 || from > latest || to < 1 || to > latest {
+		return nil, fmt.Errorf("diff anamnesis revisions: revisions must be between 1 and %d: %w", latest, se.ErrInvalidRequest)
+	}
+	// Revisions are numbered 1..n without gaps, so the list index is the number minus one.
+	return &pb.AnamnesisRevisionDiffResponse{
+		FromRevision: from,
+		ToRevision:   to,
+		Changes:      diffRevisions(revisions[from-1].GetContent(), revisions[to-1].GetContent()),
+	}, nil
+}
+
+// loadRevisions checks that the note belongs to the doctor's patient and returns its revisions.
+func (s *service) loadRevisions(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string) ([]*pb.AnamnesisRevision, error) {
+	if _, err := s.ownedVisit(ctx, doctorUUID, patientUUID, anamnesisUUID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRevisions(ctx, anamnesisUUID)
+}
@@ -0,0 +1,38 @@
+package anamneses
+
+import (
+	"testing"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	from := &pb.Anamnesis{
+		Anamnesis:    "Bol u koljenu 3 tjedna.",
+		Diagnosis:    "Gonartroza",
+		Measurements: []*pb.Measurement{{Kind: MeasurementNRS, Value: 6, Unit: "/10"}},
+	}
+	to := &pb.Anamnesis{
+		Anamnesis:    "Bol u koljenu 3 tjedna.",
+		Diagnosis:    "Gonartroza desno",
+		Therapy:      "TENS",
+		Measurements: []*pb.Measurement{{Kind: MeasurementNRS, Value: 4, Unit: "/10"}},
+	}
+	changes := diffRevisions(from, to)
+	want := []*pb.AnamnesisFieldChange{
+		{Field: "diagnosis", From: "Gonartroza", To: "Gonartroza desno"},
+		{Field: "therapy", From: "", To: "TENS"},
+		{Field: "measurements", From: "NRS bol: 6/10", To: "NRS bol: 4/10"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes %v, want %d", len(changes), changes, len(want))
+	}
+	for i, c := range changes {
+		if c.GetField() != want[i].GetField() || c.GetFrom() != want[i].GetFrom() || c.GetTo() != want[i].GetTo() {
+			t.Errorf("change %d: got %v, want %v", i, c, want[i])
+		}
+	}
+	if n := len(diffRevisions(to, to)); n != 0 {
+		t.Errorf("identical revisions: got %d changes", n)
+	}
+}
@@ -0,0 +1,127 @@
+package anamneses
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+)
+
+// templatePlaceholders lists what anamnesis templates may use; anything else in braces is rejected on save.
+var templatePlaceholders = map[string]bool{
+	"first_name":            true,
+	"last_name":             true,
+	"age":                   true,
+	"today":                 true,
+	"last_visit":            true,
+	"days_since_last_visit": true,
+}
+
+var templatePlaceholderRe = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// unknownValue stands in for a placeholder that cannot be resolved, e.g. the age of a patient
+// without a date of birth or the last visit of a new patient.
+const unknownValue = "?"
+
+// ValidateTemplateText rejects unknown placeholders in one text of an anamnesis template.
+func ValidateTemplateText(name, text string) error {
+	for _, m := range templatePlaceholderRe.FindAllStringSubmatch(text, -1) {
+		if !templatePlaceholders[m[1]] {
+			return fmt.Errorf("%s: unknown placeholder {%s}: %w", name, m[1], se.ErrInvalidRequest)
+		}
+	}
+	return nil
+}
+
+// ValidateMeasurements applies the visit rules to the default measurements of a template. A default
+// may leave the value unset to only name what to measure; a set value, 0 included, is range-checked.
+func ValidateMeasurements(inputs []*pb.MeasurementInput) error {
+	for i, in := range inputs {
+		if _, err := normalizeMeasurement(i, in, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateValues resolves the placeholders for one patient; lastVisit is nil for the first visit.
+// Dates are taken in the location of now, the practice's time zone.
+func templateValues(patient *pb.Patient, lastVisit *pb.Anamnesis, now time.Time) map[string]string {
+	values := map[string]string{
+		"first_name":            patient.GetFirstName(),
+		"last_name":             patient.GetLastName(),
+		"age":                   unknownValue,
+		"today":                 now.Format("02.01.2006."),
+		"last_visit":            unknownValue,
+		"days_since_last_visit": unknownValue,
+	}
+	if dob, err := time.Parse("2006-01-02", strings.TrimSpace(patient.GetDateOfBirth().GetValue())); err == nil {
+		age := now.Year() - dob.Year()
+		if now.Month() < dob.Month() || (now.Month() == dob.Month() && now.Day() < dob.Day()) {
+			age--
+		}
+		values["age"] = strconv.Itoa(age)
+	}
+	if lastVisit != nil && lastVisit.GetCreatedAt() != nil {
+		last := lastVisit.GetCreatedAt().AsTime().In(now.Location())
+		values["last_visit"] = last.Format("02.01.2006.")
+		days := int(dateOnly(now).Sub(dateOnly(last)).Hours() / 24)
+		values["days_since_last_visit"] = strconv.Itoa(days)
+	}
+	return values
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func renderTemplate(text string, values map[string]string) string {
+	return templatePlaceholderRe.ReplaceAllStringFunc(text, func(m string) string {
+		if v, ok := values[m[1:len(m)-1]]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// applyTemplate fills the texts the doctor left empty from the template and puts the template's
+// default measurements that carry a value in front of the requested ones; a requested measurement
+// of the same kind, site, side and movement replaces the default. Defaults without a value are
+// only prefilled in the form and are never saved as a reading of 0.
+func applyTemplate(req *pb.CreateAnamnesisRequest, t *pb.AnamnesisTemplate, values map[string]string) {
+	fill := func(field *string, text string) {
+		if strings.TrimSpace(*field) == "" {
+			*field = renderTemplate(text, values)
+		}
+	}
+	fill(&req.Anamnesis, t.GetAnamnesis())
+	fill(&req.Status, t.GetStatus())
+	fill(&req.Diagnosis, t.GetDiagnosis())
+	fill(&req.Therapy, t.GetTherapy())
+	fill(&req.OtherInfo, t.GetOtherInfo())
+
+	requested := make(map[string]bool, len(req.GetMeasurements()))
+	for _, m := range req.GetMeasurements() {
+		requested[measurementInputKey(m)] = true
+	}
+	merged := make([]*pb.MeasurementInput, 0, len(t.GetMeasurements())+len(req.GetMeasurements()))
+	for _, m := range t.GetMeasurements() {
+		if m.Value != nil && !requested[measurementInputKey(m)] {
+			merged = append(merged, m)
+		}
+	}
+	req.Measurements = append(merged, req.GetMeasurements()...)
+}
+
+func measurementInputKey(m *pb.MeasurementInput) string {
+	return strings.ToLower(strings.Join([]string{
+		strings.TrimSpace(m.GetKind()),
+		strings.TrimSpace(m.GetSite()),
+		strings.TrimSpace(m.GetSide()),
+		strings.TrimSpace(m.GetMovement()),
+	}, "|"))
+}
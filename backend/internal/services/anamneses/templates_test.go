@@ -0,0 +1,126 @@
+package anamneses
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTemplateValues(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	patient := &pb.Patient{FirstName: "Ana", LastName: "Kovač", DateOfBirth: wrapperspb.String("1990-03-11")}
+	last := &pb.Anamnesis{CreatedAt: timestamppb.New(time.Date(2026, 3, 3, 17, 30, 0, 0, time.UTC))}
+
+	got := templateValues(patient, last, now)
+	want := map[string]string{
+		"first_name":            "Ana",
+		"last_name":             "Kovač",
+		"age":                   "35", // birthday is tomorrow
+		"today":                 "10.03.2026.",
+		"last_visit":            "03.03.2026.",
+		"days_since_last_visit": "7",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %q, want %q", k, got[k], v)
+		}
+	}
+
+	// Shortly after midnight in Zagreb it is still the previous day in UTC.
+	zagreb, err := time.LoadLocation("Europe/Zagreb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := templateValues(patient, last, time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC).In(zagreb))
+	if local["today"] != "11.03.2026." || local["age"] != "36" || local["days_since_last_visit"] != "8" {
+		t.Errorf("local day: got today %q, age %q, days %q", local["today"], local["age"], local["days_since_last_visit"])
+	}
+
+	first := templateValues(&pb.Patient{FirstName: "Ivo"}, nil, now)
+	for _, k := range []string{"age", "last_visit", "days_since_last_visit"} {
+		if first[k] != unknownValue {
+			t.Errorf("first visit %s: got %q, want %q", k, first[k], unknownValue)
+		}
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	tmpl := &pb.AnamnesisTemplate{
+		Anamnesis: "{first_name}, {age} god., zadnji posjet prije {days_since_last_visit} dana.",
+		Therapy:   "Kineziterapija {unknown}",
+		Measurements: []*pb.MeasurementInput{
+			{Kind: "rom", Site: "knee", Side: "right", Movement: "flexion"},
+			{Kind: "girth", Site: "natkoljenica", Side: "right"},
+			{Kind: "mmt", Site: "kvadriceps", Side: "right", Value: wrapperspb.Double(4)},
+			{Kind: "mmt", Site: "kvadriceps", Side: "left", Value: wrapperspb.Double(5)},
+			{Kind: "rom", Site: "knee", Side: "right", Movement: "extension", Value: wrapperspb.Double(0)},
+			{Kind: "nrs"},
+		},
+	}
+	req := &pb.CreateAnamnesisRequest{
+		Diagnosis:    "Ruptura LCA",
+		Therapy:      "Vlastiti tekst",
+		Measurements: []*pb.MeasurementInput{{Kind: "NRS", Value: wrapperspb.Double(4)}, {Kind: "mmt", Site: "Kvadriceps", Side: "left", Value: wrapperspb.Double(3)}},
+	}
+	applyTemplate(req, tmpl, map[string]string{"first_name": "Ana", "age": "35", "days_since_last_visit": "7"})
+
+	if want := "Ana, 35 god., zadnji posjet prije 7 dana."; req.GetAnamnesis() != want {
+		t.Errorf("anamnesis: got %q, want %q", req.GetAnamnesis(), want)
+	}
+	if req.GetDiagnosis() != "Ruptura LCA" || req.GetTherapy() != "Vlastiti tekst" {
+		t.Errorf("texts sent by the doctor were overwritten: %q, %q", req.GetDiagnosis(), req.GetTherapy())
+	}
+	// Defaults without a value are not readings: the right mmt and the 0° extension defaults are
+	// saved, the left mmt one is replaced by the doctor's value.
+	got := req.GetMeasurements()
+	if len(got) != 4 || got[0].GetSide() != "right" || got[0].GetValue().GetValue() != 4 ||
+		got[1].GetMovement() != "extension" || got[1].Value == nil || got[1].GetValue().GetValue() != 0 ||
+		got[2].GetValue().GetValue() != 4 || got[3].GetValue().GetValue() != 3 {
+		t.Errorf("measurements: got %v", got)
+	}
+	for _, m := range got {
+		if m.Value == nil {
+			t.Errorf("default without a value saved: %v", m)
+		}
+	}
+}
+
+func TestValidateMeasurements(t *testing.T) {
+	valid := []*pb.MeasurementInput{
+		{Kind: "rom", Site: "koljeno", Side: "right", Movement: "flexion"},
+		{Kind: "girth", Site: "natkoljenica"},
+		{Kind: "nrs"},
+		{Kind: "mmt", Site: "kvadriceps", Side: "left", Value: wrapperspb.Double(4)},
+		{Kind: "mmt", Site: "kvadriceps", Side: "right", Value: wrapperspb.Double(0)},
+		{Kind: "nrs", Value: wrapperspb.Double(0)},
+	}
+	if err := ValidateMeasurements(valid); err != nil {
+		t.Errorf("defaults without a value: unexpected error %v", err)
+	}
+	for name, in := range map[string]*pb.MeasurementInput{
+		"girth below minimum":  {Kind: "girth", Site: "natkoljenica", Value: wrapperspb.Double(0.05)},
+		"girth of 0":           {Kind: "girth", Site: "natkoljenica", Value: wrapperspb.Double(0)},
+		"nrs out of range":     {Kind: "nrs", Value: wrapperspb.Double(12)},
+		"rom without movement": {Kind: "rom", Site: "koljeno", Side: "right"},
+		"mmt without side":     {Kind: "mmt", Site: "kvadriceps"},
+		"unknown kind":         {Kind: "bmi"},
+	} {
+		if err := ValidateMeasurements([]*pb.MeasurementInput{in}); !errors.Is(err, se.ErrInvalidRequest) {
+			t.Errorf("%s: got %v, want ErrInvalidRequest", name, err)
+		}
+	}
+}
+
+func TestValidateTemplateText(t *testing.T) {
+	if err := ValidateTemplateText("anamnesis", "{first_name} ({age}), {today}"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := ValidateTemplateText("anamnesis", "{diagnosis}"); !errors.Is(err, se.ErrInvalidRequest) {
+		t.Errorf("got %v, want ErrInvalidRequest", err)
+	}
+}
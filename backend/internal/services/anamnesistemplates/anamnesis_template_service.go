@@ -0,0 +1,173 @@
+package anamnesistemplates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/anamnesistemplates"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcanamneses "github.com/OPetricevic/physio-tracker/backend/internal/services/anamneses"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Service interface {
+	Create(ctx context.Context, doctorUUID string, req *pb.CreateAnamnesisTemplateRequest) (*pb.AnamnesisTemplate, error)
+	Update(ctx context.Context, doctorUUID string, req *pb.UpdateAnamnesisTemplateRequest) (*pb.AnamnesisTemplate, error)
+	Get(ctx context.Context, doctorUUID, uuid string) (*pb.AnamnesisTemplate, error)
+	List(ctx context.Context, doctorUUID, condition string) ([]*pb.AnamnesisTemplate, error)
+	Delete(ctx context.Context, doctorUUID, uuid string) error
+}
+
+type service struct {
+	repo out.Repository
+	now  func() time.Time
+}
+
+func NewService(repo out.Repository) Service {
+	return &service{repo: repo, now: time.Now}
+}
+
+func (s *service) Create(ctx context.Context, doctorUUID string, req *pb.CreateAnamnesisTemplateRequest) (*pb.AnamnesisTemplate, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("create anamnesis template: %w", se.ErrInvalidRequest)
+	}
+	t := &pb.AnamnesisTemplate{
+		Uuid:         uuid.NewString(),
+		DoctorUuid:   doctorUUID,
+		Name:         strings.TrimSpace(req.GetName()),
+		Condition:    strings.TrimSpace(req.GetCondition()),
+		Anamnesis:    strings.TrimSpace(req.GetAnamnesis()),
+		Status:       strings.TrimSpace(req.GetStatus()),
+		Diagnosis:    strings.TrimSpace(req.GetDiagnosis()),
+		Therapy:      strings.TrimSpace(req.GetTherapy()),
+		OtherInfo:    strings.TrimSpace(req.GetOtherInfo()),
+		Measurements: req.GetMeasurements(),
+		CreatedAt:    timestamppb.New(s.now().UTC()),
+	}
+	if err := validateTemplate(t); err != nil {
+		return nil, fmt.Errorf("create anamnesis template: %w", err)
+	}
+	created, err := s.repo.Create(ctx, t)
+	if err != nil {
+		if errors.Is(err, re.ErrConflict) {
+			return nil, fmt.Errorf("create anamnesis template: name %q already used: %w", t.GetName(), se.ErrConflict)
+		}
+		return nil, fmt.Errorf("create anamnesis template: %w", err)
+	}
+	return created, nil
+}
+
+func (s *service) Update(ctx context.Context, doctorUUID string, req *pb.UpdateAnamnesisTemplateRequest) (*pb.AnamnesisTemplate, error) {
+	t, err := s.Get(ctx, doctorUUID, req.GetUuid())
+	if err != nil {
+		return nil, fmt.Errorf("update anamnesis template: %w", err)
+	}
+	if req.Name != nil {
+		t.Name = strings.TrimSpace(req.GetName().GetValue())
+	}
+	if req.Condition != nil {
+		t.Condition = strings.TrimSpace(req.GetCondition().GetValue())
+	}
+	if req.Anamnesis != nil {
+		t.Anamnesis = strings.TrimSpace(req.GetAnamnesis().GetValue())
+	}
+	if req.Status != nil {
+		t.Status = strings.TrimSpace(req.GetStatus().GetValue())
+	}
+	if req.Diagnosis != nil {
+		t.Diagnosis = strings.TrimSpace(req.GetDiagnosis().GetValue())
+	}
+	if req.Therapy != nil {
+		t.Therapy = strings.TrimSpace(req.GetTherapy().GetValue())
+	}
+	if req.OtherInfo != nil {
+		t.OtherInfo = strings.TrimSpace(req.GetOtherInfo().GetValue())
+	}
+	if req.Measurements != nil {
+		t.Measurements = req.GetMeasurements().GetItems()
+	}
+	if err := validateTemplate(t); err != nil {
+		return nil, fmt.Errorf("update anamnesis template: %w", err)
+	}
+	t.UpdatedAt = timestamppb.New(s.now().UTC())
+	updated, err := s.repo.Update(ctx, t)
+	if err != nil {
+		switch {
+		case errors.Is(err, re.ErrNotFound):
+			return nil, fmt.Errorf("update anamnesis template: %w", se.ErrNotFound)
+		case errors.Is(err, re.ErrConflict):
+			return nil, fmt.Errorf("update anamnesis template: name %q already used: %w", t.GetName(), se.ErrConflict)
+		}
+		return nil, fmt.Errorf("update anamnesis template: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *service) Get(ctx context.Context, doctorUUID, templateUUID string) (*pb.AnamnesisTemplate, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(templateUUID) == "" {
+		return nil, fmt.Errorf("get anamnesis template: %w", se.ErrInvalidRequest)
+	}
+	t, err := s.repo.Get(ctx, templateUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("get anamnesis template: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get anamnesis template: %w", err)
+	}
+	if t.GetDoctorUuid() != doctorUUID {
+		return nil, fmt.Errorf("get anamnesis template: %w", se.ErrNotFound)
+	}
+	return t, nil
+}
+
+func (s *service) List(ctx context.Context, doctorUUID, condition string) ([]*pb.AnamnesisTemplate, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("list anamnesis templates: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.repo.List(ctx, doctorUUID, condition)
+	if err != nil {
+		return nil, fmt.Errorf("list anamnesis templates: %w", err)
+	}
+	return list, nil
+}
+
+// Delete removes a template; visits created from it keep their texts.
+func (s *service) Delete(ctx context.Context, doctorUUID, templateUUID string) error {
+	if _, err := s.Get(ctx, doctorUUID, templateUUID); err != nil {
+		return fmt.Errorf("delete anamnesis template: %w", err)
+	}
+	if err := s.repo.Delete(ctx, templateUUID); err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return fmt.Errorf("delete anamnesis template: %w", se.ErrNotFound)
+		}
+		return fmt.Errorf("delete anamnesis template: %w", err)
+	}
+	return nil
+}
+
+// validateTemplate checks the name, the placeholders of every text and the default measurements,
+// so that a saved template can always be applied.
+func validateTemplate(t *pb.AnamnesisTemplate) error {
+	if t.GetName() == "" {
+		return fmt.Errorf("empty name: %w", se.ErrInvalidRequest)
+	}
+	texts := []struct{ name, text string }{
+		{"anamnesis", t.GetAnamnesis()},
+		{"status", t.GetStatus()},
+		{"diagnosis", t.GetDiagnosis()},
+		{"therapy", t.GetTherapy()},
+		{"other_info", t.GetOtherInfo()},
+	}
+	for _, f := range texts {
+		if err := svcanamneses.ValidateTemplateText(f.name, f.text); err != nil {
+			return err
+		}
+	}
+	return svcanamneses.ValidateMeasurements(t.GetMeasurements())
+}
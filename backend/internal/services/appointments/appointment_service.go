@@ -0,0 +1,314 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	outanamneses "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/anamneses"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/appointments"
+	outdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctorprofiles"
+	outboundportpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	StatusScheduled = "scheduled"
+	StatusCancelled = "cancelled"
+)
+
+// maxListRange caps a single list query so the calendar cannot request years at once.
+const maxListRange = 366 * 24 * time.Hour
+
+type Service interface {
+	Create(ctx context.Context, doctorUUID string, req *pb.CreateAppointmentRequest) (*pb.AppointmentResponse, error)
+	Update(ctx context.Context, doctorUUID string, req *pb.UpdateAppointmentRequest) (*pb.AppointmentResponse, error)
+	Cancel(ctx context.Context, doctorUUID string, req *pb.CancelAppointmentRequest) (*pb.Appointment, error)
+	List(ctx context.Context, doctorUUID string, req *pb.ListAppointmentsRequest) ([]*pb.Appointment, error)
+	FreeSlots(ctx context.Context, doctorUUID string, req *pb.ListFreeSlotsRequest) (*pb.ListFreeSlotsResponse, error)
+
+	ExportICS(ctx context.Context, doctorUUID string, from, to time.Time) ([]byte, error)
+	FeedICS(ctx context.Context, token string) ([]byte, error)
+	GetCalendarFeed(ctx context.Context, doctorUUID string) (*pb.CalendarFeed, error)
+	RotateCalendarFeed(ctx context.Context, doctorUUID string) (*pb.CalendarFeed, error)
+	RevokeCalendarFeed(ctx context.Context, doctorUUID string) error
+
+	ImportICS(ctx context.Context, doctorUUID string, data []byte) (*pb.ImportAppointmentsResponse, error)
+	ListImportReviews(ctx context.Context, doctorUUID, status string) ([]*pb.ImportReview, error)
+	ResolveImportReview(ctx context.Context, doctorUUID string, req *pb.ResolveImportReviewRequest) (*pb.ImportReview, error)
+	DismissImportReview(ctx context.Context, doctorUUID, reviewUUID string) (*pb.ImportReview, error)
+
+	CreateSeries(ctx context.Context, doctorUUID string, req *pb.CreateAppointmentSeriesRequest) (*pb.AppointmentSeriesResponse, error)
+	ListSeries(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.AppointmentSeries, error)
+	CancelSeries(ctx context.Context, doctorUUID, patientUUID, seriesUUID, reason string) (*pb.AppointmentSeries, error)
+	UpdateOccurrence(ctx context.Context, doctorUUID, patientUUID string, req *pb.UpdateOccurrenceRequest) (*pb.AppointmentSeriesResponse, error)
+	CancelOccurrence(ctx context.Context, doctorUUID, patientUUID string, req *pb.CancelOccurrenceRequest) (*pb.AppointmentSeriesResponse, error)
+
+	RecordOutcome(ctx context.Context, doctorUUID, patientUUID string, req *pb.RecordOutcomeRequest) (*pb.Appointment, error)
+
+	CreateWaitlistEntry(ctx context.Context, doctorUUID string, req *pb.CreateWaitlistEntryRequest) (*pb.WaitlistEntry, error)
+	ListWaitlist(ctx context.Context, doctorUUID, status string) ([]*pb.WaitlistEntry, error)
+	RemoveWaitlistEntry(ctx context.Context, doctorUUID, entryUUID string) error
+	ListWaitlistOffers(ctx context.Context, doctorUUID, status string) ([]*pb.WaitlistOffer, error)
+	AcceptWaitlistOffer(ctx context.Context, doctorUUID, offerUUID string) (*pb.WaitlistOfferResponse, error)
+	DeclineWaitlistOffer(ctx context.Context, doctorUUID, offerUUID string) (*pb.WaitlistOffer, error)
+	ExpireWaitlistOffers(ctx context.Context) (int, error)
+
+	CreateResource(ctx context.Context, doctorUUID string, req *pb.CreateResourceRequest) (*pb.Resource, error)
+	UpdateResource(ctx context.Context, doctorUUID string, req *pb.UpdateResourceRequest) (*pb.Resource, error)
+	ListResources(ctx context.Context, doctorUUID string, includeInactive bool) ([]*pb.Resource, error)
+	ResourceSchedules(ctx context.Context, doctorUUID, date string) (*pb.ListResourceSchedulesResponse, error)
+}
+
+type service struct {
+	repo          out.Repository
+	seriesRepo    out.SeriesRepository
+	patientRepo   outboundportpatients.Repository
+	anamnesisRepo outanamneses.Repository
+	profileRepo   outdoctorprofiles.Repository
+	feedRepo      out.FeedRepository
+	importRepo    out.ImportRepository
+	waitlistRepo  out.WaitlistRepository
+	resourceRepo  out.ResourceRepository
+	offerTTL      time.Duration
+	// now decides what counts as past (free slots, series cancellation); replaceable in tests.
+	now func() time.Time
+}
+
+func NewService(
+	repo out.Repository,
+	seriesRepo out.SeriesRepository,
+	pRepo outboundportpatients.Repository,
+	aRepo outanamneses.Repository,
+	profRepo outdoctorprofiles.Repository,
+	feedRepo out.FeedRepository,
+	importRepo out.ImportRepository,
+	waitlistRepo out.WaitlistRepository,
+	resourceRepo out.ResourceRepository) Service {
+	return &service{
+		repo:          repo,
+		seriesRepo:    seriesRepo,
+		patientRepo:   pRepo,
+		anamnesisRepo: aRepo,
+		profileRepo:   profRepo,
+		feedRepo:      feedRepo,
+		importRepo:    importRepo,
+		waitlistRepo:  waitlistRepo,
+		resourceRepo:  resourceRepo,
+		offerTTL:      offerTTLFromEnv(),
+		now:           time.Now,
+	}
+}
+
+func (s *service) Create(ctx context.Context, doctorUUID string, req *pb.CreateAppointmentRequest) (*pb.AppointmentResponse, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(req.GetPatientUuid()) == "" {
+		return nil, fmt.Errorf("create appointment: %w", se.ErrInvalidRequest)
+	}
+	if req.GetStartsAt() == nil || req.GetEndsAt() == nil {
+		return nil, fmt.Errorf("create appointment: missing start or end: %w", se.ErrInvalidRequest)
+	}
+	start, end := req.GetStartsAt().AsTime().UTC(), req.GetEndsAt().AsTime().UTC()
+	if !end.After(start) {
+		return nil, fmt.Errorf("create appointment: end must be after start: %w", se.ErrInvalidRequest)
+	}
+	patientUUID := strings.TrimSpace(req.GetPatientUuid())
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("create appointment: %w", err)
+	}
+	anamnesisUUID := strings.TrimSpace(req.GetAnamnesisUuid())
+	if err := s.ensureAnamnesis(ctx, patientUUID, anamnesisUUID); err != nil {
+		return nil, fmt.Errorf("create appointment: %w", err)
+	}
+	resources, err := s.ensureResources(ctx, doctorUUID, req.GetResourceUuids())
+	if err != nil {
+		return nil, fmt.Errorf("create appointment: %w", err)
+	}
+	conflicts, err := s.guardConflicts(ctx, doctorUUID, []slot{{start, end}}, resources, nil, req.GetAllowConflicts())
+	if err != nil {
+		return nil, fmt.Errorf("create appointment: %w", err)
+	}
+
+	now := s.now().UTC()
+	a := &pb.Appointment{
+		Uuid:          uuid.NewString(),
+		DoctorUuid:    doctorUUID,
+		PatientUuid:   patientUUID,
+		AnamnesisUuid: anamnesisUUID,
+		StartsAt:      timestamppb.New(start),
+		EndsAt:        timestamppb.New(end),
+		Status:        StatusScheduled,
+		Note:          strings.TrimSpace(req.GetNote()),
+		CreatedAt:     timestamppb.New(now),
+		ResourceUuids: resources,
+	}
+	created, err := s.repo.Create(ctx, a)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("create appointment: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("create appointment: %w", err)
+	}
+	return &pb.AppointmentResponse{Appointment: created, Conflicts: conflicts}, nil
+}
+
+func (s *service) Update(ctx context.Context, doctorUUID string, req *pb.UpdateAppointmentRequest) (*pb.AppointmentResponse, error) {
+	if strings.TrimSpace(req.GetUuid()) == "" {
+		return nil, fmt.Errorf("update appointment: %w", se.ErrInvalidRequest)
+	}
+	existing, err := s.load(ctx, doctorUUID, req.GetUuid())
+	if err != nil {
+		return nil, fmt.Errorf("update appointment: %w", err)
+	}
+	if existing.GetStatus() == StatusCancelled {
+		return nil, fmt.Errorf("update appointment: appointment is cancelled: %w", se.ErrConflict)
+	}
+	if req.StartsAt != nil {
+		existing.StartsAt = timestamppb.New(req.GetStartsAt().AsTime().UTC())
+	}
+	if req.EndsAt != nil {
+		existing.EndsAt = timestamppb.New(req.GetEndsAt().AsTime().UTC())
+	}
+	if !existing.GetEndsAt().AsTime().After(existing.GetStartsAt().AsTime()) {
+		return nil, fmt.Errorf("update appointment: end must be after start: %w", se.ErrInvalidRequest)
+	}
+	if req.Resources != nil {
+		if existing.ResourceUuids, err = s.ensureResources(ctx, doctorUUID, req.GetResources().GetUuids()); err != nil {
+			return nil, fmt.Errorf("update appointment: %w", err)
+		}
+	}
+	var conflicts []*pb.ScheduleConflict
+	if req.StartsAt != nil || req.EndsAt != nil || req.Resources != nil {
+		moved := slot{existing.GetStartsAt().AsTime(), existing.GetEndsAt().AsTime()}
+		self := func(a *pb.Appointment) bool { return a.GetUuid() == existing.GetUuid() }
+		if conflicts, err = s.guardConflicts(ctx, doctorUUID, []slot{moved}, existing.GetResourceUuids(), self, req.GetAllowConflicts()); err != nil {
+			return nil, fmt.Errorf("update appointment: %w", err)
+		}
+	}
+	if req.AnamnesisUuid != nil {
+		anamnesisUUID := strings.TrimSpace(req.GetAnamnesisUuid().GetValue())
+		if err := s.ensureAnamnesis(ctx, existing.GetPatientUuid(), anamnesisUUID); err != nil {
+			return nil, fmt.Errorf("update appointment: %w", err)
+		}
+		existing.AnamnesisUuid = anamnesisUUID
+	}
+	if req.Note != nil {
+		existing.Note = strings.TrimSpace(req.GetNote().GetValue())
+	}
+	existing.UpdatedAt = timestamppb.New(s.now().UTC())
+
+	updated, err := s.repo.Update(ctx, existing)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("update appointment: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("update appointment: %w", err)
+	}
+	return &pb.AppointmentResponse{Appointment: updated, Conflicts: conflicts}, nil
+}
+
+func (s *service) Cancel(ctx context.Context, doctorUUID string, req *pb.CancelAppointmentRequest) (*pb.Appointment, error) {
+	if strings.TrimSpace(req.GetUuid()) == "" {
+		return nil, fmt.Errorf("cancel appointment: %w", se.ErrInvalidRequest)
+	}
+	existing, err := s.load(ctx, doctorUUID, req.GetUuid())
+	if err != nil {
+		return nil, fmt.Errorf("cancel appointment: %w", err)
+	}
+	if existing.GetStatus() == StatusCancelled {
+		return existing, nil
+	}
+	at := s.now().UTC()
+	outcome, err := cancelOutcome(req.GetCancelledBy(), existing.GetStartsAt().AsTime(), at)
+	if err != nil {
+		return nil, fmt.Errorf("cancel appointment: %w", err)
+	}
+	now := timestamppb.New(at)
+	existing.Status = StatusCancelled
+	existing.CancelReason = strings.TrimSpace(req.GetReason())
+	existing.CancelledAt = now
+	existing.UpdatedAt = now
+	existing.Outcome = outcome
+	existing.OutcomeAt = now
+	existing.OutcomeReason = existing.GetCancelReason()
+
+	updated, err := s.repo.Update(ctx, existing)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("cancel appointment: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("cancel appointment: %w", err)
+	}
+	s.slotFreed(ctx, updated)
+	return updated, nil
+}
+
+func (s *service) List(ctx context.Context, doctorUUID string, req *pb.ListAppointmentsRequest) ([]*pb.Appointment, error) {
+	if strings.TrimSpace(doctorUUID) == "" || req.GetFrom() == nil || req.GetTo() == nil {
+		return nil, fmt.Errorf("list appointments: %w", se.ErrInvalidRequest)
+	}
+	from, to := req.GetFrom().AsTime().UTC(), req.GetTo().AsTime().UTC()
+	if !to.After(from) || to.Sub(from) > maxListRange {
+		return nil, fmt.Errorf("list appointments: invalid range: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.appointmentsInRange(ctx, doctorUUID, strings.TrimSpace(req.GetPatientUuid()), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("list appointments: %w", err)
+	}
+	return list, nil
+}
+
+// appointmentsInRange merges stored appointments with expanded series occurrences, oldest first.
+func (s *service) appointmentsInRange(ctx context.Context, doctorUUID, patientUUID string, from, to time.Time) ([]*pb.Appointment, error) {
+	list, err := s.repo.ListByRange(ctx, doctorUUID, from, to, patientUUID)
+	if err != nil {
+		return nil, err
+	}
+	occurrences, err := s.expandSeries(ctx, doctorUUID, patientUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	list = append(list, occurrences...)
+	sortByStart(list)
+	return list, nil
+}
+
+// load fetches an appointment and makes sure it belongs to the doctor.
+func (s *service) load(ctx context.Context, doctorUUID, appointmentUUID string) (*pb.Appointment, error) {
+	a, err := s.repo.Get(ctx, appointmentUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, se.ErrNotFound
+		}
+		return nil, fmt.Errorf("load appointment: %w", err)
+	}
+	if strings.TrimSpace(a.GetDoctorUuid()) != strings.TrimSpace(doctorUUID) {
+		return nil, se.ErrNotFound
+	}
+	return a, nil
+}
+
+// ensureAnamnesis validates an optional visit-note link; empty means no link.
+func (s *service) ensureAnamnesis(ctx context.Context, patientUUID, anamnesisUUID string) error {
+	if anamnesisUUID == "" {
+		return nil
+	}
+	a, err := s.anamnesisRepo.Get(ctx, anamnesisUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return se.ErrNotFound
+		}
+		return fmt.Errorf("load anamnesis: %w", err)
+	}
+	if strings.TrimSpace(a.GetPatientUuid()) != patientUUID {
+		return fmt.Errorf("anamnesis belongs to another patient: %w", se.ErrInvalidRequest)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package appointments
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// The subscribable feed covers recent history and the coming year.
+const (
+	feedWindowPast   = 30 * 24 * time.Hour
+	feedWindowFuture = 366 * 24 * time.Hour
+)
+
+const feedPathPrefix = "/api/calendar/"
+
+// ExportICS renders the doctor's appointments in [from, to) as iCalendar; zero times use the feed window.
+func (s *service) ExportICS(ctx context.Context, doctorUUID string, from, to time.Time) ([]byte, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("export calendar: %w", se.ErrInvalidRequest)
+	}
+	now := s.now().UTC()
+	if from.IsZero() {
+		from = now.Add(-feedWindowPast)
+	}
+	if to.IsZero() {
+		to = now.Add(feedWindowFuture)
+	}
+	if !to.After(from) || to.Sub(from) > maxListRange+feedWindowPast {
+		return nil, fmt.Errorf("export calendar: invalid range: %w", se.ErrInvalidRequest)
+	}
+	data, err := s.calendarICS(ctx, doctorUUID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("export calendar: %w", err)
+	}
+	return data, nil
+}
+
+// FeedICS resolves a feed token and renders the doctor's calendar window; unknown tokens are not found.
+func (s *service) FeedICS(ctx context.Context, token string) ([]byte, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("calendar feed: %w", se.ErrNotFound)
+	}
+	doctorUUID, err := s.feedRepo.GetByTokenHash(ctx, hashFeedToken(token))
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("calendar feed: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("calendar feed: %w", err)
+	}
+	now := s.now().UTC()
+	data, err := s.calendarICS(ctx, doctorUUID, now.Add(-feedWindowPast), now.Add(feedWindowFuture))
+	if err != nil {
+		return nil, fmt.Errorf("calendar feed: %w", err)
+	}
+	return data, nil
+}
+
+// GetCalendarFeed reports whether the doctor has a feed; the URL itself is only shown on rotation.
+func (s *service) GetCalendarFeed(ctx context.Context, doctorUUID string) (*pb.CalendarFeed, error) {
+	feed, err := s.feedRepo.Get(ctx, doctorUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("get calendar feed: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get calendar feed: %w", err)
+	}
+	return feed, nil
+}
+
+// RotateCalendarFeed creates the feed token or replaces it, which invalidates the previous URL. The
+// token is returned only here; the database keeps its SHA-256.
+func (s *service) RotateCalendarFeed(ctx context.Context, doctorUUID string) (*pb.CalendarFeed, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("rotate calendar feed: %w", se.ErrInvalidRequest)
+	}
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, fmt.Errorf("rotate calendar feed: %w", err)
+	}
+	now := s.now().UTC()
+	if err := s.feedRepo.Save(ctx, doctorUUID, hashFeedToken(token), now); err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("rotate calendar feed: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("rotate calendar feed: %w", err)
+	}
+	return &pb.CalendarFeed{
+		Token:     token,
+		Path:      feedPathPrefix + token + ".ics",
+		CreatedAt: timestamppb.New(now),
+	}, nil
+}
+
+func (s *service) RevokeCalendarFeed(ctx context.Context, doctorUUID string) error {
+	if err := s.feedRepo.Delete(ctx, doctorUUID); err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("revoke calendar feed: %w", se.ErrNotFound)
+		}
+		return fmt.Errorf("revoke calendar feed: %w", err)
+	}
+	return nil
+}
+
+func (s *service) calendarICS(ctx context.Context, doctorUUID string, from, to time.Time) ([]byte, error) {
+	list, err := s.appointmentsInRange(ctx, doctorUUID, "", from, to)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	events := make([]icsEvent, 0, len(list))
+	for _, a := range list {
+		summary, ok := names[a.GetPatientUuid()]
+		if !ok {
+			p, err := s.patientRepo.Get(ctx, a.GetPatientUuid())
+			if err != nil && !errors.Is(err, re.ErrNotFound) && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("load patient: %w", err)
+			}
+			summary = initials(p.GetFirstName(), p.GetLastName())
+			names[a.GetPatientUuid()] = summary
+		}
+		ev := icsEvent{
+			UID:       eventUID(a),
+			Start:     a.GetStartsAt().AsTime(),
+			End:       a.GetEndsAt().AsTime(),
+			Summary:   summary,
+			Cancelled: a.GetStatus() == StatusCancelled,
+		}
+		if a.GetUpdatedAt() != nil {
+			ev.Modified = a.GetUpdatedAt().AsTime()
+		}
+		events = append(events, ev)
+	}
+	return writeICS("Physio", events, s.now()), nil
+}
+
+// eventUID keeps series occurrences stable whether or not they have been stored as an exception.
+func eventUID(a *pb.Appointment) string {
+	if a.GetSeriesUuid() != "" {
+		return fmt.Sprintf("%s-%d@physio-tracker", a.GetSeriesUuid(), a.GetOriginalStartsAt().AsTime().Unix())
+	}
+	return a.GetUuid() + "@physio-tracker"
+}
+
+func newFeedToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashFeedToken is the stored form of a feed token; lookups compare hashes, never the token itself.
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,262 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
+	"gorm.io/gorm"
+)
+
+const (
+	ConflictOverlap             = "overlap"
+	ConflictOutsideWorkingHours = "outside_working_hours"
+	ConflictDayOff              = "day_off"
+	ConflictResourceBusy        = "resource_busy"
+)
+
+// seriesConflictHorizon limits how far ahead an open-ended series is checked for conflicts.
+const seriesConflictHorizon = 366 * 24 * time.Hour
+
+// ConflictError is returned when a booking clashes with the calendar and conflicts were not allowed.
+// It unwraps to se.ErrConflict so generic error handling still maps it to 409.
+type ConflictError struct {
+	Conflicts []*pb.ScheduleConflict
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%d scheduling conflict(s)", len(e.Conflicts))
+}
+
+func (e *ConflictError) Unwrap() error { return se.ErrConflict }
+
+type slot struct {
+	start, end time.Time
+}
+
+// guardConflicts checks slots reserving resources against the calendar. Conflicts are returned
+// for the response when allow is set, otherwise they are turned into a *ConflictError. A busy
+// resource is never allowed: the doctor may see two patients at once, a laser cannot.
+func (s *service) guardConflicts(ctx context.Context, doctorUUID string, slots []slot, resources []string, ignore func(*pb.Appointment) bool, allow bool) ([]*pb.ScheduleConflict, error) {
+	conflicts, err := s.checkConflicts(ctx, doctorUUID, slots, resources, ignore)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 && (!allow || hasResourceConflict(conflicts)) {
+		return nil, &ConflictError{Conflicts: conflicts}
+	}
+	return conflicts, nil
+}
+
+func hasResourceConflict(conflicts []*pb.ScheduleConflict) bool {
+	for _, c := range conflicts {
+		if c.GetReason() == ConflictResourceBusy {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConflicts reports overlaps with other scheduled appointments (including series occurrences),
+// overlapping appointments that reserve one of resources, days off and times outside the doctor's
+// working hours. ignore skips appointments that the change replaces, e.g. the appointment being moved.
+func (s *service) checkConflicts(ctx context.Context, doctorUUID string, slots []slot, resources []string, ignore func(*pb.Appointment) bool) ([]*pb.ScheduleConflict, error) {
+	if len(slots) == 0 {
+		return nil, nil
+	}
+	from, to := slots[0].start, slots[0].end
+	for _, sl := range slots[1:] {
+		if sl.start.Before(from) {
+			from = sl.start
+		}
+		if sl.end.After(to) {
+			to = sl.end
+		}
+	}
+	existing, err := s.appointmentsInRange(ctx, doctorUUID, "", from, to)
+	if err != nil {
+		return nil, fmt.Errorf("load calendar: %w", err)
+	}
+	sched, err := s.profileRepo.GetSchedule(ctx, doctorUUID)
+	if err != nil {
+		if !errors.Is(err, re.ErrNotFound) && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("load working schedule: %w", err)
+		}
+		sched = nil
+	}
+	loc, err := svcdoctorprofiles.ScheduleLocation(sched)
+	if err != nil {
+		return nil, fmt.Errorf("schedule timezone: %w", err)
+	}
+
+	var conflicts []*pb.ScheduleConflict
+	for _, sl := range slots {
+		for _, a := range existing {
+			if a.GetStatus() == StatusCancelled || (ignore != nil && ignore(a)) {
+				continue
+			}
+			if a.GetStartsAt().AsTime().Before(sl.end) && sl.start.Before(a.GetEndsAt().AsTime()) {
+				conflicts = append(conflicts, &pb.ScheduleConflict{
+					Reason:      ConflictOverlap,
+					Appointment: a,
+					Message: fmt.Sprintf("%s overlaps with an appointment at %s",
+						formatLocal(sl.start, loc), formatLocal(a.GetStartsAt().AsTime(), loc)),
+				})
+				for _, res := range sharedResources(resources, a.GetResourceUuids()) {
+					conflicts = append(conflicts, &pb.ScheduleConflict{
+						Reason:       ConflictResourceBusy,
+						Appointment:  a,
+						ResourceUuid: res,
+						Message: fmt.Sprintf("resource is already reserved at %s by an appointment at %s",
+							formatLocal(sl.start, loc), formatLocal(a.GetStartsAt().AsTime(), loc)),
+					})
+				}
+			}
+		}
+		if sched == nil {
+			continue
+		}
+		if c := dayOffConflict(sched, sl, loc); c != nil {
+			conflicts = append(conflicts, c)
+			continue
+		}
+		if c := workingHoursConflict(sched, sl, loc); c != nil {
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts, nil
+}
+
+func dayOffConflict(sched *pb.WorkingSchedule, sl slot, loc *time.Location) *pb.ScheduleConflict {
+	for _, t := range []time.Time{sl.start, sl.end.Add(-time.Nanosecond)} {
+		lt := t.In(loc)
+		reason, off := dayOff(sched, lt.Year(), lt.Month(), lt.Day())
+		if !off {
+			continue
+		}
+		msg := fmt.Sprintf("%s is a day off", lt.Format("2006-01-02"))
+		if reason != "" {
+			msg += " (" + reason + ")"
+		}
+		return &pb.ScheduleConflict{Reason: ConflictDayOff, Message: msg}
+	}
+	return nil
+}
+
+// workingHoursConflict requires the slot to fit inside one working interval (breaks excluded)
+// of its local day. A schedule without any hours disables the check.
+func workingHoursConflict(sched *pb.WorkingSchedule, sl slot, loc *time.Location) *pb.ScheduleConflict {
+	if len(sched.GetHours()) == 0 {
+		return nil
+	}
+	ls := sl.start.In(loc)
+	for _, iv := range workingIntervals(sched, loc, ls.Year(), ls.Month(), ls.Day()) {
+		if !sl.start.Before(iv.start) && !sl.end.After(iv.end) {
+			return nil
+		}
+	}
+	return &pb.ScheduleConflict{
+		Reason:  ConflictOutsideWorkingHours,
+		Message: fmt.Sprintf("%s-%s is outside working hours", formatLocal(sl.start, loc), sl.end.In(loc).Format("15:04")),
+	}
+}
+
+// dayOff reports whether the civil date is a configured day off or, when observed, a public holiday.
+func dayOff(sched *pb.WorkingSchedule, year int, month time.Month, day int) (string, bool) {
+	date := fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+	for _, d := range sched.GetDaysOff() {
+		if d.GetDate() == date {
+			return d.GetReason(), true
+		}
+	}
+	if sched.GetObservePublicHolidays() {
+		return publicHoliday(year, month, day)
+	}
+	return "", false
+}
+
+// workingIntervals returns the working time of a civil date with breaks cut out, as absolute times.
+// Intervals are built from wall-clock times in loc, so they follow DST changes.
+func workingIntervals(sched *pb.WorkingSchedule, loc *time.Location, year int, month time.Month, day int) []slot {
+	weekday := int32(weekdayIndex(time.Date(year, month, day, 12, 0, 0, 0, loc).Weekday()) + 1)
+	clock := func(h *pb.WorkingHours) (slot, bool) {
+		start, err := svcdoctorprofiles.ParseClock(h.GetStart())
+		if err != nil {
+			return slot{}, false
+		}
+		end, err := svcdoctorprofiles.ParseClock(h.GetEnd())
+		if err != nil {
+			return slot{}, false
+		}
+		return slot{
+			start: time.Date(year, month, day, start/60, start%60, 0, 0, loc).UTC(),
+			end:   time.Date(year, month, day, end/60, end%60, 0, 0, loc).UTC(),
+		}, true
+	}
+	var hours, breaks []slot
+	for _, h := range sched.GetHours() {
+		if iv, ok := clock(h); ok && h.GetWeekday() == weekday && iv.end.After(iv.start) {
+			hours = append(hours, iv)
+		}
+	}
+	for _, b := range sched.GetBreaks() {
+		if iv, ok := clock(b); ok && b.GetWeekday() == weekday {
+			breaks = append(breaks, iv)
+		}
+	}
+	return subtractSlots(hours, breaks)
+}
+
+// subtractSlots removes every cut interval from base; base must be sorted and non-overlapping.
+func subtractSlots(base, cuts []slot) []slot {
+	res := base
+	for _, c := range cuts {
+		var next []slot
+		for _, b := range res {
+			if !c.start.Before(b.end) || !b.start.Before(c.end) {
+				next = append(next, b)
+				continue
+			}
+			if b.start.Before(c.start) {
+				next = append(next, slot{start: b.start, end: c.start})
+			}
+			if c.end.Before(b.end) {
+				next = append(next, slot{start: c.end, end: b.end})
+			}
+		}
+		res = next
+	}
+	return res
+}
+
+func formatLocal(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("2006-01-02 15:04")
+}
+
+// seriesSlots returns the occurrences of a series starting within the conflict horizon.
+func seriesSlots(rule Rule, dtstart time.Time, loc *time.Location, duration time.Duration) []slot {
+	occs := rule.Occurrences(dtstart, loc, dtstart, dtstart.Add(seriesConflictHorizon))
+	slots := make([]slot, 0, len(occs))
+	for _, occ := range occs {
+		slots = append(slots, slot{start: occ, end: occ.Add(duration)})
+	}
+	return slots
+}
+
+func sharedResources(a, b []string) []string {
+	var shared []string
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				shared = append(shared, x)
+				break
+			}
+		}
+	}
+	return shared
+}
@@ -0,0 +1,119 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// maxFreeSlotsRange keeps the slot list small enough to render; the UI pages week by week.
+const maxFreeSlotsRange = 31 * 24 * time.Hour
+
+// slotAlignment rounds slot starts that would otherwise land on odd minutes (e.g. "now").
+const slotAlignment = 5 * time.Minute
+
+func (s *service) FreeSlots(ctx context.Context, doctorUUID string, req *pb.ListFreeSlotsRequest) (*pb.ListFreeSlotsResponse, error) {
+	if strings.TrimSpace(doctorUUID) == "" || req.GetFrom() == nil || req.GetTo() == nil {
+		return nil, fmt.Errorf("free slots: %w", se.ErrInvalidRequest)
+	}
+	from, to := req.GetFrom().AsTime().UTC(), req.GetTo().AsTime().UTC()
+	if !to.After(from) || to.Sub(from) > maxFreeSlotsRange {
+		return nil, fmt.Errorf("free slots: invalid range: %w", se.ErrInvalidRequest)
+	}
+	if req.GetDurationMinutes() <= 0 || req.GetDurationMinutes() > 24*60 || req.GetStepMinutes() < 0 {
+		return nil, fmt.Errorf("free slots: invalid duration: %w", se.ErrInvalidRequest)
+	}
+	duration := time.Duration(req.GetDurationMinutes()) * time.Minute
+	step := time.Duration(req.GetStepMinutes()) * time.Minute
+
+	sched, err := s.profileRepo.GetSchedule(ctx, doctorUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("free slots: working hours are not configured: %w", se.ErrInvalidRequest)
+		}
+		return nil, fmt.Errorf("free slots: %w", err)
+	}
+	if len(sched.GetHours()) == 0 {
+		return nil, fmt.Errorf("free slots: working hours are not configured: %w", se.ErrInvalidRequest)
+	}
+	loc, err := svcdoctorprofiles.ScheduleLocation(sched)
+	if err != nil {
+		return nil, fmt.Errorf("free slots: schedule timezone: %w", err)
+	}
+
+	// Past times are never offered.
+	if now := s.now().UTC(); from.Before(now) {
+		from = now
+	}
+	if !to.After(from) {
+		return &pb.ListFreeSlotsResponse{Timezone: loc.String()}, nil
+	}
+	existing, err := s.appointmentsInRange(ctx, doctorUUID, "", from, to)
+	if err != nil {
+		return nil, fmt.Errorf("free slots: %w", err)
+	}
+	busy := make([]slot, 0, len(existing))
+	for _, a := range existing {
+		if a.GetStatus() == StatusCancelled {
+			continue
+		}
+		busy = append(busy, slot{start: a.GetStartsAt().AsTime(), end: a.GetEndsAt().AsTime()})
+	}
+
+	res := &pb.ListFreeSlotsResponse{Timezone: loc.String()}
+	for _, sl := range freeSlots(sched, loc, busy, from, to, duration, step) {
+		res.Slots = append(res.Slots, &pb.FreeSlot{
+			StartsAt: timestamppb.New(sl.start),
+			EndsAt:   timestamppb.New(sl.end),
+		})
+	}
+	return res, nil
+}
+
+// freeSlots lists bookable slots of the given duration in [from, to). For every local day it takes
+// the working intervals (breaks removed, days off and holidays skipped), removes busy times and
+// places slots step apart (default: duration) from the start of each free window.
+func freeSlots(sched *pb.WorkingSchedule, loc *time.Location, busy []slot, from, to time.Time, duration, step time.Duration) []slot {
+	if duration <= 0 {
+		return nil
+	}
+	if step <= 0 {
+		step = duration
+	}
+	var res []slot
+	lf, lt := from.In(loc), to.In(loc)
+	day := time.Date(lf.Year(), lf.Month(), lf.Day(), 0, 0, 0, 0, time.UTC)
+	last := time.Date(lt.Year(), lt.Month(), lt.Day(), 0, 0, 0, 0, time.UTC)
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if _, off := dayOff(sched, day.Year(), day.Month(), day.Day()); off {
+			continue
+		}
+		windows := subtractSlots(workingIntervals(sched, loc, day.Year(), day.Month(), day.Day()), busy)
+		for _, w := range windows {
+			start := w.start
+			if start.Before(from) {
+				start = from
+			}
+			end := w.end
+			if end.After(to) {
+				end = to
+			}
+			if rem := start.Sub(w.start) % slotAlignment; rem != 0 {
+				start = start.Add(slotAlignment - rem)
+			}
+			for t := start; !t.Add(duration).After(end); t = t.Add(step) {
+				res = append(res, slot{start: t, end: t.Add(duration)})
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,152 @@
+package appointments
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+func TestFreeSlots(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Zagreb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, loc)
+	}
+	sched := &pb.WorkingSchedule{
+		Timezone: "Europe/Zagreb",
+		Hours: []*pb.WorkingHours{
+			{Weekday: 1, Start: "08:00", End: "12:00"},
+			{Weekday: 4, Start: "08:00", End: "10:00"},
+			{Weekday: 6, Start: "08:00", End: "10:00"},
+			{Weekday: 7, Start: "08:00", End: "10:00"},
+		},
+		Breaks:                []*pb.WorkingHours{{Weekday: 1, Start: "10:00", End: "10:30"}},
+		DaysOff:               []*pb.DayOff{{Date: "2026-03-28", Reason: "seminar"}},
+		ObservePublicHolidays: true,
+	}
+
+	tests := []struct {
+		name     string
+		busy     []slot
+		from, to time.Time
+		duration time.Duration
+		step     time.Duration
+		want     []string // slot starts as "2006-01-02 15:04 MST" in Europe/Zagreb
+	}{
+		{
+			name:     "spring forward weekend with day off, busy block and break",
+			busy:     []slot{{start: at(2026, 3, 30, 9, 0), end: at(2026, 3, 30, 9, 45)}},
+			from:     at(2026, 3, 28, 0, 0),
+			to:       at(2026, 3, 31, 0, 0),
+			duration: 45 * time.Minute,
+			want: []string{
+				"2026-03-29 08:00 CEST", "2026-03-29 08:45 CEST",
+				"2026-03-30 08:00 CEST", "2026-03-30 10:30 CEST", "2026-03-30 11:15 CEST",
+			},
+		},
+		{
+			name:     "fall back weekend keeps wall-clock hours",
+			from:     at(2026, 10, 24, 0, 0),
+			to:       at(2026, 10, 26, 0, 0),
+			duration: 45 * time.Minute,
+			want: []string{
+				"2026-10-24 08:00 CEST", "2026-10-24 08:45 CEST",
+				"2026-10-25 08:00 CET", "2026-10-25 08:45 CET",
+			},
+		},
+		{
+			name:     "easter sunday and easter monday are holidays",
+			from:     at(2026, 4, 4, 0, 0),
+			to:       at(2026, 4, 7, 0, 0),
+			duration: 60 * time.Minute,
+			want:     []string{"2026-04-04 08:00 CEST", "2026-04-04 09:00 CEST"},
+		},
+		{
+			name:     "corpus christi is a holiday",
+			from:     at(2026, 6, 4, 0, 0),
+			to:       at(2026, 6, 7, 0, 0),
+			duration: 60 * time.Minute,
+			want:     []string{"2026-06-06 08:00 CEST", "2026-06-06 09:00 CEST"},
+		},
+		{
+			name:     "start inside a window is aligned and step is honoured",
+			from:     at(2026, 3, 30, 10, 37),
+			to:       at(2026, 3, 30, 12, 0),
+			duration: 45 * time.Minute,
+			step:     15 * time.Minute,
+			want:     []string{"2026-03-30 10:40 CEST", "2026-03-30 10:55 CEST", "2026-03-30 11:10 CEST"},
+		},
+		{
+			name:     "busy time covering the whole day leaves nothing",
+			busy:     []slot{{start: at(2026, 10, 25, 7, 0), end: at(2026, 10, 25, 11, 0)}},
+			from:     at(2026, 10, 25, 0, 0),
+			to:       at(2026, 10, 26, 0, 0),
+			duration: 30 * time.Minute,
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, sl := range freeSlots(sched, loc, tt.busy, tt.from.UTC(), tt.to.UTC(), tt.duration, tt.step) {
+				if sl.end.Sub(sl.start) != tt.duration {
+					t.Errorf("slot %s lasts %s, want %s", sl.start.In(loc), sl.end.Sub(sl.start), tt.duration)
+				}
+				got = append(got, sl.start.In(loc).Format("2006-01-02 15:04 MST"))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("freeSlots() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEasterSunday(t *testing.T) {
+	tests := []struct {
+		year int
+		want string
+	}{
+		{2000, "2000-04-23"},
+		{2024, "2024-03-31"},
+		{2025, "2025-04-20"},
+		{2026, "2026-04-05"},
+		{2027, "2027-03-28"},
+	}
+	for _, tt := range tests {
+		if got := easterSunday(tt.year).Format("2006-01-02"); got != tt.want {
+			t.Errorf("easterSunday(%d) = %s, want %s", tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestPublicHoliday(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2026-01-01", "Nova godina"},
+		{"2026-04-05", "Uskrs"},
+		{"2026-04-06", "Uskrsni ponedjeljak"},
+		{"2025-06-19", "Tijelovo"},
+		{"2026-06-04", "Tijelovo"},
+		{"2026-05-30", "Dan državnosti"},
+		{"2026-11-18", "Dan sjećanja na žrtve Domovinskog rata"},
+		{"2026-04-07", ""},
+		{"2026-12-24", ""},
+	}
+	for _, tt := range tests {
+		d, err := time.Parse("2006-01-02", tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := publicHoliday(d.Year(), d.Month(), d.Day())
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("publicHoliday(%s) = %q, %v, want %q", tt.date, got, ok, tt.want)
+		}
+	}
+}
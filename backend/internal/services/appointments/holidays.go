@@ -0,0 +1,45 @@
+package appointments
+
+import "time"
+
+// publicHoliday reports whether the civil date is a public holiday in Croatia (law in force since 2020).
+func publicHoliday(year int, month time.Month, day int) (string, bool) {
+	fixed := map[time.Month]map[int]string{
+		time.January:  {1: "Nova godina", 6: "Bogojavljenje ili Sveta tri kralja"},
+		time.May:      {1: "Praznik rada", 30: "Dan državnosti"},
+		time.June:     {22: "Dan antifašističke borbe"},
+		time.August:   {5: "Dan pobjede i domovinske zahvalnosti", 15: "Velika Gospa"},
+		time.November: {1: "Svi sveti", 18: "Dan sjećanja na žrtve Domovinskog rata"},
+		time.December: {25: "Božić", 26: "Sveti Stjepan"},
+	}
+	if name, ok := fixed[month][day]; ok {
+		return name, true
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	easter := easterSunday(year)
+	switch int(date.Sub(easter).Hours() / 24) {
+	case 0:
+		return "Uskrs", true
+	case 1:
+		return "Uskrsni ponedjeljak", true
+	case 60:
+		return "Tijelovo", true
+	}
+	return "", false
+}
+
+// easterSunday computes the Gregorian Easter date (anonymous Gregorian algorithm).
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,93 @@
+package appointments
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// icsEvent is one VEVENT of an exported calendar.
+type icsEvent struct {
+	UID       string
+	Start     time.Time
+	End       time.Time
+	Summary   string
+	Cancelled bool
+	Modified  time.Time
+}
+
+// writeICS renders a VCALENDAR (RFC 5545) with CRLF line endings and folded lines.
+func writeICS(name string, events []icsEvent, stamp time.Time) []byte {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICSLine(s))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//physio-tracker//appointments//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICSText(name))
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + escapeICSText(e.UID))
+		line("DTSTAMP:" + stamp.UTC().Format(icsTimeFormat))
+		line("DTSTART:" + e.Start.UTC().Format(icsTimeFormat))
+		line("DTEND:" + e.End.UTC().Format(icsTimeFormat))
+		line("SUMMARY:" + escapeICSText(e.Summary))
+		if e.Cancelled {
+			line("STATUS:CANCELLED")
+		} else {
+			line("STATUS:CONFIRMED")
+		}
+		if !e.Modified.IsZero() {
+			line("LAST-MODIFIED:" + e.Modified.UTC().Format(icsTimeFormat))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// escapeICSText escapes a TEXT value (backslash, semicolon, comma and newlines).
+func escapeICSText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	return r.Replace(s)
+}
+
+// foldICSLine splits content lines longer than 75 octets without breaking UTF-8 sequences.
+func foldICSLine(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	width := limit
+	for len(s) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		width = limit - 1 // continuation lines start with a space
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// initials turns "Ana Marić" into "A.M." so exported calendars carry no full names.
+func initials(firstName, lastName string) string {
+	var b strings.Builder
+	for _, part := range []string{firstName, lastName} {
+		if r, _ := utf8.DecodeRuneInString(strings.TrimSpace(part)); r != utf8.RuneError {
+			b.WriteString(strings.ToUpper(string(r)))
+			b.WriteString(".")
+		}
+	}
+	return b.String()
+}
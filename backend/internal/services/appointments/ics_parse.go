@@ -0,0 +1,255 @@
+package appointments
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// importedEvent is a VEVENT as read from an uploaded calendar. Times are absolute; Loc is the zone
+// the event was written in and drives RRULE expansion.
+type importedEvent struct {
+	UID          string
+	Summary      string
+	Status       string
+	Start, End   time.Time
+	Loc          *time.Location
+	AllDay       bool
+	RRule        string
+	ExDates      []time.Time
+	RecurrenceID time.Time // set on overrides of a single occurrence
+}
+
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// parseICS reads the VEVENTs of an iCalendar file. Floating times and unknown TZIDs (e.g. Windows
+// zone names) are read in defLoc. Events that cannot be read are reported as warnings.
+func parseICS(data []byte, defLoc *time.Location) ([]importedEvent, []string, error) {
+	lines := unfoldICS(data)
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "BEGIN:VCALENDAR") {
+		return nil, nil, fmt.Errorf("not an iCalendar file")
+	}
+	var (
+		events   []importedEvent
+		warnings []string
+		props    []icsProperty
+		inEvent  bool
+		nested   int
+	)
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		p, ok := parseICSProperty(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case p.Name == "BEGIN" && strings.EqualFold(p.Value, "VEVENT") && !inEvent:
+			inEvent, props, nested = true, nil, 0
+		case p.Name == "BEGIN" && inEvent:
+			// VALARM and other sub-components do not describe the appointment.
+			nested++
+		case p.Name == "END" && inEvent && nested > 0:
+			nested--
+		case p.Name == "END" && strings.EqualFold(p.Value, "VEVENT") && inEvent:
+			inEvent = false
+			ev, err := buildImportedEvent(props, defLoc)
+			if err != nil {
+				warnings = append(warnings, err.Error())
+				continue
+			}
+			events = append(events, ev)
+		case inEvent && nested == 0:
+			props = append(props, p)
+		}
+	}
+	return events, warnings, nil
+}
+
+func buildImportedEvent(props []icsProperty, defLoc *time.Location) (importedEvent, error) {
+	ev := importedEvent{Loc: defLoc}
+	var (
+		hasEnd   bool
+		duration time.Duration
+		err      error
+	)
+	for _, p := range props {
+		switch p.Name {
+		case "UID":
+			ev.UID = strings.TrimSpace(p.Value)
+		case "SUMMARY":
+			ev.Summary = strings.TrimSpace(unescapeICSText(p.Value))
+		case "STATUS":
+			ev.Status = strings.ToUpper(strings.TrimSpace(p.Value))
+		case "DTSTART":
+			var loc *time.Location
+			ev.Start, ev.AllDay, loc, err = parseICSTime(p, defLoc)
+			if err != nil {
+				return ev, fmt.Errorf("event %q: DTSTART: %v", ev.UID, err)
+			}
+			ev.Loc = loc
+		case "DTEND":
+			if ev.End, _, _, err = parseICSTime(p, defLoc); err != nil {
+				return ev, fmt.Errorf("event %q: DTEND: %v", ev.UID, err)
+			}
+			hasEnd = true
+		case "DURATION":
+			if duration, err = parseICSDuration(p.Value); err != nil {
+				return ev, fmt.Errorf("event %q: %v", ev.UID, err)
+			}
+		case "RRULE":
+			ev.RRule = strings.TrimSpace(p.Value)
+		case "EXDATE":
+			for _, v := range strings.Split(p.Value, ",") {
+				t, _, _, err := parseICSTime(icsProperty{Params: p.Params, Value: v}, defLoc)
+				if err != nil {
+					return ev, fmt.Errorf("event %q: EXDATE: %v", ev.UID, err)
+				}
+				ev.ExDates = append(ev.ExDates, t)
+			}
+		case "RECURRENCE-ID":
+			if ev.RecurrenceID, _, _, err = parseICSTime(p, defLoc); err != nil {
+				return ev, fmt.Errorf("event %q: RECURRENCE-ID: %v", ev.UID, err)
+			}
+		}
+	}
+	if ev.UID == "" {
+		return ev, fmt.Errorf("event %q without UID skipped", ev.Summary)
+	}
+	if ev.Start.IsZero() {
+		return ev, fmt.Errorf("event %q without DTSTART skipped", ev.UID)
+	}
+	if !hasEnd {
+		ev.End = ev.Start.Add(duration)
+	}
+	return ev, nil
+}
+
+// unfoldICS splits the content into logical lines, joining continuation lines (RFC 5545 3.1).
+func unfoldICS(data []byte) []string {
+	var lines []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseICSProperty splits "NAME;PARAM=a;PARAM2=\"b:c\":value"; colons inside quoted parameters are kept.
+func parseICSProperty(line string) (icsProperty, bool) {
+	p := icsProperty{Params: map[string]string{}}
+	inQuotes := false
+	var segments []string
+	start := 0
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ';' && !inQuotes:
+			segments = append(segments, line[start:i])
+			start = i + 1
+		case r == ':' && !inQuotes:
+			segments = append(segments, line[start:i])
+			p.Name = strings.ToUpper(strings.TrimSpace(segments[0]))
+			for _, seg := range segments[1:] {
+				kv := strings.SplitN(seg, "=", 2)
+				if len(kv) == 2 {
+					p.Params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+				}
+			}
+			p.Value = line[i+1:]
+			return p, p.Name != ""
+		}
+	}
+	return p, false
+}
+
+// parseICSTime reads DATE-TIME (UTC, TZID or floating) and DATE values.
+func parseICSTime(p icsProperty, defLoc *time.Location) (time.Time, bool, *time.Location, error) {
+	val := strings.TrimSpace(p.Value)
+	loc := defLoc
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	if strings.EqualFold(p.Params["VALUE"], "DATE") || len(val) == 8 {
+		t, err := time.ParseInLocation("20060102", val, loc)
+		if err != nil {
+			return time.Time{}, false, nil, fmt.Errorf("invalid date %q", val)
+		}
+		return t.UTC(), true, loc, nil
+	}
+	if strings.HasSuffix(val, "Z") {
+		t, err := time.Parse("20060102T150405Z", val)
+		if err != nil {
+			return time.Time{}, false, nil, fmt.Errorf("invalid time %q", val)
+		}
+		return t.UTC(), false, loc, nil
+	}
+	t, err := time.ParseInLocation("20060102T150405", val, loc)
+	if err != nil {
+		return time.Time{}, false, nil, fmt.Errorf("invalid time %q", val)
+	}
+	return t.UTC(), false, loc, nil
+}
+
+// parseICSDuration reads positive durations such as "PT45M", "PT1H30M" or "P1D". Weeks and days
+// come before the "T", hours, minutes and seconds after it.
+func parseICSDuration(val string) (time.Duration, error) {
+	s := strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(val), "+"))
+	if !strings.HasPrefix(s, "P") || len(s) == 1 {
+		return 0, fmt.Errorf("invalid DURATION %q", val)
+	}
+	dateUnits := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+	timeUnits := map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+	var (
+		total  time.Duration
+		num    string
+		inTime bool
+	)
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == 'T' && !inTime && num == "":
+			inTime = true
+		case c >= '0' && c <= '9':
+			num += string(c)
+		default:
+			units := dateUnits
+			if inTime {
+				units = timeUnits
+			}
+			n, err := strconv.Atoi(num)
+			unit, ok := units[c]
+			if err != nil || !ok {
+				return 0, fmt.Errorf("invalid DURATION %q", val)
+			}
+			total += time.Duration(n) * unit
+			num = ""
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid DURATION %q", val)
+	}
+	return total, nil
+}
+
+func unescapeICSText(s string) string {
+	r := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return r.Replace(s)
+}
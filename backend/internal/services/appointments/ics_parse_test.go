@@ -0,0 +1,249 @@
+package appointments
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// googleExport is trimmed from a Google Calendar export: VTIMEZONE block, folded lines, a weekly
+// series with an EXDATE and a moved occurrence, a reminder and a cancelled event.
+const googleExport = "BEGIN:VCALENDAR\r\n" +
+	"PRODID:-//Google Inc//Google Calendar 70.9054//EN\r\n" +
+	"VERSION:2.0\r\n" +
+	"X-WR-TIMEZONE:Europe/Zagreb\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Europe/Zagreb\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19701025T030000\r\n" +
+	"TZOFFSETFROM:+0200\r\n" +
+	"TZOFFSETTO:+0100\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=Europe/Zagreb:20260323T083000\r\n" +
+	"DTEND;TZID=Europe/Zagreb:20260323T091500\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=6\r\n" +
+	"EXDATE;TZID=Europe/Zagreb:20260406T083000,20260413T083000\r\n" +
+	"UID:series-1@google.com\r\n" +
+	"SUMMARY:Ana Horvat - kontrola\\, lijevo koljeno\\; vježbe kod kuće i ponovna procje\r\n" +
+	" na\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"SUMMARY:Reminder\r\n" +
+	"TRIGGER:-P0DT0H30M0S\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=Europe/Zagreb:20260330T100000\r\n" +
+	"DTEND;TZID=Europe/Zagreb:20260330T104500\r\n" +
+	"RECURRENCE-ID;TZID=Europe/Zagreb:20260330T083000\r\n" +
+	"UID:series-1@google.com\r\n" +
+	"SUMMARY:Ana Horvat - kontrola\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20260325T140000Z\r\n" +
+	"DTEND:20260325T144500Z\r\n" +
+	"UID:single-1@google.com\r\n" +
+	"STATUS:cancelled\r\n" +
+	"SUMMARY:Ivan Kovač\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// outlookExport uses a Windows zone name, DURATION instead of DTEND, LF line endings, a tab
+// continuation and an all-day event.
+const outlookExport = "BEGIN:VCALENDAR\n" +
+	"VERSION:2.0\n" +
+	"BEGIN:VEVENT\n" +
+	"UID:040000008200E00074C5B7101A82E008\n" +
+	"DTSTART;TZID=\"Central Europe Standard Time\":20260701T090000\n" +
+	"DURATION:PT1H30M\n" +
+	"SUMMARY:Horvat\n" +
+	"\t, Ana\n" +
+	"END:VEVENT\n" +
+	"BEGIN:VEVENT\n" +
+	"UID:allday-1\n" +
+	"DTSTART;VALUE=DATE:20260702\n" +
+	"DTEND;VALUE=DATE:20260703\n" +
+	"SUMMARY:Godišnji odmor\n" +
+	"END:VEVENT\n" +
+	"END:VCALENDAR\n"
+
+func TestParseICS(t *testing.T) {
+	zagreb, err := time.LoadLocation("Europe/Zagreb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, zagreb).UTC()
+	}
+
+	events, warnings, err := parseICS([]byte(googleExport), time.UTC)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("google export: err %v, warnings %v", err, warnings)
+	}
+	if len(events) != 3 {
+		t.Fatalf("google export: got %d events, want 3", len(events))
+	}
+	series, moved, single := events[0], events[1], events[2]
+	if want := "Ana Horvat - kontrola, lijevo koljeno; vježbe kod kuće i ponovna procjena"; series.Summary != want {
+		t.Errorf("folded summary = %q, want %q", series.Summary, want)
+	}
+	if !series.Start.Equal(at(2026, 3, 23, 8, 30)) || !series.End.Equal(at(2026, 3, 23, 9, 15)) || series.Loc.String() != "Europe/Zagreb" {
+		t.Errorf("series: start %s end %s loc %s", series.Start, series.End, series.Loc)
+	}
+	if series.RRule != "FREQ=WEEKLY;BYDAY=MO;COUNT=6" || !series.RecurrenceID.IsZero() {
+		t.Errorf("series: rrule %q recurrence-id %s", series.RRule, series.RecurrenceID)
+	}
+	if len(series.ExDates) != 2 || !series.ExDates[0].Equal(at(2026, 4, 6, 8, 30)) || !series.ExDates[1].Equal(at(2026, 4, 13, 8, 30)) {
+		t.Errorf("series: exdates %v", series.ExDates)
+	}
+	if moved.UID != series.UID || !moved.RecurrenceID.Equal(at(2026, 3, 30, 8, 30)) || !moved.Start.Equal(at(2026, 3, 30, 10, 0)) {
+		t.Errorf("moved occurrence: uid %q recurrence-id %s start %s", moved.UID, moved.RecurrenceID, moved.Start)
+	}
+	if single.Status != "CANCELLED" || !single.Start.Equal(time.Date(2026, 3, 25, 14, 0, 0, 0, time.UTC)) || unsupportedImport(single) != "cancelled" {
+		t.Errorf("cancelled event: status %q start %s", single.Status, single.Start)
+	}
+
+	events, warnings, err = parseICS([]byte(outlookExport), zagreb)
+	if err != nil || len(warnings) != 0 || len(events) != 2 {
+		t.Fatalf("outlook export: err %v, warnings %v, %d events", err, warnings, len(events))
+	}
+	outlook, allDay := events[0], events[1]
+	if !outlook.Start.Equal(at(2026, 7, 1, 9, 0)) || outlook.End.Sub(outlook.Start) != 90*time.Minute || outlook.Loc != zagreb {
+		t.Errorf("windows zone falls back to the schedule zone: start %s end %s loc %s", outlook.Start, outlook.End, outlook.Loc)
+	}
+	if outlook.Summary != "Horvat, Ana" || importName(outlook.Summary) != "Horvat Ana" {
+		t.Errorf("tab continuation: summary %q", outlook.Summary)
+	}
+	if !allDay.AllDay || unsupportedImport(allDay) != "all-day event" {
+		t.Errorf("all-day event: %+v", allDay)
+	}
+}
+
+func TestParseICSMalformed(t *testing.T) {
+	wrap := func(body string) []byte {
+		return []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + body + "END:VCALENDAR\r\n")
+	}
+	tests := []struct {
+		name     string
+		data     []byte
+		fails    bool
+		events   int
+		warnings []string // substrings, in order
+	}{
+		{name: "empty file", data: nil, fails: true},
+		{name: "not a calendar", data: []byte("BEGIN:VCARD\r\nFN:Ana\r\nEND:VCARD\r\n"), fails: true},
+		{name: "html error page", data: []byte("<html><body>404</body></html>"), fails: true},
+		{
+			name:     "event without uid",
+			data:     wrap("BEGIN:VEVENT\r\nDTSTART:20260301T080000Z\r\nSUMMARY:Ana\r\nEND:VEVENT\r\n"),
+			warnings: []string{`"Ana" without UID`},
+		},
+		{
+			name:     "event without start",
+			data:     wrap("BEGIN:VEVENT\r\nUID:a\r\nEND:VEVENT\r\n"),
+			warnings: []string{`"a" without DTSTART`},
+		},
+		{
+			name:     "broken times are reported per event",
+			data:     wrap("BEGIN:VEVENT\r\nUID:a\r\nDTSTART:2026-03-01 08:00\r\nEND:VEVENT\r\nBEGIN:VEVENT\r\nUID:b\r\nDTSTART:20260301T080000Z\r\nEXDATE:20260308T080000Z,tomorrow\r\nEND:VEVENT\r\nBEGIN:VEVENT\r\nUID:c\r\nDTSTART:20260301T080000Z\r\nDURATION:P1M\r\nEND:VEVENT\r\nBEGIN:VEVENT\r\nUID:d\r\nDTSTART:20260301T080000Z\r\nDURATION:PT45M\r\nEND:VEVENT\r\n"),
+			events:   1,
+			warnings: []string{`"a": DTSTART: invalid time`, `"b": EXDATE: invalid date "tomorrow"`, `"c": invalid DURATION "P1M"`},
+		},
+		{
+			name:   "lines without a colon and stray components are ignored",
+			data:   wrap("garbage line\r\nBEGIN:VTODO\r\nUID:todo\r\nEND:VTODO\r\nBEGIN:VEVENT\r\nUID:a\r\nDTSTART:20260301T080000Z\r\nnot a property\r\nEND:VEVENT\r\n"),
+			events: 1,
+		},
+		{
+			name:   "truncated file keeps the complete events",
+			data:   []byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nDTSTART:20260301T080000Z\r\nEND:VEVENT\r\nBEGIN:VEVENT\r\nUID:b\r\nDTSTA"),
+			events: 1,
+		},
+	}
+	for _, tt := range tests {
+		events, warnings, err := parseICS(tt.data, time.UTC)
+		if tt.fails {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if len(events) != tt.events {
+			t.Errorf("%s: got %d events, want %d", tt.name, len(events), tt.events)
+		}
+		if len(warnings) != len(tt.warnings) {
+			t.Errorf("%s: got warnings %q, want %d", tt.name, warnings, len(tt.warnings))
+			continue
+		}
+		for i, w := range tt.warnings {
+			if !strings.Contains(warnings[i], w) {
+				t.Errorf("%s: warning %q does not contain %q", tt.name, warnings[i], w)
+			}
+		}
+	}
+}
+
+func TestParseICSDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		bad  bool
+	}{
+		{in: "PT45M", want: 45 * time.Minute},
+		{in: "PT1H30M", want: 90 * time.Minute},
+		{in: "+PT30S", want: 30 * time.Second},
+		{in: "P1D", want: 24 * time.Hour},
+		{in: "P1W", want: 7 * 24 * time.Hour},
+		{in: "P0DT1H0M0S", want: time.Hour},
+		{in: "pt15m", want: 15 * time.Minute},
+		{in: "", bad: true},
+		{in: "P", bad: true},
+		{in: "45M", bad: true},
+		{in: "P1M", bad: true}, // months are not a fixed duration
+		{in: "PT1D", bad: true},
+		{in: "PT", want: 0},
+		{in: "PT1H30", bad: true},
+		{in: "PTT1H", bad: true},
+		{in: "-PT15M", bad: true},
+	}
+	for _, tt := range tests {
+		got, err := parseICSDuration(tt.in)
+		if tt.bad {
+			if err == nil {
+				t.Errorf("parseICSDuration(%q) = %s, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseICSDuration(%q) = %s, %v, want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseICSProperty(t *testing.T) {
+	p, ok := parseICSProperty(`dtstart;TZID="America/Argentina/Buenos_Aires";X-NOTE="a:b;c":20260301T080000`)
+	if !ok || p.Name != "DTSTART" || p.Value != "20260301T080000" {
+		t.Fatalf("got %+v, %v", p, ok)
+	}
+	if p.Params["TZID"] != "America/Argentina/Buenos_Aires" || p.Params["X-NOTE"] != "a:b;c" {
+		t.Errorf("params %v", p.Params)
+	}
+	if p, ok := parseICSProperty("DESCRIPTION:Link: https://example.com/a;b"); !ok || p.Value != "Link: https://example.com/a;b" {
+		t.Errorf("value with colons: got %+v", p)
+	}
+	for _, line := range []string{"no colon here", ":value without name", `X-BROKEN;P="unterminated:value`} {
+		if _, ok := parseICSProperty(line); ok {
+			t.Errorf("parseICSProperty(%q): expected no property", line)
+		}
+	}
+	if got := unescapeICSText(`a\,b\;c\\n\nd\Ne`); got != "a,b;c\\n\nd\ne" {
+		t.Errorf("unescapeICSText = %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+package appointments
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeICSText(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"A.M.", "A.M."},
+		{"Kontrola, koljeno; desno", `Kontrola\, koljeno\; desno`},
+		{`C:\temp`, `C:\\temp`},
+		{"prvi red\ndrugi\r\ntreći\rčetvrti", `prvi red\ndrugi\ntreći\nčetvrti`},
+		{`\,`, `\\\,`},
+	}
+	for _, tt := range tests {
+		if got := escapeICSText(tt.in); got != tt.want {
+			t.Errorf("escapeICSText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"76 octets", "SUMMARY:" + strings.Repeat("a", 68)},
+		{"several continuation lines", "DESCRIPTION:" + strings.Repeat("0123456789", 20)},
+		{"multi-byte runes across the limit", "SUMMARY:" + strings.Repeat("čćžšđ", 30)},
+	}
+	for _, tt := range tests {
+		folded := foldICSLine(tt.in)
+		lines := strings.Split(folded, "\r\n")
+		if len(tt.in) <= 75 && len(lines) != 1 {
+			t.Errorf("%s: folded a line of %d octets", tt.name, len(tt.in))
+		}
+		for i, l := range lines {
+			if len(l) > 75 {
+				t.Errorf("%s: line %d has %d octets", tt.name, i, len(l))
+			}
+			if i > 0 && !strings.HasPrefix(l, " ") {
+				t.Errorf("%s: continuation line %d does not start with a space", tt.name, i)
+			}
+			if !utf8.ValidString(l) {
+				t.Errorf("%s: line %d splits a UTF-8 sequence", tt.name, i)
+			}
+		}
+		if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != tt.in {
+			t.Errorf("%s: unfolding gives %q, want %q", tt.name, unfolded, tt.in)
+		}
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	stamp := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	start := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+	out := string(writeICS("Physio; Split", []icsEvent{
+		{UID: "a@physio-tracker", Start: start, End: start.Add(45 * time.Minute), Summary: "A.M.", Modified: stamp},
+		{UID: "b@physio-tracker", Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Summary: "I.K.", Cancelled: true},
+	}, stamp))
+
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Fatalf("unexpected calendar envelope:\n%s", out)
+	}
+	if strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Error("found a bare LF line ending")
+	}
+	for _, want := range []string{
+		"X-WR-CALNAME:Physio\\; Split\r\n",
+		"BEGIN:VEVENT\r\nUID:a@physio-tracker\r\nDTSTAMP:20260301T100000Z\r\nDTSTART:20260302T073000Z\r\nDTEND:20260302T081500Z\r\nSUMMARY:A.M.\r\nSTATUS:CONFIRMED\r\nLAST-MODIFIED:20260301T100000Z\r\nEND:VEVENT\r\n",
+		"UID:b@physio-tracker\r\n",
+		"STATUS:CANCELLED\r\nEND:VEVENT\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q", want)
+		}
+	}
+	if n := strings.Count(out, "BEGIN:VEVENT"); n != 2 {
+		t.Errorf("got %d events, want 2", n)
+	}
+}
+
+func TestInitials(t *testing.T) {
+	tests := []struct{ first, last, want string }{
+		{"Ana", "Marić", "A.M."},
+		{" ivan ", "ćosić", "I.Ć."},
+		{"", "Horvat", "H."},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := initials(tt.first, tt.last); got != tt.want {
+			t.Errorf("initials(%q, %q) = %q, want %q", tt.first, tt.last, got, tt.want)
+		}
+	}
+}
+
+func TestHashFeedToken(t *testing.T) {
+	token := "4f6c0b1e2d3a4f6c0b1e2d3a4f6c0b1e2d3a4f6c0b1e2d3a"
+	h := hashFeedToken(token)
+	if len(h) != 64 || strings.Contains(h, token) {
+		t.Errorf("hashFeedToken(%q) = %q, want a 64 character SHA-256 hex digest", token, h)
+	}
+	if hashFeedToken(token) != h || hashFeedToken(token+"0") == h {
+		t.Error("hashFeedToken is not a stable, token-specific digest")
+	}
+}
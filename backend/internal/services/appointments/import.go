@@ -0,0 +1,439 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	ReviewNoMatch   = "no_match"
+	ReviewAmbiguous = "ambiguous"
+
+	ReviewStatusPending   = "pending"
+	ReviewStatusResolved  = "resolved"
+	ReviewStatusDismissed = "dismissed"
+)
+
+// importBatch collects everything one import creates, so the whole file is stored in one
+// transaction or not at all.
+type importBatch struct {
+	series       []*pb.AppointmentSeries
+	appointments []*pb.Appointment
+	reviews      []*pb.ImportReview
+}
+
+// ImportICS turns the VEVENTs of an uploaded calendar into appointments and series. Events are
+// matched to patients by name; the rest goes to the review list. Re-importing the same file is
+// a no-op because every event is remembered by its UID. Imported events skip the conflict check:
+// they already happened or were booked in the old calendar.
+func (s *service) ImportICS(ctx context.Context, doctorUUID string, data []byte) (*pb.ImportAppointmentsResponse, error) {
+	if strings.TrimSpace(doctorUUID) == "" || len(data) == 0 {
+		return nil, fmt.Errorf("import appointments: %w", se.ErrInvalidRequest)
+	}
+	sched, err := s.profileRepo.GetSchedule(ctx, doctorUUID)
+	if err != nil {
+		if !errors.Is(err, re.ErrNotFound) && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("import appointments: load working schedule: %w", err)
+		}
+		sched = nil
+	}
+	defLoc, err := svcdoctorprofiles.ScheduleLocation(sched)
+	if err != nil {
+		return nil, fmt.Errorf("import appointments: schedule timezone: %w", err)
+	}
+	events, warnings, err := parseICS(data, defLoc)
+	if err != nil {
+		return nil, fmt.Errorf("import appointments: %v: %w", err, se.ErrInvalidRequest)
+	}
+	known, err := s.importRepo.ImportedUIDs(ctx, doctorUUID)
+	if err != nil {
+		return nil, fmt.Errorf("import appointments: %w", err)
+	}
+
+	res := &pb.ImportAppointmentsResponse{Warnings: warnings}
+	batch := &importBatch{}
+	var masters []importedEvent
+	overrides := map[string][]importedEvent{}
+	for _, ev := range events {
+		if ev.RecurrenceID.IsZero() {
+			masters = append(masters, ev)
+		} else {
+			overrides[ev.UID] = append(overrides[ev.UID], ev)
+		}
+	}
+
+	for _, ev := range masters {
+		if known[ev.UID] {
+			res.Skipped++
+			continue
+		}
+		known[ev.UID] = true
+		if reason := unsupportedImport(ev); reason != "" {
+			res.Skipped++
+			if ev.Status != "CANCELLED" {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("%q at %s skipped: %s", ev.Summary, formatLocal(ev.Start, ev.Loc), reason))
+			}
+			continue
+		}
+		patientUUID, reason, err := s.matchPatient(ctx, doctorUUID, ev.Summary)
+		if err != nil {
+			return nil, fmt.Errorf("import appointments: %w", err)
+		}
+		if reason != "" {
+			rv, err := s.newReview(ev, reason)
+			if err != nil {
+				return nil, fmt.Errorf("import appointments: %w", err)
+			}
+			batch.reviews = append(batch.reviews, rv)
+			res.Unmatched++
+			res.Reviews = append(res.Reviews, rv)
+			if n := len(overrides[ev.UID]); n > 0 {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("%q: %d changed occurrence(s) are not kept for review", ev.Summary, n))
+			}
+			delete(overrides, ev.UID)
+			continue
+		}
+		if _, _, err := s.planImported(ctx, batch, doctorUUID, patientUUID, ev, overrides[ev.UID]); err != nil {
+			return nil, fmt.Errorf("import appointments: %w", err)
+		}
+		delete(overrides, ev.UID)
+		res.Created++
+	}
+
+	// Overrides whose recurring event is not in the file belong to a series imported earlier.
+	for uid, list := range overrides {
+		ser, err := s.importRepo.SeriesByExternalUID(ctx, doctorUUID, uid)
+		if err != nil {
+			if !errors.Is(err, re.ErrNotFound) {
+				return nil, fmt.Errorf("import appointments: %w", err)
+			}
+			res.Skipped += int32(len(list))
+			res.Warnings = append(res.Warnings, fmt.Sprintf("%d changed occurrence(s) of unknown recurring event %q skipped", len(list), uid))
+			continue
+		}
+		n, err := s.importOverrides(ctx, batch, ser, list)
+		if err != nil {
+			return nil, fmt.Errorf("import appointments: %w", err)
+		}
+		res.Created += int32(n)
+		res.Skipped += int32(len(list) - n)
+	}
+	if err := s.saveImport(ctx, doctorUUID, batch); err != nil {
+		return nil, fmt.Errorf("import appointments: %w", err)
+	}
+	return res, nil
+}
+
+func (s *service) saveImport(ctx context.Context, doctorUUID string, batch *importBatch) error {
+	if err := s.importRepo.SaveImport(ctx, doctorUUID, batch.series, batch.appointments, batch.reviews); err != nil {
+		switch {
+		case errors.Is(err, re.ErrNotFound):
+			return se.ErrNotFound
+		case errors.Is(err, re.ErrConflict):
+			return fmt.Errorf("calendar was imported concurrently: %w", se.ErrConflict)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) ListImportReviews(ctx context.Context, doctorUUID, status string) ([]*pb.ImportReview, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("list import reviews: %w", se.ErrInvalidRequest)
+	}
+	status = strings.ToLower(strings.TrimSpace(status))
+	switch status {
+	case "", ReviewStatusPending, ReviewStatusResolved, ReviewStatusDismissed:
+	default:
+		return nil, fmt.Errorf("list import reviews: invalid status: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.importRepo.ListReviews(ctx, doctorUUID, status)
+	if err != nil {
+		return nil, fmt.Errorf("list import reviews: %w", err)
+	}
+	return list, nil
+}
+
+// ResolveImportReview books a reviewed event for the chosen patient.
+func (s *service) ResolveImportReview(ctx context.Context, doctorUUID string, req *pb.ResolveImportReviewRequest) (*pb.ImportReview, error) {
+	rv, err := s.loadPendingReview(ctx, doctorUUID, req.GetUuid())
+	if err != nil {
+		return nil, fmt.Errorf("resolve import review: %w", err)
+	}
+	patientUUID := strings.TrimSpace(req.GetPatientUuid())
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("resolve import review: %w", err)
+	}
+	loc, err := time.LoadLocation(rv.GetTimezone())
+	if err != nil {
+		return nil, fmt.Errorf("resolve import review: timezone: %w", err)
+	}
+	ev := importedEvent{
+		UID:     rv.GetExternalUid(),
+		Summary: rv.GetSummary(),
+		Start:   rv.GetStartsAt().AsTime().UTC(),
+		End:     rv.GetEndsAt().AsTime().UTC(),
+		Loc:     loc,
+		RRule:   rv.GetRrule(),
+	}
+	for _, t := range rv.GetExdates() {
+		ev.ExDates = append(ev.ExDates, t.AsTime().UTC())
+	}
+	batch := &importBatch{}
+	apptUUID, seriesUUID, err := s.planImported(ctx, batch, doctorUUID, patientUUID, ev, nil)
+	if err != nil {
+		return nil, fmt.Errorf("resolve import review: %w", err)
+	}
+	if err := s.saveImport(ctx, doctorUUID, batch); err != nil {
+		return nil, fmt.Errorf("resolve import review: %w", err)
+	}
+	rv.Status = ReviewStatusResolved
+	rv.AppointmentUuid = apptUUID
+	rv.SeriesUuid = seriesUUID
+	rv.ResolvedAt = timestamppb.New(s.now().UTC())
+	updated, err := s.importRepo.UpdateReviewStatus(ctx, rv, doctorUUID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve import review: %w", err)
+	}
+	return updated, nil
+}
+
+// DismissImportReview drops a reviewed event; its UID stays known so a re-import skips it.
+func (s *service) DismissImportReview(ctx context.Context, doctorUUID, reviewUUID string) (*pb.ImportReview, error) {
+	rv, err := s.loadPendingReview(ctx, doctorUUID, reviewUUID)
+	if err != nil {
+		return nil, fmt.Errorf("dismiss import review: %w", err)
+	}
+	rv.Status = ReviewStatusDismissed
+	rv.ResolvedAt = timestamppb.New(s.now().UTC())
+	updated, err := s.importRepo.UpdateReviewStatus(ctx, rv, doctorUUID)
+	if err != nil {
+		return nil, fmt.Errorf("dismiss import review: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *service) loadPendingReview(ctx context.Context, doctorUUID, reviewUUID string) (*pb.ImportReview, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(reviewUUID) == "" {
+		return nil, se.ErrInvalidRequest
+	}
+	rv, err := s.importRepo.GetReview(ctx, doctorUUID, reviewUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, se.ErrNotFound
+		}
+		return nil, err
+	}
+	if rv.GetStatus() != ReviewStatusPending {
+		return nil, fmt.Errorf("review is already %s: %w", rv.GetStatus(), se.ErrConflict)
+	}
+	return rv, nil
+}
+
+// unsupportedImport explains why an event cannot become an appointment; empty means importable.
+func unsupportedImport(ev importedEvent) string {
+	switch {
+	case ev.Status == "CANCELLED":
+		return "cancelled"
+	case ev.AllDay:
+		return "all-day event"
+	case !ev.End.After(ev.Start) || ev.End.Sub(ev.Start) > 24*time.Hour || ev.End.Sub(ev.Start)%time.Minute != 0:
+		return "unsupported duration"
+	}
+	if ev.RRule == "" {
+		return ""
+	}
+	rule, err := ParseRule(ev.RRule, ev.Loc)
+	if err != nil {
+		return err.Error()
+	}
+	if !rule.IsOccurrence(ev.Start, ev.Loc, ev.Start) {
+		return "start does not match the rule"
+	}
+	return ""
+}
+
+// matchPatient finds the patient named in an event summary with the patient list search. Several
+// hits are narrowed to an exact full-name match; otherwise the reason for review is returned.
+func (s *service) matchPatient(ctx context.Context, doctorUUID, summary string) (string, string, error) {
+	name := importName(summary)
+	if name == "" {
+		return "", ReviewNoMatch, nil
+	}
+	list, err := s.patientRepo.List(ctx, &pb.ListPatientsRequest{Query: name}, doctorUUID, 0, 0)
+	if err != nil {
+		return "", "", fmt.Errorf("match patient: %w", err)
+	}
+	switch len(list) {
+	case 0:
+		return "", ReviewNoMatch, nil
+	case 1:
+		return list[0].GetUuid(), "", nil
+	}
+	key := strings.ToLower(name)
+	var exact []string
+	for _, p := range list {
+		first, last := strings.ToLower(strings.TrimSpace(p.GetFirstName())), strings.ToLower(strings.TrimSpace(p.GetLastName()))
+		if key == first+" "+last || key == last+" "+first {
+			exact = append(exact, p.GetUuid())
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0], "", nil
+	}
+	return "", ReviewAmbiguous, nil
+}
+
+// importName strips what calendars usually append to a patient's name ("Ana Horvat - kontrola")
+// and accepts "Horvat, Ana".
+func importName(summary string) string {
+	name := summary
+	for _, sep := range []string{" - ", " – ", ":", "(", "/"} {
+		if i := strings.Index(name, sep); i > 0 {
+			name = name[:i]
+		}
+	}
+	return strings.Join(strings.Fields(strings.ReplaceAll(name, ",", " ")), " ")
+}
+
+func (s *service) newReview(ev importedEvent, reason string) (*pb.ImportReview, error) {
+	rv := &pb.ImportReview{
+		Uuid:        uuid.NewString(),
+		ExternalUid: ev.UID,
+		Summary:     ev.Summary,
+		StartsAt:    timestamppb.New(ev.Start),
+		EndsAt:      timestamppb.New(ev.End),
+		Timezone:    ev.Loc.String(),
+		Reason:      reason,
+		Status:      ReviewStatusPending,
+		CreatedAt:   timestamppb.New(s.now().UTC()),
+	}
+	if ev.RRule != "" {
+		rule, err := ParseRule(ev.RRule, ev.Loc)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", err, se.ErrInvalidRequest)
+		}
+		rv.Rrule = rule.String()
+	}
+	for _, t := range ev.ExDates {
+		rv.Exdates = append(rv.Exdates, timestamppb.New(t))
+	}
+	return rv, nil
+}
+
+// planImported adds a single appointment or, for recurring events, a series with its EXDATEs as
+// cancelled occurrences and overrides as changed occurrences to the batch.
+func (s *service) planImported(ctx context.Context, batch *importBatch, doctorUUID, patientUUID string, ev importedEvent, overrides []importedEvent) (string, string, error) {
+	now := timestamppb.New(s.now().UTC())
+	if ev.RRule == "" {
+		appt := &pb.Appointment{
+			Uuid:        uuid.NewString(),
+			DoctorUuid:  doctorUUID,
+			PatientUuid: patientUUID,
+			StartsAt:    timestamppb.New(ev.Start),
+			EndsAt:      timestamppb.New(ev.End),
+			Status:      StatusScheduled,
+			Note:        ev.Summary,
+			ExternalUid: ev.UID,
+			CreatedAt:   now,
+		}
+		batch.appointments = append(batch.appointments, appt)
+		return appt.GetUuid(), "", nil
+	}
+
+	rule, err := ParseRule(ev.RRule, ev.Loc)
+	if err != nil {
+		return "", "", fmt.Errorf("%v: %w", err, se.ErrInvalidRequest)
+	}
+	ser := &pb.AppointmentSeries{
+		Uuid:            uuid.NewString(),
+		DoctorUuid:      doctorUUID,
+		PatientUuid:     patientUUID,
+		Rrule:           rule.String(),
+		StartsAt:        timestamppb.New(ev.Start),
+		DurationMinutes: int32(ev.End.Sub(ev.Start) / time.Minute),
+		Timezone:        ev.Loc.String(),
+		Note:            ev.Summary,
+		Status:          SeriesStatusActive,
+		ExternalUid:     ev.UID,
+		CreatedAt:       now,
+	}
+	batch.series = append(batch.series, ser)
+	overridden := map[int64]bool{}
+	for _, o := range overrides {
+		overridden[o.RecurrenceID.Unix()] = true
+	}
+	for _, ex := range ev.ExDates {
+		if overridden[ex.Unix()] || !rule.IsOccurrence(ev.Start, ev.Loc, ex) {
+			continue
+		}
+		appt := virtualOccurrence(ser, ex)
+		appt.Uuid = uuid.NewString()
+		appt.Status = StatusCancelled
+		appt.CancelledAt = now
+		appt.CreatedAt = now
+		batch.appointments = append(batch.appointments, appt)
+	}
+	if _, err := s.importOverrides(ctx, batch, ser, overrides); err != nil {
+		return "", "", err
+	}
+	return "", ser.GetUuid(), nil
+}
+
+// importOverrides adds changed occurrences (RECURRENCE-ID) of an imported series to the batch and
+// returns how many were new. Occurrences that already have an exception are left as they are.
+func (s *service) importOverrides(ctx context.Context, batch *importBatch, ser *pb.AppointmentSeries, overrides []importedEvent) (int, error) {
+	if len(overrides) == 0 {
+		return 0, nil
+	}
+	loc, err := time.LoadLocation(ser.GetTimezone())
+	if err != nil {
+		return 0, fmt.Errorf("series timezone: %w", err)
+	}
+	rule, err := ParseRule(ser.GetRrule(), loc)
+	if err != nil {
+		return 0, fmt.Errorf("series rule: %w", err)
+	}
+	now := timestamppb.New(s.now().UTC())
+	created := 0
+	seen := map[int64]bool{}
+	for _, o := range overrides {
+		if seen[o.RecurrenceID.Unix()] || !rule.IsOccurrence(ser.GetStartsAt().AsTime(), loc, o.RecurrenceID) {
+			continue
+		}
+		seen[o.RecurrenceID.Unix()] = true
+		if _, err := s.repo.GetOccurrence(ctx, ser.GetUuid(), o.RecurrenceID); err == nil {
+			continue
+		} else if !errors.Is(err, re.ErrNotFound) && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return created, fmt.Errorf("load occurrence: %w", err)
+		}
+		appt := virtualOccurrence(ser, o.RecurrenceID)
+		appt.Uuid = uuid.NewString()
+		appt.CreatedAt = now
+		if o.Status == "CANCELLED" {
+			appt.Status = StatusCancelled
+			appt.CancelledAt = now
+		} else if !o.AllDay && o.End.After(o.Start) {
+			appt.StartsAt = timestamppb.New(o.Start)
+			appt.EndsAt = timestamppb.New(o.End)
+			if o.Summary != "" {
+				appt.Note = o.Summary
+			}
+		}
+		batch.appointments = append(batch.appointments, appt)
+		created++
+	}
+	return created, nil
+}
@@ -0,0 +1,125 @@
+package appointments
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	OutcomeAttended           = "attended"
+	OutcomeNoShow             = "no_show"
+	OutcomeCancelledByPatient = "cancelled_by_patient"
+	OutcomeCancelledByClinic  = "cancelled_by_clinic"
+	OutcomeLateCancel         = "late_cancel"
+
+	CancelledByPatient = "patient"
+	CancelledByClinic  = "clinic"
+)
+
+// lateCancelWindow is how close to the visit a patient cancellation counts as late.
+const lateCancelWindow = 24 * time.Hour
+
+// RecordOutcome stores what happened to an appointment or series occurrence. Cancellation
+// outcomes also cancel the appointment; attended and no-show can only be recorded once it started.
+func (s *service) RecordOutcome(ctx context.Context, doctorUUID, patientUUID string, req *pb.RecordOutcomeRequest) (*pb.Appointment, error) {
+	outcome := strings.TrimSpace(req.GetOutcome())
+	if !validOutcome(outcome) {
+		return nil, fmt.Errorf("record outcome: invalid outcome: %w", se.ErrInvalidRequest)
+	}
+	appt, err := s.outcomeTarget(ctx, doctorUUID, patientUUID, req)
+	if err != nil {
+		return nil, fmt.Errorf("record outcome: %w", err)
+	}
+	now := s.now().UTC()
+	at := now
+	if req.GetOutcomeAt() != nil {
+		at = req.GetOutcomeAt().AsTime().UTC()
+	}
+
+	freed := false
+	switch outcome {
+	case OutcomeAttended, OutcomeNoShow:
+		if appt.GetStatus() == StatusCancelled {
+			return nil, fmt.Errorf("record outcome: appointment is cancelled: %w", se.ErrConflict)
+		}
+		if appt.GetStartsAt().AsTime().After(now) {
+			return nil, fmt.Errorf("record outcome: appointment has not started yet: %w", se.ErrInvalidRequest)
+		}
+	default:
+		freed = appt.GetStatus() != StatusCancelled
+		if freed {
+			appt.Status = StatusCancelled
+			appt.CancelledAt = timestamppb.New(at)
+			appt.CancelReason = strings.TrimSpace(req.GetReason())
+		}
+	}
+	appt.Outcome = outcome
+	appt.OutcomeAt = timestamppb.New(at)
+	appt.OutcomeReason = strings.TrimSpace(req.GetReason())
+	appt.UpdatedAt = timestamppb.New(now)
+
+	updated, err := s.repo.Update(ctx, appt)
+	if err != nil {
+		return nil, fmt.Errorf("record outcome: %w", err)
+	}
+	if freed {
+		s.slotFreed(ctx, updated)
+	}
+	return updated, nil
+}
+
+// outcomeTarget loads the appointment by uuid or materializes the addressed series occurrence.
+func (s *service) outcomeTarget(ctx context.Context, doctorUUID, patientUUID string, req *pb.RecordOutcomeRequest) (*pb.Appointment, error) {
+	if id := strings.TrimSpace(req.GetUuid()); id != "" {
+		appt, err := s.load(ctx, doctorUUID, id)
+		if err != nil {
+			return nil, err
+		}
+		if patientUUID != "" && appt.GetPatientUuid() != patientUUID {
+			return nil, se.ErrNotFound
+		}
+		return appt, nil
+	}
+	if req.GetOriginalStartsAt() == nil {
+		return nil, se.ErrInvalidRequest
+	}
+	ser, rule, loc, err := s.loadSeries(ctx, doctorUUID, patientUUID, req.GetSeriesUuid())
+	if err != nil {
+		return nil, err
+	}
+	orig := req.GetOriginalStartsAt().AsTime().UTC()
+	if !rule.IsOccurrence(ser.GetStartsAt().AsTime(), loc, orig) {
+		return nil, fmt.Errorf("no occurrence at %s: %w", orig.Format(time.RFC3339), se.ErrNotFound)
+	}
+	return s.materializeOccurrence(ctx, ser, orig)
+}
+
+// cancelOutcome derives the outcome of a cancellation; a patient cancelling within
+// lateCancelWindow before the start is a late cancel.
+func cancelOutcome(cancelledBy string, start, now time.Time) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(cancelledBy)) {
+	case "", CancelledByClinic:
+		return OutcomeCancelledByClinic, nil
+	case CancelledByPatient:
+		if start.Sub(now) < lateCancelWindow {
+			return OutcomeLateCancel, nil
+		}
+		return OutcomeCancelledByPatient, nil
+	default:
+		return "", fmt.Errorf("invalid cancelled_by: %w", se.ErrInvalidRequest)
+	}
+}
+
+func validOutcome(v string) bool {
+	switch v {
+	case OutcomeAttended, OutcomeNoShow, OutcomeCancelledByPatient, OutcomeCancelledByClinic, OutcomeLateCancel:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	ResourceKindRoom      = "room"
+	ResourceKindEquipment = "equipment"
+)
+
+func (s *service) CreateResource(ctx context.Context, doctorUUID string, req *pb.CreateResourceRequest) (*pb.Resource, error) {
+	name := strings.TrimSpace(req.GetName())
+	if strings.TrimSpace(doctorUUID) == "" || name == "" || !validResourceKind(req.GetKind()) {
+		return nil, fmt.Errorf("create resource: %w", se.ErrInvalidRequest)
+	}
+	res := &pb.Resource{
+		Uuid:       uuid.NewString(),
+		DoctorUuid: doctorUUID,
+		Name:       name,
+		Kind:       req.GetKind(),
+		Note:       strings.TrimSpace(req.GetNote()),
+		Active:     true,
+		CreatedAt:  timestamppb.New(s.now().UTC()),
+	}
+	created, err := s.resourceRepo.Create(ctx, res)
+	if err != nil {
+		if errors.Is(err, re.ErrConflict) {
+			return nil, fmt.Errorf("create resource: name %q is taken: %w", name, se.ErrConflict)
+		}
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("create resource: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("create resource: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateResource renames or (de)activates a resource. Deactivated resources stay on existing
+// appointments but cannot be booked again.
+func (s *service) UpdateResource(ctx context.Context, doctorUUID string, req *pb.UpdateResourceRequest) (*pb.Resource, error) {
+	res, err := s.loadResource(ctx, doctorUUID, req.GetUuid())
+	if err != nil {
+		return nil, fmt.Errorf("update resource: %w", err)
+	}
+	if req.Name != nil {
+		if res.Name = strings.TrimSpace(req.GetName().GetValue()); res.Name == "" {
+			return nil, fmt.Errorf("update resource: empty name: %w", se.ErrInvalidRequest)
+		}
+	}
+	if req.Kind != nil {
+		if res.Kind = req.GetKind().GetValue(); !validResourceKind(res.Kind) {
+			return nil, fmt.Errorf("update resource: unknown kind %q: %w", res.Kind, se.ErrInvalidRequest)
+		}
+	}
+	if req.Note != nil {
+		res.Note = strings.TrimSpace(req.GetNote().GetValue())
+	}
+	if req.Active != nil {
+		res.Active = req.GetActive().GetValue()
+	}
+	res.UpdatedAt = timestamppb.New(s.now().UTC())
+	updated, err := s.resourceRepo.Update(ctx, res)
+	if err != nil {
+		if errors.Is(err, re.ErrConflict) {
+			return nil, fmt.Errorf("update resource: name %q is taken: %w", res.Name, se.ErrConflict)
+		}
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("update resource: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("update resource: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *service) ListResources(ctx context.Context, doctorUUID string, includeInactive bool) ([]*pb.Resource, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("list resources: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.resourceRepo.List(ctx, doctorUUID, !includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("list resources: %w", err)
+	}
+	return list, nil
+}
+
+// ResourceSchedules lists, for each active resource, the scheduled appointments reserving it on
+// date (YYYY-MM-DD in the doctor's schedule timezone; empty = today).
+func (s *service) ResourceSchedules(ctx context.Context, doctorUUID, date string) (*pb.ListResourceSchedulesResponse, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("resource schedules: %w", se.ErrInvalidRequest)
+	}
+	sched, err := s.profileRepo.GetSchedule(ctx, doctorUUID)
+	if err != nil {
+		if !errors.Is(err, re.ErrNotFound) && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("resource schedules: load working schedule: %w", err)
+		}
+		sched = nil
+	}
+	loc, err := svcdoctorprofiles.ScheduleLocation(sched)
+	if err != nil {
+		return nil, fmt.Errorf("resource schedules: schedule timezone: %w", err)
+	}
+	day := s.now().In(loc)
+	if strings.TrimSpace(date) != "" {
+		if day, err = time.ParseInLocation("2006-01-02", strings.TrimSpace(date), loc); err != nil {
+			return nil, fmt.Errorf("resource schedules: invalid date %q: %w", date, se.ErrInvalidRequest)
+		}
+	}
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, 1)
+
+	resources, err := s.resourceRepo.List(ctx, doctorUUID, true)
+	if err != nil {
+		return nil, fmt.Errorf("resource schedules: %w", err)
+	}
+	appts, err := s.appointmentsInRange(ctx, doctorUUID, "", from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("resource schedules: %w", err)
+	}
+	resp := &pb.ListResourceSchedulesResponse{Date: from.Format("2006-01-02")}
+	for _, res := range resources {
+		rs := &pb.ResourceSchedule{Resource: res, Appointments: []*pb.Appointment{}}
+		for _, a := range appts {
+			if a.GetStatus() != StatusCancelled && len(sharedResources([]string{res.GetUuid()}, a.GetResourceUuids())) > 0 {
+				rs.Appointments = append(rs.Appointments, a)
+			}
+		}
+		resp.Schedules = append(resp.Schedules, rs)
+	}
+	return resp, nil
+}
+
+// ensureResources checks that the doctor owns the requested resources and that they are active.
+// It returns the uuids trimmed and without duplicates.
+func (s *service) ensureResources(ctx context.Context, doctorUUID string, uuids []string) ([]string, error) {
+	res := make([]string, 0, len(uuids))
+	seen := map[string]bool{}
+	for _, id := range uuids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		r, err := s.loadResource(ctx, doctorUUID, id)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s: %w", id, err)
+		}
+		if !r.GetActive() {
+			return nil, fmt.Errorf("resource %q is inactive: %w", r.GetName(), se.ErrInvalidRequest)
+		}
+		res = append(res, id)
+	}
+	return res, nil
+}
+
+func (s *service) loadResource(ctx context.Context, doctorUUID, resourceUUID string) (*pb.Resource, error) {
+	if strings.TrimSpace(resourceUUID) == "" {
+		return nil, se.ErrInvalidRequest
+	}
+	res, err := s.resourceRepo.Get(ctx, resourceUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, se.ErrNotFound
+		}
+		return nil, err
+	}
+	if res.GetDoctorUuid() != doctorUUID {
+		return nil, se.ErrNotFound
+	}
+	return res, nil
+}
+
+func validResourceKind(kind string) bool {
+	return kind == ResourceKindRoom || kind == ResourceKindEquipment
+}
@@ -0,0 +1,289 @@
+package appointments
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is the supported RRULE subset: FREQ (DAILY, WEEKLY), INTERVAL, BYDAY, COUNT and UNTIL.
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    time.Time // zero when the series is open-ended (or bounded by COUNT)
+}
+
+const (
+	FreqDaily  = "DAILY"
+	FreqWeekly = "WEEKLY"
+)
+
+// maxOccurrences guards expansion of open-ended rules.
+const maxOccurrences = 5000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// ParseRule parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=10".
+// A leading "RRULE:" prefix is accepted. Date-only UNTIL values are read in loc
+// and cover the whole day.
+func ParseRule(value string, loc *time.Location) (Rule, error) {
+	s := strings.TrimSpace(value)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "RRULE:"), "rrule:")
+	if s == "" {
+		return Rule{}, fmt.Errorf("rrule: empty")
+	}
+	r := Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return Rule{}, fmt.Errorf("rrule: malformed part %q", part)
+		}
+		key, val := strings.ToUpper(strings.TrimSpace(kv[0])), strings.ToUpper(strings.TrimSpace(kv[1]))
+		switch key {
+		case "FREQ":
+			if val != FreqDaily && val != FreqWeekly {
+				return Rule{}, fmt.Errorf("rrule: unsupported FREQ %q", val)
+			}
+			r.Freq = val
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n <= 0 {
+				return Rule{}, fmt.Errorf("rrule: invalid INTERVAL %q", val)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n <= 0 {
+				return Rule{}, fmt.Errorf("rrule: invalid COUNT %q", val)
+			}
+			r.Count = n
+		case "UNTIL":
+			t, err := parseUntil(val, loc)
+			if err != nil {
+				return Rule{}, err
+			}
+			r.Until = t
+		case "BYDAY":
+			days := []time.Weekday{}
+			seen := map[time.Weekday]bool{}
+			for _, code := range strings.Split(val, ",") {
+				wd, ok := weekdayCodes[strings.TrimSpace(code)]
+				if !ok {
+					return Rule{}, fmt.Errorf("rrule: unsupported BYDAY %q", code)
+				}
+				if !seen[wd] {
+					seen[wd] = true
+					days = append(days, wd)
+				}
+			}
+			sort.Slice(days, func(i, j int) bool { return weekdayIndex(days[i]) < weekdayIndex(days[j]) })
+			r.ByDay = days
+		case "WKST":
+			if val != "MO" {
+				return Rule{}, fmt.Errorf("rrule: only WKST=MO is supported")
+			}
+		default:
+			return Rule{}, fmt.Errorf("rrule: unsupported part %q", key)
+		}
+	}
+	if r.Freq == "" {
+		return Rule{}, fmt.Errorf("rrule: FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return Rule{}, fmt.Errorf("rrule: COUNT and UNTIL are mutually exclusive")
+	}
+	return r, nil
+}
+
+func parseUntil(val string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", val); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", val, loc); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.ParseInLocation("20060102", val, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("rrule: invalid UNTIL %q", val)
+}
+
+// String renders the rule back to RRULE syntax (without the "RRULE:" prefix).
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			codes = append(codes, weekdayCode(wd))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences expands the rule anchored at dtstart and returns the starts that fall in [from, to).
+// Expansion keeps the wall-clock time of dtstart in loc, so a 08:30 series stays at 08:30 across DST.
+func (r Rule) Occurrences(dtstart time.Time, loc *time.Location, from, to time.Time) []time.Time {
+	var res []time.Time
+	r.each(dtstart, loc, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			res = append(res, t)
+		}
+		return true
+	})
+	return res
+}
+
+// CountBefore returns how many occurrences start before t.
+func (r Rule) CountBefore(dtstart time.Time, loc *time.Location, t time.Time) int {
+	n := 0
+	r.each(dtstart, loc, func(occ time.Time) bool {
+		if !occ.Before(t) {
+			return false
+		}
+		n++
+		return true
+	})
+	return n
+}
+
+// First returns up to n occurrence starts, in order.
+func (r Rule) First(dtstart time.Time, loc *time.Location, n int) []time.Time {
+	if n <= 0 {
+		return nil
+	}
+	res := make([]time.Time, 0, n)
+	r.each(dtstart, loc, func(t time.Time) bool {
+		res = append(res, t)
+		return len(res) < n
+	})
+	return res
+}
+
+// IsOccurrence reports whether t is one of the generated occurrence starts.
+func (r Rule) IsOccurrence(dtstart time.Time, loc *time.Location, t time.Time) bool {
+	found := false
+	r.each(dtstart, loc, func(occ time.Time) bool {
+		if occ.Equal(t) {
+			found = true
+			return false
+		}
+		return occ.Before(t)
+	})
+	return found
+}
+
+// each walks occurrences in order until fn returns false or the rule is exhausted.
+func (r Rule) each(dtstart time.Time, loc *time.Location, fn func(time.Time) bool) {
+	local := dtstart.In(loc)
+	hour, minute, sec := local.Clock()
+	interval := r.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+	days := r.ByDay
+	if len(days) == 0 && r.Freq == FreqWeekly {
+		days = []time.Weekday{local.Weekday()}
+	}
+	allowed := map[time.Weekday]bool{}
+	for _, wd := range days {
+		allowed[wd] = true
+	}
+
+	// Day-by-day walk on calendar dates; wall-clock time is rebuilt per day.
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	weekStart := day.AddDate(0, 0, -weekdayIndex(day.Weekday()))
+	emitted := 0
+	for i := 0; emitted < maxOccurrences && i < maxOccurrences*7*interval; i++ {
+		cur := day.AddDate(0, 0, i)
+		if !r.dayMatches(cur, day, weekStart, interval, allowed) {
+			continue
+		}
+		occ := time.Date(cur.Year(), cur.Month(), cur.Day(), hour, minute, sec, 0, loc).UTC()
+		if occ.Before(dtstart.UTC()) {
+			continue
+		}
+		if !r.Until.IsZero() && occ.After(r.Until) {
+			return
+		}
+		if !fn(occ) {
+			return
+		}
+		emitted++
+		if r.Count > 0 && emitted >= r.Count {
+			return
+		}
+	}
+}
+
+func (r Rule) dayMatches(cur, first, weekStart time.Time, interval int, allowed map[time.Weekday]bool) bool {
+	switch r.Freq {
+	case FreqDaily:
+		if int(cur.Sub(first).Hours()/24)%interval != 0 {
+			return false
+		}
+		return len(allowed) == 0 || allowed[cur.Weekday()]
+	case FreqWeekly:
+		week := int(cur.Sub(weekStart).Hours()/24) / 7
+		if week%interval != 0 {
+			return false
+		}
+		return allowed[cur.Weekday()]
+	}
+	return false
+}
+
+// ShiftDays moves every BYDAY entry by delta days (used when a series is moved to another weekday).
+func (r Rule) ShiftDays(delta int) Rule {
+	if len(r.ByDay) == 0 || delta%7 == 0 {
+		return r
+	}
+	shifted := make([]time.Weekday, 0, len(r.ByDay))
+	for _, wd := range r.ByDay {
+		shifted = append(shifted, time.Weekday(((int(wd)+delta)%7+7)%7))
+	}
+	sort.Slice(shifted, func(i, j int) bool { return weekdayIndex(shifted[i]) < weekdayIndex(shifted[j]) })
+	r.ByDay = shifted
+	return r
+}
+
+// weekdayIndex returns 0 for Monday through 6 for Sunday (WKST=MO).
+func weekdayIndex(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+func weekdayCode(wd time.Weekday) string {
+	for code, d := range weekdayCodes {
+		if d == wd {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,160 @@
+package appointments
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseRule(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Zagreb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid := []string{
+		"",
+		"RRULE:",
+		"FREQWEEKLY",
+		"INTERVAL=2",
+		"FREQ=MONTHLY",
+		"FREQ=WEEKLY;INTERVAL=0",
+		"FREQ=WEEKLY;COUNT=x",
+		"FREQ=WEEKLY;COUNT=-1",
+		"FREQ=WEEKLY;BYDAY=MO,XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;UNTIL=2026-03-01",
+		"FREQ=WEEKLY;COUNT=3;UNTIL=20260301",
+		"FREQ=WEEKLY;WKST=SU",
+		"FREQ=WEEKLY;BYMONTH=3",
+	}
+	for _, val := range invalid {
+		if _, err := ParseRule(val, loc); err == nil {
+			t.Errorf("ParseRule(%q): expected an error", val)
+		}
+	}
+
+	tests := []struct {
+		in    string
+		want  string
+		until string // in Europe/Zagreb, empty when unset
+	}{
+		{"RRULE:freq=weekly;byday=fr,mo,mo;interval=2;count=4", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=4", ""},
+		{"FREQ=DAILY;;UNTIL=20260305", "FREQ=DAILY;UNTIL=20260305T225959Z", "2026-03-05 23:59:59 CET"},
+		{"FREQ=WEEKLY;UNTIL=20260701T080000", "FREQ=WEEKLY;UNTIL=20260701T060000Z", "2026-07-01 08:00:00 CEST"},
+		{"FREQ=WEEKLY;UNTIL=20260701T080000Z;WKST=MO", "FREQ=WEEKLY;UNTIL=20260701T080000Z", "2026-07-01 10:00:00 CEST"},
+	}
+	for _, tt := range tests {
+		r, err := ParseRule(tt.in, loc)
+		if err != nil {
+			t.Errorf("ParseRule(%q): %v", tt.in, err)
+			continue
+		}
+		if r.String() != tt.want {
+			t.Errorf("ParseRule(%q).String() = %q, want %q", tt.in, r.String(), tt.want)
+		}
+		if tt.until != "" {
+			if got := r.Until.In(loc).Format("2006-01-02 15:04:05 MST"); got != tt.until {
+				t.Errorf("ParseRule(%q).Until = %s, want %s", tt.in, got, tt.until)
+			}
+		}
+	}
+}
+
+func TestRuleOccurrences(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Zagreb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		want    []string // occurrence starts as "2006-01-02 15:04 MST" in Europe/Zagreb
+	}{
+		{
+			name:    "count limits the series",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+			dtstart: time.Date(2026, 3, 2, 8, 30, 0, 0, loc),
+			want:    []string{"2026-03-02 08:30 CET", "2026-03-04 08:30 CET", "2026-03-09 08:30 CET", "2026-03-11 08:30 CET"},
+		},
+		{
+			name:    "date-only until includes its whole day",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20260309",
+			dtstart: time.Date(2026, 3, 2, 8, 30, 0, 0, loc),
+			want:    []string{"2026-03-02 08:30 CET", "2026-03-04 08:30 CET", "2026-03-09 08:30 CET"},
+		},
+		{
+			name:    "until before the next occurrence stops it",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20260309T070000Z",
+			dtstart: time.Date(2026, 3, 2, 8, 30, 0, 0, loc),
+			want:    []string{"2026-03-02 08:30 CET", "2026-03-04 08:30 CET"},
+		},
+		{
+			name:    "biweekly skips every other week",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=4",
+			dtstart: time.Date(2026, 3, 2, 8, 30, 0, 0, loc),
+			want:    []string{"2026-03-02 08:30 CET", "2026-03-06 08:30 CET", "2026-03-16 08:30 CET", "2026-03-20 08:30 CET"},
+		},
+		{
+			name:    "dtstart between byday entries starts at the next match",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=2",
+			dtstart: time.Date(2026, 3, 3, 8, 30, 0, 0, loc),
+			want:    []string{"2026-03-04 08:30 CET", "2026-03-09 08:30 CET"},
+		},
+		{
+			name:    "spring forward keeps the wall-clock time",
+			rule:    "FREQ=WEEKLY;COUNT=3",
+			dtstart: time.Date(2026, 3, 23, 8, 30, 0, 0, loc),
+			want:    []string{"2026-03-23 08:30 CET", "2026-03-30 08:30 CEST", "2026-04-06 08:30 CEST"},
+		},
+		{
+			name:    "fall back keeps the wall-clock time",
+			rule:    "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			dtstart: time.Date(2026, 10, 23, 17, 0, 0, 0, loc),
+			want:    []string{"2026-10-23 17:00 CEST", "2026-10-25 17:00 CET", "2026-10-27 17:00 CET"},
+		},
+	}
+	for _, tt := range tests {
+		r, err := ParseRule(tt.rule, loc)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		from, to := tt.dtstart.AddDate(0, 0, -7), tt.dtstart.AddDate(1, 0, 0)
+		var got []string
+		for _, occ := range r.Occurrences(tt.dtstart, loc, from, to) {
+			got = append(got, occ.In(loc).Format("2006-01-02 15:04 MST"))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got  %v\n want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRuleHelpers(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Zagreb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dtstart := time.Date(2026, 3, 23, 8, 30, 0, 0, loc)
+	r, err := ParseRule("FREQ=WEEKLY;BYDAY=MO", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	afterDST := time.Date(2026, 3, 30, 8, 30, 0, 0, loc)
+	if !r.IsOccurrence(dtstart, loc, afterDST) {
+		t.Errorf("IsOccurrence(%s) = false, want true", afterDST)
+	}
+	if shifted := afterDST.Add(time.Hour); r.IsOccurrence(dtstart, loc, shifted) {
+		t.Errorf("IsOccurrence(%s) = true, want false", shifted)
+	}
+	if n := r.CountBefore(dtstart, loc, time.Date(2026, 4, 13, 0, 0, 0, 0, loc)); n != 3 {
+		t.Errorf("CountBefore = %d, want 3", n)
+	}
+	if n := len(r.First(dtstart, loc, maxOccurrences+100)); n != maxOccurrences {
+		t.Errorf("open-ended rule expanded to %d occurrences, want the cap of %d", n, maxOccurrences)
+	}
+	if got := r.ShiftDays(-1).String(); got != "FREQ=WEEKLY;BYDAY=SU" {
+		t.Errorf("ShiftDays(-1) = %q, want FREQ=WEEKLY;BYDAY=SU", got)
+	}
+}
@@ -0,0 +1,535 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	SeriesStatusActive    = "active"
+	SeriesStatusCancelled = "cancelled"
+
+	ScopeThis      = "this"
+	ScopeFollowing = "following"
+)
+
+func (s *service) CreateSeries(ctx context.Context, doctorUUID string, req *pb.CreateAppointmentSeriesRequest) (*pb.AppointmentSeriesResponse, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(req.GetPatientUuid()) == "" || req.GetStartsAt() == nil {
+		return nil, fmt.Errorf("create appointment series: %w", se.ErrInvalidRequest)
+	}
+	if req.GetDurationMinutes() <= 0 || req.GetDurationMinutes() > 24*60 {
+		return nil, fmt.Errorf("create appointment series: invalid duration: %w", se.ErrInvalidRequest)
+	}
+	patientUUID := strings.TrimSpace(req.GetPatientUuid())
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("create appointment series: %w", err)
+	}
+	tz := strings.TrimSpace(req.GetTimezone())
+	if tz == "" {
+		tz = svcdoctorprofiles.DefaultTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("create appointment series: unknown timezone %q: %w", tz, se.ErrInvalidRequest)
+	}
+	rule, err := ParseRule(req.GetRrule(), loc)
+	if err != nil {
+		return nil, fmt.Errorf("create appointment series: %v: %w", err, se.ErrInvalidRequest)
+	}
+	start := req.GetStartsAt().AsTime().UTC()
+	if !rule.IsOccurrence(start, loc, start) {
+		return nil, fmt.Errorf("create appointment series: start does not match the rule: %w", se.ErrInvalidRequest)
+	}
+	resources, err := s.ensureResources(ctx, doctorUUID, req.GetResourceUuids())
+	if err != nil {
+		return nil, fmt.Errorf("create appointment series: %w", err)
+	}
+	duration := time.Duration(req.GetDurationMinutes()) * time.Minute
+	conflicts, err := s.guardConflicts(ctx, doctorUUID, seriesSlots(rule, start, loc, duration), resources, nil, req.GetAllowConflicts())
+	if err != nil {
+		return nil, fmt.Errorf("create appointment series: %w", err)
+	}
+
+	now := s.now().UTC()
+	ser := &pb.AppointmentSeries{
+		Uuid:            uuid.NewString(),
+		DoctorUuid:      doctorUUID,
+		PatientUuid:     patientUUID,
+		Rrule:           rule.String(),
+		StartsAt:        timestamppb.New(start),
+		DurationMinutes: req.GetDurationMinutes(),
+		Timezone:        tz,
+		Note:            strings.TrimSpace(req.GetNote()),
+		Status:          SeriesStatusActive,
+		CreatedAt:       timestamppb.New(now),
+		ResourceUuids:   resources,
+	}
+	created, err := s.seriesRepo.Create(ctx, ser)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("create appointment series: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("create appointment series: %w", err)
+	}
+	return &pb.AppointmentSeriesResponse{Series: created, Conflicts: conflicts}, nil
+}
+
+func (s *service) ListSeries(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.AppointmentSeries, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(patientUUID) == "" {
+		return nil, fmt.Errorf("list appointment series: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.seriesRepo.ListByPatient(ctx, doctorUUID, patientUUID)
+	if err != nil {
+		return nil, fmt.Errorf("list appointment series: %w", err)
+	}
+	return list, nil
+}
+
+// CancelSeries cancels every occurrence from now on; past occurrences stay untouched.
+func (s *service) CancelSeries(ctx context.Context, doctorUUID, patientUUID, seriesUUID, reason string) (*pb.AppointmentSeries, error) {
+	ser, rule, loc, err := s.loadSeries(ctx, doctorUUID, patientUUID, seriesUUID)
+	if err != nil {
+		return nil, fmt.Errorf("cancel appointment series: %w", err)
+	}
+	now := s.now().UTC()
+	s.truncateSeries(ser, rule, loc, now)
+	updated, err := s.seriesRepo.Truncate(ctx, ser, now, strings.TrimSpace(reason))
+	if err != nil {
+		return nil, fmt.Errorf("cancel appointment series: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *service) UpdateOccurrence(ctx context.Context, doctorUUID, patientUUID string, req *pb.UpdateOccurrenceRequest) (*pb.AppointmentSeriesResponse, error) {
+	if req.GetOriginalStartsAt() == nil {
+		return nil, fmt.Errorf("update occurrence: %w", se.ErrInvalidRequest)
+	}
+	ser, rule, loc, err := s.loadSeries(ctx, doctorUUID, patientUUID, req.GetSeriesUuid())
+	if err != nil {
+		return nil, fmt.Errorf("update occurrence: %w", err)
+	}
+	orig := req.GetOriginalStartsAt().AsTime().UTC()
+	if !rule.IsOccurrence(ser.GetStartsAt().AsTime(), loc, orig) {
+		return nil, fmt.Errorf("update occurrence: no occurrence at %s: %w", orig.Format(time.RFC3339), se.ErrNotFound)
+	}
+
+	switch scope(req.GetScope()) {
+	case ScopeThis:
+		appt, conflicts, err := s.updateSingleOccurrence(ctx, ser, orig, req)
+		if err != nil {
+			return nil, fmt.Errorf("update occurrence: %w", err)
+		}
+		return &pb.AppointmentSeriesResponse{Series: ser, Appointment: appt, Conflicts: conflicts}, nil
+	case ScopeFollowing:
+		next, conflicts, err := s.splitSeries(ctx, ser, rule, loc, orig, req)
+		if err != nil {
+			return nil, fmt.Errorf("update occurrence: %w", err)
+		}
+		return &pb.AppointmentSeriesResponse{Series: next, Conflicts: conflicts}, nil
+	default:
+		return nil, fmt.Errorf("update occurrence: invalid scope: %w", se.ErrInvalidRequest)
+	}
+}
+
+func (s *service) CancelOccurrence(ctx context.Context, doctorUUID, patientUUID string, req *pb.CancelOccurrenceRequest) (*pb.AppointmentSeriesResponse, error) {
+	if req.GetOriginalStartsAt() == nil {
+		return nil, fmt.Errorf("cancel occurrence: %w", se.ErrInvalidRequest)
+	}
+	ser, rule, loc, err := s.loadSeries(ctx, doctorUUID, patientUUID, req.GetSeriesUuid())
+	if err != nil {
+		return nil, fmt.Errorf("cancel occurrence: %w", err)
+	}
+	orig := req.GetOriginalStartsAt().AsTime().UTC()
+	if !rule.IsOccurrence(ser.GetStartsAt().AsTime(), loc, orig) {
+		return nil, fmt.Errorf("cancel occurrence: no occurrence at %s: %w", orig.Format(time.RFC3339), se.ErrNotFound)
+	}
+	reason := strings.TrimSpace(req.GetReason())
+
+	switch scope(req.GetScope()) {
+	case ScopeThis:
+		at := s.now().UTC()
+		// Validated before the occurrence is materialized; a moved occurrence is judged by its actual start below.
+		if _, err := cancelOutcome(req.GetCancelledBy(), orig, at); err != nil {
+			return nil, fmt.Errorf("cancel occurrence: %w", err)
+		}
+		appt, err := s.materializeOccurrence(ctx, ser, orig)
+		if err != nil {
+			return nil, fmt.Errorf("cancel occurrence: %w", err)
+		}
+		if appt.GetStatus() != StatusCancelled {
+			outcome, _ := cancelOutcome(req.GetCancelledBy(), appt.GetStartsAt().AsTime(), at)
+			now := timestamppb.New(at)
+			appt.Status = StatusCancelled
+			appt.CancelReason = reason
+			appt.CancelledAt = now
+			appt.UpdatedAt = now
+			appt.Outcome = outcome
+			appt.OutcomeAt = now
+			appt.OutcomeReason = reason
+			if appt, err = s.repo.Update(ctx, appt); err != nil {
+				return nil, fmt.Errorf("cancel occurrence: %w", err)
+			}
+			s.slotFreed(ctx, appt)
+		}
+		return &pb.AppointmentSeriesResponse{Series: ser, Appointment: appt}, nil
+	case ScopeFollowing:
+		s.truncateSeries(ser, rule, loc, orig)
+		updated, err := s.seriesRepo.Truncate(ctx, ser, orig, reason)
+		if err != nil {
+			return nil, fmt.Errorf("cancel occurrence: %w", err)
+		}
+		return &pb.AppointmentSeriesResponse{Series: updated}, nil
+	default:
+		return nil, fmt.Errorf("cancel occurrence: invalid scope: %w", se.ErrInvalidRequest)
+	}
+}
+
+// updateSingleOccurrence edits one occurrence; the exception row is only stored once the change passed the conflict check.
+func (s *service) updateSingleOccurrence(ctx context.Context, ser *pb.AppointmentSeries, orig time.Time, req *pb.UpdateOccurrenceRequest) (*pb.Appointment, []*pb.ScheduleConflict, error) {
+	appt, err := s.repo.GetOccurrence(ctx, ser.GetUuid(), orig)
+	if err != nil {
+		if !errors.Is(err, re.ErrNotFound) && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("load occurrence: %w", err)
+		}
+		appt = virtualOccurrence(ser, orig)
+	}
+	if appt.GetStatus() == StatusCancelled {
+		return nil, nil, fmt.Errorf("occurrence is cancelled: %w", se.ErrConflict)
+	}
+	start, end := appt.GetStartsAt().AsTime(), appt.GetEndsAt().AsTime()
+	if req.StartsAt != nil {
+		dur := end.Sub(start)
+		start = req.GetStartsAt().AsTime().UTC()
+		end = start.Add(dur)
+	}
+	if req.EndsAt != nil {
+		end = req.GetEndsAt().AsTime().UTC()
+	}
+	if !end.After(start) {
+		return nil, nil, fmt.Errorf("end must be after start: %w", se.ErrInvalidRequest)
+	}
+	var conflicts []*pb.ScheduleConflict
+	if req.StartsAt != nil || req.EndsAt != nil {
+		self := func(a *pb.Appointment) bool {
+			return a.GetSeriesUuid() == ser.GetUuid() && a.GetOriginalStartsAt().AsTime().Equal(orig)
+		}
+		if conflicts, err = s.guardConflicts(ctx, ser.GetDoctorUuid(), []slot{{start, end}}, appt.GetResourceUuids(), self, req.GetAllowConflicts()); err != nil {
+			return nil, nil, err
+		}
+	}
+	appt.StartsAt = timestamppb.New(start)
+	appt.EndsAt = timestamppb.New(end)
+	if req.Note != nil {
+		appt.Note = strings.TrimSpace(req.GetNote().GetValue())
+	}
+	now := timestamppb.New(s.now().UTC())
+	if appt.GetUuid() == "" {
+		appt.Uuid = uuid.NewString()
+		appt.CreatedAt = now
+		appt, err = s.repo.Create(ctx, appt)
+	} else {
+		appt.UpdatedAt = now
+		appt, err = s.repo.Update(ctx, appt)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return appt, conflicts, nil
+}
+
+// splitSeries ends the current series before orig and starts a new one carrying the change.
+func (s *service) splitSeries(ctx context.Context, ser *pb.AppointmentSeries, rule Rule, loc *time.Location, orig time.Time, req *pb.UpdateOccurrenceRequest) (*pb.AppointmentSeries, []*pb.ScheduleConflict, error) {
+	dtstart := ser.GetStartsAt().AsTime()
+	before := rule.CountBefore(dtstart, loc, orig)
+
+	newStart := orig
+	if req.StartsAt != nil {
+		newStart = req.GetStartsAt().AsTime().UTC()
+	}
+	duration := time.Duration(ser.GetDurationMinutes()) * time.Minute
+	if req.EndsAt != nil {
+		duration = req.GetEndsAt().AsTime().UTC().Sub(newStart)
+	}
+	if duration <= 0 || duration > 24*time.Hour || duration%time.Minute != 0 {
+		return nil, nil, fmt.Errorf("invalid duration: %w", se.ErrInvalidRequest)
+	}
+
+	var nextRule Rule
+	if strings.TrimSpace(req.GetRrule()) != "" {
+		parsed, err := ParseRule(req.GetRrule(), loc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v: %w", err, se.ErrInvalidRequest)
+		}
+		nextRule = parsed
+	} else {
+		nextRule = rule
+		// The new series gets the occurrences the old one had left; exceptions among them
+		// are carried over by index below.
+		if rule.Count > 0 {
+			nextRule.Count = rule.Count - before
+		}
+		nextRule = nextRule.ShiftDays(localDayDelta(orig, newStart, loc))
+	}
+	if !nextRule.IsOccurrence(newStart, loc, newStart) {
+		return nil, nil, fmt.Errorf("new start does not match the rule: %w", se.ErrInvalidRequest)
+	}
+	// Occurrences from orig on are replaced by the new series, so they cannot conflict with it.
+	replaced := func(a *pb.Appointment) bool {
+		return a.GetSeriesUuid() == ser.GetUuid() && !a.GetOriginalStartsAt().AsTime().Before(orig)
+	}
+	conflicts, err := s.guardConflicts(ctx, ser.GetDoctorUuid(), seriesSlots(nextRule, newStart, loc, duration), ser.GetResourceUuids(), replaced, req.GetAllowConflicts())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := s.now().UTC()
+	next := &pb.AppointmentSeries{
+		Uuid:            uuid.NewString(),
+		DoctorUuid:      ser.GetDoctorUuid(),
+		PatientUuid:     ser.GetPatientUuid(),
+		Rrule:           nextRule.String(),
+		StartsAt:        timestamppb.New(newStart),
+		DurationMinutes: int32(duration / time.Minute),
+		Timezone:        ser.GetTimezone(),
+		Note:            ser.GetNote(),
+		Status:          SeriesStatusActive,
+		CreatedAt:       timestamppb.New(now),
+		ResourceUuids:   ser.GetResourceUuids(),
+	}
+	if req.Note != nil {
+		next.Note = strings.TrimSpace(req.GetNote().GetValue())
+	}
+	carried, err := s.carryExceptions(ctx, ser, rule, loc, orig, next, nextRule)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.truncateSeries(ser, rule, loc, orig)
+	created, err := s.seriesRepo.Split(ctx, ser, next, carried)
+	if err != nil {
+		return nil, nil, err
+	}
+	return created, conflicts, nil
+}
+
+// carryExceptions maps stored exceptions from orig on onto the new series by occurrence index:
+// the k-th remaining occurrence of the old series becomes the k-th occurrence of the new one.
+// Moved occurrences keep their time, cancelled ones stay cancelled and visit-note links are kept.
+// A changed occurrence the new rule no longer produces is rejected so the user resolves it first.
+func (s *service) carryExceptions(ctx context.Context, ser *pb.AppointmentSeries, rule Rule, loc *time.Location, orig time.Time, next *pb.AppointmentSeries, nextRule Rule) ([]*pb.Appointment, error) {
+	// Exceptions are keyed by their original start, which never lies before the series start.
+	exceptions, err := s.repo.ListExceptions(ctx, []string{ser.GetUuid()}, orig, orig.AddDate(100, 0, 0))
+	if err != nil {
+		return nil, fmt.Errorf("load exceptions: %w", err)
+	}
+	if len(exceptions) == 0 {
+		return nil, nil
+	}
+	dtstart := ser.GetStartsAt().AsTime()
+	before := rule.CountBefore(dtstart, loc, orig)
+	indexes := make([]int, len(exceptions))
+	maxIdx := 0
+	for i, e := range exceptions {
+		indexes[i] = rule.CountBefore(dtstart, loc, e.GetOriginalStartsAt().AsTime()) - before
+		if indexes[i] > maxIdx {
+			maxIdx = indexes[i]
+		}
+	}
+	nextOccs := nextRule.First(next.GetStartsAt().AsTime(), loc, maxIdx+1)
+	duration := time.Duration(next.GetDurationMinutes()) * time.Minute
+	now := timestamppb.New(s.now().UTC())
+
+	var carried []*pb.Appointment
+	unmapped := 0
+	for i, e := range exceptions {
+		idx := indexes[i]
+		cancelled := e.GetStatus() == StatusCancelled
+		if idx == 0 && cancelled {
+			return nil, fmt.Errorf("occurrence is cancelled: %w", se.ErrConflict)
+		}
+		if idx >= len(nextOccs) {
+			// A cancelled occurrence past the new rule simply stays with the old series.
+			if !cancelled {
+				unmapped++
+			}
+			continue
+		}
+		occ := nextOccs[idx]
+		moved := !e.GetStartsAt().AsTime().Equal(e.GetOriginalStartsAt().AsTime())
+		if idx == 0 || !moved {
+			e.StartsAt = timestamppb.New(occ)
+			e.EndsAt = timestamppb.New(occ.Add(duration))
+		}
+		e.SeriesUuid = next.GetUuid()
+		e.OriginalStartsAt = timestamppb.New(occ)
+		e.UpdatedAt = now
+		carried = append(carried, e)
+	}
+	if unmapped > 0 {
+		return nil, fmt.Errorf("%d changed occurrence(s) from %s on do not fit the new rule, move or cancel them first: %w",
+			unmapped, formatLocal(orig, loc), se.ErrConflict)
+	}
+	return carried, nil
+}
+
+// materializeOccurrence returns the stored exception for orig, creating it from the series if needed.
+func (s *service) materializeOccurrence(ctx context.Context, ser *pb.AppointmentSeries, orig time.Time) (*pb.Appointment, error) {
+	existing, err := s.repo.GetOccurrence(ctx, ser.GetUuid(), orig)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, re.ErrNotFound) && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("load occurrence: %w", err)
+	}
+	appt := virtualOccurrence(ser, orig)
+	appt.Uuid = uuid.NewString()
+	appt.CreatedAt = timestamppb.New(s.now().UTC())
+	return s.repo.Create(ctx, appt)
+}
+
+// expandSeries returns the virtual occurrences of active series overlapping [from, to),
+// skipping dates that already have a stored exception.
+func (s *service) expandSeries(ctx context.Context, doctorUUID, patientUUID string, from, to time.Time) ([]*pb.Appointment, error) {
+	seriesList, err := s.seriesRepo.ListActive(ctx, doctorUUID, patientUUID, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(seriesList) == 0 {
+		return nil, nil
+	}
+	uuids := make([]string, 0, len(seriesList))
+	var maxDur time.Duration
+	for _, ser := range seriesList {
+		uuids = append(uuids, ser.GetUuid())
+		if d := time.Duration(ser.GetDurationMinutes()) * time.Minute; d > maxDur {
+			maxDur = d
+		}
+	}
+	exceptions, err := s.repo.ListExceptions(ctx, uuids, from.Add(-maxDur), to)
+	if err != nil {
+		return nil, err
+	}
+	overridden := make(map[string]bool, len(exceptions))
+	for _, e := range exceptions {
+		overridden[occurrenceKey(e.GetSeriesUuid(), e.GetOriginalStartsAt().AsTime())] = true
+	}
+
+	var res []*pb.Appointment
+	for _, ser := range seriesList {
+		loc, err := time.LoadLocation(ser.GetTimezone())
+		if err != nil {
+			loc = time.UTC
+		}
+		rule, err := ParseRule(ser.GetRrule(), loc)
+		if err != nil {
+			// A stored rule that no longer parses must not break the whole calendar.
+			continue
+		}
+		dur := time.Duration(ser.GetDurationMinutes()) * time.Minute
+		for _, occ := range rule.Occurrences(ser.GetStartsAt().AsTime(), loc, from.Add(-dur), to) {
+			if !occ.Add(dur).After(from) || overridden[occurrenceKey(ser.GetUuid(), occ)] {
+				continue
+			}
+			res = append(res, virtualOccurrence(ser, occ))
+		}
+	}
+	return res, nil
+}
+
+func (s *service) loadSeries(ctx context.Context, doctorUUID, patientUUID, seriesUUID string) (*pb.AppointmentSeries, Rule, *time.Location, error) {
+	if strings.TrimSpace(seriesUUID) == "" {
+		return nil, Rule{}, nil, se.ErrInvalidRequest
+	}
+	ser, err := s.seriesRepo.Get(ctx, seriesUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, Rule{}, nil, se.ErrNotFound
+		}
+		return nil, Rule{}, nil, fmt.Errorf("load appointment series: %w", err)
+	}
+	if ser.GetDoctorUuid() != doctorUUID || (patientUUID != "" && ser.GetPatientUuid() != patientUUID) {
+		return nil, Rule{}, nil, se.ErrNotFound
+	}
+	if ser.GetStatus() != SeriesStatusActive {
+		return nil, Rule{}, nil, fmt.Errorf("series is cancelled: %w", se.ErrConflict)
+	}
+	loc, err := time.LoadLocation(ser.GetTimezone())
+	if err != nil {
+		return nil, Rule{}, nil, fmt.Errorf("series timezone: %w", err)
+	}
+	rule, err := ParseRule(ser.GetRrule(), loc)
+	if err != nil {
+		return nil, Rule{}, nil, fmt.Errorf("series rule: %w", err)
+	}
+	return ser, rule, loc, nil
+}
+
+// truncateSeries shortens the series so its last occurrence starts before at.
+// A series without any earlier occurrence is cancelled altogether.
+func (s *service) truncateSeries(ser *pb.AppointmentSeries, rule Rule, loc *time.Location, at time.Time) {
+	before := rule.CountBefore(ser.GetStartsAt().AsTime(), loc, at)
+	ser.UpdatedAt = timestamppb.New(s.now().UTC())
+	if before == 0 {
+		ser.Status = SeriesStatusCancelled
+		return
+	}
+	if rule.Count > 0 {
+		rule.Count = before
+	} else {
+		rule.Until = at.Add(-time.Second).UTC()
+	}
+	ser.Rrule = rule.String()
+}
+
+func virtualOccurrence(ser *pb.AppointmentSeries, start time.Time) *pb.Appointment {
+	dur := time.Duration(ser.GetDurationMinutes()) * time.Minute
+	return &pb.Appointment{
+		DoctorUuid:       ser.GetDoctorUuid(),
+		PatientUuid:      ser.GetPatientUuid(),
+		StartsAt:         timestamppb.New(start),
+		EndsAt:           timestamppb.New(start.Add(dur)),
+		Status:           StatusScheduled,
+		Note:             ser.GetNote(),
+		SeriesUuid:       ser.GetUuid(),
+		OriginalStartsAt: timestamppb.New(start),
+		ResourceUuids:    ser.GetResourceUuids(),
+	}
+}
+
+func occurrenceKey(seriesUUID string, start time.Time) string {
+	return fmt.Sprintf("%s|%d", seriesUUID, start.UTC().Unix())
+}
+
+func scope(val string) string {
+	v := strings.ToLower(strings.TrimSpace(val))
+	if v == "" {
+		return ScopeThis
+	}
+	return v
+}
+
+// localDayDelta returns the number of calendar days between a and b in loc.
+func localDayDelta(a, b time.Time, loc *time.Location) int {
+	la, lb := a.In(loc), b.In(loc)
+	da := time.Date(la.Year(), la.Month(), la.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(lb.Year(), lb.Month(), lb.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+func sortByStart(list []*pb.Appointment) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].GetStartsAt().AsTime().Before(list[j].GetStartsAt().AsTime())
+	})
+}
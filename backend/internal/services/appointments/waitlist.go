@@ -0,0 +1,437 @@
+package appointments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	EntryStatusWaiting = "waiting"
+	EntryStatusOffered = "offered"
+	EntryStatusBooked  = "booked"
+	EntryStatusRemoved = "removed"
+
+	OfferStatusPending   = "pending"
+	OfferStatusAccepted  = "accepted"
+	OfferStatusDeclined  = "declined"
+	OfferStatusExpired   = "expired"
+	OfferStatusWithdrawn = "withdrawn"
+)
+
+// defaultOfferTTL is how long a patient has to answer an offer; WAITLIST_OFFER_TTL overrides it (e.g. "90m").
+const defaultOfferTTL = 2 * time.Hour
+
+func offerTTLFromEnv() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("WAITLIST_OFFER_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return defaultOfferTTL
+}
+
+func (s *service) CreateWaitlistEntry(ctx context.Context, doctorUUID string, req *pb.CreateWaitlistEntryRequest) (*pb.WaitlistEntry, error) {
+	if strings.TrimSpace(doctorUUID) == "" || req.GetWindowFrom() == nil || req.GetWindowTo() == nil {
+		return nil, fmt.Errorf("create waitlist entry: %w", se.ErrInvalidRequest)
+	}
+	from, to := req.GetWindowFrom().AsTime().UTC(), req.GetWindowTo().AsTime().UTC()
+	now := s.now().UTC()
+	if !to.After(from) || !to.After(now) {
+		return nil, fmt.Errorf("create waitlist entry: invalid window: %w", se.ErrInvalidRequest)
+	}
+	if req.GetDurationMinutes() <= 0 || req.GetDurationMinutes() > 24*60 {
+		return nil, fmt.Errorf("create waitlist entry: invalid duration: %w", se.ErrInvalidRequest)
+	}
+	dayStart, dayEnd := strings.TrimSpace(req.GetDayStart()), strings.TrimSpace(req.GetDayEnd())
+	if dayStart != "" || dayEnd != "" {
+		start, err := svcdoctorprofiles.ParseClock(dayStart)
+		if err != nil {
+			return nil, fmt.Errorf("create waitlist entry: %v: %w", err, se.ErrInvalidRequest)
+		}
+		end, err := svcdoctorprofiles.ParseClock(dayEnd)
+		if err != nil {
+			return nil, fmt.Errorf("create waitlist entry: %v: %w", err, se.ErrInvalidRequest)
+		}
+		if end <= start {
+			return nil, fmt.Errorf("create waitlist entry: day end must be after day start: %w", se.ErrInvalidRequest)
+		}
+	}
+	var weekdays []int32
+	seen := map[int32]bool{}
+	for _, wd := range req.GetWeekdays() {
+		if wd < 1 || wd > 7 {
+			return nil, fmt.Errorf("create waitlist entry: invalid weekday %d: %w", wd, se.ErrInvalidRequest)
+		}
+		if !seen[wd] {
+			seen[wd] = true
+			weekdays = append(weekdays, wd)
+		}
+	}
+	patientUUID := strings.TrimSpace(req.GetPatientUuid())
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("create waitlist entry: %w", err)
+	}
+
+	created, err := s.waitlistRepo.CreateEntry(ctx, &pb.WaitlistEntry{
+		Uuid:            uuid.NewString(),
+		DoctorUuid:      doctorUUID,
+		PatientUuid:     patientUUID,
+		WindowFrom:      timestamppb.New(from),
+		WindowTo:        timestamppb.New(to),
+		DayStart:        dayStart,
+		DayEnd:          dayEnd,
+		Weekdays:        weekdays,
+		DurationMinutes: req.GetDurationMinutes(),
+		Priority:        req.GetPriority(),
+		Status:          EntryStatusWaiting,
+		Note:            strings.TrimSpace(req.GetNote()),
+		CreatedAt:       timestamppb.New(now),
+	})
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("create waitlist entry: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("create waitlist entry: %w", err)
+	}
+	return created, nil
+}
+
+func (s *service) ListWaitlist(ctx context.Context, doctorUUID, status string) ([]*pb.WaitlistEntry, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("list waitlist: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.waitlistRepo.ListEntries(ctx, doctorUUID, strings.ToLower(strings.TrimSpace(status)))
+	if err != nil {
+		return nil, fmt.Errorf("list waitlist: %w", err)
+	}
+	return list, nil
+}
+
+// RemoveWaitlistEntry takes the patient off the list; an open offer is withdrawn and passed on.
+func (s *service) RemoveWaitlistEntry(ctx context.Context, doctorUUID, entryUUID string) error {
+	entry, err := s.loadEntry(ctx, doctorUUID, entryUUID)
+	if err != nil {
+		return fmt.Errorf("remove waitlist entry: %w", err)
+	}
+	if entry.GetStatus() == EntryStatusOffered {
+		offer, err := s.waitlistRepo.PendingOfferForEntry(ctx, entry.GetUuid())
+		if err != nil && !errors.Is(err, re.ErrNotFound) {
+			return fmt.Errorf("remove waitlist entry: %w", err)
+		}
+		if offer != nil {
+			if err := s.releaseOffer(ctx, offer, OfferStatusWithdrawn); err != nil {
+				return fmt.Errorf("remove waitlist entry: %w", err)
+			}
+		}
+	}
+	err = s.waitlistRepo.UpdateEntryStatus(ctx, entry.GetUuid(), EntryStatusRemoved,
+		[]string{EntryStatusWaiting, EntryStatusOffered}, s.now().UTC())
+	if err != nil {
+		if errors.Is(err, re.ErrConflict) {
+			return fmt.Errorf("remove waitlist entry: entry is %s: %w", entry.GetStatus(), se.ErrConflict)
+		}
+		return fmt.Errorf("remove waitlist entry: %w", err)
+	}
+	return nil
+}
+
+func (s *service) ListWaitlistOffers(ctx context.Context, doctorUUID, status string) ([]*pb.WaitlistOffer, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("list waitlist offers: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.waitlistRepo.ListOffers(ctx, doctorUUID, strings.ToLower(strings.TrimSpace(status)))
+	if err != nil {
+		return nil, fmt.Errorf("list waitlist offers: %w", err)
+	}
+	return list, nil
+}
+
+// AcceptWaitlistOffer books the offered slot. If the time was taken in the meantime the offer is
+// withdrawn and passed on, and the conflict is returned.
+func (s *service) AcceptWaitlistOffer(ctx context.Context, doctorUUID, offerUUID string) (*pb.WaitlistOfferResponse, error) {
+	offer, err := s.loadPendingOffer(ctx, doctorUUID, offerUUID)
+	if err != nil {
+		return nil, fmt.Errorf("accept waitlist offer: %w", err)
+	}
+	start, end := offer.GetStartsAt().AsTime(), offer.GetEndsAt().AsTime()
+	// The slot was booked before, so only a new overlapping booking can stand in the way.
+	conflicts, err := s.checkConflicts(ctx, doctorUUID, []slot{{start, end}}, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("accept waitlist offer: %w", err)
+	}
+	var overlaps []*pb.ScheduleConflict
+	for _, c := range conflicts {
+		if c.GetReason() == ConflictOverlap {
+			overlaps = append(overlaps, c)
+		}
+	}
+	if len(overlaps) > 0 {
+		if err := s.releaseOffer(ctx, offer, OfferStatusWithdrawn); err != nil {
+			return nil, fmt.Errorf("accept waitlist offer: %w", err)
+		}
+		return nil, fmt.Errorf("accept waitlist offer: %w", &ConflictError{Conflicts: overlaps})
+	}
+
+	now := s.now().UTC()
+	offer.Status = OfferStatusAccepted
+	offer.RespondedAt = timestamppb.New(now)
+	offer, appt, err := s.waitlistRepo.AcceptOffer(ctx, offer, &pb.Appointment{
+		Uuid:        uuid.NewString(),
+		DoctorUuid:  doctorUUID,
+		PatientUuid: offer.GetPatientUuid(),
+		StartsAt:    timestamppb.New(start),
+		EndsAt:      timestamppb.New(end),
+		Status:      StatusScheduled,
+		CreatedAt:   timestamppb.New(now),
+	})
+	if err != nil {
+		if errors.Is(err, re.ErrConflict) {
+			return nil, fmt.Errorf("accept waitlist offer: offer is no longer pending: %w", se.ErrConflict)
+		}
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("accept waitlist offer: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("accept waitlist offer: %w", err)
+	}
+	if err := s.waitlistRepo.UpdateEntryStatus(ctx, offer.GetEntryUuid(), EntryStatusBooked,
+		[]string{EntryStatusOffered, EntryStatusWaiting}, now); err != nil && !errors.Is(err, re.ErrConflict) {
+		return nil, fmt.Errorf("accept waitlist offer: %w", err)
+	}
+	return &pb.WaitlistOfferResponse{Offer: offer, Appointment: appt}, nil
+}
+
+// DeclineWaitlistOffer keeps the patient on the list and offers the slot to the next candidate.
+func (s *service) DeclineWaitlistOffer(ctx context.Context, doctorUUID, offerUUID string) (*pb.WaitlistOffer, error) {
+	offer, err := s.loadPendingOffer(ctx, doctorUUID, offerUUID)
+	if err != nil {
+		return nil, fmt.Errorf("decline waitlist offer: %w", err)
+	}
+	if err := s.releaseOffer(ctx, offer, OfferStatusDeclined); err != nil {
+		return nil, fmt.Errorf("decline waitlist offer: %w", err)
+	}
+	return offer, nil
+}
+
+// ExpireWaitlistOffers closes pending offers past their deadline and tries the next candidate.
+// It is run periodically by the background worker and returns the number of expired offers.
+func (s *service) ExpireWaitlistOffers(ctx context.Context) (int, error) {
+	offers, err := s.waitlistRepo.ListExpiredOffers(ctx, s.now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("expire waitlist offers: %w", err)
+	}
+	expired := 0
+	for _, offer := range offers {
+		if err := s.releaseOffer(ctx, offer, OfferStatusExpired); err != nil {
+			if errors.Is(err, re.ErrConflict) {
+				// Answered while we were looking at it.
+				continue
+			}
+			return expired, fmt.Errorf("expire waitlist offers: %w", err)
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// slotFreed is called after an appointment was cancelled. Offering is best effort: a failure is
+// logged and never undoes the cancellation.
+func (s *service) slotFreed(ctx context.Context, appt *pb.Appointment) {
+	start, end := appt.GetStartsAt().AsTime().UTC(), appt.GetEndsAt().AsTime().UTC()
+	if !start.After(s.now()) {
+		return
+	}
+	if err := s.offerSlot(ctx, appt.GetDoctorUuid(), start, end); err != nil {
+		log.Printf("waitlist: offering freed slot %s failed: %v", start.Format(time.RFC3339), err)
+	}
+}
+
+// releaseOffer closes a pending offer with status, puts the patient back on the list and
+// passes the slot on to the next candidate.
+func (s *service) releaseOffer(ctx context.Context, offer *pb.WaitlistOffer, status string) error {
+	now := s.now().UTC()
+	offer.Status = status
+	offer.RespondedAt = timestamppb.New(now)
+	if _, err := s.waitlistRepo.CloseOffer(ctx, offer); err != nil {
+		return err
+	}
+	if err := s.waitlistRepo.UpdateEntryStatus(ctx, offer.GetEntryUuid(), EntryStatusWaiting,
+		[]string{EntryStatusOffered}, now); err != nil && !errors.Is(err, re.ErrConflict) {
+		return err
+	}
+	if !offer.GetStartsAt().AsTime().After(now) {
+		return nil
+	}
+	return s.offerSlot(ctx, offer.GetDoctorUuid(), offer.GetStartsAt().AsTime(), offer.GetSlotEndsAt().AsTime())
+}
+
+// offerSlot creates a pending offer for the best waiting candidate that fits [start, slotEnd).
+// Patients already offered this slot, and patients who cancelled it, are skipped.
+func (s *service) offerSlot(ctx context.Context, doctorUUID string, start, slotEnd time.Time) error {
+	candidates, err := s.waitlistRepo.Candidates(ctx, doctorUUID, start, slotEnd)
+	if err != nil || len(candidates) == 0 {
+		return err
+	}
+	offered, err := s.waitlistRepo.OfferedEntries(ctx, doctorUUID, start)
+	if err != nil {
+		return err
+	}
+	existing, err := s.appointmentsInRange(ctx, doctorUUID, "", start, slotEnd)
+	if err != nil {
+		return err
+	}
+	cancelledBy := map[string]bool{}
+	var busy []slot
+	for _, a := range existing {
+		if a.GetStatus() == StatusCancelled {
+			if a.GetStartsAt().AsTime().Equal(start) {
+				cancelledBy[a.GetPatientUuid()] = true
+			}
+			continue
+		}
+		busy = append(busy, slot{a.GetStartsAt().AsTime(), a.GetEndsAt().AsTime()})
+	}
+	sched, err := s.profileRepo.GetSchedule(ctx, doctorUUID)
+	if err != nil {
+		if !errors.Is(err, re.ErrNotFound) && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		sched = nil
+	}
+	loc, err := svcdoctorprofiles.ScheduleLocation(sched)
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	for _, e := range candidates {
+		if offered[e.GetUuid()] || cancelledBy[e.GetPatientUuid()] {
+			continue
+		}
+		end := start.Add(time.Duration(e.GetDurationMinutes()) * time.Minute)
+		if !entryAccepts(e, loc, start, end) || overlapsAny(slot{start, end}, busy) {
+			continue
+		}
+		_, err := s.waitlistRepo.CreateOffer(ctx, &pb.WaitlistOffer{
+			Uuid:        uuid.NewString(),
+			DoctorUuid:  doctorUUID,
+			EntryUuid:   e.GetUuid(),
+			PatientUuid: e.GetPatientUuid(),
+			StartsAt:    timestamppb.New(start),
+			EndsAt:      timestamppb.New(end),
+			SlotEndsAt:  timestamppb.New(slotEnd),
+			Status:      OfferStatusPending,
+			ExpiresAt:   timestamppb.New(now.Add(s.offerTTL)),
+			CreatedAt:   timestamppb.New(now),
+		})
+		if err != nil {
+			if errors.Is(err, re.ErrConflict) {
+				continue
+			}
+			return err
+		}
+		if err := s.waitlistRepo.UpdateEntryStatus(ctx, e.GetUuid(), EntryStatusOffered,
+			[]string{EntryStatusWaiting}, now); err != nil && !errors.Is(err, re.ErrConflict) {
+			return err
+		}
+		return nil
+	}
+	return nil
+}
+
+// entryAccepts checks the entry's weekdays and daily time window in the schedule time zone.
+func entryAccepts(e *pb.WaitlistEntry, loc *time.Location, start, end time.Time) bool {
+	ls, le := start.In(loc), end.In(loc)
+	if len(e.GetWeekdays()) > 0 {
+		wd := int32(weekdayIndex(ls.Weekday()) + 1)
+		match := false
+		for _, d := range e.GetWeekdays() {
+			if d == wd {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	if e.GetDayStart() == "" {
+		return true
+	}
+	dayStart, err := svcdoctorprofiles.ParseClock(e.GetDayStart())
+	if err != nil {
+		return false
+	}
+	dayEnd, err := svcdoctorprofiles.ParseClock(e.GetDayEnd())
+	if err != nil {
+		return false
+	}
+	// The last minute of the slot must fall on the same local day as its start.
+	last := le.Add(-time.Minute)
+	if last.Year() != ls.Year() || last.YearDay() != ls.YearDay() {
+		return false
+	}
+	startMin := ls.Hour()*60 + ls.Minute()
+	endMin := last.Hour()*60 + last.Minute() + 1
+	return startMin >= dayStart && endMin <= dayEnd
+}
+
+func overlapsAny(sl slot, busy []slot) bool {
+	for _, b := range busy {
+		if b.start.Before(sl.end) && sl.start.Before(b.end) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *service) loadEntry(ctx context.Context, doctorUUID, entryUUID string) (*pb.WaitlistEntry, error) {
+	if strings.TrimSpace(entryUUID) == "" {
+		return nil, se.ErrInvalidRequest
+	}
+	entry, err := s.waitlistRepo.GetEntry(ctx, entryUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, se.ErrNotFound
+		}
+		return nil, err
+	}
+	if entry.GetDoctorUuid() != doctorUUID {
+		return nil, se.ErrNotFound
+	}
+	return entry, nil
+}
+
+func (s *service) loadPendingOffer(ctx context.Context, doctorUUID, offerUUID string) (*pb.WaitlistOffer, error) {
+	if strings.TrimSpace(offerUUID) == "" {
+		return nil, se.ErrInvalidRequest
+	}
+	offer, err := s.waitlistRepo.GetOffer(ctx, offerUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, se.ErrNotFound
+		}
+		return nil, err
+	}
+	if offer.GetDoctorUuid() != doctorUUID {
+		return nil, se.ErrNotFound
+	}
+	if offer.GetStatus() != OfferStatusPending {
+		return nil, fmt.Errorf("offer is %s: %w", offer.GetStatus(), se.ErrConflict)
+	}
+	if !offer.GetExpiresAt().AsTime().After(s.now()) {
+		return nil, fmt.Errorf("offer expired: %w", se.ErrConflict)
+	}
+	return offer, nil
+}
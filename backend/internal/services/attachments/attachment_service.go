@@ -0,0 +1,260 @@
+package attachments
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	outanamneses "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/anamneses"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/attachments"
+	outboundportpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcanamneses "github.com/OPetricevic/physio-tracker/backend/internal/services/anamneses"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// allowedTypes are the content types accepted for attachments, as detected from the first bytes of
+// the file; the extension and the type sent by the browser are not trusted.
+var allowedTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/webp":      true,
+}
+
+const (
+	maxFileNameLen    = 255
+	maxDescriptionLen = 1000
+)
+
+// Upload is one file sent by the doctor.
+type Upload struct {
+	PatientUUID   string
+	AnamnesisUUID string // empty for patient-level attachments
+	FileName      string
+	Description   string
+	Content       io.Reader
+}
+
+type Service interface {
+	Upload(ctx context.Context, doctorUUID string, in Upload) (*pb.Attachment, error)
+	// List returns the patient's attachments; anamnesisUUID, when set, keeps only that visit's.
+	List(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string) ([]*pb.Attachment, error)
+	// Open returns the attachment and its content; the caller closes the reader.
+	Open(ctx context.Context, doctorUUID, patientUUID, uuid string) (*pb.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, doctorUUID, patientUUID, uuid string) error
+}
+
+type service struct {
+	repo          out.Repository
+	store         out.Store
+	patientRepo   outboundportpatients.Repository
+	anamnesisRepo outanamneses.Repository
+
+	autoLockAfter time.Duration
+}
+
+func NewService(repo out.Repository, store out.Store, pRepo outboundportpatients.Repository, aRepo outanamneses.Repository) Service {
+	return &service{repo: repo, store: store, patientRepo: pRepo, anamnesisRepo: aRepo,
+		autoLockAfter: svcanamneses.AutoLockAfterFromEnv()}
+}
+
+func (s *service) Upload(ctx context.Context, doctorUUID string, in Upload) (*pb.Attachment, error) {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, in.PatientUUID); err != nil {
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+	if in.AnamnesisUUID != "" {
+		if err := s.ensureUnlockedVisit(ctx, in.PatientUUID, in.AnamnesisUUID); err != nil {
+			return nil, fmt.Errorf("upload attachment: %w", err)
+		}
+	}
+	name, err := cleanFileName(in.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+	description := strings.TrimSpace(in.Description)
+	if utf8.RuneCountInString(description) > maxDescriptionLen {
+		return nil, fmt.Errorf("upload attachment: description longer than %d characters: %w", maxDescriptionLen, se.ErrInvalidRequest)
+	}
+	content, contentType, err := sniff(in.Content)
+	if err != nil {
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+
+	a := &pb.Attachment{
+		Uuid:          uuid.NewString(),
+		DoctorUuid:    doctorUUID,
+		PatientUuid:   in.PatientUUID,
+		AnamnesisUuid: in.AnamnesisUUID,
+		FileName:      name,
+		ContentType:   contentType,
+		Description:   description,
+		CreatedAt:     timestamppb.New(time.Now().UTC()),
+	}
+	key := doctorUUID + "/" + a.GetUuid()
+	size, err := s.store.Save(ctx, key, content)
+	if err != nil {
+		if errors.Is(err, out.ErrTooLarge) {
+			return nil, fmt.Errorf("upload attachment: %v: %w", err, se.ErrInvalidRequest)
+		}
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+	a.SizeBytes = size
+	created, err := s.repo.Create(ctx, a, key)
+	if err != nil {
+		_ = s.store.Remove(ctx, key)
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("upload attachment: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("upload attachment: %w", err)
+	}
+	return created, nil
+}
+
+func (s *service) List(ctx context.Context, doctorUUID, patientUUID, anamnesisUUID string) ([]*pb.Attachment, error) {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("list attachments: %w", err)
+	}
+	if anamnesisUUID != "" {
+		if _, err := s.ensureVisit(ctx, patientUUID, anamnesisUUID); err != nil {
+			return nil, fmt.Errorf("list attachments: %w", err)
+		}
+	}
+	list, err := s.repo.List(ctx, patientUUID, anamnesisUUID)
+	if err != nil {
+		return nil, fmt.Errorf("list attachments: %w", err)
+	}
+	return list, nil
+}
+
+func (s *service) Open(ctx context.Context, doctorUUID, patientUUID, attachmentUUID string) (*pb.Attachment, io.ReadCloser, error) {
+	a, key, err := s.owned(ctx, doctorUUID, patientUUID, attachmentUUID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open attachment: %w", err)
+	}
+	rc, err := s.store.Open(ctx, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open attachment: %w", err)
+	}
+	return a, rc, nil
+}
+
+// Delete removes the attachment; the file is removed after the row so that a failure never leaves
+// a listed attachment without content. Attachments of a locked visit stay.
+func (s *service) Delete(ctx context.Context, doctorUUID, patientUUID, attachmentUUID string) error {
+	a, key, err := s.owned(ctx, doctorUUID, patientUUID, attachmentUUID)
+	if err != nil {
+		return fmt.Errorf("delete attachment: %w", err)
+	}
+	if a.GetAnamnesisUuid() != "" {
+		if err := s.ensureUnlockedVisit(ctx, patientUUID, a.GetAnamnesisUuid()); err != nil {
+			return fmt.Errorf("delete attachment: %w", err)
+		}
+	}
+	if err := s.repo.Delete(ctx, attachmentUUID); err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return fmt.Errorf("delete attachment: %w", se.ErrNotFound)
+		}
+		return fmt.Errorf("delete attachment: %w", err)
+	}
+	if err := s.store.Remove(ctx, key); err != nil {
+		return fmt.Errorf("delete attachment: %w", err)
+	}
+	return nil
+}
+
+// owned loads an attachment of one of the doctor's patients with the key of its content.
+func (s *service) owned(ctx context.Context, doctorUUID, patientUUID, attachmentUUID string) (*pb.Attachment, string, error) {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, "", err
+	}
+	if strings.TrimSpace(attachmentUUID) == "" {
+		return nil, "", se.ErrInvalidRequest
+	}
+	a, key, err := s.repo.Get(ctx, attachmentUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, "", se.ErrNotFound
+		}
+		return nil, "", err
+	}
+	if a.GetPatientUuid() != patientUUID || a.GetDoctorUuid() != doctorUUID {
+		return nil, "", se.ErrNotFound
+	}
+	return a, key, nil
+}
+
+func (s *service) ensureVisit(ctx context.Context, patientUUID, anamnesisUUID string) (*pb.Anamnesis, error) {
+	visit, err := s.anamnesisRepo.Get(ctx, anamnesisUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, se.ErrNotFound
+		}
+		return nil, fmt.Errorf("load anamnesis: %w", err)
+	}
+	if visit.GetPatientUuid() != patientUUID {
+		return nil, se.ErrNotFound
+	}
+	return visit, nil
+}
+
+// ensureUnlockedVisit also rejects a signed or auto-locked visit: its attachments are part of the
+// record and are corrected with addenda, like its text.
+func (s *service) ensureUnlockedVisit(ctx context.Context, patientUUID, anamnesisUUID string) error {
+	visit, err := s.ensureVisit(ctx, patientUUID, anamnesisUUID)
+	if err != nil {
+		return err
+	}
+	return svcanamneses.CheckUnlocked(visit, s.autoLockAfter, time.Now().UTC())
+}
+
+// sniff detects the content type from the first bytes and returns a reader that still yields the
+// whole content.
+func sniff(r io.Reader) (io.Reader, string, error) {
+	if r == nil {
+		return nil, "", fmt.Errorf("file is required: %w", se.ErrInvalidRequest)
+	}
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, "", fmt.Errorf("read file: %w", err)
+	}
+	if len(head) == 0 {
+		return nil, "", fmt.Errorf("empty file: %w", se.ErrInvalidRequest)
+	}
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if !allowedTypes[contentType] {
+		return nil, "", fmt.Errorf("unsupported file type %s, only PDF and PNG, JPEG or WebP images are accepted: %w", contentType, se.ErrInvalidRequest)
+	}
+	return br, contentType, nil
+}
+
+// cleanFileName keeps the base name the browser sent, without path or control characters.
+func cleanFileName(name string) (string, error) {
+	name = filepath.Base(strings.ReplaceAll(strings.TrimSpace(name), `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "", fmt.Errorf("file name is required: %w", se.ErrInvalidRequest)
+	}
+	if utf8.RuneCountInString(name) > maxFileNameLen {
+		return "", fmt.Errorf("file name longer than %d characters: %w", maxFileNameLen, se.ErrInvalidRequest)
+	}
+	return name, nil
+}
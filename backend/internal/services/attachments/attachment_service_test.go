@@ -0,0 +1,160 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	outanamneses "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/anamneses"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/attachments"
+	outboundportpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSniff(t *testing.T) {
+	pdf := "%PDF-1.7\n" + strings.Repeat("x", 2000)
+	r, contentType, err := sniff(strings.NewReader(pdf))
+	if err != nil || contentType != "application/pdf" {
+		t.Fatalf("got %q, %v; want application/pdf", contentType, err)
+	}
+	if b, _ := io.ReadAll(r); string(b) != pdf {
+		t.Errorf("content changed by sniffing: %d bytes, want %d", len(b), len(pdf))
+	}
+
+	for name, content := range map[string]string{
+		"html":  "<html><script>alert(1)</script></html>",
+		"text":  "just a note",
+		"empty": "",
+	} {
+		if _, _, err := sniff(strings.NewReader(content)); !errors.Is(err, se.ErrInvalidRequest) {
+			t.Errorf("%s: got %v, want ErrInvalidRequest", name, err)
+		}
+	}
+}
+
+func TestCleanFileName(t *testing.T) {
+	cases := map[string]string{
+		"MR koljena.pdf":               "MR koljena.pdf",
+		`C:\Users\ana\Desktop\rtg.jpg`: "rtg.jpg",
+		"../../etc/passwd":             "passwd",
+		"nalaz\"\r\n.pdf":              "nalaz.pdf",
+	}
+	for in, want := range cases {
+		if got, err := cleanFileName(in); err != nil || got != want {
+			t.Errorf("cleanFileName(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := cleanFileName("  "); !errors.Is(err, se.ErrInvalidRequest) {
+		t.Errorf("blank name: got %v, want ErrInvalidRequest", err)
+	}
+}
+
+type fakePatients struct {
+	outboundportpatients.Repository
+}
+
+func (fakePatients) Get(ctx context.Context, uuid string) (*pb.Patient, error) {
+	return &pb.Patient{Uuid: uuid, DoctorUuid: "doc"}, nil
+}
+
+type fakeVisits struct {
+	outanamneses.Repository
+	visits map[string]*pb.Anamnesis
+}
+
+func (f fakeVisits) Get(ctx context.Context, uuid string) (*pb.Anamnesis, error) {
+	if v, ok := f.visits[uuid]; ok {
+		return v, nil
+	}
+	return nil, re.ErrNotFound
+}
+
+type fakeRepo struct {
+	out.Repository
+	attachments map[string]*pb.Attachment
+	deleted     []string
+}
+
+func (f *fakeRepo) Create(ctx context.Context, a *pb.Attachment, key string) (*pb.Attachment, error) {
+	return a, nil
+}
+
+func (f *fakeRepo) Get(ctx context.Context, uuid string) (*pb.Attachment, string, error) {
+	if a, ok := f.attachments[uuid]; ok {
+		return a, "doc/" + uuid, nil
+	}
+	return nil, "", re.ErrNotFound
+}
+
+func (f *fakeRepo) Delete(ctx context.Context, uuid string) error {
+	f.deleted = append(f.deleted, uuid)
+	return nil
+}
+
+type fakeStore struct {
+	out.Store
+}
+
+func (fakeStore) Save(ctx context.Context, key string, r io.Reader) (int64, error) {
+	return io.Copy(io.Discard, r)
+}
+
+func (fakeStore) Remove(ctx context.Context, key string) error { return nil }
+
+func TestLockedVisitAttachments(t *testing.T) {
+	now := time.Now().UTC()
+	daysAgo := func(d int) *timestamppb.Timestamp { return timestamppb.New(now.AddDate(0, 0, -d)) }
+	visits := map[string]*pb.Anamnesis{
+		"open":   {Uuid: "open", PatientUuid: "p1", CreatedAt: daysAgo(1)},
+		"signed": {Uuid: "signed", PatientUuid: "p1", CreatedAt: daysAgo(1), SignedAt: daysAgo(0), LockedAt: daysAgo(0)},
+		"old":    {Uuid: "old", PatientUuid: "p1", CreatedAt: daysAgo(10)},
+	}
+	repo := &fakeRepo{attachments: map[string]*pb.Attachment{}}
+	for uuid := range visits {
+		repo.attachments["a-"+uuid] = &pb.Attachment{Uuid: "a-" + uuid, DoctorUuid: "doc", PatientUuid: "p1", AnamnesisUuid: uuid}
+	}
+	repo.attachments["a-patient"] = &pb.Attachment{Uuid: "a-patient", DoctorUuid: "doc", PatientUuid: "p1"}
+	s := &service{
+		repo:          repo,
+		store:         fakeStore{},
+		patientRepo:   fakePatients{},
+		anamnesisRepo: fakeVisits{visits: visits},
+		autoLockAfter: 7 * 24 * time.Hour,
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		visit  string
+		locked bool
+	}{
+		{"open", false},
+		{"signed", true},
+		{"old", true},
+		{"", false}, // patient-level attachment
+	}
+	for _, tt := range tests {
+		_, err := s.Upload(ctx, "doc", Upload{PatientUUID: "p1", AnamnesisUUID: tt.visit, FileName: "nalaz.pdf", Content: strings.NewReader("%PDF-1.7\n")})
+		if tt.locked != errors.Is(err, se.ErrConflict) || (!tt.locked && err != nil) {
+			t.Errorf("upload to %q: got %v, locked %v", tt.visit, err, tt.locked)
+		}
+
+		attachment := "a-" + tt.visit
+		if tt.visit == "" {
+			attachment = "a-patient"
+		}
+		repo.deleted = nil
+		err = s.Delete(ctx, "doc", "p1", attachment)
+		if tt.locked != errors.Is(err, se.ErrConflict) || (!tt.locked && err != nil) {
+			t.Errorf("delete %s: got %v, locked %v", attachment, err, tt.locked)
+		}
+		if tt.locked && len(repo.deleted) != 0 {
+			t.Errorf("delete %s: attachment of a locked visit was removed", attachment)
+		}
+	}
+}
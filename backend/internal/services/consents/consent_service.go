@@ -0,0 +1,362 @@
+package consents
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image/png"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/consents"
+	doctorprofilesoutboundport "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctorprofiles"
+	outdoctors "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctors"
+	outboundportpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Consent kinds; a valid treatment consent is expected before the first visit note.
+const (
+	KindTreatment = "treatment"
+	KindGDPR      = "gdpr"
+	KindOther     = "other"
+)
+
+var kinds = map[string]bool{KindTreatment: true, KindGDPR: true, KindOther: true}
+
+const (
+	maxNameLen       = 100
+	maxTextLen       = 20000
+	maxValidMonths   = 120
+	maxSignatureSide = 4000 // px; larger canvases are not a signature pad
+	maxSignerNameLen = 200
+)
+
+type Service interface {
+	CreateTemplate(ctx context.Context, doctorUUID string, req *pb.CreateConsentTemplateRequest) (*pb.ConsentTemplate, error)
+	UpdateTemplate(ctx context.Context, doctorUUID string, req *pb.UpdateConsentTemplateRequest) (*pb.ConsentTemplate, error)
+	GetTemplate(ctx context.Context, doctorUUID, uuid string) (*pb.ConsentTemplate, error)
+	ListTemplates(ctx context.Context, doctorUUID string, includeInactive bool) ([]*pb.ConsentTemplate, error)
+	DeleteTemplate(ctx context.Context, doctorUUID, uuid string) error
+
+	// Sign stores the consent with its own copy of the template text, the hash of that text and the signature.
+	Sign(ctx context.Context, doctorUUID string, req *pb.SignConsentRequest) (*pb.Consent, error)
+	List(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.Consent, error)
+	Revoke(ctx context.Context, doctorUUID, patientUUID, uuid string) (*pb.Consent, error)
+	PDF(ctx context.Context, doctorUUID, patientUUID, uuid string) ([]byte, error)
+}
+
+type service struct {
+	repo        out.Repository
+	patientRepo outboundportpatients.Repository
+	profileRepo doctorprofilesoutboundport.Repository
+	doctorRepo  outdoctors.Repository
+	now         func() time.Time
+}
+
+func NewService(
+	repo out.Repository,
+	pRepo outboundportpatients.Repository,
+	profRepo doctorprofilesoutboundport.Repository,
+	dRepo outdoctors.Repository) Service {
+	return &service{repo: repo, patientRepo: pRepo, profileRepo: profRepo, doctorRepo: dRepo, now: time.Now}
+}
+
+func (s *service) CreateTemplate(ctx context.Context, doctorUUID string, req *pb.CreateConsentTemplateRequest) (*pb.ConsentTemplate, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("create consent template: %w", se.ErrInvalidRequest)
+	}
+	t := &pb.ConsentTemplate{
+		Uuid:        uuid.NewString(),
+		DoctorUuid:  doctorUUID,
+		Name:        strings.TrimSpace(req.GetName()),
+		Kind:        req.GetKind(),
+		Text:        normalizeText(req.GetText()),
+		ValidMonths: req.GetValidMonths(),
+		Active:      true,
+		CreatedAt:   timestamppb.New(s.now().UTC()),
+	}
+	if err := validateTemplate(t); err != nil {
+		return nil, fmt.Errorf("create consent template: %w", err)
+	}
+	created, err := s.repo.CreateTemplate(ctx, t)
+	if err != nil {
+		if errors.Is(err, re.ErrConflict) {
+			return nil, fmt.Errorf("create consent template: name %q already used: %w", t.GetName(), se.ErrConflict)
+		}
+		return nil, fmt.Errorf("create consent template: %w", err)
+	}
+	return withTextHash(created), nil
+}
+
+func (s *service) UpdateTemplate(ctx context.Context, doctorUUID string, req *pb.UpdateConsentTemplateRequest) (*pb.ConsentTemplate, error) {
+	t, err := s.GetTemplate(ctx, doctorUUID, req.GetUuid())
+	if err != nil {
+		return nil, fmt.Errorf("update consent template: %w", err)
+	}
+	if req.Name != nil {
+		t.Name = strings.TrimSpace(req.GetName().GetValue())
+	}
+	if req.Kind != nil {
+		t.Kind = req.GetKind().GetValue()
+	}
+	if req.Text != nil {
+		t.Text = normalizeText(req.GetText().GetValue())
+	}
+	if req.ValidMonths != nil {
+		t.ValidMonths = req.GetValidMonths().GetValue()
+	}
+	if req.Active != nil {
+		t.Active = req.GetActive().GetValue()
+	}
+	if err := validateTemplate(t); err != nil {
+		return nil, fmt.Errorf("update consent template: %w", err)
+	}
+	t.UpdatedAt = timestamppb.New(s.now().UTC())
+	updated, err := s.repo.UpdateTemplate(ctx, t)
+	if err != nil {
+		switch {
+		case errors.Is(err, re.ErrNotFound):
+			return nil, fmt.Errorf("update consent template: %w", se.ErrNotFound)
+		case errors.Is(err, re.ErrConflict):
+			return nil, fmt.Errorf("update consent template: name %q already used: %w", t.GetName(), se.ErrConflict)
+		}
+		return nil, fmt.Errorf("update consent template: %w", err)
+	}
+	return withTextHash(updated), nil
+}
+
+func (s *service) GetTemplate(ctx context.Context, doctorUUID, templateUUID string) (*pb.ConsentTemplate, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(templateUUID) == "" {
+		return nil, fmt.Errorf("get consent template: %w", se.ErrInvalidRequest)
+	}
+	t, err := s.repo.GetTemplate(ctx, templateUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("get consent template: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get consent template: %w", err)
+	}
+	if t.GetDoctorUuid() != doctorUUID {
+		return nil, fmt.Errorf("get consent template: %w", se.ErrNotFound)
+	}
+	return withTextHash(t), nil
+}
+
+func (s *service) ListTemplates(ctx context.Context, doctorUUID string, includeInactive bool) ([]*pb.ConsentTemplate, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("list consent templates: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.repo.ListTemplates(ctx, doctorUUID, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("list consent templates: %w", err)
+	}
+	for _, t := range list {
+		withTextHash(t)
+	}
+	return list, nil
+}
+
+// DeleteTemplate removes a template; consents signed from it keep their text.
+func (s *service) DeleteTemplate(ctx context.Context, doctorUUID, templateUUID string) error {
+	if _, err := s.GetTemplate(ctx, doctorUUID, templateUUID); err != nil {
+		return fmt.Errorf("delete consent template: %w", err)
+	}
+	if err := s.repo.DeleteTemplate(ctx, templateUUID); err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return fmt.Errorf("delete consent template: %w", se.ErrNotFound)
+		}
+		return fmt.Errorf("delete consent template: %w", err)
+	}
+	return nil
+}
+
+func (s *service) Sign(ctx context.Context, doctorUUID string, req *pb.SignConsentRequest) (*pb.Consent, error) {
+	patient, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, req.GetPatientUuid())
+	if err != nil {
+		return nil, fmt.Errorf("sign consent: %w", err)
+	}
+	t, err := s.GetTemplate(ctx, doctorUUID, req.GetTemplateUuid())
+	if err != nil {
+		return nil, fmt.Errorf("sign consent: %w", err)
+	}
+	if !t.GetActive() {
+		return nil, fmt.Errorf("sign consent: template %q is inactive: %w", t.GetName(), se.ErrInvalidRequest)
+	}
+	if !strings.EqualFold(req.GetTextSha256(), t.GetTextSha256()) {
+		return nil, fmt.Errorf("sign consent: the template text changed since it was shown, show it again: %w", se.ErrConflict)
+	}
+	if err := checkSignature(req.GetSignaturePng()); err != nil {
+		return nil, fmt.Errorf("sign consent: %w", err)
+	}
+	signer := strings.TrimSpace(req.GetSignerName())
+	if signer == "" {
+		signer = strings.TrimSpace(patient.GetFirstName() + " " + patient.GetLastName())
+	}
+	if len([]rune(signer)) > maxSignerNameLen {
+		return nil, fmt.Errorf("sign consent: signer name longer than %d characters: %w", maxSignerNameLen, se.ErrInvalidRequest)
+	}
+
+	now := s.now().UTC()
+	c := &pb.Consent{
+		Uuid:            uuid.NewString(),
+		DoctorUuid:      doctorUUID,
+		PatientUuid:     patient.GetUuid(),
+		TemplateUuid:    t.GetUuid(),
+		Kind:            t.GetKind(),
+		Title:           t.GetName(),
+		Text:            t.GetText(),
+		TextSha256:      t.GetTextSha256(),
+		SignerName:      signer,
+		SignatureSha256: hashHex(req.GetSignaturePng()),
+		SignedAt:        timestamppb.New(now),
+	}
+	if m := t.GetValidMonths(); m > 0 {
+		c.ExpiresAt = timestamppb.New(now.AddDate(0, int(m), 0))
+	}
+	created, err := s.repo.Create(ctx, c, req.GetSignaturePng())
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("sign consent: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("sign consent: %w", err)
+	}
+	created.Valid = isValid(created, now)
+	return created, nil
+}
+
+func (s *service) List(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.Consent, error) {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("list consents: %w", err)
+	}
+	list, err := s.repo.List(ctx, patientUUID)
+	if err != nil {
+		return nil, fmt.Errorf("list consents: %w", err)
+	}
+	now := s.now()
+	for _, c := range list {
+		c.Valid = isValid(c, now)
+	}
+	return list, nil
+}
+
+// Revoke records the patient's withdrawal of a consent; the signed text and signature are kept.
+func (s *service) Revoke(ctx context.Context, doctorUUID, patientUUID, consentUUID string) (*pb.Consent, error) {
+	c, err := s.owned(ctx, doctorUUID, patientUUID, consentUUID)
+	if err != nil {
+		return nil, fmt.Errorf("revoke consent: %w", err)
+	}
+	now := s.now().UTC()
+	if err := s.repo.Revoke(ctx, consentUUID, now); err != nil {
+		if errors.Is(err, re.ErrConflict) {
+			return nil, fmt.Errorf("revoke consent: already revoked: %w", se.ErrConflict)
+		}
+		return nil, fmt.Errorf("revoke consent: %w", err)
+	}
+	c.RevokedAt = timestamppb.New(now)
+	c.Valid = false
+	return c, nil
+}
+
+func (s *service) PDF(ctx context.Context, doctorUUID, patientUUID, consentUUID string) ([]byte, error) {
+	c, err := s.owned(ctx, doctorUUID, patientUUID, consentUUID)
+	if err != nil {
+		return nil, fmt.Errorf("generate consent pdf: %w", err)
+	}
+	signature, err := s.repo.GetSignature(ctx, consentUUID)
+	if err != nil {
+		return nil, fmt.Errorf("generate consent pdf: %w", err)
+	}
+	patient, err := s.patientRepo.Get(ctx, patientUUID)
+	if err != nil {
+		return nil, fmt.Errorf("generate consent pdf: load patient: %w", err)
+	}
+	profile, _ := s.profileRepo.GetByDoctor(ctx, doctorUUID) // optional
+	doctor, _ := s.doctorRepo.Get(ctx, doctorUUID)           // optional
+	return buildConsentPDF(profile, doctor, patient, c, signature)
+}
+
+// owned loads a consent of one of the doctor's patients.
+func (s *service) owned(ctx context.Context, doctorUUID, patientUUID, consentUUID string) (*pb.Consent, error) {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(consentUUID) == "" {
+		return nil, se.ErrInvalidRequest
+	}
+	c, err := s.repo.Get(ctx, consentUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, se.ErrNotFound
+		}
+		return nil, err
+	}
+	if c.GetPatientUuid() != patientUUID || c.GetDoctorUuid() != doctorUUID {
+		return nil, se.ErrNotFound
+	}
+	c.Valid = isValid(c, s.now())
+	return c, nil
+}
+
+func validateTemplate(t *pb.ConsentTemplate) error {
+	switch {
+	case t.GetName() == "" || len([]rune(t.GetName())) > maxNameLen:
+		return fmt.Errorf("name must have 1 to %d characters: %w", maxNameLen, se.ErrInvalidRequest)
+	case !kinds[t.GetKind()]:
+		return fmt.Errorf("unknown kind %q: %w", t.GetKind(), se.ErrInvalidRequest)
+	case strings.TrimSpace(t.GetText()) == "" || len([]rune(t.GetText())) > maxTextLen:
+		return fmt.Errorf("text must have 1 to %d characters: %w", maxTextLen, se.ErrInvalidRequest)
+	case t.GetValidMonths() < 0 || t.GetValidMonths() > maxValidMonths:
+		return fmt.Errorf("valid months must be between 0 and %d: %w", maxValidMonths, se.ErrInvalidRequest)
+	}
+	return nil
+}
+
+// isValid reports whether the consent is in force at the given time.
+func isValid(c *pb.Consent, at time.Time) bool {
+	if c.GetRevokedAt() != nil {
+		return false
+	}
+	return c.GetExpiresAt() == nil || c.GetExpiresAt().AsTime().After(at)
+}
+
+// normalizeText trims the text and uses \n line endings, so that the hash does not depend on the
+// browser the template was typed in.
+func normalizeText(text string) string {
+	return strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+}
+
+func withTextHash(t *pb.ConsentTemplate) *pb.ConsentTemplate {
+	t.TextSha256 = hashHex([]byte(t.GetText()))
+	return t
+}
+
+func hashHex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// checkSignature accepts only a complete PNG image of a sensible size. The size is checked from the
+// header first, then the whole image is decoded so that a truncated or corrupt one is rejected now
+// rather than when the consent PDF is drawn.
+func checkSignature(b []byte) error {
+	cfg, err := png.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("signature must be a PNG image: %w", se.ErrInvalidRequest)
+	}
+	if cfg.Width < 1 || cfg.Height < 1 || cfg.Width > maxSignatureSide || cfg.Height > maxSignatureSide {
+		return fmt.Errorf("signature image must be at most %d×%d px: %w", maxSignatureSide, maxSignatureSide, se.ErrInvalidRequest)
+	}
+	if _, err := png.Decode(bytes.NewReader(b)); err != nil {
+		return fmt.Errorf("signature is not a readable PNG image: %w", se.ErrInvalidRequest)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package consents
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestIsValid(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		c    *pb.Consent
+		want bool
+	}{
+		{"until revoked", &pb.Consent{}, true},
+		{"not yet expired", &pb.Consent{ExpiresAt: timestamppb.New(now.Add(time.Hour))}, true},
+		{"expired", &pb.Consent{ExpiresAt: timestamppb.New(now)}, false},
+		{"revoked", &pb.Consent{RevokedAt: timestamppb.New(now.Add(-time.Hour))}, false},
+	}
+	for _, tt := range tests {
+		if got := isValid(tt.c, now); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTextHashIgnoresLineEndings(t *testing.T) {
+	a := withTextHash(&pb.ConsentTemplate{Text: normalizeText("Suglasan sam s terapijom.\r\nPotpis:\r\n")})
+	b := withTextHash(&pb.ConsentTemplate{Text: normalizeText("Suglasan sam s terapijom.\nPotpis:")})
+	if a.GetTextSha256() != b.GetTextSha256() || len(a.GetTextSha256()) != 64 {
+		t.Errorf("got %q and %q, want the same 64 character hash", a.GetTextSha256(), b.GetTextSha256())
+	}
+}
+
+func TestCheckSignature(t *testing.T) {
+	encode := func(w, h int) []byte {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		return buf.Bytes()
+	}
+	valid := encode(600, 200)
+	if err := checkSignature(valid); err != nil {
+		t.Errorf("signature pad image: unexpected error %v", err)
+	}
+	corrupt := append([]byte(nil), valid...)
+	for i := len(corrupt) - 40; i < len(corrupt)-20; i++ {
+		corrupt[i] ^= 0xff
+	}
+	for name, b := range map[string][]byte{
+		"jpeg header":        {0xff, 0xd8, 0xff, 0xe0},
+		"too large":          encode(maxSignatureSide+1, 10),
+		"truncated":          valid[:len(valid)/2],
+		"header only":        valid[:33],
+		"corrupt pixel data": corrupt,
+	} {
+		if err := checkSignature(b); !errors.Is(err, se.ErrInvalidRequest) {
+			t.Errorf("%s: got %v, want ErrInvalidRequest", name, err)
+		}
+	}
+}
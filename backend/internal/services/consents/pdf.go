@@ -0,0 +1,162 @@
+package consents
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
+	"github.com/jung-kurt/gofpdf"
+)
+
+const signatureWidth = 60.0 // mm
+
+// buildConsentPDF prints the signed text exactly as stored, the signature and the hashes that tie
+// the document to the stored consent.
+func buildConsentPDF(profile *pb.DoctorProfile, doctor *pb.Doctor, patient *pb.Patient, c *pb.Consent, signature []byte) ([]byte, error) {
+	loc, err := svcdoctorprofiles.ScheduleLocation(profile.GetSchedule())
+	if err != nil {
+		loc = time.UTC
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	fontDir := filepath.Join("assets", "fonts")
+	pdf.SetFontLocation(fontDir)
+	pdf.AddUTF8Font("DejaVu", "", "DejaVuSans.ttf")
+	pdf.AddUTF8Font("DejaVu", "B", "DejaVuSans-Bold.ttf")
+	if pdf.Err() {
+		return nil, fmt.Errorf("generate consent pdf: load fonts from %s: %v", fontDir, pdf.Error())
+	}
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	// Header: practice on the left, logo on the right.
+	if profile != nil {
+		if local := localFromStatic(profile.GetLogoPath()); local != "" {
+			pdf.ImageOptions(local, 171, 15, 24, 0, false, gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+			pdf.ClearError() // the consent is valid without the logo
+		}
+		pdf.SetXY(15, 15)
+		pdf.SetFont("DejaVu", "B", 12)
+		pdf.Cell(0, 6, profile.GetPracticeName())
+		pdf.Ln(6)
+		pdf.SetFont("DejaVu", "", 10)
+		for _, line := range []string{profile.GetAddress(), profile.GetPhone(), profile.GetEmail()} {
+			if strings.TrimSpace(line) != "" {
+				pdf.Cell(0, 5, strings.TrimSpace(line))
+				pdf.Ln(5)
+			}
+		}
+	}
+	pdf.Ln(4)
+	pdf.SetLineWidth(0.2)
+	pdf.Line(15, pdf.GetY(), 195, pdf.GetY())
+	pdf.Ln(6)
+
+	pdf.SetFont("DejaVu", "B", 13)
+	pdf.MultiCell(0, 6, strings.ToUpper(c.GetTitle()), "", "C", false)
+	pdf.Ln(3)
+	pdf.SetFont("DejaVu", "B", 10)
+	pdf.Cell(30, 5, "Pacijent:")
+	pdf.SetFont("DejaVu", "", 10)
+	pdf.Cell(0, 5, strings.TrimSpace(patient.GetFirstName()+" "+patient.GetLastName()))
+	pdf.Ln(5)
+	if dob := patient.GetDateOfBirth(); dob != nil && strings.TrimSpace(dob.GetValue()) != "" {
+		pdf.SetFont("DejaVu", "B", 10)
+		pdf.Cell(30, 5, "Datum rođenja:")
+		pdf.SetFont("DejaVu", "", 10)
+		pdf.Cell(0, 5, formatPlainDate(dob.GetValue()))
+		pdf.Ln(5)
+	}
+	pdf.Ln(4)
+
+	pdf.SetFont("DejaVu", "", 10)
+	pdf.MultiCell(0, 5, c.GetText(), "", "", false)
+	pdf.Ln(8)
+
+	// Signature block, kept on one page.
+	if pdf.GetY() > 297-20-55 {
+		pdf.AddPage()
+	}
+	pdf.SetFont("DejaVu", "B", 10)
+	pdf.Cell(0, 5, "Potpis:")
+	pdf.Ln(6)
+	info := pdf.RegisterImageOptionsReader("signature", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(signature))
+	if info != nil && info.Width() > 0 {
+		height := min(signatureWidth*info.Height()/info.Width(), 30)
+		top := pdf.GetY()
+		pdf.ImageOptions("signature", 15, top, 0, height, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+		pdf.SetY(top + height + 1)
+	}
+	if pdf.Err() {
+		return nil, fmt.Errorf("generate consent pdf: signature: %v", pdf.Error())
+	}
+	pdf.Line(15, pdf.GetY(), 15+signatureWidth+20, pdf.GetY())
+	pdf.Ln(2)
+	pdf.SetFont("DejaVu", "", 10)
+	pdf.Cell(0, 5, c.GetSignerName())
+	pdf.Ln(5)
+	pdf.Cell(0, 5, "Potpisano: "+c.GetSignedAt().AsTime().In(loc).Format("02.01.2006. 15:04"))
+	pdf.Ln(5)
+	if c.GetExpiresAt() != nil {
+		pdf.Cell(0, 5, "Vrijedi do: "+c.GetExpiresAt().AsTime().In(loc).Format("02.01.2006."))
+		pdf.Ln(5)
+	}
+	if c.GetRevokedAt() != nil {
+		pdf.SetFont("DejaVu", "B", 10)
+		pdf.Cell(0, 5, "Opozvano: "+c.GetRevokedAt().AsTime().In(loc).Format("02.01.2006. 15:04"))
+		pdf.Ln(5)
+	}
+
+	if doctor != nil {
+		if name := strings.TrimSpace(doctor.GetFirstName() + " " + doctor.GetLastName()); name != "" {
+			pdf.Ln(4)
+			pdf.SetFont("DejaVu", "B", 9)
+			pdf.Cell(0, 5, "Fizioterapeut:")
+			pdf.Ln(5)
+			pdf.SetFont("DejaVu", "", 9)
+			pdf.Cell(0, 5, "bacc.physioth "+name)
+			pdf.Ln(5)
+		}
+	}
+
+	pdf.Ln(4)
+	pdf.SetFont("DejaVu", "", 7)
+	pdf.MultiCell(0, 3.5, fmt.Sprintf("Oznaka privole: %s\nSHA-256 teksta: %s\nSHA-256 potpisa: %s", c.GetUuid(), c.GetTextSha256(), c.GetSignatureSha256()), "", "", false)
+
+	if pdf.Err() {
+		return nil, fmt.Errorf("generate consent pdf: prepare content: %v", pdf.Error())
+	}
+	var buf strings.Builder
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("generate consent pdf: render: %w", err)
+	}
+	return []byte(buf.String()), nil
+}
+
+// formatPlainDate prints a stored date as DD.MM.YYYY.; other values are printed as they are.
+func formatPlainDate(val string) string {
+	s := strings.TrimSpace(val)
+	for _, l := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(l, s); err == nil {
+			return t.Format("02.01.2006.")
+		}
+	}
+	return s
+}
+
+func localFromStatic(path string) string {
+	const prefix = "/static/"
+	if !strings.HasPrefix(path, prefix) {
+		return ""
+	}
+	local := filepath.Join("uploads", strings.TrimPrefix(path, prefix))
+	if _, err := os.Stat(local); err == nil {
+		return local
+	}
+	return ""
+}
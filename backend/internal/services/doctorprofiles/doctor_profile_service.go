@@ -81,6 +81,13 @@ func (s *service) Upsert(ctx context.Context, doctorUUID string, req *pt.UpsertD
 	p.Website = trim(p.GetWebsite())
 	p.LogoPath = trim(p.GetLogoPath())
 	p.UpdatedAt = now
+	if p.Schedule != nil {
+		sched, err := normalizeSchedule(p.GetSchedule())
+		if err != nil {
+			return nil, fmt.Errorf("upsert doctor profile: schedule: %v: %w", err, se.ErrInvalidRequest)
+		}
+		p.Schedule = sched
+	}
 
 	var existing *pt.DoctorProfile
 	if ex, err := s.repo.GetByDoctor(ctx, doctorUUID); err == nil {
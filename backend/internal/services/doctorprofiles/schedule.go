@@ -0,0 +1,113 @@
+package doctorprofiles
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pt "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+// DefaultTimezone is used when a working schedule does not name one.
+const DefaultTimezone = "Europe/Zagreb"
+
+// ParseClock parses an "HH:MM" wall-clock time into minutes since midnight.
+func ParseClock(v string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(v))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", v)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ScheduleLocation returns the schedule's time zone, falling back to DefaultTimezone.
+func ScheduleLocation(sched *pt.WorkingSchedule) (*time.Location, error) {
+	name := strings.TrimSpace(sched.GetTimezone())
+	if name == "" {
+		name = DefaultTimezone
+	}
+	return time.LoadLocation(name)
+}
+
+// normalizeSchedule validates a working schedule and returns it trimmed and sorted.
+func normalizeSchedule(sched *pt.WorkingSchedule) (*pt.WorkingSchedule, error) {
+	tz := strings.TrimSpace(sched.GetTimezone())
+	if tz == "" {
+		tz = DefaultTimezone
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", tz)
+	}
+
+	hours, err := normalizeIntervals(sched.GetHours(), "working hours")
+	if err != nil {
+		return nil, err
+	}
+	breaks, err := normalizeIntervals(sched.GetBreaks(), "breaks")
+	if err != nil {
+		return nil, err
+	}
+
+	daysOff := make([]*pt.DayOff, 0, len(sched.GetDaysOff()))
+	seen := map[string]bool{}
+	for _, d := range sched.GetDaysOff() {
+		date := strings.TrimSpace(d.GetDate())
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("invalid day off %q, expected YYYY-MM-DD", d.GetDate())
+		}
+		if seen[date] {
+			continue
+		}
+		seen[date] = true
+		daysOff = append(daysOff, &pt.DayOff{Date: date, Reason: strings.TrimSpace(d.GetReason())})
+	}
+	sort.Slice(daysOff, func(i, j int) bool { return daysOff[i].GetDate() < daysOff[j].GetDate() })
+
+	return &pt.WorkingSchedule{
+		Timezone:              tz,
+		Hours:                 hours,
+		DaysOff:               daysOff,
+		Breaks:                breaks,
+		ObservePublicHolidays: sched.GetObservePublicHolidays(),
+	}, nil
+}
+
+// normalizeIntervals validates weekday intervals and sorts them by weekday and start.
+func normalizeIntervals(list []*pt.WorkingHours, label string) ([]*pt.WorkingHours, error) {
+	res := make([]*pt.WorkingHours, 0, len(list))
+	for _, h := range list {
+		if h.GetWeekday() < 1 || h.GetWeekday() > 7 {
+			return nil, fmt.Errorf("%s: weekday must be between 1 and 7", label)
+		}
+		start, err := ParseClock(h.GetStart())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", label, err)
+		}
+		end, err := ParseClock(h.GetEnd())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", label, err)
+		}
+		if end <= start {
+			return nil, fmt.Errorf("%s: end must be after start (%s-%s)", label, h.GetStart(), h.GetEnd())
+		}
+		res = append(res, &pt.WorkingHours{
+			Weekday: h.GetWeekday(),
+			Start:   strings.TrimSpace(h.GetStart()),
+			End:     strings.TrimSpace(h.GetEnd()),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].GetWeekday() != res[j].GetWeekday() {
+			return res[i].GetWeekday() < res[j].GetWeekday()
+		}
+		return res[i].GetStart() < res[j].GetStart()
+	})
+	for i := 1; i < len(res); i++ {
+		prev, cur := res[i-1], res[i]
+		if prev.GetWeekday() == cur.GetWeekday() && cur.GetStart() < prev.GetEnd() {
+			return nil, fmt.Errorf("%s overlap on weekday %d", label, cur.GetWeekday())
+		}
+	}
+	return res, nil
+}
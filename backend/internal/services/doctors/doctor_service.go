@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
 	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	outattachments "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/attachments"
 	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctors"
 	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
 	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
@@ -25,11 +27,13 @@ type Service interface {
 }
 
 type service struct {
-	repo out.Repository
+	repo        out.Repository
+	attachRepo  outattachments.Repository
+	attachStore outattachments.Store
 }
 
-func NewService(repo out.Repository) Service {
-	return &service{repo: repo}
+func NewService(repo out.Repository, attachRepo outattachments.Repository, attachStore outattachments.Store) Service {
+	return &service{repo: repo, attachRepo: attachRepo, attachStore: attachStore}
 }
 
 func (s *service) Create(ctx context.Context, req *pb.CreateDoctorRequest) (*pb.Doctor, error) {
@@ -114,12 +118,22 @@ func (s *service) Delete(ctx context.Context, uuid string) error {
 	if strings.TrimSpace(uuid) == "" {
 		return fmt.Errorf("delete doctor: %w", se.ErrInvalidRequest)
 	}
+	// The attachment rows go with the doctor; their files are removed once the rows are gone.
+	keys, err := s.attachRepo.StorageKeys(ctx, uuid, "")
+	if err != nil {
+		return fmt.Errorf("delete doctor: %w", err)
+	}
 	if err := s.repo.Delete(ctx, uuid); err != nil {
 		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
 			return fmt.Errorf("delete doctor: %w", se.ErrNotFound)
 		}
 		return fmt.Errorf("delete doctor: %w", err)
 	}
+	for _, key := range keys {
+		if err := s.attachStore.Remove(ctx, key); err != nil {
+			log.Printf("doctors: removing attachment file %s of deleted doctor %s: %v", key, uuid, err)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,345 @@
+package exercises
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	doctorprofilesoutboundport "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctorprofiles"
+	outdoctors "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctors"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/exercises"
+	outboundportpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Dosage limits shared by library defaults and per-patient overrides.
+const (
+	maxSets        = 20
+	maxReps        = 200
+	maxHoldSeconds = 600
+	maxFrequency   = 50
+)
+
+type Service interface {
+	CreateExercise(ctx context.Context, doctorUUID string, req *pb.CreateExerciseRequest) (*pb.Exercise, error)
+	UpdateExercise(ctx context.Context, doctorUUID string, req *pb.UpdateExerciseRequest) (*pb.Exercise, error)
+	GetExercise(ctx context.Context, doctorUUID, uuid string) (*pb.Exercise, error)
+	ListExercises(ctx context.Context, doctorUUID, query string, includeInactive bool) ([]*pb.Exercise, error)
+	DeleteExercise(ctx context.Context, doctorUUID, uuid string) error
+
+	CreateProgram(ctx context.Context, doctorUUID string, req *pb.CreateExerciseProgramRequest) (*pb.ExerciseProgram, error)
+	UpdateProgram(ctx context.Context, doctorUUID string, req *pb.UpdateExerciseProgramRequest) (*pb.ExerciseProgram, error)
+	GetProgram(ctx context.Context, doctorUUID, patientUUID, uuid string) (*pb.ExerciseProgram, error)
+	ListPrograms(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.ExerciseProgram, error)
+	DeleteProgram(ctx context.Context, doctorUUID, patientUUID, uuid string) error
+	ProgramPDF(ctx context.Context, doctorUUID, patientUUID, uuid string) ([]byte, error)
+}
+
+type service struct {
+	repo        out.Repository
+	patientRepo outboundportpatients.Repository
+	profileRepo doctorprofilesoutboundport.Repository
+	doctorRepo  outdoctors.Repository
+	now         func() time.Time
+}
+
+func NewService(
+	repo out.Repository,
+	pRepo outboundportpatients.Repository,
+	profRepo doctorprofilesoutboundport.Repository,
+	dRepo outdoctors.Repository) Service {
+	return &service{repo: repo, patientRepo: pRepo, profileRepo: profRepo, doctorRepo: dRepo, now: time.Now}
+}
+
+func (s *service) CreateExercise(ctx context.Context, doctorUUID string, req *pb.CreateExerciseRequest) (*pb.Exercise, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("create exercise: %w", se.ErrInvalidRequest)
+	}
+	now := s.now().UTC()
+	e := &pb.Exercise{
+		Uuid:               uuid.NewString(),
+		DoctorUuid:         doctorUUID,
+		Name:               strings.TrimSpace(req.GetName()),
+		Description:        strings.TrimSpace(req.GetDescription()),
+		ImageUrl:           strings.TrimSpace(req.GetImageUrl()),
+		DefaultSets:        req.GetDefaultSets(),
+		DefaultReps:        req.GetDefaultReps(),
+		DefaultHoldSeconds: req.GetDefaultHoldSeconds(),
+		DefaultFrequency:   strings.TrimSpace(req.GetDefaultFrequency()),
+		Active:             true,
+		CreatedAt:          timestamppb.New(now),
+	}
+	if err := validateExercise(e); err != nil {
+		return nil, fmt.Errorf("create exercise: %w", err)
+	}
+	created, err := s.repo.CreateExercise(ctx, e)
+	if err != nil {
+		if errors.Is(err, re.ErrConflict) {
+			return nil, fmt.Errorf("create exercise: name %q is already used: %w", e.GetName(), se.ErrConflict)
+		}
+		return nil, fmt.Errorf("create exercise: %w", err)
+	}
+	return created, nil
+}
+
+func (s *service) UpdateExercise(ctx context.Context, doctorUUID string, req *pb.UpdateExerciseRequest) (*pb.Exercise, error) {
+	e, err := s.GetExercise(ctx, doctorUUID, req.GetUuid())
+	if err != nil {
+		return nil, fmt.Errorf("update exercise: %w", err)
+	}
+	if req.Name != nil {
+		e.Name = strings.TrimSpace(req.GetName().GetValue())
+	}
+	if req.Description != nil {
+		e.Description = strings.TrimSpace(req.GetDescription().GetValue())
+	}
+	if req.ImageUrl != nil {
+		e.ImageUrl = strings.TrimSpace(req.GetImageUrl().GetValue())
+	}
+	if req.DefaultSets != nil {
+		e.DefaultSets = req.GetDefaultSets().GetValue()
+	}
+	if req.DefaultReps != nil {
+		e.DefaultReps = req.GetDefaultReps().GetValue()
+	}
+	if req.DefaultHoldSeconds != nil {
+		e.DefaultHoldSeconds = req.GetDefaultHoldSeconds().GetValue()
+	}
+	if req.DefaultFrequency != nil {
+		e.DefaultFrequency = strings.TrimSpace(req.GetDefaultFrequency().GetValue())
+	}
+	if req.Active != nil {
+		e.Active = req.GetActive().GetValue()
+	}
+	if err := validateExercise(e); err != nil {
+		return nil, fmt.Errorf("update exercise: %w", err)
+	}
+	e.UpdatedAt = timestamppb.New(s.now().UTC())
+	updated, err := s.repo.UpdateExercise(ctx, e)
+	if err != nil {
+		switch {
+		case errors.Is(err, re.ErrNotFound):
+			return nil, fmt.Errorf("update exercise: %w", se.ErrNotFound)
+		case errors.Is(err, re.ErrConflict):
+			return nil, fmt.Errorf("update exercise: name %q is already used: %w", e.GetName(), se.ErrConflict)
+		}
+		return nil, fmt.Errorf("update exercise: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *service) GetExercise(ctx context.Context, doctorUUID, exerciseUUID string) (*pb.Exercise, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(exerciseUUID) == "" {
+		return nil, fmt.Errorf("get exercise: %w", se.ErrInvalidRequest)
+	}
+	e, err := s.repo.GetExercise(ctx, exerciseUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("get exercise: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get exercise: %w", err)
+	}
+	if e.GetDoctorUuid() != doctorUUID {
+		return nil, fmt.Errorf("get exercise: %w", se.ErrNotFound)
+	}
+	return e, nil
+}
+
+func (s *service) ListExercises(ctx context.Context, doctorUUID, query string, includeInactive bool) ([]*pb.Exercise, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("list exercises: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.repo.ListExercises(ctx, doctorUUID, query, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("list exercises: %w", err)
+	}
+	return list, nil
+}
+
+// DeleteExercise removes an unused exercise; exercises in a program can only be deactivated.
+func (s *service) DeleteExercise(ctx context.Context, doctorUUID, exerciseUUID string) error {
+	if _, err := s.GetExercise(ctx, doctorUUID, exerciseUUID); err != nil {
+		return fmt.Errorf("delete exercise: %w", err)
+	}
+	if err := s.repo.DeleteExercise(ctx, exerciseUUID); err != nil {
+		switch {
+		case errors.Is(err, re.ErrNotFound):
+			return fmt.Errorf("delete exercise: %w", se.ErrNotFound)
+		case errors.Is(err, re.ErrConflict):
+			return fmt.Errorf("delete exercise: used in a program, deactivate it instead: %w", se.ErrConflict)
+		}
+		return fmt.Errorf("delete exercise: %w", err)
+	}
+	return nil
+}
+
+func (s *service) CreateProgram(ctx context.Context, doctorUUID string, req *pb.CreateExerciseProgramRequest) (*pb.ExerciseProgram, error) {
+	patientUUID := strings.TrimSpace(req.GetPatientUuid())
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("create exercise program: %w", err)
+	}
+	items, err := s.buildItems(ctx, doctorUUID, req.GetItems(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create exercise program: %w", err)
+	}
+	p := &pb.ExerciseProgram{
+		Uuid:        uuid.NewString(),
+		DoctorUuid:  doctorUUID,
+		PatientUuid: patientUUID,
+		Title:       strings.TrimSpace(req.GetTitle()),
+		Notes:       strings.TrimSpace(req.GetNotes()),
+		Items:       items,
+		CreatedAt:   timestamppb.New(s.now().UTC()),
+	}
+	created, err := s.repo.CreateProgram(ctx, p)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("create exercise program: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("create exercise program: %w", err)
+	}
+	return created, nil
+}
+
+func (s *service) UpdateProgram(ctx context.Context, doctorUUID string, req *pb.UpdateExerciseProgramRequest) (*pb.ExerciseProgram, error) {
+	p, err := s.GetProgram(ctx, doctorUUID, req.GetPatientUuid(), req.GetUuid())
+	if err != nil {
+		return nil, fmt.Errorf("update exercise program: %w", err)
+	}
+	if req.Title != nil {
+		p.Title = strings.TrimSpace(req.GetTitle().GetValue())
+	}
+	if req.Notes != nil {
+		p.Notes = strings.TrimSpace(req.GetNotes().GetValue())
+	}
+	if req.Items != nil {
+		if p.Items, err = s.buildItems(ctx, doctorUUID, req.GetItems().GetItems(), p.GetItems()); err != nil {
+			return nil, fmt.Errorf("update exercise program: %w", err)
+		}
+	}
+	p.UpdatedAt = timestamppb.New(s.now().UTC())
+	updated, err := s.repo.UpdateProgram(ctx, p)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("update exercise program: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("update exercise program: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *service) GetProgram(ctx context.Context, doctorUUID, patientUUID, programUUID string) (*pb.ExerciseProgram, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(programUUID) == "" {
+		return nil, fmt.Errorf("get exercise program: %w", se.ErrInvalidRequest)
+	}
+	p, err := s.repo.GetProgram(ctx, programUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("get exercise program: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get exercise program: %w", err)
+	}
+	if p.GetDoctorUuid() != doctorUUID || p.GetPatientUuid() != patientUUID {
+		return nil, fmt.Errorf("get exercise program: %w", se.ErrNotFound)
+	}
+	return p, nil
+}
+
+func (s *service) ListPrograms(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.ExerciseProgram, error) {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("list exercise programs: %w", err)
+	}
+	list, err := s.repo.ListPrograms(ctx, patientUUID)
+	if err != nil {
+		return nil, fmt.Errorf("list exercise programs: %w", err)
+	}
+	return list, nil
+}
+
+func (s *service) DeleteProgram(ctx context.Context, doctorUUID, patientUUID, programUUID string) error {
+	if _, err := s.GetProgram(ctx, doctorUUID, patientUUID, programUUID); err != nil {
+		return fmt.Errorf("delete exercise program: %w", err)
+	}
+	if err := s.repo.DeleteProgram(ctx, programUUID); err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return fmt.Errorf("delete exercise program: %w", se.ErrNotFound)
+		}
+		return fmt.Errorf("delete exercise program: %w", err)
+	}
+	return nil
+}
+
+// buildItems validates the overrides and checks that every exercise is the doctor's own.
+// Inactive exercises may stay in a program that already has them but cannot be newly added.
+func (s *service) buildItems(ctx context.Context, doctorUUID string, inputs []*pb.ExerciseProgramItemInput, existing []*pb.ExerciseProgramItem) ([]*pb.ExerciseProgramItem, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("program has no exercises: %w", se.ErrInvalidRequest)
+	}
+	kept := map[string]bool{}
+	for _, it := range existing {
+		kept[it.GetExerciseUuid()] = true
+	}
+	res := make([]*pb.ExerciseProgramItem, 0, len(inputs))
+	for i, in := range inputs {
+		exerciseUUID := strings.TrimSpace(in.GetExerciseUuid())
+		e, err := s.GetExercise(ctx, doctorUUID, exerciseUUID)
+		if err != nil {
+			return nil, fmt.Errorf("exercise %d: %w", i+1, err)
+		}
+		if !e.GetActive() && !kept[exerciseUUID] {
+			return nil, fmt.Errorf("exercise %d: %q is inactive: %w", i+1, e.GetName(), se.ErrInvalidRequest)
+		}
+		it := &pb.ExerciseProgramItem{
+			ExerciseUuid:        exerciseUUID,
+			Position:            int32(i + 1),
+			SetsOverride:        in.GetSetsOverride(),
+			RepsOverride:        in.GetRepsOverride(),
+			HoldSecondsOverride: in.GetHoldSecondsOverride(),
+			Notes:               strings.TrimSpace(in.GetNotes()),
+		}
+		if in.GetFrequencyOverride() != nil {
+			it.FrequencyOverride = wrapperspb.String(strings.TrimSpace(in.GetFrequencyOverride().GetValue()))
+		}
+		if err := checkDosage(it.GetSetsOverride().GetValue(), it.GetRepsOverride().GetValue(), it.GetHoldSecondsOverride().GetValue(), it.GetFrequencyOverride().GetValue()); err != nil {
+			return nil, fmt.Errorf("exercise %d: %w", i+1, err)
+		}
+		res = append(res, it)
+	}
+	return res, nil
+}
+
+func validateExercise(e *pb.Exercise) error {
+	if e.GetName() == "" || len([]rune(e.GetName())) > 150 {
+		return fmt.Errorf("name must be 1-150 characters: %w", se.ErrInvalidRequest)
+	}
+	if url := e.GetImageUrl(); url != "" {
+		ext := strings.ToLower(filepath.Ext(url))
+		if !strings.HasPrefix(url, "/static/") || strings.Contains(url, "..") || (ext != ".png" && ext != ".jpg" && ext != ".jpeg") {
+			return fmt.Errorf("image_url must be a PNG or JPEG returned by /files/upload: %w", se.ErrInvalidRequest)
+		}
+	}
+	return checkDosage(e.GetDefaultSets(), e.GetDefaultReps(), e.GetDefaultHoldSeconds(), e.GetDefaultFrequency())
+}
+
+func checkDosage(sets, reps, hold int32, frequency string) error {
+	switch {
+	case sets < 0 || sets > maxSets:
+		return fmt.Errorf("sets must be 0-%d: %w", maxSets, se.ErrInvalidRequest)
+	case reps < 0 || reps > maxReps:
+		return fmt.Errorf("reps must be 0-%d: %w", maxReps, se.ErrInvalidRequest)
+	case hold < 0 || hold > maxHoldSeconds:
+		return fmt.Errorf("hold must be 0-%d seconds: %w", maxHoldSeconds, se.ErrInvalidRequest)
+	case len([]rune(frequency)) > maxFrequency:
+		return fmt.Errorf("frequency must be at most %d characters: %w", maxFrequency, se.ErrInvalidRequest)
+	}
+	return nil
+}
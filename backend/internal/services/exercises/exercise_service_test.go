@@ -0,0 +1,53 @@
+package exercises
+
+import (
+	"errors"
+	"testing"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+)
+
+func TestFormatDosage(t *testing.T) {
+	tests := []struct {
+		name string
+		item *pb.ExerciseProgramItem
+		want string
+	}{
+		{"sets and reps", &pb.ExerciseProgramItem{Sets: 3, Reps: 10, Frequency: "2x dnevno"}, "3 × 10 ponavljanja, 2x dnevno"},
+		{"hold only", &pb.ExerciseProgramItem{HoldSeconds: 30}, "zadržati 30 s"},
+		{"sets without reps", &pb.ExerciseProgramItem{Sets: 2, HoldSeconds: 5}, "serije: 2, zadržati 5 s"},
+		{"nothing", &pb.ExerciseProgramItem{}, ""},
+	}
+	for _, tt := range tests {
+		if got := formatDosage(tt.item); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateExercise(t *testing.T) {
+	tests := []struct {
+		name string
+		e    *pb.Exercise
+		ok   bool
+	}{
+		{"uploaded image", &pb.Exercise{Name: "Most", ImageUrl: "/static/branding/1.png", DefaultSets: 3}, true},
+		{"no image", &pb.Exercise{Name: "Most"}, true},
+		{"missing name", &pb.Exercise{}, false},
+		{"foreign image", &pb.Exercise{Name: "Most", ImageUrl: "https://example.com/a.png"}, false},
+		{"path traversal", &pb.Exercise{Name: "Most", ImageUrl: "/static/../secret.png"}, false},
+		{"not an image", &pb.Exercise{Name: "Most", ImageUrl: "/static/branding/a.pdf"}, false},
+		{"too many reps", &pb.Exercise{Name: "Most", DefaultReps: maxReps + 1}, false},
+		{"negative hold", &pb.Exercise{Name: "Most", DefaultHoldSeconds: -1}, false},
+	}
+	for _, tt := range tests {
+		err := validateExercise(tt.e)
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, se.ErrInvalidRequest) {
+			t.Errorf("%s: got %v, want ErrInvalidRequest", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package exercises
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"github.com/jung-kurt/gofpdf"
+	"gorm.io/gorm"
+)
+
+const (
+	imageWidth = 45.0 // mm, exercise pictures are scaled to this width
+	textX      = 65.0
+	pageBottom = 297.0 - 20.0
+)
+
+// ProgramPDF renders the program for the patient: one block per exercise with its picture and dosage.
+func (s *service) ProgramPDF(ctx context.Context, doctorUUID, patientUUID, programUUID string) ([]byte, error) {
+	p, err := s.GetProgram(ctx, doctorUUID, patientUUID, programUUID)
+	if err != nil {
+		return nil, fmt.Errorf("generate program pdf: %w", err)
+	}
+	patient, err := s.patientRepo.Get(ctx, patientUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("generate program pdf: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("generate program pdf: load patient: %w", err)
+	}
+	profile, _ := s.profileRepo.GetByDoctor(ctx, doctorUUID) // optional
+	doctor, _ := s.doctorRepo.Get(ctx, doctorUUID)           // optional
+	return buildProgramPDF(profile, doctor, patient, p, s.now())
+}
+
+func buildProgramPDF(profile *pb.DoctorProfile, doctor *pb.Doctor, patient *pb.Patient, p *pb.ExerciseProgram, printedAt time.Time) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	fontDir := filepath.Join("assets", "fonts")
+	pdf.SetFontLocation(fontDir)
+	pdf.AddUTF8Font("DejaVu", "", "DejaVuSans.ttf")
+	pdf.AddUTF8Font("DejaVu", "B", "DejaVuSans-Bold.ttf")
+	if pdf.Err() {
+		return nil, fmt.Errorf("generate program pdf: load fonts from %s: %v", fontDir, pdf.Error())
+	}
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	// Header: practice on the left, print date on the right.
+	pdf.SetFont("DejaVu", "", 10)
+	pdf.SetXY(-70, 15)
+	pdf.Cell(55, 5, "Datum: "+printedAt.Format("02.01.2006."))
+	pdf.SetXY(15, 15)
+	if profile != nil {
+		pdf.SetFont("DejaVu", "B", 12)
+		pdf.Cell(0, 6, profile.GetPracticeName())
+		pdf.Ln(6)
+		pdf.SetFont("DejaVu", "", 10)
+		for _, line := range []string{profile.GetAddress(), profile.GetPhone(), profile.GetEmail()} {
+			if strings.TrimSpace(line) != "" {
+				pdf.Cell(0, 5, strings.TrimSpace(line))
+				pdf.Ln(5)
+			}
+		}
+	}
+	pdf.Ln(4)
+	pdf.SetLineWidth(0.2)
+	pdf.Line(15, pdf.GetY(), 195, pdf.GetY())
+	pdf.Ln(6)
+
+	pdf.SetFont("DejaVu", "B", 13)
+	title := "PROGRAM VJEŽBI ZA KUĆU"
+	if p.GetTitle() != "" {
+		title += " – " + p.GetTitle()
+	}
+	pdf.MultiCell(0, 6, title, "", "C", false)
+	pdf.Ln(3)
+	pdf.SetFont("DejaVu", "B", 10)
+	pdf.Cell(25, 5, "Pacijent:")
+	pdf.SetFont("DejaVu", "", 10)
+	pdf.Cell(0, 5, strings.TrimSpace(patient.GetFirstName()+" "+patient.GetLastName()))
+	pdf.Ln(7)
+	if p.GetNotes() != "" {
+		pdf.MultiCell(0, 5, p.GetNotes(), "", "", false)
+		pdf.Ln(3)
+	}
+
+	for i, it := range p.GetItems() {
+		drawExercise(pdf, i+1, it)
+	}
+
+	if doctor != nil {
+		if name := strings.TrimSpace(doctor.GetFirstName() + " " + doctor.GetLastName()); name != "" {
+			if pdf.GetY() > pageBottom-15 {
+				pdf.AddPage()
+			}
+			pdf.Ln(6)
+			pdf.SetFont("DejaVu", "B", 9)
+			pdf.Cell(0, 5, "Fizioterapeut:")
+			pdf.Ln(5)
+			pdf.SetFont("DejaVu", "", 9)
+			pdf.Cell(0, 5, "bacc.physioth "+name)
+		}
+	}
+
+	if pdf.Err() {
+		return nil, fmt.Errorf("generate program pdf: prepare content: %v", pdf.Error())
+	}
+	var buf strings.Builder
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("generate program pdf: render: %w", err)
+	}
+	return []byte(buf.String()), nil
+}
+
+// drawExercise prints one exercise with the picture on the left; a block never starts
+// so low that its picture would be cut by the page break.
+func drawExercise(pdf *gofpdf.Fpdf, n int, it *pb.ExerciseProgramItem) {
+	e := it.GetExercise()
+	imgPath, imgHeight := "", 0.0
+	if local := localFromStatic(e.GetImageUrl()); local != "" {
+		info := pdf.RegisterImageOptions(local, gofpdf.ImageOptions{ReadDpi: true})
+		if info != nil && info.Width() > 0 {
+			imgPath, imgHeight = local, imageWidth*info.Height()/info.Width()
+		}
+		pdf.ClearError() // a broken picture should not cost the patient the whole program
+	}
+	if pdf.GetY()+max(imgHeight, 25) > pageBottom {
+		pdf.AddPage()
+	}
+	top, page := pdf.GetY(), pdf.PageNo()
+	left := 15.0
+	if imgPath != "" {
+		pdf.ImageOptions(imgPath, 15, top, imageWidth, imgHeight, false, gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+		left = textX
+	}
+	pdf.SetLeftMargin(left)
+	pdf.SetXY(left, top)
+	pdf.SetFont("DejaVu", "B", 11)
+	pdf.MultiCell(0, 5, fmt.Sprintf("%d. %s", n, e.GetName()), "", "", false)
+	if dosage := formatDosage(it); dosage != "" {
+		pdf.SetFont("DejaVu", "B", 10)
+		pdf.MultiCell(0, 5, dosage, "", "", false)
+	}
+	pdf.SetFont("DejaVu", "", 10)
+	if e.GetDescription() != "" {
+		pdf.MultiCell(0, 5, e.GetDescription(), "", "", false)
+	}
+	if it.GetNotes() != "" {
+		pdf.MultiCell(0, 5, "Napomena: "+it.GetNotes(), "", "", false)
+	}
+	pdf.SetLeftMargin(15)
+	bottom := pdf.GetY()
+	if imgPath != "" && pdf.PageNo() == page && top+imgHeight > bottom {
+		bottom = top + imgHeight
+	}
+	pdf.SetXY(15, bottom+4)
+	pdf.Line(15, pdf.GetY(), 195, pdf.GetY())
+	pdf.Ln(4)
+}
+
+// formatDosage describes the effective dosage, e.g. "3 × 10 ponavljanja, zadržati 5 s, 2x dnevno".
+func formatDosage(it *pb.ExerciseProgramItem) string {
+	parts := make([]string, 0, 3)
+	switch {
+	case it.GetSets() > 0 && it.GetReps() > 0:
+		parts = append(parts, fmt.Sprintf("%d × %d ponavljanja", it.GetSets(), it.GetReps()))
+	case it.GetSets() > 0:
+		parts = append(parts, fmt.Sprintf("serije: %d", it.GetSets()))
+	case it.GetReps() > 0:
+		parts = append(parts, fmt.Sprintf("%d ponavljanja", it.GetReps()))
+	}
+	if it.GetHoldSeconds() > 0 {
+		parts = append(parts, fmt.Sprintf("zadržati %d s", it.GetHoldSeconds()))
+	}
+	if it.GetFrequency() != "" {
+		parts = append(parts, it.GetFrequency())
+	}
+	return strings.Join(parts, ", ")
+}
+
+func localFromStatic(path string) string {
+	const prefix = "/static/"
+	if !strings.HasPrefix(path, prefix) {
+		return ""
+	}
+	local := filepath.Join("uploads", strings.TrimPrefix(path, prefix))
+	if _, err := os.Stat(local); err == nil {
+		return local
+	}
+	return ""
+}
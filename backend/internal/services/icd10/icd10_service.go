@@ -0,0 +1,45 @@
+package icd10
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/icd10"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+	maxQueryLen  = 100
+)
+
+type Service interface {
+	// Search looks up codes by code or description; limit 0 means the default of 20.
+	Search(ctx context.Context, query string, limit int) ([]*pb.DiagnosisCode, error)
+}
+
+type service struct {
+	catalogue out.Catalogue
+}
+
+func NewService(catalogue out.Catalogue) Service {
+	return &service{catalogue: catalogue}
+}
+
+func (s *service) Search(_ context.Context, query string, limit int) ([]*pb.DiagnosisCode, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > maxQueryLen {
+		return nil, fmt.Errorf("search icd-10: query of 1 to %d characters required: %w", maxQueryLen, se.ErrInvalidRequest)
+	}
+	if limit < 0 || limit > maxLimit {
+		return nil, fmt.Errorf("search icd-10: limit must be between 1 and %d: %w", maxLimit, se.ErrInvalidRequest)
+	}
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	return s.catalogue.Search(query, limit), nil
+}
@@ -0,0 +1,33 @@
+package patients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	pt "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	"gorm.io/gorm"
+)
+
+// Owned loads a patient of the doctor for the services that keep records per patient.
+// Another doctor's patient is reported as not found, so its existence is not revealed.
+func Owned(ctx context.Context, repo out.Repository, doctorUUID, patientUUID string) (*pt.Patient, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(patientUUID) == "" {
+		return nil, se.ErrInvalidRequest
+	}
+	p, err := repo.Get(ctx, patientUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, se.ErrNotFound
+		}
+		return nil, fmt.Errorf("load patient: %w", err)
+	}
+	if p.GetDoctorUuid() != doctorUUID {
+		return nil, se.ErrNotFound
+	}
+	return p, nil
+}
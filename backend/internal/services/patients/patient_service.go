@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"net/mail"
 	"strings"
 	"time"
 
 	pt "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	outattachments "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/attachments"
 	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
 	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
 	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
@@ -25,12 +28,21 @@ type Service interface {
 	Delete(ctx context.Context, uuid string) error
 }
 
+// LockedNotes is implemented by the anamneses service. Signed and auto-locked notes are never
+// deleted, so neither is their patient.
+type LockedNotes interface {
+	HasLockedNotes(ctx context.Context, patientUUID string) (bool, error)
+}
+
 type service struct {
-	repo out.Repository
+	repo        out.Repository
+	notes       LockedNotes
+	attachRepo  outattachments.Repository
+	attachStore outattachments.Store
 }
 
-func NewService(repo out.Repository) Service {
-	return &service{repo: repo}
+func NewService(repo out.Repository, notes LockedNotes, attachRepo outattachments.Repository, attachStore outattachments.Store) Service {
+	return &service{repo: repo, notes: notes, attachRepo: attachRepo, attachStore: attachStore}
 }
 
 func (s *service) Create(ctx context.Context, req *pt.CreatePatientRequest) (*pt.Patient, error) {
@@ -40,6 +52,10 @@ func (s *service) Create(ctx context.Context, req *pt.CreatePatientRequest) (*pt
 	if strings.TrimSpace(req.GetFirstName()) == "" || strings.TrimSpace(req.GetLastName()) == "" {
 		return nil, fmt.Errorf("create patient: %w", se.ErrInvalidRequest)
 	}
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return nil, fmt.Errorf("create patient: %w", err)
+	}
 	now := time.Now().UTC()
 	p := &pt.Patient{
 		Uuid:        uuid.NewString(),
@@ -50,6 +66,7 @@ func (s *service) Create(ctx context.Context, req *pt.CreatePatientRequest) (*pt
 		Address:     normalizeWrapper(req.Address),
 		DateOfBirth: normalizeWrapper(req.DateOfBirth),
 		Sex:         normalizeWrapper(req.Sex),
+		Email:       email,
 		CreatedAt:   timestamppb.New(now),
 		UpdatedAt:   nil,
 	}
@@ -97,6 +114,11 @@ func (s *service) Update(ctx context.Context, req *pt.UpdatePatientRequest) (*pt
 	if req.Sex != nil {
 		existing.Sex = normalizeWrapper(req.Sex)
 	}
+	if req.Email != nil {
+		if existing.Email, err = normalizeEmail(req.Email); err != nil {
+			return nil, fmt.Errorf("update patient: %w", err)
+		}
+	}
 	now := time.Now().UTC()
 	existing.UpdatedAt = timestamppb.New(now)
 	updated, err := s.repo.Update(ctx, existing)
@@ -131,12 +153,32 @@ func (s *service) Delete(ctx context.Context, uuid string) error {
 	if strings.TrimSpace(uuid) == "" {
 		return fmt.Errorf("delete patient: %w", se.ErrInvalidRequest)
 	}
+	locked, err := s.notes.HasLockedNotes(ctx, uuid)
+	if err != nil {
+		return fmt.Errorf("delete patient: %w", err)
+	}
+	if locked {
+		return fmt.Errorf("delete patient: %s has signed or locked notes: %w", uuid, se.ErrConflict)
+	}
+	// The attachment rows go with the patient; their files are removed once the rows are gone.
+	keys, err := s.attachRepo.StorageKeys(ctx, "", uuid)
+	if err != nil {
+		return fmt.Errorf("delete patient: %w", err)
+	}
 	if err := s.repo.Delete(ctx, uuid); err != nil {
 		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
 			return fmt.Errorf("delete patient: %w", se.ErrNotFound)
 		}
+		if errors.Is(err, re.ErrConflict) {
+			return fmt.Errorf("delete patient: %s has signed or locked notes: %w", uuid, se.ErrConflict)
+		}
 		return fmt.Errorf("delete patient: %w", err)
 	}
+	for _, key := range keys {
+		if err := s.attachStore.Remove(ctx, key); err != nil {
+			log.Printf("patients: removing attachment file %s of deleted patient %s: %v", key, uuid, err)
+		}
+	}
 	return nil
 }
 
@@ -151,6 +193,19 @@ func normalizeWrapper(w *wrapperspb.StringValue) *wrapperspb.StringValue {
 	return &wrapperspb.StringValue{Value: val}
 }
 
+// normalizeEmail trims the address and rejects values that cannot receive reminders.
+func normalizeEmail(w *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	w = normalizeWrapper(w)
+	if w == nil {
+		return nil, nil
+	}
+	addr, err := mail.ParseAddress(w.GetValue())
+	if err != nil || addr.Address != w.GetValue() {
+		return nil, fmt.Errorf("invalid email %q: %w", w.GetValue(), se.ErrInvalidRequest)
+	}
+	return w, nil
+}
+
 func isUniqueViolation(err error) bool {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
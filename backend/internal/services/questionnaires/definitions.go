@@ -0,0 +1,348 @@
+package questionnaires
+
+import (
+	"strconv"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+const (
+	CodeODI  = "odi"
+	CodeNDI  = "ndi"
+	CodeDASH = "dash"
+	CodeKOOS = "koos"
+	CodeLEFS = "lefs"
+)
+
+// definition is a built-in questionnaire. Item texts are short Croatian labels of the official
+// questions; the full wording is on the licensed paper forms.
+type definition struct {
+	code           string
+	name           string
+	description    string
+	unit           string
+	itemMin        int32
+	itemMax        int32
+	items          []item
+	subscales      []subscale
+	hasTotal       bool
+	scoreMin       float64
+	scoreMax       float64
+	higherIsBetter bool
+	mcid           float64 // minimal clinically important difference of the total (or each subscale for KOOS)
+	score          func(def *definition, answers map[int32]int32) (scored, error)
+}
+
+type item struct {
+	code     string
+	text     string
+	subscale string
+}
+
+type subscale struct {
+	code string
+	name string
+}
+
+// definitions lists the questionnaires in the order the UI offers them.
+var definitions = []*definition{
+	{
+		code:        CodeODI,
+		name:        "Oswestry Disability Index (ODI 2.1a)",
+		description: "10 područja, odgovori 0–5. Rezultat 0–100 %, viši rezultat znači veću onesposobljenost.",
+		unit:        "%",
+		itemMin:     0,
+		itemMax:     5,
+		items: plainItems(
+			"Intenzitet boli",
+			"Osobna njega (pranje, oblačenje)",
+			"Podizanje tereta",
+			"Hodanje",
+			"Sjedenje",
+			"Stajanje",
+			"Spavanje",
+			"Spolni život",
+			"Društveni život",
+			"Putovanje",
+		),
+		hasTotal: true,
+		scoreMax: 100,
+		mcid:     10, // Ostelo et al. 2008
+		score:    scorePercentOfAnswered,
+	},
+	{
+		code:        CodeNDI,
+		name:        "Neck Disability Index (NDI)",
+		description: "10 područja, odgovori 0–5. Rezultat 0–100 %, viši rezultat znači veću onesposobljenost.",
+		unit:        "%",
+		itemMin:     0,
+		itemMax:     5,
+		items: plainItems(
+			"Intenzitet boli",
+			"Osobna njega (pranje, oblačenje)",
+			"Podizanje tereta",
+			"Čitanje",
+			"Glavobolja",
+			"Koncentracija",
+			"Rad",
+			"Vožnja",
+			"Spavanje",
+			"Rekreacija",
+		),
+		hasTotal: true,
+		scoreMax: 100,
+		mcid:     10, // 5 of 50 points, MacDermid et al. 2009
+		score:    scorePercentOfAnswered,
+	},
+	{
+		code:        CodeDASH,
+		name:        "Disabilities of the Arm, Shoulder and Hand (DASH)",
+		description: "30 pitanja i dva neobavezna modula (rad; sport i glazba), odgovori 1–5. Rezultat 0–100, viši rezultat znači veću onesposobljenost.",
+		itemMin:     1,
+		itemMax:     5,
+		items: concatItems(
+			plainItems(
+				"Otvoriti čvrsto zatvorenu staklenku",
+				"Pisati",
+				"Okrenuti ključ",
+				"Pripremiti obrok",
+				"Gurnuti i otvoriti teška vrata",
+				"Staviti predmet na policu iznad glave",
+				"Teški kućanski poslovi (pranje zidova, podova)",
+				"Rad u vrtu ili dvorištu",
+				"Namjestiti krevet",
+				"Nositi vrećicu s namirnicama ili aktovku",
+				"Nositi težak predmet (preko 5 kg)",
+				"Promijeniti žarulju iznad glave",
+				"Oprati ili osušiti kosu",
+				"Oprati leđa",
+				"Obući džemper",
+				"Rezati hranu nožem",
+				"Lagane rekreacijske aktivnosti (kartanje, pletenje)",
+				"Rekreacija uz silu ili udarac kroz ruku (golf, tenis)",
+				"Rekreacija uz slobodno pomicanje ruke (plivanje, badminton)",
+				"Snalaženje u prijevozu",
+				"Spolne aktivnosti",
+				"Utjecaj na druženje s obitelji, prijateljima, susjedima",
+				"Ograničenje na poslu ili u svakodnevnim aktivnostima",
+				"Bol u ruci, ramenu ili šaci",
+				"Bol u ruci, ramenu ili šaci pri određenoj aktivnosti",
+				"Trnci u ruci, ramenu ili šaci",
+				"Slabost ruke, ramena ili šake",
+				"Ukočenost ruke, ramena ili šake",
+				"Teškoće sa spavanjem zbog boli",
+				"Osjećaj manje sposobnosti i samopouzdanja",
+			),
+			subscaleItems("W", "work",
+				"Rad uobičajenom tehnikom",
+				"Obavljanje uobičajenog posla zbog boli",
+				"Obavljanje posla onako dobro kako biste željeli",
+				"Obavljanje posla u uobičajenom vremenu",
+			),
+			subscaleItems("S", "sports",
+				"Sviranje ili sport uobičajenom tehnikom",
+				"Sviranje ili sport zbog boli",
+				"Sviranje ili sport onako dobro kako biste željeli",
+				"Sviranje ili sport u uobičajenom vremenu",
+			),
+		),
+		subscales: []subscale{
+			{code: "work", name: "Rad"},
+			{code: "sports", name: "Sport i glazba"},
+		},
+		hasTotal: true,
+		scoreMax: 100,
+		mcid:     10.2, // Beaton et al. 2001
+		score:    scoreDASH,
+	},
+	{
+		code:        CodeKOOS,
+		name:        "Knee injury and Osteoarthritis Outcome Score (KOOS)",
+		description: "42 pitanja u pet podljestvica, odgovori 0–4 (0 = bez tegoba). Svaka podljestvica 0–100, viši rezultat znači manje tegoba.",
+		itemMin:     0,
+		itemMax:     4,
+		items: concatItems(
+			subscaleItems("S", "symptoms",
+				"Oteklina koljena",
+				"Škripanje, klikanje ili drugi zvukovi u koljenu",
+				"Zapinjanje ili blokiranje koljena",
+				"Potpuno ispružiti koljeno",
+				"Potpuno saviti koljeno",
+				"Ukočenost nakon buđenja",
+				"Ukočenost nakon sjedenja, ležanja ili odmora kasnije tijekom dana",
+			),
+			subscaleItems("P", "pain",
+				"Učestalost boli u koljenu",
+				"Okretanje na koljenu",
+				"Potpuno ispružanje koljena",
+				"Potpuno savijanje koljena",
+				"Hodanje po ravnom",
+				"Hodanje uz ili niz stepenice",
+				"Noću u krevetu",
+				"Sjedenje ili ležanje",
+				"Uspravno stajanje",
+			),
+			subscaleItems("A", "adl",
+				"Silaženje niz stepenice",
+				"Penjanje uz stepenice",
+				"Ustajanje iz sjedećeg položaja",
+				"Stajanje",
+				"Saginjanje do poda ili podizanje predmeta",
+				"Hodanje po ravnom",
+				"Ulazak u automobil ili izlazak",
+				"Odlazak u kupovinu",
+				"Obuvanje čarapa",
+				"Ustajanje iz kreveta",
+				"Izuvanje čarapa",
+				"Ležanje u krevetu (okretanje, držanje položaja koljena)",
+				"Ulazak u kadu ili izlazak",
+				"Sjedenje",
+				"Sjedanje na WC ili ustajanje",
+				"Teški kućanski poslovi",
+				"Lagani kućanski poslovi",
+			),
+			subscaleItems("SP", "sport",
+				"Čučanje",
+				"Trčanje",
+				"Skakanje",
+				"Okretanje na ozlijeđenom koljenu",
+				"Klečanje",
+			),
+			subscaleItems("Q", "qol",
+				"Koliko često ste svjesni problema s koljenom",
+				"Promjena načina života zbog koljena",
+				"Nepovjerenje u koljeno",
+				"Općenite teškoće s koljenom",
+			),
+		),
+		subscales: []subscale{
+			{code: "symptoms", name: "Simptomi"},
+			{code: "pain", name: "Bol"},
+			{code: "adl", name: "Svakodnevne aktivnosti"},
+			{code: "sport", name: "Sport i rekreacija"},
+			{code: "qol", name: "Kvaliteta života"},
+		},
+		scoreMax:       100,
+		higherIsBetter: true,
+		mcid:           8, // per subscale, KOOS user's guide
+		score:          scoreKOOS,
+	},
+	{
+		code:        CodeLEFS,
+		name:        "Lower Extremity Functional Scale (LEFS)",
+		description: "20 aktivnosti, odgovori 0–4 (0 = izrazite teškoće ili nemogućnost, 4 = bez teškoća). Rezultat 0–80, viši rezultat znači bolju funkciju.",
+		itemMin:     0,
+		itemMax:     4,
+		items: plainItems(
+			"Uobičajeni poslovi, kućanstvo ili škola",
+			"Uobičajeni hobiji, rekreacija ili sport",
+			"Ulazak u kadu ili izlazak",
+			"Hodanje između soba",
+			"Obuvanje cipela ili čarapa",
+			"Čučanje",
+			"Podizanje predmeta s poda (npr. vrećice s namirnicama)",
+			"Lakši kućanski poslovi",
+			"Teži kućanski poslovi",
+			"Ulazak u automobil ili izlazak",
+			"Hodanje dva bloka",
+			"Hodanje 1,5 km",
+			"Penjanje ili silaženje 10 stepenica (otprilike jedan kat)",
+			"Stajanje 1 sat",
+			"Sjedenje 1 sat",
+			"Trčanje po ravnom",
+			"Trčanje po neravnom",
+			"Nagle promjene smjera pri brzom trčanju",
+			"Poskakivanje",
+			"Okretanje u krevetu",
+		),
+		hasTotal:       true,
+		scoreMax:       80,
+		higherIsBetter: true,
+		mcid:           9, // Binkley et al. 1999
+		score:          scoreLEFS,
+	},
+}
+
+func plainItems(texts ...string) []item {
+	res := make([]item, 0, len(texts))
+	for _, t := range texts {
+		res = append(res, item{text: t})
+	}
+	return res
+}
+
+// subscaleItems numbers the items of a subscale with its prefix, e.g. P1..P9.
+func subscaleItems(prefix, sub string, texts ...string) []item {
+	res := make([]item, 0, len(texts))
+	for i, t := range texts {
+		res = append(res, item{code: prefix + strconv.Itoa(i+1), text: t, subscale: sub})
+	}
+	return res
+}
+
+func concatItems(groups ...[]item) []item {
+	var res []item
+	for _, g := range groups {
+		res = append(res, g...)
+	}
+	return res
+}
+
+func findDefinition(code string) *definition {
+	for _, d := range definitions {
+		if d.code == code {
+			return d
+		}
+	}
+	return nil
+}
+
+// Name returns the display name of a questionnaire code, or the code itself when unknown.
+func Name(code string) string {
+	if d := findDefinition(code); d != nil {
+		return d.name
+	}
+	return code
+}
+
+// Trend describes how a questionnaire score (subscale "" = the total) should be read over time:
+// its display unit, whether a higher score is better and the minimal clinically important
+// difference, 0 when none is established (e.g. the DASH optional modules).
+func Trend(code, subscale string) (unit string, higherIsBetter bool, mcid float64) {
+	d := findDefinition(code)
+	if d == nil {
+		return "", false, 0
+	}
+	if subscale != "" && d.code != CodeKOOS {
+		return d.unit, d.higherIsBetter, 0
+	}
+	return d.unit, d.higherIsBetter, d.mcid
+}
+
+func (d *definition) toPB() *pb.QuestionnaireDefinition {
+	res := &pb.QuestionnaireDefinition{
+		Code:           d.code,
+		Name:           d.name,
+		Description:    d.description,
+		HasTotal:       d.hasTotal,
+		ScoreMin:       d.scoreMin,
+		ScoreMax:       d.scoreMax,
+		HigherIsBetter: d.higherIsBetter,
+		Unit:           d.unit,
+		Mcid:           d.mcid,
+	}
+	for i, it := range d.items {
+		res.Items = append(res.Items, &pb.QuestionnaireItem{
+			Number:   int32(i + 1),
+			Code:     it.code,
+			Text:     it.text,
+			Subscale: it.subscale,
+			Min:      d.itemMin,
+			Max:      d.itemMax,
+		})
+	}
+	for _, s := range d.subscales {
+		res.Subscales = append(res.Subscales, &pb.QuestionnaireSubscale{Code: s.code, Name: s.name})
+	}
+	return res
+}
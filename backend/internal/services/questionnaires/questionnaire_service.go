@@ -0,0 +1,171 @@
+package questionnaires
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	outanamneses "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/anamneses"
+	outboundportpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/questionnaires"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Definitions() []*pb.QuestionnaireDefinition
+	Create(ctx context.Context, doctorUUID string, req *pb.CreateQuestionnaireResultRequest) (*pb.QuestionnaireResult, error)
+	List(ctx context.Context, doctorUUID, patientUUID, code string) ([]*pb.QuestionnaireResult, error)
+	Delete(ctx context.Context, doctorUUID, patientUUID, uuid string) error
+}
+
+type service struct {
+	repo          out.Repository
+	patientRepo   outboundportpatients.Repository
+	anamnesisRepo outanamneses.Repository
+	now           func() time.Time
+}
+
+func NewService(repo out.Repository, pRepo outboundportpatients.Repository, aRepo outanamneses.Repository) Service {
+	return &service{repo: repo, patientRepo: pRepo, anamnesisRepo: aRepo, now: time.Now}
+}
+
+func (s *service) Definitions() []*pb.QuestionnaireDefinition {
+	res := make([]*pb.QuestionnaireDefinition, 0, len(definitions))
+	for _, d := range definitions {
+		res = append(res, d.toPB())
+	}
+	return res
+}
+
+// Create scores the answers server-side and stores the result; the client never sends a score.
+func (s *service) Create(ctx context.Context, doctorUUID string, req *pb.CreateQuestionnaireResultRequest) (*pb.QuestionnaireResult, error) {
+	def := findDefinition(strings.ToLower(strings.TrimSpace(req.GetCode())))
+	if def == nil {
+		return nil, fmt.Errorf("create questionnaire result: unknown questionnaire %q: %w", req.GetCode(), se.ErrInvalidRequest)
+	}
+	patientUUID := strings.TrimSpace(req.GetPatientUuid())
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("create questionnaire result: %w", err)
+	}
+	anamnesisUUID := strings.TrimSpace(req.GetAnamnesisUuid())
+	if anamnesisUUID != "" {
+		anm, err := s.anamnesisRepo.Get(ctx, anamnesisUUID)
+		if err != nil {
+			if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("create questionnaire result: anamnesis: %w", se.ErrNotFound)
+			}
+			return nil, fmt.Errorf("create questionnaire result: load anamnesis: %w", err)
+		}
+		if anm.GetPatientUuid() != patientUUID {
+			return nil, fmt.Errorf("create questionnaire result: anamnesis belongs to another patient: %w", se.ErrInvalidRequest)
+		}
+	}
+	sc, err := score(def, req.GetAnswers())
+	if err != nil {
+		return nil, fmt.Errorf("create questionnaire result: %w", err)
+	}
+
+	now := s.now().UTC()
+	completedAt := now
+	if req.GetCompletedAt() != nil {
+		if completedAt = req.GetCompletedAt().AsTime().UTC(); completedAt.After(now) {
+			return nil, fmt.Errorf("create questionnaire result: completed_at is in the future: %w", se.ErrInvalidRequest)
+		}
+	}
+	res := &pb.QuestionnaireResult{
+		Uuid:          uuid.NewString(),
+		DoctorUuid:    doctorUUID,
+		PatientUuid:   patientUUID,
+		AnamnesisUuid: anamnesisUUID,
+		Code:          def.code,
+		Answers:       req.GetAnswers(),
+		Subscores:     sc.subscores,
+		MissingItems:  sc.missing,
+		CompletedAt:   timestamppb.New(completedAt),
+		CreatedAt:     timestamppb.New(now),
+	}
+	if sc.total != nil {
+		res.Score = wrapperspb.Double(*sc.total)
+	}
+	created, err := s.repo.Create(ctx, res)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("create questionnaire result: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("create questionnaire result: %w", err)
+	}
+	return created, nil
+}
+
+func (s *service) List(ctx context.Context, doctorUUID, patientUUID, code string) ([]*pb.QuestionnaireResult, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code != "" && findDefinition(code) == nil {
+		return nil, fmt.Errorf("list questionnaire results: unknown questionnaire %q: %w", code, se.ErrInvalidRequest)
+	}
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("list questionnaire results: %w", err)
+	}
+	list, err := s.repo.List(ctx, patientUUID, code)
+	if err != nil {
+		return nil, fmt.Errorf("list questionnaire results: %w", err)
+	}
+	return list, nil
+}
+
+func (s *service) Delete(ctx context.Context, doctorUUID, patientUUID, resultUUID string) error {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return fmt.Errorf("delete questionnaire result: %w", err)
+	}
+	res, err := s.repo.Get(ctx, resultUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return fmt.Errorf("delete questionnaire result: %w", se.ErrNotFound)
+		}
+		return fmt.Errorf("delete questionnaire result: %w", err)
+	}
+	if res.GetPatientUuid() != patientUUID {
+		return fmt.Errorf("delete questionnaire result: %w", se.ErrNotFound)
+	}
+	if err := s.repo.Delete(ctx, resultUUID); err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return fmt.Errorf("delete questionnaire result: %w", se.ErrNotFound)
+		}
+		return fmt.Errorf("delete questionnaire result: %w", err)
+	}
+	return nil
+}
+
+// Summary renders a result on one line for the visit PDF,
+// e.g. "ODI: 32,0 %" or "KOOS: Bol 61,1; Simptomi 75,0".
+func Summary(res *pb.QuestionnaireResult) string {
+	label := strings.ToUpper(res.GetCode())
+	parts := []string{}
+	if res.Score != nil {
+		unit := ""
+		if d := findDefinition(res.GetCode()); d != nil && d.unit != "" {
+			unit = " " + d.unit
+		}
+		parts = append(parts, formatScore(res.GetScore().GetValue())+unit)
+	}
+	for _, sub := range res.GetSubscores() {
+		parts = append(parts, sub.GetName()+" "+formatScore(sub.GetScore()))
+	}
+	line := label + ": " + strings.Join(parts, "; ")
+	if res.GetMissingItems() > 0 {
+		line += fmt.Sprintf(" (bez odgovora: %d)", res.GetMissingItems())
+	}
+	return line
+}
+
+func formatScore(v float64) string {
+	return strings.Replace(fmt.Sprintf("%.1f", v), ".", ",", 1)
+}
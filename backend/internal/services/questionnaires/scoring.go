@@ -0,0 +1,120 @@
+package questionnaires
+
+import (
+	"fmt"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+)
+
+// scored is the outcome of a scoring function. total is nil when the questionnaire has no total.
+type scored struct {
+	total     *float64
+	subscores []*pb.QuestionnaireSubscore
+	missing   int32
+}
+
+// tally sums the answered items of one subscale ("" = the items that count towards the total).
+func (d *definition) tally(answers map[int32]int32, sub string) (sum float64, answered, missing int) {
+	for i, it := range d.items {
+		if it.subscale != sub {
+			continue
+		}
+		if v, ok := answers[int32(i+1)]; ok {
+			sum += float64(v)
+			answered++
+		} else {
+			missing++
+		}
+	}
+	return sum, answered, missing
+}
+
+// scorePercentOfAnswered scores the ODI (Fairbank & Pynsent 2000) and the NDI (Vernon & Mior 1991)
+// as a percentage of the maximum possible for the answered sections: total / (5 × answered) × 100.
+// Missing sections are left out of the denominator; more than two missing sections are not scored.
+func scorePercentOfAnswered(d *definition, answers map[int32]int32) (scored, error) {
+	sum, answered, missing := d.tally(answers, "")
+	if missing > 2 {
+		return scored{}, fmt.Errorf("%s: %d of %d sections missing, at most 2 allowed: %w", d.code, missing, len(d.items), se.ErrInvalidRequest)
+	}
+	total := sum / (float64(d.itemMax) * float64(answered)) * 100
+	return scored{total: &total, missing: int32(missing)}, nil
+}
+
+// scoreDASH follows the DASH scoring manual: ((sum of n responses / n) - 1) × 25 over the 30 main
+// items, not calculated with more than 3 missing items. An optional module (work, sports/music)
+// is only scored when all four of its items are answered.
+func scoreDASH(d *definition, answers map[int32]int32) (scored, error) {
+	sum, answered, missing := d.tally(answers, "")
+	if missing > 3 {
+		return scored{}, fmt.Errorf("dash: %d items missing, at most 3 allowed: %w", missing, se.ErrInvalidRequest)
+	}
+	total := (sum/float64(answered) - 1) * 25
+	res := scored{total: &total, missing: int32(missing)}
+	for _, sub := range d.subscales {
+		sum, answered, missing := d.tally(answers, sub.code)
+		if missing > 0 || answered == 0 {
+			continue
+		}
+		res.subscores = append(res.subscores, &pb.QuestionnaireSubscore{
+			Code:  sub.code,
+			Name:  sub.name,
+			Score: (sum/float64(answered) - 1) * 25,
+		})
+	}
+	return res, nil
+}
+
+// scoreKOOS follows the KOOS user's guide: each subscale is 100 - mean × 25, where missing items
+// take the subscale mean; a subscale with more than two missing items is not calculated.
+// KOOS has no total score.
+func scoreKOOS(d *definition, answers map[int32]int32) (scored, error) {
+	var res scored
+	for _, sub := range d.subscales {
+		sum, answered, missing := d.tally(answers, sub.code)
+		res.missing += int32(missing)
+		if missing > 2 {
+			continue
+		}
+		res.subscores = append(res.subscores, &pb.QuestionnaireSubscore{
+			Code:         sub.code,
+			Name:         sub.name,
+			Score:        100 - sum/float64(answered)*100/float64(d.itemMax),
+			MissingItems: int32(missing),
+		})
+	}
+	if len(res.subscores) == 0 {
+		return scored{}, fmt.Errorf("koos: every subscale has more than 2 missing items: %w", se.ErrInvalidRequest)
+	}
+	return res, nil
+}
+
+// scoreLEFS sums the 20 items (Binkley et al. 1999). The original publication has no rule for
+// missing items, so up to 4 missing items are prorated from the answered ones.
+func scoreLEFS(d *definition, answers map[int32]int32) (scored, error) {
+	sum, answered, missing := d.tally(answers, "")
+	if missing > 4 {
+		return scored{}, fmt.Errorf("lefs: %d items missing, at most 4 allowed: %w", missing, se.ErrInvalidRequest)
+	}
+	total := sum / float64(answered) * float64(len(d.items))
+	return scored{total: &total, missing: int32(missing)}, nil
+}
+
+// score validates the answers against the definition and computes the result.
+func score(d *definition, answers []*pb.QuestionnaireAnswer) (scored, error) {
+	byItem := make(map[int32]int32, len(answers))
+	for _, a := range answers {
+		if a.GetItem() < 1 || int(a.GetItem()) > len(d.items) {
+			return scored{}, fmt.Errorf("%s: no item %d: %w", d.code, a.GetItem(), se.ErrInvalidRequest)
+		}
+		if a.GetValue() < d.itemMin || a.GetValue() > d.itemMax {
+			return scored{}, fmt.Errorf("%s: item %d must be between %d and %d: %w", d.code, a.GetItem(), d.itemMin, d.itemMax, se.ErrInvalidRequest)
+		}
+		if _, dup := byItem[a.GetItem()]; dup {
+			return scored{}, fmt.Errorf("%s: item %d answered twice: %w", d.code, a.GetItem(), se.ErrInvalidRequest)
+		}
+		byItem[a.GetItem()] = a.GetValue()
+	}
+	return d.score(d, byItem)
+}
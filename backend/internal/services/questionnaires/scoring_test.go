@@ -0,0 +1,216 @@
+package questionnaires
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+)
+
+// answers numbers the values from item 1; -1 leaves an item unanswered.
+func answers(values ...int32) []*pb.QuestionnaireAnswer {
+	var res []*pb.QuestionnaireAnswer
+	for i, v := range values {
+		if v >= 0 {
+			res = append(res, &pb.QuestionnaireAnswer{Item: int32(i + 1), Value: v})
+		}
+	}
+	return res
+}
+
+func repeat(v int32, n int) []int32 {
+	res := make([]int32, n)
+	for i := range res {
+		res[i] = v
+	}
+	return res
+}
+
+func concat(groups ...[]int32) []int32 {
+	var res []int32
+	for _, g := range groups {
+		res = append(res, g...)
+	}
+	return res
+}
+
+func TestScore(t *testing.T) {
+	koosPain := []int32{2, 1, 2, 1, 2, 2, 1, 2, 1} // sum 14
+	tests := []struct {
+		name      string
+		code      string
+		values    []int32
+		wantTotal *float64 // nil: no total expected
+		wantSubs  map[string]float64
+		wantMiss  int32
+		wantErr   bool
+	}{
+		{
+			// Fairbank & Pynsent (2000): "16 (total scored) / 50 (total possible score) x 100 = 32%".
+			name:      "odi published example",
+			code:      CodeODI,
+			values:    []int32{3, 2, 2, 1, 2, 2, 1, 0, 2, 1},
+			wantTotal: ptr(32),
+		},
+		{
+			// Same paper, one section missed: "16 / 45 x 100 = 35.5%" (printed truncated).
+			name:      "odi published example with one section missed",
+			code:      CodeODI,
+			values:    []int32{3, 2, 2, 1, 2, 2, 1, -1, 2, 1},
+			wantTotal: ptr(16.0 / 45 * 100),
+			wantMiss:  1,
+		},
+		{
+			name:    "odi with three sections missed",
+			code:    CodeODI,
+			values:  []int32{3, 2, 2, 1, 2, 2, 1, -1, -1, -1},
+			wantErr: true,
+		},
+		{
+			// Vernon & Mior (1991): raw 0-50, reported as a percentage (raw 20 = 40%).
+			name:      "ndi raw score as percentage",
+			code:      CodeNDI,
+			values:    []int32{2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+			wantTotal: ptr(40),
+		},
+		{
+			name:      "dash no disability",
+			code:      CodeDASH,
+			values:    repeat(1, 30),
+			wantTotal: ptr(0),
+		},
+		{
+			name:      "dash most severe disability",
+			code:      CodeDASH,
+			values:    repeat(5, 30),
+			wantTotal: ptr(100),
+		},
+		{
+			// DASH manual: ((sum of n responses / n) - 1) x 25; three missing items are allowed.
+			name:      "dash with three missing items and complete work module",
+			code:      CodeDASH,
+			values:    concat(repeat(2, 27), repeat(-1, 3), []int32{2, 2, 3, 3}, []int32{4, -1, 4, 4}),
+			wantTotal: ptr(25),
+			wantSubs:  map[string]float64{"work": 37.5},
+			wantMiss:  3,
+		},
+		{
+			name:    "dash with four missing items",
+			code:    CodeDASH,
+			values:  concat(repeat(2, 26), repeat(-1, 4)),
+			wantErr: true,
+		},
+		{
+			// KOOS user's guide: Pain = 100 - (P1..P9 sum x 100) / 36; missing items take the
+			// subscale mean and a subscale with more than two missing items is not scored.
+			name: "koos subscales with missing items",
+			code: CodeKOOS,
+			values: concat(
+				[]int32{0, 0, 0, 0, 0, -1, -1},
+				koosPain,
+				repeat(4, 17),
+				[]int32{2, 2, 2, 2, 2},
+				[]int32{1, -1, -1, -1},
+			),
+			wantSubs: map[string]float64{
+				"symptoms": 100,
+				"pain":     100 - 14.0*100/36,
+				"adl":      0,
+				"sport":    50,
+			},
+			wantMiss: 5,
+		},
+		{
+			name:    "koos with every subscale missing",
+			code:    CodeKOOS,
+			values:  []int32{1},
+			wantErr: true,
+		},
+		{
+			name:      "lefs full function",
+			code:      CodeLEFS,
+			values:    repeat(4, 20),
+			wantTotal: ptr(80),
+		},
+		{
+			name:      "lefs prorates missing items",
+			code:      CodeLEFS,
+			values:    concat(repeat(3, 16), repeat(-1, 4)),
+			wantTotal: ptr(60),
+			wantMiss:  4,
+		},
+		{
+			name:    "lefs with five missing items",
+			code:    CodeLEFS,
+			values:  concat(repeat(3, 15), repeat(-1, 5)),
+			wantErr: true,
+		},
+		{
+			name:    "answer out of range",
+			code:    CodeODI,
+			values:  []int32{6, 2, 2, 1, 2, 2, 1, 0, 2, 1},
+			wantErr: true,
+		},
+		{
+			name:    "unknown item",
+			code:    CodeNDI,
+			values:  repeat(1, 11),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := score(findDefinition(tt.code), answers(tt.values...))
+			if tt.wantErr {
+				if !errors.Is(err, se.ErrInvalidRequest) {
+					t.Fatalf("want invalid request, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case tt.wantTotal == nil && got.total != nil:
+				t.Errorf("total = %v, want none", *got.total)
+			case tt.wantTotal != nil && got.total == nil:
+				t.Errorf("total missing, want %v", *tt.wantTotal)
+			case tt.wantTotal != nil && !near(*got.total, *tt.wantTotal):
+				t.Errorf("total = %v, want %v", *got.total, *tt.wantTotal)
+			}
+			if len(got.subscores) != len(tt.wantSubs) {
+				t.Errorf("got %d subscores, want %d", len(got.subscores), len(tt.wantSubs))
+			}
+			for _, sub := range got.subscores {
+				if want, ok := tt.wantSubs[sub.GetCode()]; !ok || !near(sub.GetScore(), want) {
+					t.Errorf("subscore %s = %v, want %v", sub.GetCode(), sub.GetScore(), want)
+				}
+			}
+			if got.missing != tt.wantMiss {
+				t.Errorf("missing = %d, want %d", got.missing, tt.wantMiss)
+			}
+		})
+	}
+}
+
+func TestScoreRejectsDuplicateAnswers(t *testing.T) {
+	ans := append(answers(repeat(2, 10)...), &pb.QuestionnaireAnswer{Item: 3, Value: 1})
+	if _, err := score(findDefinition(CodeODI), ans); !errors.Is(err, se.ErrInvalidRequest) {
+		t.Fatalf("want invalid request, got %v", err)
+	}
+}
+
+func TestDefinitionsItemCounts(t *testing.T) {
+	want := map[string]int{CodeODI: 10, CodeNDI: 10, CodeDASH: 38, CodeKOOS: 42, CodeLEFS: 20}
+	for code, n := range want {
+		if got := len(findDefinition(code).items); got != n {
+			t.Errorf("%s has %d items, want %d", code, got, n)
+		}
+	}
+}
+
+func ptr(v float64) *float64 { return &v }
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
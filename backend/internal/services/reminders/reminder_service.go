@@ -0,0 +1,495 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	outappointments "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/appointments"
+	outdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctorprofiles"
+	outdoctors "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctors"
+	outboundportpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/reminders"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcappointments "github.com/OPetricevic/physio-tracker/backend/internal/services/appointments"
+	svcdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/services/doctorprofiles"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+
+	StatusPending   = "pending"
+	StatusSending   = "sending"
+	StatusSent      = "sent"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+const (
+	defaultHoursBefore = 24
+	maxHoursBefore     = 7 * 24
+	// maxAttempts bounds retries of temporary failures; rejected messages fail at once.
+	maxAttempts = 5
+	claimBatch  = 50
+	// staleAfter is how long a reminder may stay in "sending". Older ones were interrupted by a
+	// crash; they are failed rather than resent because the message may already have gone out.
+	staleAfter = 10 * time.Minute
+)
+
+type Service interface {
+	GetSettings(ctx context.Context, doctorUUID string) (*pb.ReminderSettings, error)
+	UpdateSettings(ctx context.Context, doctorUUID string, req *pb.UpdateReminderSettingsRequest) (*pb.ReminderSettings, error)
+	List(ctx context.Context, doctorUUID, status string, from, to time.Time) ([]*pb.Reminder, error)
+	ListForAppointment(ctx context.Context, doctorUUID, appointmentUUID string) ([]*pb.Reminder, error)
+	// ListForOccurrence lists the reminders of a series occurrence by its original start; virtual
+	// occurrences have no appointment uuid yet.
+	ListForOccurrence(ctx context.Context, doctorUUID, seriesUUID string, originalStart time.Time) ([]*pb.Reminder, error)
+	Retry(ctx context.Context, doctorUUID, reminderUUID string) (*pb.Reminder, error)
+
+	// EnqueueDue adds outbox rows for upcoming appointments of doctors with reminders enabled.
+	EnqueueDue(ctx context.Context) (int, error)
+	// DispatchDue sends due reminders and returns how many were delivered.
+	DispatchDue(ctx context.Context) (int, error)
+}
+
+type service struct {
+	repo            out.Repository
+	appointments    svcappointments.Service
+	appointmentRepo outappointments.Repository
+	patientRepo     outboundportpatients.Repository
+	doctorRepo      outdoctors.Repository
+	profileRepo     outdoctorprofiles.Repository
+	notifiers       map[string]out.Notifier
+	countryCode     string
+	now             func() time.Time
+}
+
+// NewService wires the reminder outbox. notifiers maps a channel (email, sms) to its sender;
+// channels without a notifier are not offered. SMS_COUNTRY_CODE (default 385) completes local
+// phone numbers.
+func NewService(
+	repo out.Repository,
+	appointments svcappointments.Service,
+	appointmentRepo outappointments.Repository,
+	pRepo outboundportpatients.Repository,
+	dRepo outdoctors.Repository,
+	profRepo outdoctorprofiles.Repository,
+	notifiers map[string]out.Notifier) Service {
+	countryCode := strings.TrimPrefix(strings.TrimSpace(os.Getenv("SMS_COUNTRY_CODE")), "+")
+	if countryCode == "" {
+		countryCode = "385"
+	}
+	return &service{
+		repo:            repo,
+		appointments:    appointments,
+		appointmentRepo: appointmentRepo,
+		patientRepo:     pRepo,
+		doctorRepo:      dRepo,
+		profileRepo:     profRepo,
+		notifiers:       notifiers,
+		countryCode:     countryCode,
+		now:             time.Now,
+	}
+}
+
+func (s *service) GetSettings(ctx context.Context, doctorUUID string) (*pb.ReminderSettings, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("get reminder settings: %w", se.ErrInvalidRequest)
+	}
+	set, err := s.repo.GetSettings(ctx, doctorUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return defaultSettings(doctorUUID), nil
+		}
+		return nil, fmt.Errorf("get reminder settings: %w", err)
+	}
+	return set, nil
+}
+
+func (s *service) UpdateSettings(ctx context.Context, doctorUUID string, req *pb.UpdateReminderSettingsRequest) (*pb.ReminderSettings, error) {
+	if strings.TrimSpace(doctorUUID) == "" {
+		return nil, fmt.Errorf("update reminder settings: %w", se.ErrInvalidRequest)
+	}
+	hours := req.GetHoursBefore()
+	if hours == 0 {
+		hours = defaultHoursBefore
+	}
+	if hours < 1 || hours > maxHoursBefore {
+		return nil, fmt.Errorf("update reminder settings: hours_before must be 1-%d: %w", maxHoursBefore, se.ErrInvalidRequest)
+	}
+	var channels []string
+	seen := map[string]bool{}
+	for _, ch := range req.GetChannels() {
+		ch = strings.ToLower(strings.TrimSpace(ch))
+		if ch != ChannelEmail && ch != ChannelSMS {
+			return nil, fmt.Errorf("update reminder settings: unknown channel %q: %w", ch, se.ErrInvalidRequest)
+		}
+		if req.GetEnabled() && s.notifiers[ch] == nil {
+			return nil, fmt.Errorf("update reminder settings: %s is not configured on this server: %w", ch, se.ErrInvalidRequest)
+		}
+		if !seen[ch] {
+			seen[ch] = true
+			channels = append(channels, ch)
+		}
+	}
+	if req.GetEnabled() && len(channels) == 0 {
+		return nil, fmt.Errorf("update reminder settings: at least one channel is required: %w", se.ErrInvalidRequest)
+	}
+	set := &pb.ReminderSettings{
+		DoctorUuid:   doctorUUID,
+		Enabled:      req.GetEnabled(),
+		HoursBefore:  hours,
+		Channels:     channels,
+		EmailSubject: orDefault(req.GetEmailSubject(), defaultEmailSubject),
+		EmailBody:    orDefault(req.GetEmailBody(), defaultEmailBody),
+		SmsBody:      orDefault(req.GetSmsBody(), defaultSMSBody),
+		UpdatedAt:    timestamppb.New(s.now().UTC()),
+	}
+	for name, tmpl := range map[string]string{
+		"email_subject": set.GetEmailSubject(),
+		"email_body":    set.GetEmailBody(),
+		"sms_body":      set.GetSmsBody(),
+	} {
+		if err := validateTemplate(name, tmpl); err != nil {
+			return nil, fmt.Errorf("update reminder settings: %v: %w", err, se.ErrInvalidRequest)
+		}
+	}
+	saved, err := s.repo.UpsertSettings(ctx, set)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("update reminder settings: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("update reminder settings: %w", err)
+	}
+	return saved, nil
+}
+
+func (s *service) List(ctx context.Context, doctorUUID, status string, from, to time.Time) ([]*pb.Reminder, error) {
+	if strings.TrimSpace(doctorUUID) == "" || !to.After(from) {
+		return nil, fmt.Errorf("list reminders: %w", se.ErrInvalidRequest)
+	}
+	list, err := s.repo.List(ctx, doctorUUID, strings.TrimSpace(status), from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("list reminders: %w", err)
+	}
+	return list, nil
+}
+
+func (s *service) ListForAppointment(ctx context.Context, doctorUUID, appointmentUUID string) ([]*pb.Reminder, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(appointmentUUID) == "" {
+		return nil, fmt.Errorf("list appointment reminders: %w", se.ErrInvalidRequest)
+	}
+	a, err := s.appointmentRepo.Get(ctx, appointmentUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("list appointment reminders: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("list appointment reminders: %w", err)
+	}
+	if a.GetDoctorUuid() != doctorUUID {
+		return nil, fmt.Errorf("list appointment reminders: %w", se.ErrNotFound)
+	}
+	list, err := s.repo.ListForAppointment(ctx, doctorUUID, a.GetUuid(), a.GetSeriesUuid(), a.GetOriginalStartsAt().AsTime())
+	if err != nil {
+		return nil, fmt.Errorf("list appointment reminders: %w", err)
+	}
+	return list, nil
+}
+
+func (s *service) ListForOccurrence(ctx context.Context, doctorUUID, seriesUUID string, originalStart time.Time) ([]*pb.Reminder, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(seriesUUID) == "" || originalStart.IsZero() {
+		return nil, fmt.Errorf("list occurrence reminders: %w", se.ErrInvalidRequest)
+	}
+	// Reminders are filtered by doctor, so another doctor's series has none.
+	list, err := s.repo.ListForAppointment(ctx, doctorUUID, "", seriesUUID, originalStart.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("list occurrence reminders: %w", err)
+	}
+	return list, nil
+}
+
+// Retry queues a failed reminder again, e.g. after the patient's address was corrected.
+func (s *service) Retry(ctx context.Context, doctorUUID, reminderUUID string) (*pb.Reminder, error) {
+	rem, err := s.repo.Get(ctx, reminderUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("retry reminder: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("retry reminder: %w", err)
+	}
+	if rem.GetDoctorUuid() != doctorUUID {
+		return nil, fmt.Errorf("retry reminder: %w", se.ErrNotFound)
+	}
+	if !rem.GetAppointmentStartsAt().AsTime().After(s.now()) {
+		return nil, fmt.Errorf("retry reminder: appointment already started: %w", se.ErrInvalidRequest)
+	}
+	if err := s.repo.Retry(ctx, reminderUUID, s.now().UTC()); err != nil {
+		if errors.Is(err, re.ErrConflict) {
+			return nil, fmt.Errorf("retry reminder: only failed reminders can be retried: %w", se.ErrConflict)
+		}
+		return nil, fmt.Errorf("retry reminder: %w", err)
+	}
+	return s.repo.Get(ctx, reminderUUID)
+}
+
+func (s *service) EnqueueDue(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+	settings, err := s.repo.ListEnabledSettings(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("enqueue reminders: %w", err)
+	}
+	total := 0
+	var errs []error
+	for _, set := range settings {
+		n, err := s.enqueueDoctor(ctx, set, now)
+		total += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("enqueue reminders for doctor %s: %w", set.GetDoctorUuid(), err))
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// enqueueDoctor queues reminders for appointments starting within the doctor's lead time.
+// Rows are keyed by occurrence, start and channel, so running it every minute is harmless and a
+// moved appointment gets a fresh reminder for its new time.
+func (s *service) enqueueDoctor(ctx context.Context, set *pb.ReminderSettings, now time.Time) (int, error) {
+	doctorUUID := set.GetDoctorUuid()
+	to := now.Add(time.Duration(set.GetHoursBefore()) * time.Hour)
+	list, err := s.appointments.List(ctx, doctorUUID, &pb.ListAppointmentsRequest{
+		From: timestamppb.New(now),
+		To:   timestamppb.New(to),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(list) == 0 {
+		return 0, nil
+	}
+	loc, doctorName, practice := s.senderDetails(ctx, doctorUUID)
+	patients := map[string]*pb.Patient{}
+	added := 0
+	for _, a := range list {
+		start := a.GetStartsAt().AsTime()
+		if a.GetStatus() == svcappointments.StatusCancelled || !start.After(now) {
+			continue
+		}
+		p, ok := patients[a.GetPatientUuid()]
+		if !ok {
+			if p, err = s.patientRepo.Get(ctx, a.GetPatientUuid()); err != nil {
+				return added, fmt.Errorf("load patient: %w", err)
+			}
+			patients[a.GetPatientUuid()] = p
+		}
+		data := templateData{Patient: p, Start: start, Loc: loc, Doctor: doctorName, Practice: practice}
+		for _, ch := range set.GetChannels() {
+			if s.notifiers[ch] == nil {
+				continue
+			}
+			rem := &pb.Reminder{
+				Uuid:                uuid.NewString(),
+				DoctorUuid:          doctorUUID,
+				PatientUuid:         p.GetUuid(),
+				AppointmentUuid:     a.GetUuid(),
+				SeriesUuid:          a.GetSeriesUuid(),
+				OriginalStartsAt:    a.GetOriginalStartsAt(),
+				AppointmentStartsAt: timestamppb.New(start),
+				Channel:             ch,
+				Status:              StatusPending,
+				SendAfter:           timestamppb.New(now),
+				CreatedAt:           timestamppb.New(now),
+			}
+			switch ch {
+			case ChannelEmail:
+				rem.Recipient = strings.TrimSpace(p.GetEmail().GetValue())
+				rem.Subject = render(set.GetEmailSubject(), data)
+				rem.Body = render(set.GetEmailBody(), data)
+			case ChannelSMS:
+				rem.Recipient = normalizePhone(p.GetPhone().GetValue(), s.countryCode)
+				rem.Body = render(set.GetSmsBody(), data)
+			}
+			if rem.Recipient == "" {
+				continue
+			}
+			ok, err := s.repo.Enqueue(ctx, rem, dedupeKey(a, ch))
+			if err != nil {
+				return added, err
+			}
+			if ok {
+				added++
+			}
+		}
+	}
+	return added, nil
+}
+
+// senderDetails returns the doctor's schedule zone and the names used in templates.
+func (s *service) senderDetails(ctx context.Context, doctorUUID string) (*time.Location, string, string) {
+	sched, err := s.profileRepo.GetSchedule(ctx, doctorUUID)
+	if err != nil {
+		sched = nil
+	}
+	loc, err := svcdoctorprofiles.ScheduleLocation(sched)
+	if err != nil {
+		loc = time.UTC
+	}
+	var doctorName string
+	if d, err := s.doctorRepo.Get(ctx, doctorUUID); err == nil {
+		doctorName = strings.TrimSpace(d.GetFirstName() + " " + d.GetLastName())
+	}
+	practice := doctorName
+	if prof, err := s.profileRepo.GetByDoctor(ctx, doctorUUID); err == nil && strings.TrimSpace(prof.GetPracticeName()) != "" {
+		practice = strings.TrimSpace(prof.GetPracticeName())
+	}
+	return loc, doctorName, practice
+}
+
+func (s *service) DispatchDue(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+	if n, err := s.repo.FailStale(ctx, now.Add(-staleAfter), now, "interrupted while sending; delivery unknown"); err != nil {
+		return 0, fmt.Errorf("dispatch reminders: %w", err)
+	} else if n > 0 {
+		log.Printf("reminders: %d interrupted reminder(s) marked failed", n)
+	}
+	sent := 0
+	for {
+		batch, err := s.repo.ClaimDue(ctx, now, claimBatch)
+		if err != nil {
+			return sent, fmt.Errorf("dispatch reminders: %w", err)
+		}
+		for _, rem := range batch {
+			if s.deliver(ctx, rem) {
+				sent++
+			}
+		}
+		// Retried reminders move past now, so every row is claimed at most once per run.
+		if len(batch) < claimBatch || ctx.Err() != nil {
+			return sent, nil
+		}
+	}
+}
+
+// deliver sends one claimed reminder and stores the result. It reports whether the message went out.
+func (s *service) deliver(ctx context.Context, rem *pb.Reminder) bool {
+	now := s.now().UTC()
+	reason, err := s.stillDue(ctx, rem, now)
+	switch {
+	case err != nil:
+		s.reschedule(rem, now, err)
+	case reason != "":
+		rem.Status = StatusCancelled
+		rem.LastError = reason
+	case s.notifiers[rem.GetChannel()] == nil:
+		rem.Status = StatusFailed
+		rem.LastError = rem.GetChannel() + " is not configured"
+	default:
+		err := s.notifiers[rem.GetChannel()].Send(ctx, out.Message{
+			To:      rem.GetRecipient(),
+			Subject: rem.GetSubject(),
+			Body:    rem.GetBody(),
+		})
+		if err != nil {
+			s.reschedule(rem, now, err)
+		} else {
+			rem.Status = StatusSent
+			rem.LastError = ""
+			rem.SentAt = timestamppb.New(now)
+		}
+	}
+	rem.UpdatedAt = timestamppb.New(now)
+	// The result must be stored even when shutdown cancelled ctx mid-send.
+	if err := s.repo.Finish(context.WithoutCancel(ctx), rem); err != nil {
+		log.Printf("reminders: storing result of %s: %v", rem.GetUuid(), err)
+	}
+	return rem.GetStatus() == StatusSent
+}
+
+// reschedule retries temporary failures with a growing delay; rejected messages fail at once.
+func (s *service) reschedule(rem *pb.Reminder, now time.Time, err error) {
+	rem.LastError = err.Error()
+	if errors.Is(err, out.ErrRejected) || rem.GetAttempts() >= maxAttempts {
+		rem.Status = StatusFailed
+		return
+	}
+	rem.Status = StatusPending
+	rem.SendAfter = timestamppb.New(now.Add(retryDelay(rem.GetAttempts())))
+}
+
+// stillDue checks that the appointment still takes place at the reminded time. A non-empty
+// reason cancels the reminder.
+func (s *service) stillDue(ctx context.Context, rem *pb.Reminder, now time.Time) (string, error) {
+	start := rem.GetAppointmentStartsAt().AsTime()
+	if !start.After(now) {
+		return "appointment already started", nil
+	}
+	list, err := s.appointments.List(ctx, rem.GetDoctorUuid(), &pb.ListAppointmentsRequest{
+		From:        timestamppb.New(start),
+		To:          timestamppb.New(start.Add(time.Minute)),
+		PatientUuid: rem.GetPatientUuid(),
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, a := range list {
+		if a.GetStatus() == svcappointments.StatusCancelled || !a.GetStartsAt().AsTime().Equal(start) {
+			continue
+		}
+		if rem.GetSeriesUuid() != "" {
+			if a.GetSeriesUuid() == rem.GetSeriesUuid() && a.GetOriginalStartsAt().AsTime().Equal(rem.GetOriginalStartsAt().AsTime()) {
+				return "", nil
+			}
+		} else if a.GetUuid() == rem.GetAppointmentUuid() {
+			return "", nil
+		}
+	}
+	return "appointment cancelled or moved", nil
+}
+
+func retryDelay(attempts int32) time.Duration {
+	delays := []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute, time.Hour}
+	if int(attempts) <= 0 {
+		return delays[0]
+	}
+	if int(attempts) > len(delays) {
+		return delays[len(delays)-1]
+	}
+	return delays[attempts-1]
+}
+
+// dedupeKey identifies a reminder: series occurrences by series and original start (their uuid
+// appears only once they are edited), other appointments by uuid.
+func dedupeKey(a *pb.Appointment, channel string) string {
+	id := "appt:" + a.GetUuid()
+	if a.GetSeriesUuid() != "" {
+		id = fmt.Sprintf("series:%s:%d", a.GetSeriesUuid(), a.GetOriginalStartsAt().AsTime().Unix())
+	}
+	return fmt.Sprintf("%s|%d|%s", id, a.GetStartsAt().AsTime().Unix(), channel)
+}
+
+func defaultSettings(doctorUUID string) *pb.ReminderSettings {
+	return &pb.ReminderSettings{
+		DoctorUuid:   doctorUUID,
+		HoursBefore:  defaultHoursBefore,
+		Channels:     []string{ChannelEmail},
+		EmailSubject: defaultEmailSubject,
+		EmailBody:    defaultEmailBody,
+		SmsBody:      defaultSMSBody,
+	}
+}
+
+func orDefault(v, def string) string {
+	if strings.TrimSpace(v) == "" {
+		return def
+	}
+	return v
+}
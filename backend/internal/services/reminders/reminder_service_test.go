@@ -0,0 +1,356 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	outdoctorprofiles "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctorprofiles"
+	outdoctors "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/doctors"
+	outboundportpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/reminders"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcappointments "github.com/OPetricevic/physio-tracker/backend/internal/services/appointments"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// fakeOutbox keeps the outbox in memory the way the postgres repository does: Enqueue dedupes
+// by key and ClaimDue moves due pending rows to sending and counts the attempt.
+type fakeOutbox struct {
+	out.Repository
+	rows     []*pb.Reminder
+	keys     map[string]bool
+	finished []*pb.Reminder
+	stale    int
+	cutoff   time.Time
+	listArgs []any
+}
+
+func (f *fakeOutbox) Enqueue(ctx context.Context, rem *pb.Reminder, dedupeKey string) (bool, error) {
+	if f.keys == nil {
+		f.keys = map[string]bool{}
+	}
+	if f.keys[dedupeKey] {
+		return false, nil
+	}
+	f.keys[dedupeKey] = true
+	f.rows = append(f.rows, rem)
+	return true, nil
+}
+
+func (f *fakeOutbox) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*pb.Reminder, error) {
+	var batch []*pb.Reminder
+	for _, rem := range f.rows {
+		if len(batch) == limit {
+			break
+		}
+		if rem.GetStatus() == StatusPending && !rem.GetSendAfter().AsTime().After(now) {
+			rem.Status = StatusSending
+			rem.Attempts++
+			batch = append(batch, rem)
+		}
+	}
+	return batch, nil
+}
+
+func (f *fakeOutbox) Finish(ctx context.Context, rem *pb.Reminder) error {
+	f.finished = append(f.finished, rem)
+	return nil
+}
+
+func (f *fakeOutbox) FailStale(ctx context.Context, cutoff, now time.Time, reason string) (int, error) {
+	f.cutoff = cutoff
+	return f.stale, nil
+}
+
+func (f *fakeOutbox) ListForAppointment(ctx context.Context, doctorUUID, appointmentUUID, seriesUUID string, originalStart time.Time) ([]*pb.Reminder, error) {
+	f.listArgs = []any{doctorUUID, appointmentUUID, seriesUUID, originalStart}
+	return nil, nil
+}
+
+// fakeCalendar answers List with a fixed set of appointments overlapping the requested range.
+type fakeCalendar struct {
+	svcappointments.Service
+	appointments []*pb.Appointment
+}
+
+func (f *fakeCalendar) List(ctx context.Context, doctorUUID string, req *pb.ListAppointmentsRequest) ([]*pb.Appointment, error) {
+	var res []*pb.Appointment
+	for _, a := range f.appointments {
+		if a.GetStartsAt().AsTime().Before(req.GetTo().AsTime()) && a.GetEndsAt().AsTime().After(req.GetFrom().AsTime()) {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
+type fakePatients struct {
+	outboundportpatients.Repository
+}
+
+func (fakePatients) Get(ctx context.Context, uuid string) (*pb.Patient, error) {
+	return &pb.Patient{
+		Uuid:      uuid,
+		FirstName: "Ana",
+		LastName:  "Horvat",
+		Email:     wrapperspb.String("ana@example.com"),
+		Phone:     wrapperspb.String("091 234 5678"),
+	}, nil
+}
+
+type fakeDoctors struct {
+	outdoctors.Repository
+}
+
+func (fakeDoctors) Get(ctx context.Context, uuid string) (*pb.Doctor, error) {
+	return nil, re.ErrNotFound
+}
+
+type fakeProfiles struct {
+	outdoctorprofiles.Repository
+}
+
+func (fakeProfiles) GetByDoctor(ctx context.Context, doctorUUID string) (*pb.DoctorProfile, error) {
+	return nil, re.ErrNotFound
+}
+
+func (fakeProfiles) GetSchedule(ctx context.Context, doctorUUID string) (*pb.WorkingSchedule, error) {
+	return nil, re.ErrNotFound
+}
+
+// scriptedNotifier fails the first sends with the listed errors and records what went out.
+type scriptedNotifier struct {
+	errs []error
+	sent []out.Message
+}
+
+func (n *scriptedNotifier) Send(ctx context.Context, msg out.Message) error {
+	if len(n.errs) > 0 {
+		err := n.errs[0]
+		n.errs = n.errs[1:]
+		if err != nil {
+			return err
+		}
+	}
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+func newTestService(repo *fakeOutbox, calendar []*pb.Appointment, notifiers map[string]out.Notifier, now *time.Time) *service {
+	return &service{
+		repo:         repo,
+		appointments: &fakeCalendar{appointments: calendar},
+		patientRepo:  fakePatients{},
+		doctorRepo:   fakeDoctors{},
+		profileRepo:  fakeProfiles{},
+		notifiers:    notifiers,
+		countryCode:  "385",
+		now:          func() time.Time { return *now },
+	}
+}
+
+func appointmentAt(uuid string, start time.Time) *pb.Appointment {
+	return &pb.Appointment{
+		Uuid:        uuid,
+		DoctorUuid:  "doc",
+		PatientUuid: "pat",
+		StartsAt:    timestamppb.New(start),
+		EndsAt:      timestamppb.New(start.Add(45 * time.Minute)),
+		Status:      svcappointments.StatusScheduled,
+	}
+}
+
+func TestDedupeKey(t *testing.T) {
+	start := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	virtual := &pb.Appointment{SeriesUuid: "ser", OriginalStartsAt: timestamppb.New(start), StartsAt: timestamppb.New(start)}
+	edited := &pb.Appointment{Uuid: "a1", SeriesUuid: "ser", OriginalStartsAt: timestamppb.New(start), StartsAt: timestamppb.New(start)}
+	moved := &pb.Appointment{Uuid: "a1", SeriesUuid: "ser", OriginalStartsAt: timestamppb.New(start), StartsAt: timestamppb.New(start.Add(time.Hour))}
+	single := &pb.Appointment{Uuid: "a2", StartsAt: timestamppb.New(start)}
+
+	if dedupeKey(virtual, ChannelEmail) != dedupeKey(edited, ChannelEmail) {
+		t.Error("an occurrence gets a new key when it is edited and receives a uuid")
+	}
+	if dedupeKey(edited, ChannelEmail) == dedupeKey(moved, ChannelEmail) {
+		t.Error("a moved occurrence keeps the key of its old start")
+	}
+	if dedupeKey(single, ChannelEmail) == dedupeKey(single, ChannelSMS) {
+		t.Error("channels share a key")
+	}
+	if want := fmt.Sprintf("appt:a2|%d|email", start.Unix()); dedupeKey(single, ChannelEmail) != want {
+		t.Errorf("dedupeKey = %q, want %q", dedupeKey(single, ChannelEmail), want)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	want := []time.Duration{time.Minute, time.Minute, 5 * time.Minute, 15 * time.Minute, time.Hour, time.Hour, time.Hour}
+	for attempts, d := range want {
+		if got := retryDelay(int32(attempts)); got != d {
+			t.Errorf("retryDelay(%d) = %s, want %s", attempts, got, d)
+		}
+	}
+}
+
+func TestReschedule(t *testing.T) {
+	now := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	temporary := errors.New("connection reset")
+	tests := []struct {
+		name      string
+		attempts  int32
+		err       error
+		status    string
+		sendAfter time.Time
+	}{
+		{"first temporary failure", 1, temporary, StatusPending, now.Add(time.Minute)},
+		{"third temporary failure", 3, temporary, StatusPending, now.Add(15 * time.Minute)},
+		{"last attempt", maxAttempts, temporary, StatusFailed, time.Time{}},
+		{"rejected on first attempt", 1, fmt.Errorf("smtp 550: %w", out.ErrRejected), StatusFailed, time.Time{}},
+	}
+	for _, tt := range tests {
+		rem := &pb.Reminder{Attempts: tt.attempts}
+		(&service{}).reschedule(rem, now, tt.err)
+		if rem.GetStatus() != tt.status || rem.GetLastError() != tt.err.Error() {
+			t.Errorf("%s: status %q, error %q", tt.name, rem.GetStatus(), rem.GetLastError())
+		}
+		if !tt.sendAfter.IsZero() && !rem.GetSendAfter().AsTime().Equal(tt.sendAfter) {
+			t.Errorf("%s: send after %s, want %s", tt.name, rem.GetSendAfter().AsTime(), tt.sendAfter)
+		}
+	}
+}
+
+func TestEnqueueDueIsIdempotent(t *testing.T) {
+	now := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	calendar := []*pb.Appointment{
+		appointmentAt("tomorrow", now.Add(20*time.Hour)),
+		appointmentAt("too-far", now.Add(30*time.Hour)),
+		{DoctorUuid: "doc", PatientUuid: "pat", SeriesUuid: "ser", Status: svcappointments.StatusScheduled,
+			StartsAt: timestamppb.New(now.Add(2 * time.Hour)), EndsAt: timestamppb.New(now.Add(3 * time.Hour)),
+			OriginalStartsAt: timestamppb.New(now.Add(2 * time.Hour))},
+	}
+	cancelled := appointmentAt("cancelled", now.Add(4*time.Hour))
+	cancelled.Status = svcappointments.StatusCancelled
+	calendar = append(calendar, cancelled)
+
+	repo := &fakeOutbox{}
+	s := newTestService(repo, calendar, map[string]out.Notifier{ChannelEmail: &scriptedNotifier{}, ChannelSMS: &scriptedNotifier{}}, &now)
+	set := &pb.ReminderSettings{DoctorUuid: "doc", Enabled: true, HoursBefore: 24, Channels: []string{ChannelEmail, ChannelSMS},
+		EmailSubject: defaultEmailSubject, EmailBody: defaultEmailBody, SmsBody: defaultSMSBody}
+
+	added, err := s.enqueueDoctor(context.Background(), set, now)
+	if err != nil || added != 4 {
+		t.Fatalf("first run: added %d, %v; want 4", added, err)
+	}
+	now = now.Add(time.Minute)
+	if added, err := s.enqueueDoctor(context.Background(), set, now); err != nil || added != 0 {
+		t.Errorf("second run: added %d, %v; want 0", added, err)
+	}
+	for _, rem := range repo.rows {
+		if rem.GetChannel() == ChannelSMS && rem.GetRecipient() != "+385912345678" {
+			t.Errorf("sms recipient %q, want +385912345678", rem.GetRecipient())
+		}
+		if rem.GetAppointmentUuid() == "" && (rem.GetSeriesUuid() != "ser" || rem.GetOriginalStartsAt() == nil) {
+			t.Errorf("occurrence reminder without series key: %+v", rem)
+		}
+	}
+
+	// Moving the appointment queues a reminder for the new time.
+	calendar[0].StartsAt = timestamppb.New(now.Add(21 * time.Hour))
+	calendar[0].EndsAt = timestamppb.New(now.Add(22 * time.Hour))
+	if added, err := s.enqueueDoctor(context.Background(), set, now); err != nil || added != 2 {
+		t.Errorf("after move: added %d, %v; want 2", added, err)
+	}
+}
+
+func TestDispatchDue(t *testing.T) {
+	now := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	start := now.Add(20 * time.Hour)
+	pending := func(uuid, appointmentUUID string, startsAt time.Time, attempts int32) *pb.Reminder {
+		return &pb.Reminder{
+			Uuid: uuid, DoctorUuid: "doc", PatientUuid: "pat", AppointmentUuid: appointmentUUID,
+			AppointmentStartsAt: timestamppb.New(startsAt), Channel: ChannelEmail, Recipient: "ana@example.com",
+			Status: StatusPending, Attempts: attempts, SendAfter: timestamppb.New(now),
+		}
+	}
+	repo := &fakeOutbox{stale: 1, rows: []*pb.Reminder{
+		pending("ok", "a1", start, 0),
+		pending("temporary", "a1", start, 0),
+		pending("rejected", "a1", start, 0),
+		pending("exhausted", "a1", start, maxAttempts-1),
+		pending("moved", "a2", start, 0),
+		pending("started", "a1", now.Add(-time.Minute), 0),
+		pending("later", "a1", start, 0),
+	}}
+	repo.rows[len(repo.rows)-1].SendAfter = timestamppb.New(now.Add(time.Minute))
+	notifier := &scriptedNotifier{errs: []error{
+		nil,
+		errors.New("timeout"),
+		fmt.Errorf("bad address: %w", out.ErrRejected),
+		errors.New("timeout"),
+	}}
+	calendar := []*pb.Appointment{appointmentAt("a1", start), appointmentAt("a2", start.Add(time.Hour))}
+	s := newTestService(repo, calendar, map[string]out.Notifier{ChannelEmail: notifier}, &now)
+
+	sent, err := s.DispatchDue(context.Background())
+	if err != nil || sent != 1 {
+		t.Fatalf("DispatchDue = %d, %v; want 1 sent", sent, err)
+	}
+	if !repo.cutoff.Equal(now.Add(-staleAfter)) {
+		t.Errorf("stale cutoff %s, want %s", repo.cutoff, now.Add(-staleAfter))
+	}
+	want := map[string]string{
+		"ok":        StatusSent,
+		"temporary": StatusPending,
+		"rejected":  StatusFailed,
+		"exhausted": StatusFailed,
+		"moved":     StatusCancelled,
+		"started":   StatusCancelled,
+		"later":     StatusPending,
+	}
+	got := map[string]string{}
+	for _, rem := range repo.rows {
+		got[rem.GetUuid()] = rem.GetStatus()
+	}
+	for uuid, status := range want {
+		if got[uuid] != status {
+			t.Errorf("%s: status %q, want %q", uuid, got[uuid], status)
+		}
+	}
+	if len(repo.finished) != 6 {
+		t.Errorf("finished %d reminders, want 6 (the one not yet due stays untouched)", len(repo.finished))
+	}
+
+	// The temporary failure is picked up again once its delay has passed, and only then.
+	if sent, _ := s.DispatchDue(context.Background()); sent != 0 {
+		t.Errorf("immediate second run sent %d, want 0", sent)
+	}
+	now = now.Add(2 * time.Minute)
+	if sent, _ := s.DispatchDue(context.Background()); sent != 2 {
+		t.Errorf("run after the retry delay sent %d, want 2", sent)
+	}
+}
+
+func TestListForOccurrence(t *testing.T) {
+	now := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	repo := &fakeOutbox{}
+	s := newTestService(repo, nil, nil, &now)
+	orig := time.Date(2026, 6, 3, 10, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+
+	if _, err := s.ListForOccurrence(context.Background(), "doc", "ser", orig); err != nil {
+		t.Fatal(err)
+	}
+	if repo.listArgs[0] != "doc" || repo.listArgs[1] != "" || repo.listArgs[2] != "ser" || !repo.listArgs[3].(time.Time).Equal(orig) {
+		t.Errorf("repository called with %v", repo.listArgs)
+	}
+	for _, tt := range []struct{ doctor, series string }{{"", "ser"}, {"doc", " "}} {
+		if _, err := s.ListForOccurrence(context.Background(), tt.doctor, tt.series, orig); !errors.Is(err, se.ErrInvalidRequest) {
+			t.Errorf("doctor %q series %q: got %v, want ErrInvalidRequest", tt.doctor, tt.series, err)
+		}
+	}
+	if _, err := s.ListForOccurrence(context.Background(), "doc", "ser", time.Time{}); !errors.Is(err, se.ErrInvalidRequest) {
+		t.Errorf("zero start: got %v, want ErrInvalidRequest", err)
+	}
+}
@@ -0,0 +1,92 @@
+package reminders
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+)
+
+const (
+	defaultEmailSubject = "Podsjetnik: termin {date} u {time}"
+	defaultEmailBody    = "Poštovani/a {first_name} {last_name},\n\n" +
+		"podsjećamo Vas na termin {date} u {time}.\n" +
+		"Ako ne možete doći, molimo javite nam se na vrijeme.\n\n" +
+		"{practice}"
+	defaultSMSBody = "Podsjetnik: termin {date} u {time}. {practice}"
+)
+
+// placeholders lists what templates may use; anything else in braces is rejected on save.
+var placeholders = map[string]bool{
+	"first_name": true,
+	"last_name":  true,
+	"date":       true,
+	"time":       true,
+	"doctor":     true,
+	"practice":   true,
+}
+
+var placeholderRe = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+func validateTemplate(name, tmpl string) error {
+	for _, m := range placeholderRe.FindAllStringSubmatch(tmpl, -1) {
+		if !placeholders[m[1]] {
+			return fmt.Errorf("%s: unknown placeholder {%s}", name, m[1])
+		}
+	}
+	return nil
+}
+
+// templateData holds the values substituted into a reminder.
+type templateData struct {
+	Patient  *pb.Patient
+	Start    time.Time
+	Loc      *time.Location
+	Doctor   string
+	Practice string
+}
+
+func render(tmpl string, d templateData) string {
+	local := d.Start.In(d.Loc)
+	return strings.NewReplacer(
+		"{first_name}", d.Patient.GetFirstName(),
+		"{last_name}", d.Patient.GetLastName(),
+		"{date}", local.Format("02.01.2006."),
+		"{time}", local.Format("15:04"),
+		"{doctor}", d.Doctor,
+		"{practice}", d.Practice,
+	).Replace(tmpl)
+}
+
+// normalizePhone turns local notation ("091 234 5678", "00385...") into +E.164 using
+// countryCode for numbers with a leading 0. Empty means the number is unusable.
+func normalizePhone(raw, countryCode string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(raw) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case strings.ContainsRune(" -/().", r):
+		default:
+			return ""
+		}
+	}
+	n := b.String()
+	switch {
+	case strings.HasPrefix(n, "+"):
+	case strings.HasPrefix(n, "00"):
+		n = "+" + n[2:]
+	case strings.HasPrefix(n, "0"):
+		n = "+" + countryCode + n[1:]
+	default:
+		n = "+" + n
+	}
+	if digits := len(n) - 1; digits < 8 || digits > 15 {
+		return ""
+	}
+	return n
+}
@@ -0,0 +1,268 @@
+package treatmentplans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/OPetricevic/physio-tracker/backend/golang/patients"
+	outboundportpatients "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/patients"
+	out "github.com/OPetricevic/physio-tracker/backend/internal/api/rest/core/outbound/treatmentplans"
+	re "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/repoerrors"
+	se "github.com/OPetricevic/physio-tracker/backend/internal/commonerrors/serviceerrors"
+	svcpatients "github.com/OPetricevic/physio-tracker/backend/internal/services/patients"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	StatusActive    = "active"
+	StatusCompleted = "completed"
+	StatusAbandoned = "abandoned"
+
+	GoalOpen              = "open"
+	GoalAchieved          = "achieved"
+	GoalPartiallyAchieved = "partially_achieved"
+	GoalNotAchieved       = "not_achieved"
+
+	maxPlannedSessions = 200
+)
+
+type Service interface {
+	Create(ctx context.Context, doctorUUID string, req *pb.CreateTreatmentPlanRequest) (*pb.TreatmentPlan, error)
+	Update(ctx context.Context, doctorUUID string, req *pb.UpdateTreatmentPlanRequest) (*pb.TreatmentPlan, error)
+	Get(ctx context.Context, doctorUUID, patientUUID, uuid string) (*pb.TreatmentPlan, error)
+	List(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.TreatmentPlan, error)
+	Delete(ctx context.Context, doctorUUID, patientUUID, uuid string) error
+}
+
+type service struct {
+	repo        out.Repository
+	patientRepo outboundportpatients.Repository
+	now         func() time.Time
+}
+
+func NewService(repo out.Repository, pRepo outboundportpatients.Repository) Service {
+	return &service{repo: repo, patientRepo: pRepo, now: time.Now}
+}
+
+func (s *service) Create(ctx context.Context, doctorUUID string, req *pb.CreateTreatmentPlanRequest) (*pb.TreatmentPlan, error) {
+	patientUUID := strings.TrimSpace(req.GetPatientUuid())
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("create treatment plan: %w", err)
+	}
+	diagnosis := strings.TrimSpace(req.GetReferralDiagnosis())
+	if diagnosis == "" {
+		return nil, fmt.Errorf("create treatment plan: empty referral diagnosis: %w", se.ErrInvalidRequest)
+	}
+	if err := validatePlannedSessions(req.GetPlannedSessions()); err != nil {
+		return nil, fmt.Errorf("create treatment plan: %w", err)
+	}
+	now := s.now().UTC()
+	start := now.Format("2006-01-02")
+	if v := strings.TrimSpace(req.GetStartDate()); v != "" {
+		if _, err := time.Parse("2006-01-02", v); err != nil {
+			return nil, fmt.Errorf("create treatment plan: invalid start date %q: %w", v, se.ErrInvalidRequest)
+		}
+		start = v
+	}
+	goals, err := buildGoals(req.GetGoals(), nil, now)
+	if err != nil {
+		return nil, fmt.Errorf("create treatment plan: %w", err)
+	}
+	plan := &pb.TreatmentPlan{
+		Uuid:              uuid.NewString(),
+		DoctorUuid:        doctorUUID,
+		PatientUuid:       patientUUID,
+		ReferralDiagnosis: diagnosis,
+		Goals:             goals,
+		PlannedSessions:   req.GetPlannedSessions(),
+		Frequency:         strings.TrimSpace(req.GetFrequency()),
+		Modalities:        cleanModalities(req.GetModalities()),
+		Status:            StatusActive,
+		StartDate:         start,
+		CreatedAt:         timestamppb.New(now),
+	}
+	created, err := s.repo.Create(ctx, plan)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("create treatment plan: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("create treatment plan: %w", err)
+	}
+	return created, nil
+}
+
+func (s *service) Update(ctx context.Context, doctorUUID string, req *pb.UpdateTreatmentPlanRequest) (*pb.TreatmentPlan, error) {
+	plan, err := s.Get(ctx, doctorUUID, req.GetPatientUuid(), req.GetUuid())
+	if err != nil {
+		return nil, fmt.Errorf("update treatment plan: %w", err)
+	}
+	now := s.now().UTC()
+	if req.ReferralDiagnosis != nil {
+		if plan.ReferralDiagnosis = strings.TrimSpace(req.GetReferralDiagnosis().GetValue()); plan.ReferralDiagnosis == "" {
+			return nil, fmt.Errorf("update treatment plan: empty referral diagnosis: %w", se.ErrInvalidRequest)
+		}
+	}
+	if req.Goals != nil {
+		if plan.Goals, err = buildGoals(req.GetGoals().GetItems(), plan.GetGoals(), now); err != nil {
+			return nil, fmt.Errorf("update treatment plan: %w", err)
+		}
+	}
+	if req.PlannedSessions != nil {
+		if err := validatePlannedSessions(req.GetPlannedSessions().GetValue()); err != nil {
+			return nil, fmt.Errorf("update treatment plan: %w", err)
+		}
+		plan.PlannedSessions = req.GetPlannedSessions().GetValue()
+	}
+	if req.Frequency != nil {
+		plan.Frequency = strings.TrimSpace(req.GetFrequency().GetValue())
+	}
+	if req.Modalities != nil {
+		plan.Modalities = cleanModalities(req.GetModalities().GetItems())
+	}
+	if req.StartDate != nil {
+		v := strings.TrimSpace(req.GetStartDate().GetValue())
+		if _, err := time.Parse("2006-01-02", v); err != nil {
+			return nil, fmt.Errorf("update treatment plan: invalid start date %q: %w", v, se.ErrInvalidRequest)
+		}
+		plan.StartDate = v
+	}
+	if req.Status != nil {
+		status := strings.TrimSpace(req.GetStatus().GetValue())
+		switch status {
+		case StatusActive:
+			plan.ClosedAt = nil
+		case StatusCompleted, StatusAbandoned:
+			if plan.GetStatus() == StatusActive {
+				plan.ClosedAt = timestamppb.New(now)
+			}
+		default:
+			return nil, fmt.Errorf("update treatment plan: unknown status %q: %w", status, se.ErrInvalidRequest)
+		}
+		plan.Status = status
+	}
+	plan.UpdatedAt = timestamppb.New(now)
+	updated, err := s.repo.Update(ctx, plan)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("update treatment plan: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("update treatment plan: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *service) Get(ctx context.Context, doctorUUID, patientUUID, planUUID string) (*pb.TreatmentPlan, error) {
+	if strings.TrimSpace(doctorUUID) == "" || strings.TrimSpace(planUUID) == "" {
+		return nil, fmt.Errorf("get treatment plan: %w", se.ErrInvalidRequest)
+	}
+	plan, err := s.repo.Get(ctx, planUUID)
+	if err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return nil, fmt.Errorf("get treatment plan: %w", se.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get treatment plan: %w", err)
+	}
+	if plan.GetDoctorUuid() != doctorUUID || plan.GetPatientUuid() != patientUUID {
+		return nil, fmt.Errorf("get treatment plan: %w", se.ErrNotFound)
+	}
+	return plan, nil
+}
+
+func (s *service) List(ctx context.Context, doctorUUID, patientUUID string) ([]*pb.TreatmentPlan, error) {
+	if _, err := svcpatients.Owned(ctx, s.patientRepo, doctorUUID, patientUUID); err != nil {
+		return nil, fmt.Errorf("list treatment plans: %w", err)
+	}
+	list, err := s.repo.List(ctx, patientUUID)
+	if err != nil {
+		return nil, fmt.Errorf("list treatment plans: %w", err)
+	}
+	return list, nil
+}
+
+// Delete removes a plan; its visits stay and are unlinked.
+func (s *service) Delete(ctx context.Context, doctorUUID, patientUUID, planUUID string) error {
+	if _, err := s.Get(ctx, doctorUUID, patientUUID, planUUID); err != nil {
+		return fmt.Errorf("delete treatment plan: %w", err)
+	}
+	if err := s.repo.Delete(ctx, planUUID); err != nil {
+		if errors.Is(err, re.ErrNotFound) {
+			return fmt.Errorf("delete treatment plan: %w", se.ErrNotFound)
+		}
+		return fmt.Errorf("delete treatment plan: %w", err)
+	}
+	return nil
+}
+
+// buildGoals validates the requested goals. Goals sent with the uuid of an existing goal keep their
+// identity and achievement date; achieved_at is set when a goal first becomes achieved.
+func buildGoals(inputs []*pb.TreatmentGoalInput, existing []*pb.TreatmentGoal, now time.Time) ([]*pb.TreatmentGoal, error) {
+	old := make(map[string]*pb.TreatmentGoal, len(existing))
+	for _, g := range existing {
+		old[g.GetUuid()] = g
+	}
+	res := make([]*pb.TreatmentGoal, 0, len(inputs))
+	for i, in := range inputs {
+		desc := strings.TrimSpace(in.GetDescription())
+		if desc == "" {
+			return nil, fmt.Errorf("goal %d: empty description: %w", i+1, se.ErrInvalidRequest)
+		}
+		target := strings.TrimSpace(in.GetTargetDate())
+		if _, err := time.Parse("2006-01-02", target); err != nil {
+			return nil, fmt.Errorf("goal %d: invalid target date %q: %w", i+1, target, se.ErrInvalidRequest)
+		}
+		status := strings.TrimSpace(in.GetStatus())
+		switch status {
+		case "":
+			status = GoalOpen
+		case GoalOpen, GoalAchieved, GoalPartiallyAchieved, GoalNotAchieved:
+		default:
+			return nil, fmt.Errorf("goal %d: unknown status %q: %w", i+1, status, se.ErrInvalidRequest)
+		}
+		goal := &pb.TreatmentGoal{
+			Uuid:        uuid.NewString(),
+			Description: desc,
+			Measure:     strings.TrimSpace(in.GetMeasure()),
+			TargetDate:  target,
+			Status:      status,
+		}
+		if prev, ok := old[strings.TrimSpace(in.GetUuid())]; ok {
+			goal.Uuid = prev.GetUuid()
+			goal.AchievedAt = prev.GetAchievedAt()
+		} else if in.GetUuid() != "" {
+			return nil, fmt.Errorf("goal %d: unknown goal %s: %w", i+1, in.GetUuid(), se.ErrInvalidRequest)
+		}
+		if status != GoalAchieved {
+			goal.AchievedAt = nil
+		} else if goal.AchievedAt == nil {
+			goal.AchievedAt = timestamppb.New(now)
+		}
+		res = append(res, goal)
+	}
+	return res, nil
+}
+
+// validatePlannedSessions applies the same bounds on create and update, so every stored plan stays editable.
+func validatePlannedSessions(n int32) error {
+	if n < 1 || n > maxPlannedSessions {
+		return fmt.Errorf("planned sessions must be 1-%d: %w", maxPlannedSessions, se.ErrInvalidRequest)
+	}
+	return nil
+}
+
+func cleanModalities(in []string) []string {
+	res := make([]string, 0, len(in))
+	seen := map[string]bool{}
+	for _, m := range in {
+		m = strings.TrimSpace(m)
+		if m == "" || seen[strings.ToLower(m)] {
+			continue
+		}
+		seen[strings.ToLower(m)] = true
+		res = append(res, m)
+	}
+	return res
+}
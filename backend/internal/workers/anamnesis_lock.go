@@ -0,0 +1,24 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	svcanamneses "github.com/OPetricevic/physio-tracker/backend/internal/services/anamneses"
+)
+
+// anamnesisLockInterval is how often notes past ANAMNESIS_AUTO_LOCK_DAYS are locked.
+const anamnesisLockInterval = time.Hour
+
+// StartAnamnesisLock locks old anamneses so that they can only be corrected through addenda.
+func StartAnamnesisLock(ctx context.Context, wg *sync.WaitGroup, svc svcanamneses.Service) {
+	Start(ctx, wg, "anamnesis-lock", anamnesisLockInterval, func(ctx context.Context) error {
+		n, err := svc.AutoLock(ctx)
+		if n > 0 {
+			log.Printf("worker anamnesis-lock: %d note(s) locked", n)
+		}
+		return err
+	})
+}
@@ -0,0 +1,26 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	svcreminders "github.com/OPetricevic/physio-tracker/backend/internal/services/reminders"
+)
+
+// reminderInterval is how often upcoming appointments are queued and the outbox is flushed.
+const reminderInterval = time.Minute
+
+// StartReminders queues reminders for upcoming appointments and sends the due ones.
+func StartReminders(ctx context.Context, wg *sync.WaitGroup, svc svcreminders.Service) {
+	Start(ctx, wg, "reminders", reminderInterval, func(ctx context.Context) error {
+		queued, enqueueErr := svc.EnqueueDue(ctx)
+		sent, dispatchErr := svc.DispatchDue(ctx)
+		if queued > 0 || sent > 0 {
+			log.Printf("worker reminders: %d queued, %d sent", queued, sent)
+		}
+		return errors.Join(enqueueErr, dispatchErr)
+	})
+}
@@ -0,0 +1,24 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	svcappointments "github.com/OPetricevic/physio-tracker/backend/internal/services/appointments"
+)
+
+// waitlistInterval is how often expired waiting-list offers are passed on.
+const waitlistInterval = time.Minute
+
+// StartWaitlist expires unanswered waiting-list offers and offers the slot to the next candidate.
+func StartWaitlist(ctx context.Context, wg *sync.WaitGroup, svc svcappointments.Service) {
+	Start(ctx, wg, "waitlist", waitlistInterval, func(ctx context.Context) error {
+		n, err := svc.ExpireWaitlistOffers(ctx)
+		if n > 0 {
+			log.Printf("worker waitlist: %d offer(s) expired", n)
+		}
+		return err
+	})
+}
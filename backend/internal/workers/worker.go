@@ -0,0 +1,33 @@
+// Package workers runs periodic background jobs next to the HTTP server.
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is one run of a periodic task.
+type Job func(ctx context.Context) error
+
+// Start runs job every interval until ctx is cancelled. Errors are logged and retried on the
+// next tick. wg is released once the goroutine has stopped, so main can wait for a clean shutdown.
+func Start(ctx context.Context, wg *sync.WaitGroup, name string, interval time.Duration, job Job) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := job(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("worker %s: %v", name, err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,19 @@
+-- Appointments (scheduled visits, owned by doctor)
+CREATE TABLE IF NOT EXISTS appointments (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    anamnesis_uuid VARCHAR(255) NULL REFERENCES anamneses(uuid) ON DELETE SET NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    note TEXT NOT NULL DEFAULT '',
+    cancel_reason TEXT NOT NULL DEFAULT '',
+    cancelled_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NULL DEFAULT NULL,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointments_doctor_starts ON appointments(doctor_uuid, starts_at);
+CREATE INDEX IF NOT EXISTS idx_appointments_patient ON appointments(patient_uuid, starts_at DESC);
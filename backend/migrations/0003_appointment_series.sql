@@ -0,0 +1,23 @@
+-- Recurring appointment series; occurrences are expanded by the service.
+CREATE TABLE IF NOT EXISTS appointment_series (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    rrule TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Zagreb',
+    note TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointment_series_doctor ON appointment_series(doctor_uuid, starts_at);
+CREATE INDEX IF NOT EXISTS idx_appointment_series_patient ON appointment_series(patient_uuid);
+
+-- Exceptions: a moved/cancelled occurrence is stored as an appointment pointing at its series.
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS series_uuid VARCHAR(255) NULL REFERENCES appointment_series(uuid) ON DELETE CASCADE;
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS original_starts_at TIMESTAMP NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appointments_series_occurrence ON appointments(series_uuid, original_starts_at) WHERE series_uuid IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Doctor working schedule (hours per weekday, days off) used for appointment conflict checks.
+CREATE TABLE IF NOT EXISTS doctor_working_schedules (
+    doctor_uuid VARCHAR(255) PRIMARY KEY REFERENCES doctors(uuid) ON DELETE CASCADE,
+    schedule JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+-- Secret per-doctor token for the subscribable iCalendar feed (separate from auth_tokens).
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    doctor_uuid VARCHAR(255) PRIMARY KEY REFERENCES doctors(uuid) ON DELETE CASCADE,
+    token VARCHAR(128) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
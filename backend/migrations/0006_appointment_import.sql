@@ -0,0 +1,30 @@
+-- iCalendar import: source UIDs for de-duplication and a review list for unmatched events.
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS external_uid VARCHAR(512) NULL;
+ALTER TABLE appointment_series ADD COLUMN IF NOT EXISTS external_uid VARCHAR(512) NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_appointments_external_uid
+    ON appointments(doctor_uuid, external_uid) WHERE external_uid IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_appointment_series_external_uid
+    ON appointment_series(doctor_uuid, external_uid) WHERE external_uid IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS appointment_import_reviews (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    external_uid VARCHAR(512) NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    rrule TEXT NOT NULL DEFAULT '',
+    exdates TEXT[] NOT NULL DEFAULT '{}', -- RFC3339 UTC
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    reason VARCHAR(32) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    appointment_uuid VARCHAR(255) NULL REFERENCES appointments(uuid) ON DELETE SET NULL,
+    series_uuid VARCHAR(255) NULL REFERENCES appointment_series(uuid) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP NULL DEFAULT NULL,
+    UNIQUE (doctor_uuid, external_uid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_appointment_import_reviews_status
+    ON appointment_import_reviews(doctor_uuid, status, starts_at);
@@ -0,0 +1,7 @@
+-- Appointment outcomes for no-show and late-cancellation tracking.
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS outcome VARCHAR(32) NULL;
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS outcome_at TIMESTAMP NULL DEFAULT NULL;
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS outcome_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_appointments_patient_outcome
+    ON appointments(patient_uuid, outcome) WHERE outcome IS NOT NULL;
@@ -0,0 +1,42 @@
+-- Waiting list and offers of freed slots.
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    window_from TIMESTAMP NOT NULL,
+    window_to TIMESTAMP NOT NULL,
+    day_start VARCHAR(5) NOT NULL DEFAULT '',
+    day_end VARCHAR(5) NOT NULL DEFAULT '',
+    weekdays INTEGER[] NOT NULL DEFAULT '{}',
+    duration_minutes INTEGER NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NULL DEFAULT NULL,
+    CHECK (window_to > window_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_doctor_status
+    ON waitlist_entries(doctor_uuid, status, priority DESC, created_at);
+
+CREATE TABLE IF NOT EXISTS waitlist_offers (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    entry_uuid VARCHAR(255) NOT NULL REFERENCES waitlist_entries(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    slot_ends_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMP NOT NULL,
+    appointment_uuid VARCHAR(255) NULL REFERENCES appointments(uuid) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMP NULL DEFAULT NULL,
+    UNIQUE (entry_uuid, starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_offers_pending
+    ON waitlist_offers(expires_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_waitlist_offers_doctor
+    ON waitlist_offers(doctor_uuid, created_at DESC);
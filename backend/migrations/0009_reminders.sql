@@ -0,0 +1,44 @@
+-- Appointment reminders: patient e-mail, per-doctor settings and the delivery outbox.
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS email VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS reminder_settings (
+    doctor_uuid VARCHAR(255) PRIMARY KEY REFERENCES doctors(uuid) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    hours_before INTEGER NOT NULL DEFAULT 24,
+    channels TEXT[] NOT NULL DEFAULT '{}',
+    email_subject TEXT NOT NULL DEFAULT '',
+    email_body TEXT NOT NULL DEFAULT '',
+    sms_body TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- One row per message. dedupe_key (occurrence, start and channel) makes enqueueing idempotent,
+-- so a restarted worker never queues the same reminder twice.
+CREATE TABLE IF NOT EXISTS reminder_outbox (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    appointment_uuid VARCHAR(255) NULL REFERENCES appointments(uuid) ON DELETE SET NULL,
+    series_uuid VARCHAR(255) NULL REFERENCES appointment_series(uuid) ON DELETE SET NULL,
+    original_starts_at TIMESTAMP NULL,
+    appointment_starts_at TIMESTAMP NOT NULL,
+    channel VARCHAR(10) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    send_after TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NULL DEFAULT NULL,
+    dedupe_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminder_outbox_due
+    ON reminder_outbox(send_after) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_reminder_outbox_doctor
+    ON reminder_outbox(doctor_uuid, appointment_starts_at);
+CREATE INDEX IF NOT EXISTS idx_reminder_outbox_appointment
+    ON reminder_outbox(appointment_uuid);
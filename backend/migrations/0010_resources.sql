@@ -0,0 +1,18 @@
+-- Bookable rooms and equipment; appointments and series reserve them by uuid.
+CREATE TABLE IF NOT EXISTS resources (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_resources_doctor_name ON resources(doctor_uuid, LOWER(name));
+
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS resource_uuids TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE appointment_series ADD COLUMN IF NOT EXISTS resource_uuids TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_appointments_resources ON appointments USING GIN (resource_uuids);
@@ -0,0 +1,16 @@
+-- Typed measurements (range of motion, muscle testing, pain scores, girth) recorded per visit.
+CREATE TABLE IF NOT EXISTS anamnesis_measurements (
+    uuid VARCHAR(255) PRIMARY KEY,
+    anamnesis_uuid VARCHAR(255) NOT NULL REFERENCES anamneses(uuid) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    kind VARCHAR(10) NOT NULL,
+    site VARCHAR(100) NOT NULL DEFAULT '',
+    side VARCHAR(10) NOT NULL DEFAULT '',
+    movement VARCHAR(100) NOT NULL DEFAULT '',
+    value DOUBLE PRECISION NOT NULL,
+    unit VARCHAR(10) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_anamnesis_measurements_anamnesis ON anamnesis_measurements(anamnesis_uuid, position);
@@ -0,0 +1,17 @@
+-- Scored outcome questionnaires (ODI, NDI, DASH, KOOS, LEFS) per patient, optionally linked to a visit.
+CREATE TABLE IF NOT EXISTS questionnaire_results (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    anamnesis_uuid VARCHAR(255) NULL REFERENCES anamneses(uuid) ON DELETE SET NULL,
+    code VARCHAR(10) NOT NULL,
+    answers JSONB NOT NULL DEFAULT '[]',
+    score DOUBLE PRECISION NULL,
+    subscores JSONB NOT NULL DEFAULT '[]',
+    missing_items INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_questionnaire_results_patient ON questionnaire_results(patient_uuid, code, completed_at);
+CREATE INDEX IF NOT EXISTS idx_questionnaire_results_anamnesis ON questionnaire_results(anamnesis_uuid);
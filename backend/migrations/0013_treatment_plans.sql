@@ -0,0 +1,21 @@
+-- Treatment plans per patient; goals are stored with the plan and visits link to it.
+CREATE TABLE IF NOT EXISTS treatment_plans (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    referral_diagnosis TEXT NOT NULL,
+    goals JSONB NOT NULL DEFAULT '[]',
+    planned_sessions INT NOT NULL,
+    frequency VARCHAR(50) NOT NULL DEFAULT '',
+    modalities TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    start_date DATE NOT NULL,
+    closed_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_treatment_plans_patient ON treatment_plans(patient_uuid, start_date DESC);
+
+ALTER TABLE anamneses ADD COLUMN IF NOT EXISTS treatment_plan_uuid VARCHAR(255) NULL REFERENCES treatment_plans(uuid) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_anamneses_treatment_plan ON anamneses(treatment_plan_uuid, created_at);
@@ -0,0 +1,44 @@
+-- Per-doctor exercise library and home exercise programs assigned to patients.
+CREATE TABLE IF NOT EXISTS exercises (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    name VARCHAR(150) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    image_url VARCHAR(255) NOT NULL DEFAULT '',
+    default_sets INT NOT NULL DEFAULT 0,
+    default_reps INT NOT NULL DEFAULT 0,
+    default_hold_seconds INT NOT NULL DEFAULT 0,
+    default_frequency VARCHAR(50) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercises_doctor_name ON exercises(doctor_uuid, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS exercise_programs (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    title VARCHAR(150) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_exercise_programs_patient ON exercise_programs(patient_uuid, created_at DESC);
+
+-- Override columns are NULL when the library default applies. Exercises used in a program cannot be deleted.
+CREATE TABLE IF NOT EXISTS exercise_program_items (
+    program_uuid VARCHAR(255) NOT NULL REFERENCES exercise_programs(uuid) ON DELETE CASCADE,
+    position INT NOT NULL,
+    exercise_uuid VARCHAR(255) NOT NULL REFERENCES exercises(uuid) ON DELETE RESTRICT,
+    sets_override INT NULL,
+    reps_override INT NULL,
+    hold_seconds_override INT NULL,
+    frequency_override VARCHAR(50) NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (program_uuid, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exercise_program_items_exercise ON exercise_program_items(exercise_uuid);
@@ -0,0 +1,16 @@
+-- Body chart pain map per visit: marked regions or points on the front/back outline.
+CREATE TABLE IF NOT EXISTS anamnesis_pain_marks (
+    uuid VARCHAR(255) PRIMARY KEY,
+    anamnesis_uuid VARCHAR(255) NOT NULL REFERENCES anamneses(uuid) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    view VARCHAR(5) NOT NULL,
+    region VARCHAR(50) NOT NULL DEFAULT '',
+    x DOUBLE PRECISION NOT NULL,
+    y DOUBLE PRECISION NOT NULL,
+    pain_type VARCHAR(20) NOT NULL,
+    intensity INT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_anamnesis_pain_marks_anamnesis ON anamnesis_pain_marks(anamnesis_uuid, position);
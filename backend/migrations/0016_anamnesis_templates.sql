@@ -0,0 +1,17 @@
+-- Named anamnesis templates per doctor; default measurements are kept as JSON with the template.
+CREATE TABLE IF NOT EXISTS anamnesis_templates (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    condition VARCHAR(100) NOT NULL DEFAULT '',
+    anamnesis TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    diagnosis TEXT NOT NULL DEFAULT '',
+    therapy TEXT NOT NULL DEFAULT '',
+    other_info TEXT NOT NULL DEFAULT '',
+    measurements JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_anamnesis_templates_doctor_name ON anamnesis_templates(doctor_uuid, LOWER(name));
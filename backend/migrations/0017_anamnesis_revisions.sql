@@ -0,0 +1,12 @@
+-- Edit history of anamneses: every saved version of a note with who saved it and when.
+-- Notes written before this table existed get their first revision on their next update.
+CREATE TABLE IF NOT EXISTS anamnesis_revisions (
+    uuid VARCHAR(255) PRIMARY KEY,
+    anamnesis_uuid VARCHAR(255) NOT NULL REFERENCES anamneses(uuid) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    changed_by VARCHAR(255) NULL REFERENCES doctors(uuid) ON DELETE SET NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    content JSONB NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_anamnesis_revisions_anamnesis ON anamnesis_revisions(anamnesis_uuid, revision);
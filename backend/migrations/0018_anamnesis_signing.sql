@@ -0,0 +1,14 @@
+-- Signing and locking of anamneses; locked notes are corrected only through addenda.
+ALTER TABLE anamneses ADD COLUMN IF NOT EXISTS signed_at TIMESTAMP NULL;
+ALTER TABLE anamneses ADD COLUMN IF NOT EXISTS signed_by VARCHAR(255) NULL REFERENCES doctors(uuid) ON DELETE SET NULL;
+ALTER TABLE anamneses ADD COLUMN IF NOT EXISTS locked_at TIMESTAMP NULL;
+
+CREATE TABLE IF NOT EXISTS anamnesis_addenda (
+    uuid VARCHAR(255) PRIMARY KEY,
+    anamnesis_uuid VARCHAR(255) NOT NULL REFERENCES anamneses(uuid) ON DELETE CASCADE,
+    doctor_uuid VARCHAR(255) NULL REFERENCES doctors(uuid) ON DELETE SET NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_anamnesis_addenda_anamnesis ON anamnesis_addenda(anamnesis_uuid, created_at);
@@ -0,0 +1,17 @@
+-- Files attached to patients and visits. The content lives in the attachment store (ATTACHMENTS_DIR),
+-- not under the public uploads folder.
+CREATE TABLE IF NOT EXISTS attachments (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    anamnesis_uuid VARCHAR(255) NULL REFERENCES anamneses(uuid) ON DELETE SET NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    storage_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_patient ON attachments(patient_uuid, created_at);
+CREATE INDEX IF NOT EXISTS idx_attachments_anamnesis ON attachments(anamnesis_uuid);
@@ -0,0 +1,11 @@
+-- ICD-10 coded diagnoses per visit. The description is copied from the catalogue when the code is
+-- set, so printed notes do not change if the catalogue file is replaced.
+CREATE TABLE IF NOT EXISTS anamnesis_diagnoses (
+    anamnesis_uuid VARCHAR(255) NOT NULL REFERENCES anamneses(uuid) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    code VARCHAR(10) NOT NULL,
+    description TEXT NOT NULL,
+    PRIMARY KEY (anamnesis_uuid, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_anamnesis_diagnoses_code ON anamnesis_diagnoses(code);
@@ -0,0 +1,42 @@
+-- Discharge summaries: the final report of a treatment episode with its rendered PDF. Rows are
+-- never updated; they only go away with their patient.
+CREATE TABLE IF NOT EXISTS discharge_summaries (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    treatment_plan_uuid VARCHAR(255) NULL REFERENCES treatment_plans(uuid) ON DELETE SET NULL,
+    anamnesis_uuids TEXT[] NOT NULL DEFAULT '{}',
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    session_count INT NOT NULL,
+    home_recommendations TEXT NOT NULL DEFAULT '',
+    conclusion TEXT NOT NULL DEFAULT '',
+    pdf BYTEA NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_discharge_summaries_patient ON discharge_summaries(patient_uuid, created_at);
+
+-- The stored report is a legal record: refuse changes to anything but the plan link, which is
+-- cleared by ON DELETE SET NULL when the plan is removed.
+CREATE OR REPLACE FUNCTION discharge_summaries_immutable() RETURNS trigger AS $$
+BEGIN
+    IF NEW.pdf IS DISTINCT FROM OLD.pdf
+        OR NEW.uuid IS DISTINCT FROM OLD.uuid
+        OR NEW.patient_uuid IS DISTINCT FROM OLD.patient_uuid
+        OR NEW.anamnesis_uuids IS DISTINCT FROM OLD.anamnesis_uuids
+        OR NEW.home_recommendations IS DISTINCT FROM OLD.home_recommendations
+        OR NEW.conclusion IS DISTINCT FROM OLD.conclusion
+        OR NEW.sha256 IS DISTINCT FROM OLD.sha256 THEN
+        RAISE EXCEPTION 'discharge summary % is immutable', OLD.uuid;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_discharge_summaries_immutable ON discharge_summaries;
+CREATE TRIGGER trg_discharge_summaries_immutable
+    BEFORE UPDATE ON discharge_summaries
+    FOR EACH ROW EXECUTE FUNCTION discharge_summaries_immutable();
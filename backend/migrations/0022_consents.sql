@@ -0,0 +1,61 @@
+-- Consent form templates per doctor and the consents signed by patients.
+CREATE TABLE IF NOT EXISTS consent_templates (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('treatment', 'gdpr', 'other')),
+    text TEXT NOT NULL,
+    valid_months INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_consent_templates_doctor_name ON consent_templates(doctor_uuid, LOWER(name));
+
+-- A signed consent keeps its own copy of the text, so that editing or deleting the template never
+-- changes what the patient signed.
+CREATE TABLE IF NOT EXISTS patient_consents (
+    uuid VARCHAR(255) PRIMARY KEY,
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    patient_uuid VARCHAR(255) NOT NULL REFERENCES patients(uuid) ON DELETE CASCADE,
+    template_uuid VARCHAR(255) NULL REFERENCES consent_templates(uuid) ON DELETE SET NULL,
+    kind VARCHAR(20) NOT NULL,
+    title VARCHAR(100) NOT NULL,
+    text TEXT NOT NULL,
+    text_sha256 CHAR(64) NOT NULL,
+    signer_name VARCHAR(200) NOT NULL,
+    signature BYTEA NOT NULL,
+    signature_sha256 CHAR(64) NOT NULL,
+    signed_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_patient_consents_patient ON patient_consents(patient_uuid, kind);
+
+-- Only revoking (once) and the template link cleared by ON DELETE SET NULL may change a signed consent.
+CREATE OR REPLACE FUNCTION patient_consents_immutable() RETURNS trigger AS $$
+BEGIN
+    IF NEW.uuid IS DISTINCT FROM OLD.uuid
+        OR NEW.patient_uuid IS DISTINCT FROM OLD.patient_uuid
+        OR NEW.kind IS DISTINCT FROM OLD.kind
+        OR NEW.title IS DISTINCT FROM OLD.title
+        OR NEW.text IS DISTINCT FROM OLD.text
+        OR NEW.text_sha256 IS DISTINCT FROM OLD.text_sha256
+        OR NEW.signer_name IS DISTINCT FROM OLD.signer_name
+        OR NEW.signature IS DISTINCT FROM OLD.signature
+        OR NEW.signature_sha256 IS DISTINCT FROM OLD.signature_sha256
+        OR NEW.signed_at IS DISTINCT FROM OLD.signed_at
+        OR NEW.expires_at IS DISTINCT FROM OLD.expires_at
+        OR (OLD.revoked_at IS NOT NULL AND NEW.revoked_at IS DISTINCT FROM OLD.revoked_at) THEN
+        RAISE EXCEPTION 'consent % is immutable', OLD.uuid;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_patient_consents_immutable ON patient_consents;
+CREATE TRIGGER trg_patient_consents_immutable
+    BEFORE UPDATE ON patient_consents
+    FOR EACH ROW EXECUTE FUNCTION patient_consents_immutable();
@@ -0,0 +1,23 @@
+-- Red-flag screening. A doctor without rows in red_flag_items uses the built-in spinal checklist.
+CREATE TABLE IF NOT EXISTS red_flag_items (
+    doctor_uuid VARCHAR(255) NOT NULL REFERENCES doctors(uuid) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    code VARCHAR(50) NOT NULL,
+    label VARCHAR(200) NOT NULL,
+    PRIMARY KEY (doctor_uuid, code)
+);
+
+-- Answers per visit; the label is copied from the checklist like the ICD-10 descriptions.
+CREATE TABLE IF NOT EXISTS anamnesis_red_flags (
+    anamnesis_uuid VARCHAR(255) NOT NULL REFERENCES anamneses(uuid) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    code VARCHAR(50) NOT NULL,
+    label VARCHAR(200) NOT NULL,
+    positive BOOLEAN NOT NULL DEFAULT FALSE,
+    note TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (anamnesis_uuid, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_anamnesis_red_flags_positive ON anamnesis_red_flags(anamnesis_uuid) WHERE positive;
+
+ALTER TABLE anamneses ADD COLUMN IF NOT EXISTS red_flag_action TEXT NOT NULL DEFAULT '';
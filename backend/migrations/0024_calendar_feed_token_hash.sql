@@ -0,0 +1,19 @@
+-- Feed tokens are stored as SHA-256 so a database reader can not subscribe to a doctor's calendar.
+-- Existing tokens are hashed in place and their URLs keep working.
+ALTER TABLE calendar_feed_tokens ADD COLUMN IF NOT EXISTS token_sha256 VARCHAR(64);
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'calendar_feed_tokens' AND column_name = 'token'
+    ) THEN
+        UPDATE calendar_feed_tokens
+        SET token_sha256 = encode(sha256(convert_to(token, 'UTF8')), 'hex')
+        WHERE token_sha256 IS NULL;
+        ALTER TABLE calendar_feed_tokens DROP COLUMN token;
+    END IF;
+END $$;
+
+ALTER TABLE calendar_feed_tokens ALTER COLUMN token_sha256 SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_feed_tokens_sha256 ON calendar_feed_tokens(token_sha256);
@@ -0,0 +1,4 @@
+-- The edit history outlives its note: deleting an anamnesis keeps its revisions and adds a final
+-- revision with the deleted version, so earlier clinical text is never lost.
+ALTER TABLE anamnesis_revisions DROP CONSTRAINT IF EXISTS anamnesis_revisions_anamnesis_uuid_fkey;
+ALTER TABLE anamnesis_revisions ADD COLUMN IF NOT EXISTS deleted BOOLEAN NOT NULL DEFAULT FALSE;
@@ -9,7 +9,9 @@ if (-not $env:DATABASE_URL) {
 $ScriptDir = Split-Path -Parent $MyInvocation.MyCommand.Path
 $RootDir = Split-Path -Parent $ScriptDir
 
-psql $env:DATABASE_URL -v ON_ERROR_STOP=1 -f (Join-Path $RootDir "migrations/0001_init.sql")
-psql $env:DATABASE_URL -v ON_ERROR_STOP=1 -f (Join-Path $RootDir "migrations/0002_seed_admin.sql")
+# Migrations are idempotent and applied in filename order.
+Get-ChildItem (Join-Path $RootDir "migrations") -Filter *.sql | Sort-Object Name | ForEach-Object {
+    psql $env:DATABASE_URL -v ON_ERROR_STOP=1 -f $_.FullName
+}
 
 Write-Host "Migrations applied."